@@ -1,16 +1,22 @@
 package application
 
 import (
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
+	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/web"
 	"github.com/pivotal-cf-experimental/warrant"
@@ -24,6 +30,7 @@ type Application struct {
 	logger     lager.Logger
 	dbProvider *DBProvider
 	migrator   Migrator
+	outbox     *mail.Outbox
 }
 
 func New(env Environment, dbp *DBProvider) Application {
@@ -32,27 +39,78 @@ func New(env Environment, dbp *DBProvider) Application {
 	l := lager.NewLogger("notifications")
 	l.RegisterSink(lager.NewWriterSink(os.Stdout, lager.DEBUG))
 
+	var outbox *mail.Outbox
+	if env.TestMode {
+		outbox = mail.NewOutbox(env.TestModeOutboxSize, util.NewClock())
+	}
+
 	return Application{
 		env:        env,
 		logger:     l,
 		dbProvider: dbp,
 		migrator:   NewMigrator(dbp, databaseMigrator, env.VCAPApplication.InstanceIndex == 0, env.ModelMigrationsPath, env.GobbleMigrationsPath, path.Join(env.RootPath, "templates", "default.json")),
+		outbox:     outbox,
 	}
 }
 
-func (a Application) mailClient() *mail.Client {
-	return mail.NewClient(mail.Config{
+func (a Application) mailConfig() mail.Config {
+	config := mail.Config{
 		User:              a.env.SMTPUser,
 		Pass:              a.env.SMTPPass,
 		Host:              a.env.SMTPHost,
 		Port:              a.env.SMTPPort,
 		Secret:            a.env.SMTPCRAMMD5Secret,
+		OAuthToken:        a.env.SMTPOAuthToken,
 		TestMode:          a.env.TestMode,
 		SkipVerifySSL:     !a.env.VerifySSL,
-		DisableTLS:        !a.env.SMTPTLS,
+		TLSMode:           a.env.SMTPTLSMode,
 		LoggingEnabled:    a.env.SMTPLoggingEnabled,
 		SMTPAuthMechanism: a.env.SMTPAuthMechanism,
+	}
+
+	if a.env.SMTPClientCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(a.env.SMTPClientCertFile, a.env.SMTPClientKeyFile)
+		if err != nil {
+			a.logger.Fatal("smtp-client-certificate-errored", err)
+		}
+		config.ClientCertificate = &certificate
+	}
+
+	config.DKIMSigner = a.dkimSigner()
+
+	return config
+}
+
+func (a Application) dkimSigner() *mail.DKIMSigner {
+	if a.env.DKIMPrivateKeyFile == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(a.env.DKIMPrivateKeyFile)
+	if err != nil {
+		a.logger.Fatal("dkim-private-key-errored", err)
+	}
+
+	key, err := mail.ParseDKIMPrivateKey(data)
+	if err != nil {
+		a.logger.Fatal("dkim-private-key-errored", err)
+	}
+
+	signer, err := mail.NewDKIMSigner(mail.DKIMConfig{
+		Domain:     a.env.DKIMDomain,
+		Selector:   a.env.DKIMSelector,
+		PrivateKey: key,
+		Clock:      util.NewClock(),
 	})
+	if err != nil {
+		a.logger.Fatal("dkim-signer-errored", err)
+	}
+
+	return signer
+}
+
+func (a Application) mailClient() *mail.Client {
+	return mail.NewClient(a.mailConfig())
 }
 
 func (a Application) Run() {
@@ -80,7 +138,7 @@ func (a Application) Run() {
 }
 
 func (a Application) VerifySMTPConfiguration() {
-	if a.env.TestMode {
+	if a.env.TestMode || a.env.MailTransport != mail.TransportSMTP {
 		return
 	}
 
@@ -89,6 +147,7 @@ func (a Application) VerifySMTPConfiguration() {
 	if err != nil {
 		a.logger.Fatal("smtp-connect-errored", err)
 	}
+	defer mc.Quit()
 
 	err = mc.Hello()
 	if err != nil {
@@ -97,17 +156,48 @@ func (a Application) VerifySMTPConfiguration() {
 
 	startTLSSupported, _ := mc.Extension("STARTTLS")
 
-	mc.Quit()
+	switch a.env.SMTPTLSMode {
+	case mail.TLSModeNone:
+		if startTLSSupported {
+			a.logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Not configured to use TLS over SMTP, but the mail server does support the "STARTTLS" extension.`))
+		}
+		return
+	case mail.TLSModeSTARTTLS:
+		if !startTLSSupported {
+			a.logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Configured to use TLS over SMTP, but the mail server does not support the "STARTTLS" extension.`))
+		}
+
+		err = mc.StartTLS()
+		if err != nil {
+			a.logger.Fatal("smtp-tls-errored", err)
+		}
+	}
+
+	if a.env.SMTPAuthMechanism == mail.SMTPAuthNone {
+		return
+	}
 
-	if !startTLSSupported && a.env.SMTPTLS {
-		a.logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Configured to use TLS over SMTP, but the mail server does not support the "STARTTLS" extension.`))
+	authSupported, mechanisms := mc.Extension("AUTH")
+	if !authSupported || !containsFold(strings.Fields(mechanisms), a.env.SMTPAuthMechanism) {
+		a.logger.Fatal("smtp-config-mismatch", fmt.Errorf("SMTP auth configuration mismatch: Configured to use %q authentication, but the mail server only supports %q.", a.env.SMTPAuthMechanism, mechanisms))
 	}
 
-	if startTLSSupported && !a.env.SMTPTLS {
-		a.logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Not configured to use TLS over SMTP, but the mail server does support the "STARTTLS" extension.`))
+	err = mc.Auth(a.logger)
+	if err != nil {
+		a.logger.Fatal("smtp-auth-errored", err)
 	}
 }
 
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (a Application) StartQueueGauge() {
 	if a.env.VCAPApplication.InstanceIndex != 0 {
 		return
@@ -134,8 +224,66 @@ func (a Application) StartKeyRefresher(validator *uaa.TokenValidator) {
 	}()
 }
 
+func (a Application) mailPool() *mail.Pool {
+	return mail.NewPool(a.mailConfig(), mail.PoolConfig{
+		Size:                     a.env.SMTPPoolSize,
+		MaxMessagesPerConnection: a.env.SMTPMaxMessagesPerConnection,
+		IdleTimeout:              time.Duration(a.env.SMTPIdleTimeout) * time.Millisecond,
+	})
+}
+
+// mailTransport builds the transport selected by MAIL_TRANSPORT. In test mode
+// messages are always captured in the outbox instead.
+func (a Application) mailTransport() mail.Transport {
+	if a.env.TestMode {
+		return a.outbox
+	}
+
+	transports := mail.TransportRegistry{
+		mail.TransportSMTP: func() (mail.Transport, error) {
+			return a.mailPool(), nil
+		},
+		mail.TransportHTTP: func() (mail.Transport, error) {
+			// The mail API is handed message content and the API key, so its
+			// certificate is verified whatever VERIFY_SSL says.
+			return mail.NewHTTPTransport(mail.HTTPTransportConfig{
+				URL:    a.env.MailHTTPURL,
+				APIKey: a.env.MailHTTPAPIKey,
+				HTTPClient: &http.Client{
+					Timeout: 30 * time.Second,
+				},
+			}), nil
+		},
+		mail.TransportSendmail: func() (mail.Transport, error) {
+			return mail.NewSendmailTransport(mail.SendmailTransportConfig{
+				Path:       a.env.SendmailPath,
+				DKIMSigner: a.dkimSigner(),
+			}), nil
+		},
+		mail.TransportFile: func() (mail.Transport, error) {
+			return mail.NewFileTransport(mail.FileTransportConfig{
+				Directory:  a.env.MailFilePath,
+				DKIMSigner: a.dkimSigner(),
+				Clock:      util.NewClock(),
+			}), nil
+		},
+	}
+
+	transport, err := transports.Build(a.env.MailTransport)
+	if err != nil {
+		a.logger.Fatal("mail-transport-errored", err)
+	}
+
+	err = transport.Connect(a.logger)
+	if err != nil {
+		a.logger.Fatal("mail-transport-connect-errored", err)
+	}
+
+	return transport
+}
+
 func (a Application) StartWorkers(validator *uaa.TokenValidator) {
-	postal.Boot(a.mailClient, a.dbProvider.sqlDB, postal.Config{
+	postal.Boot(a.mailTransport(), a.dbProvider.sqlDB, postal.Config{
 		UAAClientID:          a.env.UAAClientID,
 		UAAClientSecret:      a.env.UAAClientSecret,
 		UAATokenValidator:    validator,
@@ -150,18 +298,34 @@ func (a Application) StartWorkers(validator *uaa.TokenValidator) {
 		Domain:               a.env.Domain,
 		QueueWaitMaxDuration: a.env.GobbleWaitMaxDuration,
 		CCHost:               a.env.CCHost,
+		PublicURL:            a.env.PublicURL,
+		HTMLAllowedTags:      a.env.HTMLAllowedTags,
+		HTMLBaseURL:          a.env.HTMLBaseURL,
 	})
 }
 
 func (a Application) StartMessageGC() {
-	messageLifetime := 24 * time.Hour
-	db := a.dbProvider.Database()
-	messagesRepo := a.dbProvider.MessagesRepo()
-	pollingInterval := 1 * time.Hour
+	if a.env.VCAPApplication.InstanceIndex != 0 {
+		return
+	}
+
+	config := postal.MessageGCConfig{
+		Lifetime:        a.env.MessageLifetime,
+		StatusLifetimes: a.env.MessageStatusLifetimes,
+		ReceiptLifetime: a.env.ReceiptLifetime,
+		PollingInterval: a.env.RetentionPollingInterval,
+		Database:        a.dbProvider.Database(),
+		MessagesRepo:    a.dbProvider.MessagesRepo(),
+		ReceiptsRepo:    a.dbProvider.ReceiptsRepo(),
+		BatchesRepo:     a.dbProvider.BatchesRepo(),
+		Logger:          log.New(os.Stdout, "", 0),
+	}
 
-	logger := log.New(os.Stdout, "", 0)
-	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, pollingInterval, logger)
-	messageGC.Run()
+	if a.env.RetentionArchivePath != "" {
+		config.Archiver = postal.NewFileArchiver(a.env.RetentionArchivePath, util.NewClock())
+	}
+
+	postal.NewMessageGC(config).Run()
 }
 
 func (a Application) StartServer(logger lager.Logger, validator *uaa.TokenValidator) {
@@ -181,6 +345,15 @@ func (a Application) StartServer(logger lager.Logger, validator *uaa.TokenValida
 		UAAClientSecret:   a.env.UAAClientSecret,
 		DefaultUAAScopes:  a.env.DefaultUAAScopes,
 		CCHost:            a.env.CCHost,
+
+		EncryptionKey: a.env.EncryptionKey,
+		Outbox:        a.outbox,
+
+		Sender:          a.env.Sender,
+		Domain:          a.env.Domain,
+		PublicURL:       a.env.PublicURL,
+		HTMLAllowedTags: a.env.HTMLAllowedTags,
+		HTMLBaseURL:     a.env.HTMLBaseURL,
 	})
 }
 
@@ -1,11 +1,14 @@
 package application
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/ryanmoran/viron"
@@ -16,23 +19,47 @@ type Environment struct {
 	CORSOrigin                         string `env:"CORS_ORIGIN" env-default:"*"`
 	DBLoggingEnabled                   bool   `env:"DB_LOGGING_ENABLED"`
 	DBMaxOpenConns                     int    `env:"DB_MAX_OPEN_CONNS"`
+	DKIMDomain                         string `env:"DKIM_DOMAIN"`
+	DKIMPrivateKeyFile                 string `env:"DKIM_PRIVATE_KEY_FILE"`
+	DKIMSelector                       string `env:"DKIM_SELECTOR"`
 	DatabaseURL                        string `env:"DATABASE_URL" env-required:"true"`
 	DefaultUAAScopesList               string `env:"DEFAULT_UAA_SCOPES"`
 	Domain                             string `env:"DOMAIN" env-required:"true"`
 	EncryptionKey                      []byte `env:"ENCRYPTION_KEY" env-required:"true"`
 	GobbleWaitMaxDuration              int    `env:"GOBBLE_WAIT_MAX_DURATION" env-default:"5000"`
+	HTMLAllowedTagsList                string `env:"HTML_ALLOWED_TAGS"`
+	HTMLBaseURL                        string `env:"HTML_BASE_URL"`
+	MailFilePath                       string `env:"MAIL_FILE_PATH"`
+	MailHTTPAPIKey                     string `env:"MAIL_HTTP_API_KEY"`
+	MailHTTPURL                        string `env:"MAIL_HTTP_URL"`
+	MailTransport                      string `env:"MAIL_TRANSPORT" env-default:"smtp"`
+	MessageRetention                   string `env:"MESSAGE_RETENTION" env-default:"24h"`
+	MessageRetentionByStatus           string `env:"MESSAGE_RETENTION_BY_STATUS"`
 	Port                               int    `env:"PORT" env-default:"3000"`
+	PublicURL                          string `env:"PUBLIC_URL"`
+	ReceiptRetention                   string `env:"RECEIPT_RETENTION"`
+	RetentionArchivePath               string `env:"RETENTION_ARCHIVE_PATH"`
+	RetentionInterval                  string `env:"RETENTION_INTERVAL" env-default:"1h"`
 	RootPath                           string `env:"ROOT_PATH"`
-	SMTPAuthMechanism                  string `env:"SMTP_AUTH_MECHANISM" env-required:"true"`
+	SMTPAuthMechanism                  string `env:"SMTP_AUTH_MECHANISM"`
 	SMTPCRAMMD5Secret                  string `env:"SMTP_CRAMMD5_SECRET"`
-	SMTPHost                           string `env:"SMTP_HOST" env-required:"true"`
+	SMTPClientCertFile                 string `env:"SMTP_CLIENT_CERT_FILE"`
+	SMTPClientKeyFile                  string `env:"SMTP_CLIENT_KEY_FILE"`
+	SMTPHost                           string `env:"SMTP_HOST"`
+	SMTPIdleTimeout                    int    `env:"SMTP_IDLE_TIMEOUT" env-default:"30000"`
 	SMTPLoggingEnabled                 bool   `env:"SMTP_LOGGING_ENABLED" env-default:"false"`
+	SMTPMaxMessagesPerConnection       int    `env:"SMTP_MAX_MESSAGES_PER_CONNECTION" env-default:"100"`
+	SMTPOAuthToken                     string `env:"SMTP_OAUTH_TOKEN"`
 	SMTPPass                           string `env:"SMTP_PASS"`
-	SMTPPort                           string `env:"SMTP_PORT" env-required:"true"`
+	SMTPPoolSize                       int    `env:"SMTP_POOL_SIZE" env-default:"10"`
+	SMTPPort                           string `env:"SMTP_PORT"`
 	SMTPTLS                            bool   `env:"SMTP_TLS" env-default:"true"`
+	SMTPTLSMode                        string `env:"SMTP_TLS_MODE"`
 	SMTPUser                           string `env:"SMTP_USER"`
+	SendmailPath                       string `env:"SENDMAIL_PATH" env-default:"/usr/sbin/sendmail"`
 	Sender                             string `env:"SENDER" env-required:"true"`
 	TestMode                           bool   `env:"TEST_MODE" env-default:"false"`
+	TestModeOutboxSize                 int    `env:"TEST_MODE_OUTBOX_SIZE" env-default:"100"`
 	UAAClientID                        string `env:"UAA_CLIENT_ID" env-required:"true"`
 	UAAClientSecret                    string `env:"UAA_CLIENT_SECRET" env-required:"true"`
 	UAAHost                            string `env:"UAA_HOST" env-required:"true"`
@@ -46,11 +73,18 @@ type Environment struct {
 		InstanceIndex int `json:"instance_index"`
 	} `env:"VCAP_APPLICATION" env-required:"true"`
 
-	ModelMigrationsPath  string
-	GobbleMigrationsPath string
-	DefaultUAAScopes     []string
+	ModelMigrationsPath      string
+	GobbleMigrationsPath     string
+	DefaultUAAScopes         []string
+	HTMLAllowedTags          []string
+	MessageLifetime          time.Duration
+	MessageStatusLifetimes   map[string]time.Duration
+	ReceiptLifetime          time.Duration
+	RetentionPollingInterval time.Duration
 }
 
+var retentionStatuses = []string{"queued", "scheduled", "delivered", "failed", "undeliverable", "canceled"}
+
 type EnvironmentError struct {
 	Err error
 }
@@ -73,13 +107,44 @@ func NewEnvironment() (Environment, error) {
 
 	env.expandRoot()
 
+	err = env.validateMailTransport()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
 	err = env.validateSMTPAuthMechanism()
 	if err != nil {
 		return env, EnvironmentError{err}
 	}
 
+	err = env.parseSMTPTLSMode()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.validateSMTPClientCertificate()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.validateDKIM()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
 	env.inferMigrationsDirs()
 	env.parseDefaultUAAScopes()
+	env.parseHTMLAllowedTags()
+
+	err = env.validateHTMLBaseURL()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.parseRetention()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
 
 	return env, nil
 }
@@ -88,6 +153,28 @@ func (env *Environment) parseDefaultUAAScopes() {
 	env.DefaultUAAScopes = strings.Split(env.DefaultUAAScopesList, ",")
 }
 
+func (env *Environment) parseHTMLAllowedTags() {
+	for _, tag := range strings.Split(env.HTMLAllowedTagsList, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			env.HTMLAllowedTags = append(env.HTMLAllowedTags, tag)
+		}
+	}
+}
+
+func (env *Environment) validateHTMLBaseURL() error {
+	if env.HTMLBaseURL == "" {
+		return nil
+	}
+
+	baseURL, err := url.Parse(env.HTMLBaseURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return fmt.Errorf("Could not parse HTML_BASE_URL %q, it must be an absolute http or https URL", env.HTMLBaseURL)
+	}
+
+	return nil
+}
+
 func (env *Environment) expandRoot() {
 	env.RootPath = os.ExpandEnv(env.RootPath)
 }
@@ -114,7 +201,47 @@ func (env *Environment) parseDatabaseURL() error {
 	return nil
 }
 
+// validateMailTransport enforces the settings that the selected transport
+// needs, so that the SMTP settings are only required when sending over SMTP.
+func (env *Environment) validateMailTransport() error {
+	type variable struct {
+		name  string
+		value string
+	}
+
+	var required []variable
+
+	switch env.MailTransport {
+	case mail.TransportSMTP:
+		required = []variable{
+			{"SMTP_HOST", env.SMTPHost},
+			{"SMTP_PORT", env.SMTPPort},
+			{"SMTP_AUTH_MECHANISM", env.SMTPAuthMechanism},
+		}
+	case mail.TransportHTTP:
+		required = []variable{{"MAIL_HTTP_URL", env.MailHTTPURL}}
+	case mail.TransportSendmail:
+		required = []variable{{"SENDMAIL_PATH", env.SendmailPath}}
+	case mail.TransportFile:
+		required = []variable{{"MAIL_FILE_PATH", env.MailFilePath}}
+	default:
+		return fmt.Errorf("Could not parse MAIL_TRANSPORT %q, it is not one of the allowed values: %+v", env.MailTransport, mail.Transports)
+	}
+
+	for _, v := range required {
+		if v.value == "" {
+			return viron.RequiredFieldError{Name: v.name}
+		}
+	}
+
+	return nil
+}
+
 func (env *Environment) validateSMTPAuthMechanism() error {
+	if env.MailTransport != mail.TransportSMTP {
+		return nil
+	}
+
 	for _, mechanism := range mail.SMTPAuthMechanisms {
 		if mechanism == env.SMTPAuthMechanism {
 			return nil
@@ -123,3 +250,128 @@ func (env *Environment) validateSMTPAuthMechanism() error {
 
 	return fmt.Errorf("Could not parse SMTP_AUTH_MECHANISM %q, it is not one of the allowed values: %+v", env.SMTPAuthMechanism, mail.SMTPAuthMechanisms)
 }
+
+// parseSMTPTLSMode falls back to SMTP_TLS when SMTP_TLS_MODE is not set so that
+// existing deployments keep their behavior.
+func (env *Environment) parseSMTPTLSMode() error {
+	if env.SMTPTLSMode == "" {
+		env.SMTPTLSMode = mail.TLSModeNone
+		if env.SMTPTLS {
+			env.SMTPTLSMode = mail.TLSModeSTARTTLS
+		}
+		return nil
+	}
+
+	env.SMTPTLSMode = strings.ToLower(env.SMTPTLSMode)
+	for _, mode := range mail.TLSModes {
+		if mode == env.SMTPTLSMode {
+			env.SMTPTLS = mode != mail.TLSModeNone
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Could not parse SMTP_TLS_MODE %q, it is not one of the allowed values: %+v", env.SMTPTLSMode, mail.TLSModes)
+}
+
+func (env *Environment) validateSMTPClientCertificate() error {
+	if (env.SMTPClientCertFile == "") != (env.SMTPClientKeyFile == "") {
+		return errors.New("Could not parse SMTP_CLIENT_CERT_FILE and SMTP_CLIENT_KEY_FILE, both must be set to use a client certificate")
+	}
+
+	return nil
+}
+
+func (env *Environment) validateDKIM() error {
+	if env.DKIMPrivateKeyFile == "" {
+		return nil
+	}
+
+	if env.DKIMDomain == "" || env.DKIMSelector == "" {
+		return errors.New("Could not parse DKIM_PRIVATE_KEY_FILE, DKIM_DOMAIN and DKIM_SELECTOR must also be set to sign messages")
+	}
+
+	return nil
+}
+
+func (env *Environment) parseRetention() error {
+	var err error
+
+	env.MessageLifetime, err = parseRetentionPeriod("MESSAGE_RETENTION", env.MessageRetention)
+	if err != nil {
+		return err
+	}
+
+	env.ReceiptLifetime, err = parseRetentionPeriod("RECEIPT_RETENTION", env.ReceiptRetention)
+	if err != nil {
+		return err
+	}
+
+	env.RetentionPollingInterval, err = parseRetentionPeriod("RETENTION_INTERVAL", env.RetentionInterval)
+	if err != nil {
+		return err
+	}
+	if env.RetentionPollingInterval == 0 {
+		return fmt.Errorf("Could not parse RETENTION_INTERVAL %q, it must be greater than zero", env.RetentionInterval)
+	}
+
+	env.MessageStatusLifetimes = map[string]time.Duration{}
+	for _, setting := range strings.Split(env.MessageRetentionByStatus, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+
+		parts := strings.SplitN(setting, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Could not parse MESSAGE_RETENTION_BY_STATUS %q, it does not fit format %q", env.MessageRetentionByStatus, "status=period,status=period")
+		}
+
+		status := strings.TrimSpace(parts[0])
+		if !containsString(retentionStatuses, status) {
+			return fmt.Errorf("Could not parse MESSAGE_RETENTION_BY_STATUS %q, %q is not one of the allowed statuses: %+v", env.MessageRetentionByStatus, status, retentionStatuses)
+		}
+
+		lifetime, err := parseRetentionPeriod("MESSAGE_RETENTION_BY_STATUS", strings.TrimSpace(parts[1]))
+		if err != nil {
+			return err
+		}
+
+		env.MessageStatusLifetimes[status] = lifetime
+	}
+
+	return nil
+}
+
+// parseRetentionPeriod accepts Go durations ("36h") as well as whole days
+// ("90d"). An empty value means records are kept forever.
+func parseRetentionPeriod(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	var period time.Duration
+	var err error
+	if strings.HasSuffix(value, "d") {
+		var days int
+		days, err = strconv.Atoi(strings.TrimSuffix(value, "d"))
+		period = time.Duration(days) * 24 * time.Hour
+	} else {
+		period, err = time.ParseDuration(value)
+	}
+
+	if err != nil || period < 0 {
+		return 0, fmt.Errorf("Could not parse %s %q, it must be a duration such as %q or a number of days such as %q", name, value, "24h", "90d")
+	}
+
+	return period, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
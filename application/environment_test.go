@@ -3,6 +3,7 @@ package application_test
 import (
 	"errors"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/application"
 	"github.com/ryanmoran/viron"
@@ -40,6 +41,30 @@ var _ = Describe("Environment", func() {
 		"VCAP_APPLICATION",
 		"VERIFY_SSL",
 		"DATABASE_ENABLE_IDENTITY_VERIFICATION",
+		"MESSAGE_RETENTION",
+		"MESSAGE_RETENTION_BY_STATUS",
+		"RECEIPT_RETENTION",
+		"RETENTION_ARCHIVE_PATH",
+		"RETENTION_INTERVAL",
+		"SMTP_IDLE_TIMEOUT",
+		"SMTP_MAX_MESSAGES_PER_CONNECTION",
+		"SMTP_POOL_SIZE",
+		"SMTP_TLS",
+		"SMTP_TLS_MODE",
+		"SMTP_CLIENT_CERT_FILE",
+		"SMTP_CLIENT_KEY_FILE",
+		"SMTP_OAUTH_TOKEN",
+		"DKIM_DOMAIN",
+		"DKIM_PRIVATE_KEY_FILE",
+		"DKIM_SELECTOR",
+		"MAIL_TRANSPORT",
+		"MAIL_HTTP_URL",
+		"MAIL_HTTP_API_KEY",
+		"MAIL_FILE_PATH",
+		"SENDMAIL_PATH",
+		"TEST_MODE_OUTBOX_SIZE",
+		"HTML_ALLOWED_TAGS",
+		"HTML_BASE_URL",
 	}
 
 	BeforeEach(func() {
@@ -221,7 +246,7 @@ var _ = Describe("Environment", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("it errors if SMTP_AUTH_MECHANISM is not one of the three supported types", func() {
+		It("it errors if SMTP_AUTH_MECHANISM is not one of the supported types", func() {
 			os.Setenv("SMTP_AUTH_MECHANISM", "cram-md5")
 			_, err := application.NewEnvironment()
 			Expect(err).NotTo(HaveOccurred())
@@ -234,9 +259,91 @@ var _ = Describe("Environment", func() {
 			_, err = application.NewEnvironment()
 			Expect(err).NotTo(HaveOccurred())
 
+			os.Setenv("SMTP_AUTH_MECHANISM", "login")
+			_, err = application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+
+			os.Setenv("SMTP_AUTH_MECHANISM", "xoauth2")
+			os.Setenv("SMTP_OAUTH_TOKEN", "some-bearer-token")
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPOAuthToken).To(Equal("some-bearer-token"))
+
 			os.Setenv("SMTP_AUTH_MECHANISM", "banana")
 			_, err = application.NewEnvironment()
-			Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New("Could not parse SMTP_AUTH_MECHANISM \"banana\", it is not one of the allowed values: [none plain cram-md5]")}))
+			Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New("Could not parse SMTP_AUTH_MECHANISM \"banana\", it is not one of the allowed values: [none plain cram-md5 login xoauth2]")}))
+		})
+
+		Context("SMTP_TLS_MODE", func() {
+			It("derives the mode from SMTP_TLS when it is not set", func() {
+				os.Setenv("SMTP_TLS_MODE", "")
+				os.Setenv("SMTP_TLS", "true")
+
+				env, err := application.NewEnvironment()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.SMTPTLSMode).To(Equal("starttls"))
+
+				os.Setenv("SMTP_TLS", "false")
+
+				env, err = application.NewEnvironment()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.SMTPTLSMode).To(Equal("none"))
+			})
+
+			It("takes precedence over SMTP_TLS", func() {
+				os.Setenv("SMTP_TLS", "false")
+				os.Setenv("SMTP_TLS_MODE", "implicit")
+
+				env, err := application.NewEnvironment()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.SMTPTLSMode).To(Equal("implicit"))
+				Expect(env.SMTPTLS).To(BeTrue())
+			})
+
+			It("errors when the mode is not supported", func() {
+				os.Setenv("SMTP_TLS_MODE", "banana")
+
+				_, err := application.NewEnvironment()
+				Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New("Could not parse SMTP_TLS_MODE \"banana\", it is not one of the allowed values: [none starttls implicit]")}))
+			})
+		})
+
+		Context("DKIM", func() {
+			It("loads the values when they are present", func() {
+				os.Setenv("DKIM_DOMAIN", "example.com")
+				os.Setenv("DKIM_SELECTOR", "notifications")
+				os.Setenv("DKIM_PRIVATE_KEY_FILE", "/path/to/dkim.pem")
+
+				env, err := application.NewEnvironment()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.DKIMDomain).To(Equal("example.com"))
+				Expect(env.DKIMSelector).To(Equal("notifications"))
+				Expect(env.DKIMPrivateKeyFile).To(Equal("/path/to/dkim.pem"))
+			})
+
+			It("errors when the private key is set without a domain and selector", func() {
+				os.Setenv("DKIM_DOMAIN", "")
+				os.Setenv("DKIM_SELECTOR", "notifications")
+				os.Setenv("DKIM_PRIVATE_KEY_FILE", "/path/to/dkim.pem")
+
+				_, err := application.NewEnvironment()
+				Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New("Could not parse DKIM_PRIVATE_KEY_FILE, DKIM_DOMAIN and DKIM_SELECTOR must also be set to sign messages")}))
+			})
+		})
+
+		It("errors when only one of the client certificate and key files is set", func() {
+			os.Setenv("SMTP_CLIENT_CERT_FILE", "/path/to/cert.pem")
+			os.Setenv("SMTP_CLIENT_KEY_FILE", "")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New("Could not parse SMTP_CLIENT_CERT_FILE and SMTP_CLIENT_KEY_FILE, both must be set to use a client certificate")}))
+
+			os.Setenv("SMTP_CLIENT_KEY_FILE", "/path/to/key.pem")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPClientCertFile).To(Equal("/path/to/cert.pem"))
+			Expect(env.SMTPClientKeyFile).To(Equal("/path/to/key.pem"))
 		})
 
 		It("errors when the values are missing", func() {
@@ -266,6 +373,85 @@ var _ = Describe("Environment", func() {
 		})
 	})
 
+	Describe("mail transport configuration", func() {
+		It("defaults to smtp", func() {
+			os.Setenv("MAIL_TRANSPORT", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.MailTransport).To(Equal("smtp"))
+			Expect(env.SendmailPath).To(Equal("/usr/sbin/sendmail"))
+		})
+
+		It("does not require the SMTP settings for other transports", func() {
+			os.Setenv("SMTP_HOST", "")
+			os.Setenv("SMTP_PORT", "")
+			os.Setenv("SMTP_AUTH_MECHANISM", "")
+			os.Setenv("MAIL_TRANSPORT", "http")
+			os.Setenv("MAIL_HTTP_URL", "https://mail.example.com/v1/send")
+			os.Setenv("MAIL_HTTP_API_KEY", "some-api-key")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.MailTransport).To(Equal("http"))
+			Expect(env.MailHTTPURL).To(Equal("https://mail.example.com/v1/send"))
+			Expect(env.MailHTTPAPIKey).To(Equal("some-api-key"))
+		})
+
+		It("requires the settings of the selected transport", func() {
+			os.Setenv("MAIL_TRANSPORT", "http")
+			os.Setenv("MAIL_HTTP_URL", "")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{Err: viron.RequiredFieldError{Name: "MAIL_HTTP_URL"}}))
+
+			os.Setenv("MAIL_TRANSPORT", "file")
+			os.Setenv("MAIL_FILE_PATH", "")
+
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{Err: viron.RequiredFieldError{Name: "MAIL_FILE_PATH"}}))
+
+			os.Setenv("MAIL_FILE_PATH", "/var/vcap/data/outbox")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.MailFilePath).To(Equal("/var/vcap/data/outbox"))
+		})
+
+		It("errors when the transport is not supported", func() {
+			os.Setenv("MAIL_TRANSPORT", "carrier-pigeon")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New("Could not parse MAIL_TRANSPORT \"carrier-pigeon\", it is not one of the allowed values: [smtp http sendmail file]")}))
+		})
+	})
+
+	Describe("SMTP pooling", func() {
+		It("has sensible defaults", func() {
+			os.Setenv("SMTP_IDLE_TIMEOUT", "")
+			os.Setenv("SMTP_MAX_MESSAGES_PER_CONNECTION", "")
+			os.Setenv("SMTP_POOL_SIZE", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPIdleTimeout).To(Equal(30000))
+			Expect(env.SMTPMaxMessagesPerConnection).To(Equal(100))
+			Expect(env.SMTPPoolSize).To(Equal(10))
+		})
+
+		It("can be configured", func() {
+			os.Setenv("SMTP_IDLE_TIMEOUT", "5000")
+			os.Setenv("SMTP_MAX_MESSAGES_PER_CONNECTION", "20")
+			os.Setenv("SMTP_POOL_SIZE", "4")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPIdleTimeout).To(Equal(5000))
+			Expect(env.SMTPMaxMessagesPerConnection).To(Equal(20))
+			Expect(env.SMTPPoolSize).To(Equal(4))
+		})
+	})
+
 	Describe("SMTP logging", func() {
 		It("loads the SMTP_LOGGING_ENABLED variable when it is present", func() {
 			os.Setenv("SMTP_LOGGING_ENABLED", "true")
@@ -387,6 +573,69 @@ var _ = Describe("Environment", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(env.TestMode).To(BeTrue())
 		})
+
+		It("keeps 100 messages in the outbox unless configured otherwise", func() {
+			os.Setenv("TEST_MODE_OUTBOX_SIZE", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.TestModeOutboxSize).To(Equal(100))
+
+			os.Setenv("TEST_MODE_OUTBOX_SIZE", "25")
+
+			env, err = application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.TestModeOutboxSize).To(Equal(25))
+		})
+	})
+
+	Describe("Retention config", func() {
+		It("keeps messages for 24 hours and receipts forever by default", func() {
+			os.Setenv("MESSAGE_RETENTION", "")
+			os.Setenv("MESSAGE_RETENTION_BY_STATUS", "")
+			os.Setenv("RECEIPT_RETENTION", "")
+			os.Setenv("RETENTION_INTERVAL", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.MessageLifetime).To(Equal(24 * time.Hour))
+			Expect(env.MessageStatusLifetimes).To(BeEmpty())
+			Expect(env.ReceiptLifetime).To(Equal(time.Duration(0)))
+			Expect(env.RetentionPollingInterval).To(Equal(time.Hour))
+		})
+
+		It("loads durations and numbers of days", func() {
+			os.Setenv("MESSAGE_RETENTION", "36h")
+			os.Setenv("MESSAGE_RETENTION_BY_STATUS", "failed=30d, delivered=7d")
+			os.Setenv("RECEIPT_RETENTION", "90d")
+			os.Setenv("RETENTION_ARCHIVE_PATH", "/var/vcap/store/archive")
+			os.Setenv("RETENTION_INTERVAL", "15m")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.MessageLifetime).To(Equal(36 * time.Hour))
+			Expect(env.MessageStatusLifetimes).To(Equal(map[string]time.Duration{
+				"failed":    30 * 24 * time.Hour,
+				"delivered": 7 * 24 * time.Hour,
+			}))
+			Expect(env.ReceiptLifetime).To(Equal(90 * 24 * time.Hour))
+			Expect(env.RetentionArchivePath).To(Equal("/var/vcap/store/archive"))
+			Expect(env.RetentionPollingInterval).To(Equal(15 * time.Minute))
+		})
+
+		It("errors when a period cannot be parsed", func() {
+			os.Setenv("RECEIPT_RETENTION", "forever")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New(`Could not parse RECEIPT_RETENTION "forever", it must be a duration such as "24h" or a number of days such as "90d"`)}))
+		})
+
+		It("errors when a status is not known", func() {
+			os.Setenv("MESSAGE_RETENTION_BY_STATUS", "bounced=7d")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New(`Could not parse MESSAGE_RETENTION_BY_STATUS "bounced=7d", "bounced" is not one of the allowed statuses: [queued scheduled delivered failed undeliverable canceled]`)}))
+		})
 	})
 
 	Describe("InstanceIndex config", func() {
@@ -491,6 +740,37 @@ var _ = Describe("Environment", func() {
 		})
 	})
 
+	Describe("HTML pipeline", func() {
+		It("splits the allowed tags", func() {
+			os.Setenv("HTML_ALLOWED_TAGS", "p, a,img,")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.HTMLAllowedTags).To(Equal([]string{"p", "a", "img"}))
+		})
+
+		It("leaves the allowed tags empty when unset so the defaults apply", func() {
+			os.Setenv("HTML_ALLOWED_TAGS", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.HTMLAllowedTags).To(BeEmpty())
+		})
+
+		It("errors when the base URL is not absolute", func() {
+			os.Setenv("HTML_BASE_URL", "/apps")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{Err: errors.New("Could not parse HTML_BASE_URL \"/apps\", it must be an absolute http or https URL")}))
+
+			os.Setenv("HTML_BASE_URL", "https://www.example.com/apps/")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.HTMLBaseURL).To(Equal("https://www.example.com/apps/"))
+		})
+	})
+
 	Describe("Domain", func() {
 		It("sets the Domain", func() {
 			os.Setenv("DOMAIN", "example.com")
@@ -74,6 +74,14 @@ func (d *DBProvider) MessagesRepo() v1models.MessagesRepo {
 	return v1models.NewMessagesRepo(util.NewIDGenerator(rand.Reader).Generate)
 }
 
+func (d *DBProvider) ReceiptsRepo() v1models.ReceiptsRepo {
+	return v1models.NewReceiptsRepo()
+}
+
+func (d *DBProvider) BatchesRepo() v1models.BatchesRepo {
+	return v1models.NewBatchesRepo(util.NewIDGenerator(rand.Reader).Generate)
+}
+
 func registerTLSConfig(env Environment) {
 	ca, err := ioutil.ReadFile(env.DatabaseCACertFile)
 	if err != nil {
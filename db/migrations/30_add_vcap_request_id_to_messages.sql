@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `messages` ADD `vcap_request_id` varchar(255) NOT NULL DEFAULT '', ADD KEY `vcap_request_id` (`vcap_request_id`);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `messages` DROP COLUMN `vcap_request_id`;
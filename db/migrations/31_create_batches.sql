@@ -0,0 +1,21 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `batches` (
+      `id` varchar(36) NOT NULL,
+      `client_id` varchar(255) NOT NULL DEFAULT '',
+      `vcap_request_id` varchar(255) NOT NULL DEFAULT '',
+      `audience` varchar(255) NOT NULL DEFAULT '',
+      `status` varchar(255) NOT NULL DEFAULT '',
+      `enqueued` int(11) NOT NULL DEFAULT '0',
+      `error` varchar(1024) NOT NULL DEFAULT '',
+      `created_at` datetime NOT NULL,
+      `updated_at` datetime NOT NULL,
+      PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `messages` ADD `batch_id` varchar(36) NOT NULL DEFAULT '', ADD KEY `batch_id` (`batch_id`);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `messages` DROP COLUMN `batch_id`;
+DROP TABLE batches;
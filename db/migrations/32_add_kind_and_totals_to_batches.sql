@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `batches` ADD `kind_id` varchar(255) NOT NULL DEFAULT '', ADD `guid` varchar(255) NOT NULL DEFAULT '', ADD `total` int(11) NOT NULL DEFAULT '0';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `batches` DROP COLUMN `kind_id`, DROP COLUMN `guid`, DROP COLUMN `total`;
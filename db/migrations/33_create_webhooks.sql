@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `webhooks` (
+      `id` varchar(36) NOT NULL,
+      `client_id` varchar(255) NOT NULL,
+      `url` varchar(1024) NOT NULL,
+      `secret` varchar(255) NOT NULL,
+      `created_at` datetime NOT NULL,
+      PRIMARY KEY (`id`),
+      KEY `client_id` (`client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE webhooks;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `messages`
+      ADD `user_guid` varchar(255) NOT NULL DEFAULT '',
+      ADD `email` varchar(255) NOT NULL DEFAULT '',
+      ADD `client_id` varchar(255) NOT NULL DEFAULT '',
+      ADD `kind_id` varchar(255) NOT NULL DEFAULT '',
+      ADD `attempts` int(11) NOT NULL DEFAULT '0',
+      ADD `created_at` datetime NOT NULL DEFAULT '2000-01-01 00:00:00',
+      ADD `delivered_at` datetime DEFAULT NULL,
+      ADD KEY `user_guid` (`user_guid`),
+      ADD KEY `email` (`email`),
+      ADD KEY `client_id_kind_id` (`client_id`, `kind_id`),
+      ADD KEY `created_at` (`created_at`);
+
+UPDATE `messages` SET `created_at` = `updated_at`;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `messages`
+      DROP COLUMN `user_guid`,
+      DROP COLUMN `email`,
+      DROP COLUMN `client_id`,
+      DROP COLUMN `kind_id`,
+      DROP COLUMN `attempts`,
+      DROP COLUMN `created_at`,
+      DROP COLUMN `delivered_at`;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `messages`
+      ADD KEY `status_updated_at` (`status`, `updated_at`);
+
+ALTER TABLE `receipts`
+      ADD `updated_at` datetime DEFAULT NULL,
+      ADD KEY `updated_at` (`updated_at`);
+
+UPDATE `receipts` SET `updated_at` = `created_at`;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `messages`
+      DROP KEY `status_updated_at`;
+
+ALTER TABLE `receipts`
+      DROP KEY `updated_at`,
+      DROP COLUMN `updated_at`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `clients` ADD `generate_text` tinyint(1) NOT NULL DEFAULT 1;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `clients` DROP COLUMN `generate_text`;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `templates` ADD `localizations` longtext;
+ALTER TABLE `template_versions` ADD `localizations` longtext;
+
+UPDATE `templates` SET `localizations` = '{}';
+UPDATE `template_versions` SET `localizations` = '{}';
+
+CREATE TABLE IF NOT EXISTS `user_locales` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `user_id` varchar(255) NOT NULL,
+      `locale` varchar(255) NOT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE user_locales;
+ALTER TABLE `template_versions` DROP COLUMN `localizations`;
+ALTER TABLE `templates` DROP COLUMN `localizations`;
@@ -0,0 +1,25 @@
+package gobble
+
+import (
+	"fmt"
+	"time"
+)
+
+type DeadJob struct {
+	ID         int       `db:"id"`
+	JobID      int       `db:"job_id"`
+	Key        string    `db:"key"`
+	Payload    string    `db:"payload"`
+	Error      string    `db:"error"`
+	RetryCount int       `db:"retry_count"`
+	ActiveAt   time.Time `db:"active_at"`
+	DiedAt     time.Time `db:"died_at"`
+}
+
+type DeadJobNotFoundError struct {
+	ID int
+}
+
+func (e DeadJobNotFoundError) Error() string {
+	return fmt.Sprintf("Dead job with ID %d could not be found", e.ID)
+}
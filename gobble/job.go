@@ -7,12 +7,15 @@ import (
 
 type Job struct {
 	ID          int       `db:"id"`
+	Key         string    `db:"key"`
 	WorkerID    string    `db:"worker_id"`
 	Payload     string    `db:"payload"`
 	Version     int64     `db:"version"`
 	RetryCount  int       `db:"retry_count"`
 	ActiveAt    time.Time `db:"active_at"`
 	ShouldRetry bool      `db:"-"`
+	ShouldBury  bool      `db:"-"`
+	LastError   string    `db:"-"`
 }
 
 func NewJob(data interface{}) *Job {
@@ -37,6 +40,12 @@ func (job *Job) Retry(duration time.Duration) {
 	job.ShouldRetry = true
 }
 
+func (job *Job) Bury(reason string) {
+	job.ShouldRetry = false
+	job.ShouldBury = true
+	job.LastError = reason
+}
+
 func (job *Job) State() (int, time.Time) {
 	return job.RetryCount, job.ActiveAt
 }
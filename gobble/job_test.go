@@ -57,6 +57,19 @@ var _ = Describe("Job", func() {
 		})
 	})
 
+	Describe("Bury", func() {
+		It("sets up the job to be moved to the dead jobs table", func() {
+			job := gobble.NewJob("the data")
+			job.ShouldRetry = true
+
+			job.Bury("some error")
+
+			Expect(job.ShouldRetry).To(BeFalse())
+			Expect(job.ShouldBury).To(BeTrue())
+			Expect(job.LastError).To(Equal("some error"))
+		})
+	})
+
 	Describe("State", func() {
 		It("returns the current retry count and active at values", func() {
 			expectedActiveAt := time.Now().Add(-5 * time.Minute)
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `dead_jobs` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `job_id` int(11) NOT NULL,
+  `payload` longtext DEFAULT NULL,
+  `error` text DEFAULT NULL,
+  `retry_count` int(11) NOT NULL DEFAULT '0',
+  `active_at` datetime NOT NULL,
+  `died_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `died_at` (`died_at`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE dead_jobs;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `jobs` ADD `key` varchar(255) NOT NULL DEFAULT '', ADD KEY `key` (`key`);
+ALTER TABLE `dead_jobs` ADD `key` varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE `jobs` DROP COLUMN `key`;
+ALTER TABLE `dead_jobs` DROP COLUMN `key`;
@@ -16,6 +16,7 @@ type QueueInterface interface {
 	Reserve(string) <-chan *Job
 	Dequeue(*Job)
 	Requeue(*Job)
+	Bury(*Job)
 	Len() (int, error)
 }
 
@@ -117,6 +118,166 @@ func (queue *Queue) Dequeue(job *Job) {
 	}
 }
 
+func (queue *Queue) DequeueByKey(key string) (bool, error) {
+	expired := queue.clock.Now().Add(-2 * time.Minute)
+	result, err := queue.database.Connection.Exec("DELETE FROM `jobs` WHERE `key` = ? AND ( `worker_id` = \"\" OR `active_at` <= ? )", key, expired)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (queue *Queue) Bury(job *Job) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		panic(err)
+	}
+
+	err = transaction.Insert(&DeadJob{
+		JobID:      job.ID,
+		Key:        job.Key,
+		Payload:    job.Payload,
+		Error:      job.LastError,
+		RetryCount: job.RetryCount,
+		ActiveAt:   job.ActiveAt,
+		DiedAt:     queue.clock.Now(),
+	})
+	if err != nil {
+		transaction.Rollback()
+		panic(err)
+	}
+
+	_, err = transaction.Delete(job)
+	if err != nil {
+		if _, ok := err.(gorp.OptimisticLockError); !ok || !strings.Contains(err.Error(), "no row found") {
+			transaction.Rollback()
+			panic(err)
+		}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (queue *Queue) DeadJobs(offset, limit int) ([]DeadJob, error) {
+	deadJobs := []DeadJob{}
+	_, err := queue.database.Connection.Select(&deadJobs, "SELECT * FROM `dead_jobs` ORDER BY `died_at` DESC, `id` DESC LIMIT ? OFFSET ?", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return deadJobs, nil
+}
+
+func (queue *Queue) DeadJob(id int) (DeadJob, error) {
+	deadJob := DeadJob{}
+	err := queue.database.Connection.SelectOne(&deadJob, "SELECT * FROM `dead_jobs` WHERE `id` = ?", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DeadJob{}, DeadJobNotFoundError{ID: id}
+		}
+		return DeadJob{}, err
+	}
+
+	return deadJob, nil
+}
+
+// RequeueDeadJob moves a dead job back onto the queue. The dead job is locked
+// for the length of the transaction, so concurrent requeues of the same job
+// cannot both enqueue it.
+func (queue *Queue) RequeueDeadJob(id int) (*Job, error) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	deadJob := DeadJob{}
+	err = transaction.SelectOne(&deadJob, "SELECT * FROM `dead_jobs` WHERE `id` = ? FOR UPDATE", id)
+	if err != nil {
+		transaction.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, DeadJobNotFoundError{ID: id}
+		}
+		return nil, err
+	}
+
+	job := &Job{
+		Key:      deadJob.Key,
+		Payload:  deadJob.Payload,
+		ActiveAt: queue.clock.Now(),
+	}
+
+	err = transaction.Insert(job)
+	if err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	result, err := transaction.Exec("DELETE FROM `dead_jobs` WHERE `id` = ?", id)
+	if err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	if count != 1 {
+		transaction.Rollback()
+		return nil, DeadJobNotFoundError{ID: id}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (queue *Queue) DeleteDeadJob(id int) error {
+	result, err := queue.database.Connection.Exec("DELETE FROM `dead_jobs` WHERE `id` = ?", id)
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return DeadJobNotFoundError{ID: id}
+	}
+
+	return nil
+}
+
+func (queue *Queue) PurgeDeadJobs() (int, error) {
+	result, err := queue.database.Connection.Exec("DELETE FROM `dead_jobs`")
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
 func (queue *Queue) findJob() *Job {
 	var job *Job
 	for job == nil {
@@ -262,6 +262,54 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
+	Describe("DequeueByKey", func() {
+		It("deletes the unreserved job with the given key", func() {
+			_, err := queue.Enqueue(&gobble.Job{Key: "some-key"}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = queue.Enqueue(&gobble.Job{Key: "other-key"}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			dequeued, err := queue.DequeueByKey("some-key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dequeued).To(BeTrue())
+
+			jobs := []gobble.Job{}
+			_, err = database.Connection.Select(&jobs, "SELECT * FROM `jobs`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs).To(HaveLen(1))
+			Expect(jobs[0].Key).To(Equal("other-key"))
+		})
+
+		It("does not delete a job that is reserved by a worker", func() {
+			_, err := queue.Enqueue(&gobble.Job{Key: "some-key", WorkerID: "some-worker", ActiveAt: clock.NowCall.Returns.Time}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			dequeued, err := queue.DequeueByKey("some-key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dequeued).To(BeFalse())
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(1))
+		})
+
+		It("deletes a job whose reservation has expired", func() {
+			_, err := queue.Enqueue(&gobble.Job{Key: "some-key", WorkerID: "some-worker", ActiveAt: clock.NowCall.Returns.Time.Add(-5 * time.Minute)}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			dequeued, err := queue.DequeueByKey("some-key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dequeued).To(BeTrue())
+		})
+
+		It("returns false when there is no job with the given key", func() {
+			dequeued, err := queue.DequeueByKey("missing-key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dequeued).To(BeFalse())
+		})
+	})
+
 	Describe("Len", func() {
 		It("returns the length of the queue", func() {
 			job, err := queue.Enqueue(&gobble.Job{}, database.Connection)
@@ -278,4 +326,140 @@ var _ = Describe("Queue", func() {
 			Expect(length).To(Equal(0))
 		})
 	})
+
+	Describe("dead jobs", func() {
+		var job *gobble.Job
+
+		BeforeEach(func() {
+			var err error
+			job, err = queue.Enqueue(&gobble.Job{
+				Payload:    "the-payload",
+				RetryCount: 10,
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			job.LastError = "some error"
+			queue.Bury(job)
+		})
+
+		Describe("Bury", func() {
+			It("moves the job into the dead jobs table", func() {
+				length, err := queue.Len()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(length).To(Equal(0))
+
+				deadJobs, err := queue.DeadJobs(0, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deadJobs).To(HaveLen(1))
+
+				deadJob := deadJobs[0]
+				Expect(deadJob.ID).NotTo(BeZero())
+				Expect(deadJob.JobID).To(Equal(job.ID))
+				Expect(deadJob.Payload).To(Equal("the-payload"))
+				Expect(deadJob.Error).To(Equal("some error"))
+				Expect(deadJob.RetryCount).To(Equal(10))
+				Expect(deadJob.DiedAt).To(BeTemporally("~", clock.NowCall.Returns.Time, time.Second))
+			})
+		})
+
+		Describe("DeadJobs", func() {
+			It("pages through the dead jobs, most recent first", func() {
+				clock.NowCall.Returns.Time = clock.NowCall.Returns.Time.Add(time.Minute)
+				otherJob, err := queue.Enqueue(&gobble.Job{Payload: "other-payload"}, database.Connection)
+				Expect(err).NotTo(HaveOccurred())
+				queue.Bury(otherJob)
+
+				deadJobs, err := queue.DeadJobs(0, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deadJobs).To(HaveLen(1))
+				Expect(deadJobs[0].JobID).To(Equal(otherJob.ID))
+
+				deadJobs, err = queue.DeadJobs(1, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deadJobs).To(HaveLen(1))
+				Expect(deadJobs[0].JobID).To(Equal(job.ID))
+			})
+		})
+
+		Describe("DeadJob", func() {
+			It("finds the dead job by ID", func() {
+				deadJobs, err := queue.DeadJobs(0, 10)
+				Expect(err).NotTo(HaveOccurred())
+
+				deadJob, err := queue.DeadJob(deadJobs[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deadJob.Payload).To(Equal("the-payload"))
+			})
+
+			It("returns a not found error when the dead job does not exist", func() {
+				_, err := queue.DeadJob(-1)
+				Expect(err).To(MatchError(gobble.DeadJobNotFoundError{ID: -1}))
+			})
+		})
+
+		Describe("RequeueDeadJob", func() {
+			It("moves the dead job back onto the queue with a fresh retry count", func() {
+				deadJobs, err := queue.DeadJobs(0, 10)
+				Expect(err).NotTo(HaveOccurred())
+
+				requeuedJob, err := queue.RequeueDeadJob(deadJobs[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeuedJob.Payload).To(Equal("the-payload"))
+				Expect(requeuedJob.RetryCount).To(Equal(0))
+
+				length, err := queue.Len()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(length).To(Equal(1))
+
+				_, err = queue.DeadJob(deadJobs[0].ID)
+				Expect(err).To(BeAssignableToTypeOf(gobble.DeadJobNotFoundError{}))
+			})
+
+			It("does not enqueue a dead job that was already requeued", func() {
+				deadJobs, err := queue.DeadJobs(0, 10)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = queue.RequeueDeadJob(deadJobs[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = queue.RequeueDeadJob(deadJobs[0].ID)
+				Expect(err).To(MatchError(gobble.DeadJobNotFoundError{ID: deadJobs[0].ID}))
+
+				length, err := queue.Len()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(length).To(Equal(1))
+			})
+		})
+
+		Describe("DeleteDeadJob", func() {
+			It("deletes the dead job", func() {
+				deadJobs, err := queue.DeadJobs(0, 10)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = queue.DeleteDeadJob(deadJobs[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				deadJobs, err = queue.DeadJobs(0, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deadJobs).To(BeEmpty())
+			})
+
+			It("returns a not found error when the dead job does not exist", func() {
+				err := queue.DeleteDeadJob(-1)
+				Expect(err).To(MatchError(gobble.DeadJobNotFoundError{ID: -1}))
+			})
+		})
+
+		Describe("PurgeDeadJobs", func() {
+			It("deletes all of the dead jobs", func() {
+				count, err := queue.PurgeDeadJobs()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(1))
+
+				deadJobs, err := queue.DeadJobs(0, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deadJobs).To(BeEmpty())
+			})
+		})
+	})
 })
@@ -35,9 +35,12 @@ func (worker *Worker) Perform() int {
 		defer worker.beater.Halt()
 		worker.callback(job)
 
-		if job.ShouldRetry {
+		switch {
+		case job.ShouldRetry:
 			worker.queue.Requeue(job)
-		} else {
+		case job.ShouldBury:
+			worker.queue.Bury(job)
+		default:
 			worker.queue.Dequeue(job)
 		}
 		return 0
@@ -97,6 +97,31 @@ var _ = Describe("Worker", func() {
 			Expect(retriedJob.ActiveAt).To(BeTemporally("~", time.Now().Add(1*time.Minute), 1*time.Minute))
 		})
 
+		It("moves jobs that are marked for burial to the dead jobs table", func() {
+			callback = func(job *gobble.Job) {
+				job.Bury("it could not be delivered")
+			}
+			worker = gobble.NewWorker(1, queue, callback, heartbeater)
+
+			job, err := queue.Enqueue(&gobble.Job{
+				Payload: "the-payload",
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			worker.Perform()
+
+			results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(0))
+
+			deadJobs, err := queue.DeadJobs(0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(HaveLen(1))
+			Expect(deadJobs[0].JobID).To(Equal(job.ID))
+			Expect(deadJobs[0].Payload).To(Equal("the-payload"))
+			Expect(deadJobs[0].Error).To(Equal("it could not be delivered"))
+		})
+
 		It("heartbeats for job ownership while the job executes", func() {
 			job, err := queue.Enqueue(&gobble.Job{
 				Payload: "the-payload",
@@ -4,7 +4,6 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"errors"
-	"fmt"
 	"net"
 	"net/smtp"
 	"strings"
@@ -17,9 +16,19 @@ const (
 	SMTPAuthNone    = "none"
 	SMTPAuthPlain   = "plain"
 	SMTPAuthCRAMMD5 = "cram-md5"
+	SMTPAuthLogin   = "login"
+	SMTPAuthXOAUTH2 = "xoauth2"
 )
 
-var SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5}
+var SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5, SMTPAuthLogin, SMTPAuthXOAUTH2}
+
+const (
+	TLSModeNone     = "none"
+	TLSModeSTARTTLS = "starttls"
+	TLSModeImplicit = "implicit"
+)
+
+var TLSModes = []string{TLSModeNone, TLSModeSTARTTLS, TLSModeImplicit}
 
 type AuthMechanism int
 
@@ -34,10 +43,13 @@ type Config struct {
 	User              string
 	Pass              string
 	Secret            string
+	OAuthToken        string
 	SMTPAuthMechanism string
 	TestMode          bool
 	SkipVerifySSL     bool
-	DisableTLS        bool
+	TLSMode           string
+	ClientCertificate *tls.Certificate
+	DKIMSigner        *DKIMSigner
 	ConnectTimeout    time.Duration
 	LoggingEnabled    bool
 }
@@ -54,10 +66,18 @@ func NewClient(config Config) *Client {
 		client.config.ConnectTimeout = 15 * time.Second
 	}
 
+	if client.config.TLSMode == "" {
+		client.config.TLSMode = TLSModeSTARTTLS
+	}
+
 	return client
 }
 
 func (c Client) createLoggerSession(logger lager.Logger) lager.Logger {
+	return createLoggerSession(logger)
+}
+
+func createLoggerSession(logger lager.Logger) lager.Logger {
 	if strings.HasSuffix(logger.SessionName(), ".smtp") {
 		return logger
 	}
@@ -99,7 +119,20 @@ func (c *Client) connect() chan connection {
 	channel := make(chan connection)
 
 	go func() {
-		client, err := smtp.Dial(net.JoinHostPort(c.config.Host, c.config.Port))
+		address := net.JoinHostPort(c.config.Host, c.config.Port)
+
+		var client *smtp.Client
+		var err error
+		if c.config.TLSMode == TLSModeImplicit {
+			var conn *tls.Conn
+			conn, err = tls.Dial("tcp", address, c.tlsConfig())
+			if err == nil {
+				client, err = smtp.NewClient(conn, c.config.Host)
+			}
+		} else {
+			client, err = smtp.Dial(address)
+		}
+
 		channel <- connection{
 			client: client,
 			err:    err,
@@ -117,63 +150,87 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 		return nil
 	}
 
-	err := c.Connect(logger)
+	err := c.open(logger)
 	if err != nil {
 		return c.Error(logger, err)
 	}
 
+	err = c.deliver(msg, logger)
+	if err != nil {
+		return c.Error(logger, err)
+	}
+
+	c.PrintLog(logger, "quiting")
+	err = c.Quit()
+	if err != nil {
+		return c.Error(logger, err)
+	}
+	c.PrintLog(logger, "disconnected")
+
+	return nil
+}
+
+func (c *Client) open(logger lager.Logger) error {
+	err := c.Connect(logger)
+	if err != nil {
+		return err
+	}
+
 	c.PrintLog(logger, "hello-initiating")
 	err = c.Hello()
 	if err != nil {
-		return c.Error(logger, err)
+		return err
 	}
 	c.PrintLog(logger, "hello-complete")
 
-	if !c.config.DisableTLS {
-		c.PrintLog(logger, "tls-starting")
-		err = c.StartTLS()
-		if err != nil {
-			return c.Error(logger, err)
+	if c.config.TLSMode != TLSModeNone {
+		if c.config.TLSMode == TLSModeSTARTTLS {
+			c.PrintLog(logger, "tls-starting")
+			err = c.StartTLS()
+			if err != nil {
+				return err
+			}
+			c.PrintLog(logger, "tls-connected")
 		}
-		c.PrintLog(logger, "tls-connected")
 
 		c.PrintLog(logger, "authentication-starting")
 		err = c.Auth(logger)
 		if err != nil {
-			return c.Error(logger, err)
+			return err
 		}
 		c.PrintLog(logger, "authenticated")
 	}
 
+	return nil
+}
+
+func (c *Client) deliver(msg Message, logger lager.Logger) error {
 	c.PrintLog(logger, "setting-msg-from", lager.Data{"from": msg.From})
-	err = c.client.Mail(msg.From)
+	err := c.client.Mail(msg.From)
 	if err != nil {
-		return c.Error(logger, err)
+		return err
 	}
 
 	c.PrintLog(logger, "setting-msg-to", lager.Data{"to": msg.To})
 	err = c.client.Rcpt(msg.To)
 	if err != nil {
-		return c.Error(logger, err)
+		return err
 	}
 
 	c.PrintLog(logger, "setting-msg-data", lager.Data{"message-data": base64.StdEncoding.EncodeToString([]byte(msg.Data()))})
 	err = c.Data(msg)
 	if err != nil {
-		return c.Error(logger, err)
+		return err
 	}
 	c.PrintLog(logger, "msg-data-sent")
 
-	c.PrintLog(logger, "quiting")
-	err = c.Quit()
-	if err != nil {
-		return c.Error(logger, err)
-	}
-	c.PrintLog(logger, "disconnected")
-
 	return nil
 }
 
+func (c *Client) Reset() error {
+	return c.client.Reset()
+}
+
 func (c *Client) Hello() error {
 	err := c.client.Hello("localhost")
 	if err != nil {
@@ -189,10 +246,7 @@ func (c *Client) Extension(name string) (bool, string) {
 
 func (c *Client) StartTLS() error {
 	if ok, _ := c.Extension("STARTTLS"); ok {
-		err := c.client.StartTLS(&tls.Config{
-			ServerName:         c.config.Host,
-			InsecureSkipVerify: c.config.SkipVerifySSL,
-		})
+		err := c.client.StartTLS(c.tlsConfig())
 		if err != nil {
 			return err
 		}
@@ -201,6 +255,19 @@ func (c *Client) StartTLS() error {
 	return nil
 }
 
+func (c *Client) tlsConfig() *tls.Config {
+	config := &tls.Config{
+		ServerName:         c.config.Host,
+		InsecureSkipVerify: c.config.SkipVerifySSL,
+	}
+
+	if c.config.ClientCertificate != nil {
+		config.Certificates = []tls.Certificate{*c.config.ClientCertificate}
+	}
+
+	return config
+}
+
 func (c *Client) Auth(logger lager.Logger) error {
 	if ok, _ := c.Extension("AUTH"); ok {
 		if mechanism := c.AuthMechanism(logger); mechanism != nil {
@@ -222,6 +289,12 @@ func (c *Client) AuthMechanism(logger lager.Logger) smtp.Auth {
 	case SMTPAuthPlain:
 		c.PrintLog(logger, "plain-authentication")
 		return smtp.PlainAuth("", c.config.User, c.config.Pass, c.config.Host)
+	case SMTPAuthLogin:
+		c.PrintLog(logger, "login-authentication")
+		return LoginAuth(c.config.User, c.config.Pass, c.config.Host)
+	case SMTPAuthXOAUTH2:
+		c.PrintLog(logger, "xoauth2-authentication")
+		return XOAUTH2Auth(c.config.User, c.config.OAuthToken)
 	default:
 		c.PrintLog(logger, "no-authentication")
 		return nil
@@ -229,13 +302,17 @@ func (c *Client) AuthMechanism(logger lager.Logger) smtp.Auth {
 }
 
 func (c *Client) Data(msg Message) error {
+	data, err := serialize(msg, c.config.DKIMSigner)
+	if err != nil {
+		return err
+	}
+
 	wc, err := c.client.Data()
 	if err != nil {
 		return err
 	}
 
-	data := strings.Replace(string(msg.Data()), "%", "%%", -1)
-	_, err = fmt.Fprintf(wc, data)
+	_, err = wc.Write(data)
 	if err != nil {
 		return err
 	}
@@ -258,11 +335,18 @@ func (c *Client) Quit() error {
 	return nil
 }
 
+func (c *Client) close() {
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
 func (c *Client) Error(logger lager.Logger, err error) error {
 	if c.client != nil {
 		failure := c.Quit()
 		if failure != nil {
-			return failure
+			logger.Error("quit-failed", failure)
 		}
 	}
 
@@ -2,6 +2,7 @@ package mail_test
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"net"
@@ -64,7 +65,7 @@ var _ = Describe("Mail", func() {
 			Pass:          "pass",
 			TestMode:      false,
 			SkipVerifySSL: true,
-			DisableTLS:    false,
+			TLSMode:       mail.TLSModeSTARTTLS,
 		}
 
 		config.Host, config.Port, err = net.SplitHostPort(mailServer.URL.Host)
@@ -203,7 +204,7 @@ var _ = Describe("Mail", func() {
 
 			Expect(delivery.Sender).To(Equal("me@example.com"))
 			Expect(delivery.Recipient).To(Equal("you@example.com"))
-			Expect(delivery.Data).To(Equal(strings.Split(msg.Data(), "\n")))
+			Expect(delivery.Data).To(Equal(strings.Split(msg.Data(), "\r\n")))
 			Expect(delivery.UsedTLS).To(BeTrue())
 		})
 
@@ -232,7 +233,7 @@ var _ = Describe("Mail", func() {
 
 			Expect(delivery.Sender).To(Equal("me@example.com"))
 			Expect(delivery.Recipient).To(Equal("you@example.com"))
-			Expect(delivery.Data).To(Equal(strings.Split(firstMsg.Data(), "\n")))
+			Expect(delivery.Data).To(Equal(strings.Split(firstMsg.Data(), "\r\n")))
 
 			secondMsg := mail.Message{
 				From:    "first@example.com",
@@ -258,7 +259,41 @@ var _ = Describe("Mail", func() {
 
 			Expect(delivery.Sender).To(Equal("first@example.com"))
 			Expect(delivery.Recipient).To(Equal("second@example.com"))
-			Expect(delivery.Data).To(Equal(strings.Split(secondMsg.Data(), "\n")))
+			Expect(delivery.Data).To(Equal(strings.Split(secondMsg.Data(), "\r\n")))
+		})
+
+		Context("when the server rejects the recipient", func() {
+			var msg mail.Message
+
+			BeforeEach(func() {
+				msg = mail.Message{
+					From:    "me@example.com",
+					To:      "nobody@example.com",
+					Subject: "Undeliverable",
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "Nobody will read this.",
+						},
+					},
+				}
+			})
+
+			It("returns a permanent failure for a 5xx reply", func() {
+				mailServer.RcptToReply = "550 5.1.1 mailbox does not exist"
+
+				err := client.Send(msg, logger)
+				Expect(err).To(MatchError(ContainSubstring("mailbox does not exist")))
+				Expect(mail.IsPermanentFailure(err)).To(BeTrue())
+			})
+
+			It("returns a transient failure for a 4xx reply", func() {
+				mailServer.RcptToReply = "450 4.2.1 mailbox busy"
+
+				err := client.Send(msg, logger)
+				Expect(err).To(MatchError(ContainSubstring("mailbox busy")))
+				Expect(mail.IsPermanentFailure(err)).To(BeFalse())
+			})
 		})
 
 		Context("when configured to use TLS", func() {
@@ -294,10 +329,104 @@ var _ = Describe("Mail", func() {
 			})
 		})
 
+		Context("when configured to use implicit TLS", func() {
+			BeforeEach(func() {
+				mailServer.ImplicitTLS = true
+				config.TLSMode = mail.TLSModeImplicit
+				client = mail.NewClient(config)
+			})
+
+			It("negotiates TLS before the SMTP greeting", func() {
+				msg := mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "This email is the most important thing you will read all day!",
+						},
+					},
+				}
+
+				err := client.Send(msg, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				Expect(mailServer.Deliveries[0].UsedTLS).To(BeTrue())
+			})
+		})
+
+		Context("when configured with a client certificate", func() {
+			BeforeEach(func() {
+				certificate, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+				Expect(err).NotTo(HaveOccurred())
+
+				mailServer.SupportsTLS = true
+				config.ClientCertificate = &certificate
+				client = mail.NewClient(config)
+			})
+
+			It("presents the certificate during the TLS handshake", func() {
+				err := client.Connect(logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = client.Hello()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = client.StartTLS()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mailServer.ClientCertified).To(BeTrue())
+			})
+		})
+
+		Context("when configured to use LOGIN auth", func() {
+			BeforeEach(func() {
+				mailServer.SupportsTLS = true
+				config.SMTPAuthMechanism = mail.SMTPAuthLogin
+				config.Host = "localhost"
+				client = mail.NewClient(config)
+			})
+
+			It("sends the username and password when prompted", func() {
+				err := client.Connect(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.Hello()).To(Succeed())
+				Expect(client.StartTLS()).To(Succeed())
+				Expect(client.Auth(logger)).To(Succeed())
+
+				Expect(mailServer.AuthMechanism).To(Equal("LOGIN"))
+				Expect(mailServer.AuthCredentials).To(Equal([]string{"user", "pass"}))
+			})
+		})
+
+		Context("when configured to use XOAUTH2 auth", func() {
+			BeforeEach(func() {
+				mailServer.SupportsTLS = true
+				config.SMTPAuthMechanism = mail.SMTPAuthXOAUTH2
+				config.OAuthToken = "some-bearer-token"
+				client = mail.NewClient(config)
+			})
+
+			It("sends the bearer token", func() {
+				err := client.Connect(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.Hello()).To(Succeed())
+				Expect(client.StartTLS()).To(Succeed())
+				Expect(client.Auth(logger)).To(Succeed())
+
+				Expect(mailServer.AuthMechanism).To(Equal("XOAUTH2"))
+				Expect(mailServer.AuthCredentials).To(Equal([]string{"user=user\x01auth=Bearer some-bearer-token\x01\x01"}))
+			})
+		})
+
 		Context("when configured to not use TLS", func() {
 			BeforeEach(func() {
 				mailServer.SupportsTLS = false
-				config.DisableTLS = true
+				config.TLSMode = mail.TLSModeNone
 				client = mail.NewClient(config)
 			})
 
@@ -408,7 +537,7 @@ var _ = Describe("Mail", func() {
 
 			ok, params := client.Extension("AUTH")
 			Expect(ok).To(BeTrue())
-			Expect(params).To(Equal("PLAIN LOGIN"))
+			Expect(params).To(Equal("PLAIN LOGIN XOAUTH2"))
 
 			ok, params = client.Extension("STARTTLS")
 			Expect(ok).To(BeTrue())
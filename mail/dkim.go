@@ -0,0 +1,350 @@
+package mail
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/util"
+)
+
+const (
+	DKIMAlgorithmRSASHA256     = "rsa-sha256"
+	DKIMAlgorithmEd25519SHA256 = "ed25519-sha256"
+)
+
+// DKIMSignedHeaders are signed when they are present. RFC 8058 requires the
+// signature to cover both List-Unsubscribe headers for one-click unsubscribe.
+var DKIMSignedHeaders = []string{
+	"From",
+	"Reply-To",
+	"To",
+	"Subject",
+	"Date",
+	"Message-ID",
+	"Mime-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+	"List-Unsubscribe",
+	"List-Unsubscribe-Post",
+}
+
+var (
+	whitespace        = regexp.MustCompile(`[ \t]+`)
+	signatureTagValue = regexp.MustCompile(`(^|;)([ \t\r\n]*b[ \t\r\n]*=)[^;]*`)
+)
+
+type DKIMError struct {
+	Err error
+}
+
+func (e DKIMError) Error() string {
+	return "DKIM: " + e.Err.Error()
+}
+
+type DKIMConfig struct {
+	Domain     string
+	Selector   string
+	PrivateKey crypto.Signer
+	Clock      clock
+}
+
+type clock interface {
+	Now() time.Time
+}
+
+// DKIMSigner signs serialized messages using relaxed/relaxed canonicalization
+// as described in RFC 6376, with RSA-SHA256 or Ed25519-SHA256 (RFC 8463)
+// depending on the type of the private key.
+type DKIMSigner struct {
+	domain    string
+	selector  string
+	key       crypto.Signer
+	algorithm string
+	clock     clock
+}
+
+func NewDKIMSigner(config DKIMConfig) (*DKIMSigner, error) {
+	signer := &DKIMSigner{
+		domain:   config.Domain,
+		selector: config.Selector,
+		key:      config.PrivateKey,
+		clock:    config.Clock,
+	}
+
+	switch config.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		signer.algorithm = DKIMAlgorithmRSASHA256
+	case ed25519.PrivateKey:
+		signer.algorithm = DKIMAlgorithmEd25519SHA256
+	default:
+		return nil, DKIMError{fmt.Errorf("unsupported private key type %T", config.PrivateKey)}
+	}
+
+	if signer.clock == nil {
+		signer.clock = util.NewClock()
+	}
+
+	return signer, nil
+}
+
+// ParseDKIMPrivateKey reads a PEM encoded RSA (PKCS #1 or PKCS #8) or Ed25519
+// (PKCS #8) private key.
+func ParseDKIMPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, DKIMError{errors.New("private key is not PEM encoded")}
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, DKIMError{err}
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, DKIMError{fmt.Errorf("unsupported private key type %T", key)}
+	}
+}
+
+// Sign returns the message with a DKIM-Signature header prepended.
+func (s *DKIMSigner) Sign(message []byte) ([]byte, error) {
+	headers, body := splitMessage(message)
+
+	bodyHash := sha256.Sum256(canonicalizeBody(body))
+
+	var names []string
+	for _, name := range DKIMSignedHeaders {
+		if _, ok := findHeader(headers, name, map[string]int{}); ok {
+			names = append(names, name)
+		}
+	}
+
+	tags := []string{
+		"v=1",
+		"a=" + s.algorithm,
+		"c=relaxed/relaxed",
+		"d=" + s.domain,
+		"s=" + s.selector,
+		"t=" + strconv.FormatInt(s.clock.Now().Unix(), 10),
+		"h=" + strings.Join(names, ":"),
+		"bh=" + base64.StdEncoding.EncodeToString(bodyHash[:]),
+		"b=",
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	writeHeader(buf, "DKIM-Signature", strings.Join(tags, "; "))
+	signatureHeader := strings.TrimSuffix(buf.String(), "\r\n")
+
+	hash := headerHash(headers, names, signatureHeader)
+
+	var signature []byte
+	var err error
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, hash)
+	}
+	if err != nil {
+		return nil, DKIMError{err}
+	}
+
+	signed := bytes.NewBufferString(signatureHeader)
+	signed.WriteString(base64.StdEncoding.EncodeToString(signature))
+	signed.WriteString("\r\n")
+	signed.Write(message)
+
+	return signed.Bytes(), nil
+}
+
+// VerifyDKIM checks the first DKIM-Signature header of a message signed by a
+// DKIMSigner against the given public key. Bare LF line endings, as left behind
+// by servers that strip CR when reading DATA, are treated as CRLF.
+func VerifyDKIM(message []byte, publicKey crypto.PublicKey) error {
+	message = bytes.ReplaceAll(bytes.ReplaceAll(message, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+	headers, body := splitMessage(message)
+
+	var signatureHeader string
+	for _, header := range headers {
+		if strings.HasPrefix(strings.ToLower(header), "dkim-signature:") {
+			signatureHeader = header
+			break
+		}
+	}
+
+	if signatureHeader == "" {
+		return DKIMError{errors.New("message is not signed")}
+	}
+
+	tags := parseTags(signatureHeader)
+	if tags["c"] != "relaxed/relaxed" {
+		return DKIMError{fmt.Errorf("unsupported canonicalization %q", tags["c"])}
+	}
+
+	bodyHash := sha256.Sum256(canonicalizeBody(body))
+	if tags["bh"] != base64.StdEncoding.EncodeToString(bodyHash[:]) {
+		return DKIMError{errors.New("body hash does not match")}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return DKIMError{err}
+	}
+
+	var names []string
+	for _, name := range strings.Split(tags["h"], ":") {
+		names = append(names, strings.TrimSpace(name))
+	}
+
+	hash := headerHash(headers, names, signatureTagValue.ReplaceAllString(signatureHeader, "$1$2"))
+
+	switch tags["a"] {
+	case DKIMAlgorithmRSASHA256:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return DKIMError{errors.New("public key does not match algorithm")}
+		}
+
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash, signature)
+		if err != nil {
+			return DKIMError{err}
+		}
+	case DKIMAlgorithmEd25519SHA256:
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return DKIMError{errors.New("public key does not match algorithm")}
+		}
+
+		if !ed25519.Verify(key, hash, signature) {
+			return DKIMError{errors.New("signature does not match")}
+		}
+	default:
+		return DKIMError{fmt.Errorf("unsupported algorithm %q", tags["a"])}
+	}
+
+	return nil
+}
+
+func headerHash(headers []string, names []string, signatureHeader string) []byte {
+	hash := sha256.New()
+
+	used := map[string]int{}
+	for _, name := range names {
+		if header, ok := findHeader(headers, name, used); ok {
+			hash.Write([]byte(canonicalizeHeader(header) + "\r\n"))
+		}
+	}
+	hash.Write([]byte(canonicalizeHeader(signatureHeader)))
+
+	return hash.Sum(nil)
+}
+
+func splitMessage(message []byte) ([]string, []byte) {
+	var headers []string
+
+	parts := bytes.SplitN(message, []byte("\r\n\r\n"), 2)
+	for _, line := range strings.Split(string(parts[0]), "\r\n") {
+		if len(headers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			headers[len(headers)-1] += "\r\n" + line
+			continue
+		}
+		headers = append(headers, line)
+	}
+
+	if len(parts) == 1 {
+		return headers, nil
+	}
+
+	return headers, parts[1]
+}
+
+// findHeader returns the last occurrence of the named header that has not
+// already been used, walking up from the bottom of the header block.
+func findHeader(headers []string, name string, used map[string]int) (string, bool) {
+	key := strings.ToLower(name)
+
+	skip := used[key]
+	for i := len(headers) - 1; i >= 0; i-- {
+		parts := strings.SplitN(headers[i], ":", 2)
+		if strings.ToLower(strings.TrimSpace(parts[0])) != key {
+			continue
+		}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		used[key]++
+		return headers[i], true
+	}
+
+	return "", false
+}
+
+func canonicalizeHeader(header string) string {
+	parts := strings.SplitN(header, ":", 2)
+
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	value := ""
+	if len(parts) == 2 {
+		value = strings.NewReplacer("\r\n", "").Replace(parts[1])
+		value = strings.TrimSpace(whitespace.ReplaceAllString(value, " "))
+	}
+
+	return name + ":" + value
+}
+
+func canonicalizeBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(whitespace.ReplaceAllString(line, " "), " ")
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return []byte{}
+	}
+
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func parseTags(header string) map[string]string {
+	tags := map[string]string{}
+
+	value := strings.SplitN(header, ":", 2)[1]
+	for _, tag := range strings.Split(value, ";") {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		tags[strings.TrimSpace(parts[0])] = strings.Join(strings.Fields(parts[1]), "")
+	}
+
+	return tags
+}
@@ -0,0 +1,235 @@
+package mail_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/testing/servers"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DKIM", func() {
+	var (
+		smtpServer *servers.SMTP
+		logger     lager.Logger
+		msg        mail.Message
+		clock      *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, 0))
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Date(2015, time.January, 20, 20, 23, 30, 0, time.UTC)
+
+		smtpServer = servers.NewSMTP()
+		smtpServer.Boot()
+
+		msg = mail.Message{
+			From:      "no-reply@example.com",
+			To:        "you@example.com",
+			Subject:   "Urgent! Read now! This subject is long enough that it needs to be folded",
+			MessageID: mail.NewMessageID("some-message-id", "example.com"),
+			Body: []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "This email is the most important thing you will read all day!  \n\n\n",
+				},
+				{
+					ContentType: "text/html",
+					Content:     "<p>This email is the most important thing you will read all day!</p>",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		smtpServer.Close()
+	})
+
+	send := func(key crypto.Signer) []byte {
+		signer, err := mail.NewDKIMSigner(mail.DKIMConfig{
+			Domain:     "example.com",
+			Selector:   "notifications",
+			PrivateKey: key,
+			Clock:      clock,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		client := mail.NewClient(mail.Config{
+			Host:       os.Getenv("SMTP_HOST"),
+			Port:       os.Getenv("SMTP_PORT"),
+			TLSMode:    mail.TLSModeNone,
+			DKIMSigner: signer,
+		})
+
+		Expect(client.Send(msg, logger)).To(Succeed())
+		Eventually(func() int {
+			return len(smtpServer.Deliveries)
+		}).Should(Equal(1))
+
+		return smtpServer.Deliveries[0].Data
+	}
+
+	It("signs messages with an RSA key", func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+
+		data := send(key)
+
+		Expect(string(data)).To(HavePrefix("DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=example.com;"))
+		Expect(string(data)).To(ContainSubstring("s=notifications; t=1421785410;"))
+		Expect(string(data)).To(ContainSubstring("h=From:To:Subject:Date:Message-ID:Mime-Version:Content-Type;"))
+		Expect(mail.VerifyDKIM(data, key.Public())).To(Succeed())
+	})
+
+	It("signs messages with an Ed25519 key", func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		data := send(key)
+
+		Expect(string(data)).To(HavePrefix("DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;"))
+		Expect(mail.VerifyDKIM(data, key.Public())).To(Succeed())
+	})
+
+	It("produces a signature that fails to verify once the message is modified", func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		data := send(key)
+
+		tampered := strings.Replace(string(data), "Subject: Urgent!", "Subject: Ignore!", 1)
+		Expect(mail.VerifyDKIM([]byte(tampered), key.Public())).To(MatchError(ContainSubstring("signature does not match")))
+
+		tampered = strings.Replace(string(data), "most important", "least important", 1)
+		Expect(mail.VerifyDKIM([]byte(tampered), key.Public())).To(MatchError(ContainSubstring("body hash does not match")))
+	})
+
+	It("signs the List-Unsubscribe headers when they are present", func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		msg.Headers = []string{
+			"List-Unsubscribe: <https://notifications.example.com/unsubscribe/some-token>",
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		}
+
+		data := send(key)
+
+		Expect(string(data)).To(ContainSubstring("h=From:To:Subject:Date:Message-ID:Mime-Version:Content-Type:List-Unsubscribe:List-Unsubscribe-Post;"))
+		Expect(mail.VerifyDKIM(data, key.Public())).To(Succeed())
+
+		tampered := strings.Replace(string(data), "some-token", "another-token", 1)
+		Expect(mail.VerifyDKIM([]byte(tampered), key.Public())).To(MatchError(ContainSubstring("signature does not match")))
+	})
+
+	Context("with the Ed25519 example from RFC 8463", func() {
+		var (
+			key     ed25519.PrivateKey
+			message string
+		)
+
+		BeforeEach(func() {
+			seed, err := base64.StdEncoding.DecodeString("nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A=")
+			Expect(err).NotTo(HaveOccurred())
+			key = ed25519.NewKeyFromSeed(seed)
+
+			message = strings.Join([]string{
+				"DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;",
+				" d=football.example.com; i=@football.example.com;",
+				" q=dns/txt; s=brisbane; t=1528637909; h=from : to :",
+				" subject : date : message-id : from : subject : date;",
+				" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;",
+				" b=/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11Bus",
+				" Fa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw==",
+				"From: Joe SixPack <joe@football.example.com>",
+				"To: Suzie Q <suzie@shopping.example.net>",
+				"Subject: Is dinner ready?",
+				"Date: Fri, 11 Jul 2003 21:00:37 -0700 (PDT)",
+				"Message-ID: <20030712040037.46341.5F8J@football.example.com>",
+				"",
+				"Hi.",
+				"",
+				"We lost the game.  Are you hungry yet?",
+				"",
+				"Joe.",
+				"",
+			}, "\r\n")
+		})
+
+		It("verifies the signature published in the RFC", func() {
+			Expect(base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))).To(Equal("11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="))
+			Expect(mail.VerifyDKIM([]byte(message), key.Public())).To(Succeed())
+
+			tampered := strings.Replace(message, "Is dinner ready?", "Is dinner  ready?", 1)
+			Expect(mail.VerifyDKIM([]byte(tampered), key.Public())).To(Succeed())
+
+			tampered = strings.Replace(message, "Is dinner ready?", "Is lunch ready?", 1)
+			Expect(mail.VerifyDKIM([]byte(tampered), key.Public())).To(MatchError(ContainSubstring("signature does not match")))
+		})
+
+		It("computes the body hash published in the RFC", func() {
+			signer, err := mail.NewDKIMSigner(mail.DKIMConfig{
+				Domain:     "football.example.com",
+				Selector:   "brisbane",
+				PrivateKey: key,
+				Clock:      clock,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			unsigned := message[strings.Index(message, "From: "):]
+			signed, err := signer.Sign([]byte(unsigned))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(signed)).To(ContainSubstring("bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;"))
+			Expect(mail.VerifyDKIM(signed, key.Public())).To(Succeed())
+		})
+	})
+
+	Describe("ParseDKIMPrivateKey", func() {
+		It("parses PKCS #1 RSA keys", func() {
+			key, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+
+			data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+			parsed, err := mail.ParseDKIMPrivateKey(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(key))
+		})
+
+		It("parses PKCS #8 Ed25519 keys", func() {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed, err := mail.ParseDKIMPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(key))
+		})
+
+		It("returns an error when the key is not PEM encoded", func() {
+			_, err := mail.ParseDKIMPrivateKey([]byte("banana"))
+			Expect(err).To(MatchError(mail.DKIMError{Err: errors.New("private key is not PEM encoded")}))
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"errors"
+	"net/textproto"
+)
+
+// DeliveryError is returned by transports that do not speak SMTP to report
+// whether retrying the message could succeed.
+type DeliveryError struct {
+	Err       error
+	Permanent bool
+}
+
+func (e DeliveryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+func IsPermanentFailure(err error) bool {
+	var reply *textproto.Error
+	if errors.As(err, &reply) {
+		return reply.Code >= 500 && reply.Code < 600
+	}
+
+	var deliveryError DeliveryError
+	if errors.As(err, &deliveryError) {
+		return deliveryError.Permanent
+	}
+
+	return false
+}
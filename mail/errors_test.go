@@ -0,0 +1,38 @@
+package mail_test
+
+import (
+	"errors"
+	"fmt"
+	"net/textproto"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IsPermanentFailure", func() {
+	It("is true for 5xx SMTP replies", func() {
+		Expect(mail.IsPermanentFailure(&textproto.Error{Code: 550, Msg: "mailbox does not exist"})).To(BeTrue())
+		Expect(mail.IsPermanentFailure(&textproto.Error{Code: 554, Msg: "transaction failed"})).To(BeTrue())
+	})
+
+	It("is true for wrapped 5xx SMTP replies", func() {
+		err := fmt.Errorf("delivery failed: %w", &textproto.Error{Code: 552, Msg: "message too large"})
+		Expect(mail.IsPermanentFailure(err)).To(BeTrue())
+	})
+
+	It("is false for 4xx SMTP replies", func() {
+		Expect(mail.IsPermanentFailure(&textproto.Error{Code: 421, Msg: "service not available"})).To(BeFalse())
+		Expect(mail.IsPermanentFailure(&textproto.Error{Code: 451, Msg: "try again later"})).To(BeFalse())
+	})
+
+	It("reports whether transport delivery errors are permanent", func() {
+		Expect(mail.IsPermanentFailure(mail.DeliveryError{Err: errors.New("invalid recipient"), Permanent: true})).To(BeTrue())
+		Expect(mail.IsPermanentFailure(mail.DeliveryError{Err: errors.New("rate limited"), Permanent: false})).To(BeFalse())
+	})
+
+	It("is false for connection errors", func() {
+		Expect(mail.IsPermanentFailure(errors.New("server timeout"))).To(BeFalse())
+	})
+})
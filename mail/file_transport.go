@@ -0,0 +1,78 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-golang/lager"
+)
+
+type FileTransportConfig struct {
+	Directory  string
+	DKIMSigner *DKIMSigner
+	Clock      clock
+}
+
+// FileTransport writes each message as an .eml file into a maildir. Files are
+// written to tmp and then moved to new so readers never see partial messages.
+type FileTransport struct {
+	directory string
+	signer    *DKIMSigner
+	clock     clock
+}
+
+func NewFileTransport(config FileTransportConfig) FileTransport {
+	return FileTransport{
+		directory: config.Directory,
+		signer:    config.DKIMSigner,
+		clock:     config.Clock,
+	}
+}
+
+func (t FileTransport) Connect(logger lager.Logger) error {
+	for _, dir := range []string{"tmp", "new", "cur"} {
+		err := os.MkdirAll(filepath.Join(t.directory, dir), 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t FileTransport) Send(msg Message, logger lager.Logger) error {
+	logger = logger.Session("file-transport")
+
+	data, err := serialize(msg, t.signer)
+	if err != nil {
+		return err
+	}
+
+	random := make([]byte, 8)
+	_, err = rand.Read(random)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.%s.eml", t.clock.Now().UnixNano(), hex.EncodeToString(random))
+	tmpPath := filepath.Join(t.directory, "tmp", name)
+	newPath := filepath.Join(t.directory, "new", name)
+
+	err = ioutil.WriteFile(tmpPath, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, newPath)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("delivered", lager.Data{"path": newPath})
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package mail_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileTransport", func() {
+	var (
+		directory string
+		transport mail.FileTransport
+		logger    lager.Logger
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "maildir")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, 0))
+
+		clock := mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Date(2015, time.January, 20, 20, 23, 30, 0, time.UTC)
+
+		transport = mail.NewFileTransport(mail.FileTransportConfig{
+			Directory: filepath.Join(directory, "outbox"),
+			Clock:     clock,
+		})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	It("writes each message as an .eml file into the maildir", func() {
+		Expect(transport.Connect(logger)).To(Succeed())
+
+		msg := mail.Message{
+			From:    "me@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent! Read now!",
+			Body: []mail.Part{
+				{ContentType: "text/plain", Content: "This email is important"},
+			},
+		}
+		Expect(transport.Send(msg, logger)).To(Succeed())
+		Expect(transport.Send(msg, logger)).To(Succeed())
+
+		files, err := filepath.Glob(filepath.Join(directory, "outbox", "new", "1421785410000000000.*.eml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+
+		contents, err := ioutil.ReadFile(files[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(ContainSubstring("To: you@example.com\r\n"))
+		Expect(string(contents)).To(HaveSuffix("This email is important"))
+
+		tmp, err := ioutil.ReadDir(filepath.Join(directory, "outbox", "tmp"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tmp).To(BeEmpty())
+	})
+})
@@ -0,0 +1,154 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pivotal-golang/lager"
+)
+
+type HTTPTransportConfig struct {
+	URL        string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// HTTPTransport posts each message as JSON to a mail API. Providers that
+// expect a different shape can be fronted by a small adapter.
+type HTTPTransport struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type httpMessage struct {
+	From        string            `json:"from"`
+	ReplyTo     string            `json:"reply_to,omitempty"`
+	To          []string          `json:"to"`
+	Subject     string            `json:"subject"`
+	MessageID   string            `json:"message_id,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	HTML        string            `json:"html,omitempty"`
+	Attachments []httpAttachment  `json:"attachments,omitempty"`
+}
+
+type httpAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+	Inline      bool   `json:"inline,omitempty"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+func NewHTTPTransport(config HTTPTransportConfig) HTTPTransport {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	return HTTPTransport{
+		url:        config.URL,
+		apiKey:     config.APIKey,
+		httpClient: config.HTTPClient,
+	}
+}
+
+func (t HTTPTransport) Connect(logger lager.Logger) error {
+	return nil
+}
+
+func (t HTTPTransport) Send(msg Message, logger lager.Logger) error {
+	logger = logger.Session("http-transport")
+
+	body, err := json.Marshal(newHTTPMessage(msg))
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", t.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if t.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	response, err := t.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		io.Copy(ioutil.Discard, response.Body)
+		logger.Info("delivered", lager.Data{"status": response.StatusCode})
+		return nil
+	}
+
+	responseBody, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+
+	return DeliveryError{
+		Err:       fmt.Errorf("mail API responded with %d: %s", response.StatusCode, strings.TrimSpace(string(responseBody))),
+		Permanent: isPermanentStatus(response.StatusCode),
+	}
+}
+
+func newHTTPMessage(msg Message) httpMessage {
+	message := httpMessage{
+		From:      msg.From,
+		ReplyTo:   msg.ReplyTo,
+		To:        []string{msg.To},
+		Subject:   msg.Subject,
+		MessageID: msg.MessageID,
+	}
+
+	for _, header := range msg.Headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if message.Headers == nil {
+			message.Headers = map[string]string{}
+		}
+		message.Headers[parts[0]] = strings.TrimSpace(parts[1])
+	}
+
+	for _, part := range msg.Body {
+		switch part.ContentType {
+		case "text/plain":
+			message.Text = part.Content
+		case "text/html":
+			message.HTML = part.Content
+		}
+	}
+
+	for _, attachment := range msg.Attachments {
+		message.Attachments = append(message.Attachments, httpAttachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+			Inline:      attachment.Inline,
+			ContentID:   attachment.ContentID,
+		})
+	}
+
+	return message
+}
+
+// Rate limiting and timeouts are worth retrying, any other client error means
+// the provider will never accept the message.
+func isPermanentStatus(status int) bool {
+	if status == http.StatusTooManyRequests || status == http.StatusRequestTimeout {
+		return false
+	}
+
+	return status >= 400 && status < 500
+}
@@ -0,0 +1,131 @@
+package mail_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTPTransport", func() {
+	var (
+		server        *httptest.Server
+		transport     mail.HTTPTransport
+		logger        lager.Logger
+		msg           mail.Message
+		request       *http.Request
+		requestBody   []byte
+		responseCode  int
+		responseError string
+	)
+
+	BeforeEach(func() {
+		responseCode = http.StatusAccepted
+		responseError = ""
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var err error
+			request = req
+			requestBody, err = ioutil.ReadAll(req.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			w.WriteHeader(responseCode)
+			w.Write([]byte(responseError))
+		}))
+
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, 0))
+
+		transport = mail.NewHTTPTransport(mail.HTTPTransportConfig{
+			URL:    server.URL + "/v1/send",
+			APIKey: "some-api-key",
+		})
+
+		msg = mail.Message{
+			From:      "me@example.com",
+			ReplyTo:   "reply@example.com",
+			To:        "you@example.com",
+			Subject:   "Urgent! Read now!",
+			MessageID: "<some-message-id@example.com>",
+			Headers:   []string{"X-CF-Notification-ID: some-message-id"},
+			Body: []mail.Part{
+				{ContentType: "text/plain", Content: "This email is important"},
+				{ContentType: "text/html", Content: "<p>This email is important</p>"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts the message as JSON", func() {
+		Expect(transport.Send(msg, logger)).To(Succeed())
+
+		Expect(request.Method).To(Equal("POST"))
+		Expect(request.URL.Path).To(Equal("/v1/send"))
+		Expect(request.Header.Get("Authorization")).To(Equal("Bearer some-api-key"))
+		Expect(request.Header.Get("Content-Type")).To(Equal("application/json"))
+
+		var body map[string]interface{}
+		Expect(json.Unmarshal(requestBody, &body)).To(Succeed())
+		Expect(body).To(Equal(map[string]interface{}{
+			"from":       "me@example.com",
+			"reply_to":   "reply@example.com",
+			"to":         []interface{}{"you@example.com"},
+			"subject":    "Urgent! Read now!",
+			"message_id": "<some-message-id@example.com>",
+			"headers":    map[string]interface{}{"X-CF-Notification-ID": "some-message-id"},
+			"text":       "This email is important",
+			"html":       "<p>This email is important</p>",
+		}))
+	})
+
+	It("includes attachments as base64 encoded content", func() {
+		msg.Attachments = []mail.Attachment{
+			{Filename: "logo.png", ContentType: "image/png", Content: []byte("png bytes"), Inline: true, ContentID: "logo"},
+		}
+
+		Expect(transport.Send(msg, logger)).To(Succeed())
+
+		var body map[string]interface{}
+		Expect(json.Unmarshal(requestBody, &body)).To(Succeed())
+		Expect(body["attachments"]).To(Equal([]interface{}{
+			map[string]interface{}{
+				"filename":     "logo.png",
+				"content_type": "image/png",
+				"content":      "cG5nIGJ5dGVz",
+				"inline":       true,
+				"content_id":   "logo",
+			},
+		}))
+	})
+
+	It("returns a permanent failure when the API rejects the message", func() {
+		responseCode = http.StatusUnprocessableEntity
+		responseError = `{"error": "invalid recipient"}`
+
+		err := transport.Send(msg, logger)
+		Expect(err).To(MatchError(`mail API responded with 422: {"error": "invalid recipient"}`))
+		Expect(mail.IsPermanentFailure(err)).To(BeTrue())
+	})
+
+	It("returns a transient failure when the API is rate limiting or unavailable", func() {
+		responseCode = http.StatusTooManyRequests
+		err := transport.Send(msg, logger)
+		Expect(err).To(HaveOccurred())
+		Expect(mail.IsPermanentFailure(err)).To(BeFalse())
+
+		responseCode = http.StatusBadGateway
+		err = transport.Send(msg, logger)
+		Expect(err).To(HaveOccurred())
+		Expect(mail.IsPermanentFailure(err)).To(BeFalse())
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
 	"log"
 	"net"
 	"net/url"
@@ -86,6 +87,14 @@ type SMTPServer struct {
 	halt            chan bool
 	ConnectionState string
 	FailsHello      bool
+	RcptToReply     string
+	MailFromReply   string
+	DropsMailFrom   bool
+	Connections     int
+	ImplicitTLS     bool
+	AuthMechanism   string
+	AuthCredentials []string
+	ClientCertified bool
 }
 
 type Delivery struct {
@@ -154,6 +163,12 @@ func (server *SMTPServer) Close() {
 func (server *SMTPServer) Respond(conn net.Conn) {
 	<-time.After(server.ConnectWait)
 	server.ConnectionState = StateConnected
+	server.Connections++
+
+	if server.ImplicitTLS {
+		conn = server.tlsServer(conn)
+		server.CurrentDelivery.UsedTLS = true
+	}
 
 	input := bufio.NewReader(conn)
 	output := bufio.NewWriter(conn)
@@ -161,27 +176,59 @@ func (server *SMTPServer) Respond(conn net.Conn) {
 
 Loop:
 	for {
-		msg, _ := input.ReadString('\n')
+		msg, err := input.ReadString('\n')
+		if err != nil {
+			break Loop
+		}
+
 		switch {
 		case strings.Contains(msg, "EHLO"):
 			server.RespondToEHLO(output)
 		case strings.Contains(msg, "STARTTLS"):
 			conn, input, output = server.RespondToStartTLS(conn, input, output)
 		case strings.Contains(msg, "AUTH PLAIN"):
+			server.AuthMechanism = "PLAIN"
+			server.RespondToAuthPlain(output)
+		case strings.Contains(msg, "AUTH LOGIN"):
+			server.RespondToAuthLogin(output, input)
+		case strings.Contains(msg, "AUTH XOAUTH2"):
+			server.AuthMechanism = "XOAUTH2"
+			server.AuthCredentials = []string{decodeBase64(strings.TrimPrefix(strings.TrimSpace(msg), "AUTH XOAUTH2 "))}
 			server.RespondToAuthPlain(output)
 		case strings.Contains(msg, "MAIL FROM"):
+			if server.DropsMailFrom {
+				server.DropsMailFrom = false
+				conn.Close()
+				break Loop
+			}
+			if server.MailFromReply != "" {
+				reply := server.MailFromReply
+				server.MailFromReply = ""
+				output.WriteString(reply + "\r\n")
+				output.Flush()
+				if strings.HasPrefix(reply, "421") {
+					conn.Close()
+					break Loop
+				}
+				continue
+			}
 			server.RespondToMailFrom(output, msg)
 		case strings.Contains(msg, "RCPT TO"):
 			server.RespondToRcptTo(output, msg)
 		case strings.Contains(msg, "DATA"):
 			server.RespondToData(output)
 			server.RecordData(output, input)
+			server.Deliveries = append(server.Deliveries, server.CurrentDelivery)
+			server.CurrentDelivery = Delivery{UsedTLS: server.CurrentDelivery.UsedTLS}
+		case strings.Contains(msg, "RSET"):
+			server.CurrentDelivery = Delivery{UsedTLS: server.CurrentDelivery.UsedTLS}
+			output.WriteString("250 OK\r\n")
+			output.Flush()
 		case strings.Contains(msg, "QUIT"):
 			server.RespondToQuit(output)
 			break Loop
 		}
 	}
-	server.Deliveries = append(server.Deliveries, server.CurrentDelivery)
 	server.CurrentDelivery = Delivery{}
 }
 
@@ -199,9 +246,11 @@ func (server *SMTPServer) RespondToEHLO(output *bufio.Writer) {
 	}
 
 	output.WriteString("250-localhost Hello\n")
-	if server.SupportsTLS {
+	if server.ImplicitTLS {
+		output.WriteString("250 AUTH PLAIN LOGIN XOAUTH2\r\n")
+	} else if server.SupportsTLS {
 		output.WriteString("250-STARTTLS\n")
-		output.WriteString("250 AUTH PLAIN LOGIN\r\n")
+		output.WriteString("250 AUTH PLAIN LOGIN XOAUTH2\r\n")
 	} else {
 		output.WriteString("250 AUTH LOGIN\r\n")
 	}
@@ -214,15 +263,57 @@ func (server *SMTPServer) RespondToStartTLS(conn net.Conn, input *bufio.Reader,
 
 	server.CurrentDelivery.UsedTLS = true
 
+	tlsConn := server.tlsServer(conn)
+
+	return tlsConn, bufio.NewReader(tlsConn), bufio.NewWriter(tlsConn)
+}
+
+func (server *SMTPServer) tlsServer(conn net.Conn) *tls.Conn {
 	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
 	if err != nil {
 		log.Fatalf("server: loadkeys: %s", err)
 	}
-	config := tls.Config{Certificates: []tls.Certificate{cert}}
+	config := tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequestClientCert,
+	}
 	config.Rand = rand.Reader
 	tlsConn := tls.Server(conn, &config)
 
-	return tlsConn, bufio.NewReader(tlsConn), bufio.NewWriter(tlsConn)
+	err = tlsConn.Handshake()
+	if err == nil && len(tlsConn.ConnectionState().PeerCertificates) > 0 {
+		server.ClientCertified = true
+	}
+
+	return tlsConn
+}
+
+func (server *SMTPServer) RespondToAuthLogin(output *bufio.Writer, input *bufio.Reader) {
+	server.AuthMechanism = "LOGIN"
+	server.AuthCredentials = nil
+
+	for _, prompt := range []string{"VXNlcm5hbWU6", "UGFzc3dvcmQ6"} {
+		output.WriteString("334 " + prompt + "\r\n")
+		output.Flush()
+
+		line, err := input.ReadString('\n')
+		if err != nil {
+			return
+		}
+		server.AuthCredentials = append(server.AuthCredentials, decodeBase64(strings.TrimSpace(line)))
+	}
+
+	output.WriteString("235 OK, Go ahead\r\n")
+	output.Flush()
+}
+
+func decodeBase64(value string) string {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(decoded)
 }
 
 func (server *SMTPServer) RespondToAuthPlain(output *bufio.Writer) {
@@ -246,6 +337,12 @@ func (server *SMTPServer) RespondToRcptTo(output *bufio.Writer, msg string) {
 	recipient = strings.Trim(recipient, "<>")
 	server.CurrentDelivery.Recipient = recipient
 
+	if server.RcptToReply != "" {
+		output.WriteString(server.RcptToReply + "\r\n")
+		output.Flush()
+		return
+	}
+
 	output.WriteString("250 OK\r\n")
 	output.Flush()
 }
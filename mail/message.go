@@ -2,38 +2,33 @@ package mail
 
 import (
 	"bytes"
-	"io/ioutil"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
 	"strings"
-	"text/template"
-
-	"gopkg.in/gomail.v1"
+	"time"
 )
 
-const emailTemplate = `{{range .Headers}}{{.}}
-{{end}}Date: {{.Date}}
-Mime-Version: {{.MimeVersion}}
-Content-Type: {{.ContentType}}
-{{if .ContentTransferEncoding}}Content-Transfer-Encoding: {{.ContentTransferEncoding}}
-{{end}}From: {{.From}}{{if .ReplyTo}}
-Reply-To: {{.ReplyTo}}{{end}}
-To: {{.To}}
-Subject: {{.Subject}}
-
-{{.CompiledBody}}`
+const maxLineLength = 78
 
 type Message struct {
-	Date                    string
-	MimeVersion             string
-	ContentType             string
-	ContentTransferEncoding string
-	From                    string
-	ReplyTo                 string
-	To                      string
-	Subject                 string
-	Body                    []Part
-	Headers                 []string
-	CompiledBody            string
+	From        string
+	ReplyTo     string
+	To          string
+	Subject     string
+	MessageID   string
+	Body        []Part
+	Headers     []string
+	Attachments []Attachment
+
+	boundary string
 }
 
 type Part struct {
@@ -41,53 +36,278 @@ type Part struct {
 	Content     string
 }
 
+// Attachment is a file sent alongside the message body. Inline attachments are
+// referenced from the HTML part by their ContentID, e.g. <img src="cid:logo">.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	Inline      bool
+	ContentID   string
+}
+
+// NewMessageID builds a Message-ID header value that is unique as long as id
+// is unique within domain.
+func NewMessageID(id, domain string) string {
+	return fmt.Sprintf("<%s@%s>", id, domain)
+}
+
 func (msg *Message) Data() string {
 	buf := bytes.NewBuffer([]byte{})
 
-	err := msg.CompileBody()
+	_, err := msg.WriteTo(buf)
 	if err != nil {
 		panic(err)
 	}
 
-	tmpl, err := template.New("test").Parse(emailTemplate)
-	if err != nil {
-		panic(err)
+	return buf.String()
+}
+
+func (msg *Message) WriteTo(w io.Writer) (int64, error) {
+	buf := bytes.NewBuffer([]byte{})
+
+	for _, header := range msg.Headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		writeHeader(buf, parts[0], strings.TrimSpace(parts[1]))
+	}
+
+	writeHeader(buf, "Date", time.Now().Format(time.RFC1123Z))
+	if msg.MessageID != "" {
+		writeHeader(buf, "Message-ID", msg.MessageID)
+	}
+	writeHeader(buf, "Mime-Version", "1.0")
+	writeHeader(buf, "From", encodeAddress(msg.From))
+	if msg.ReplyTo != "" {
+		writeHeader(buf, "Reply-To", encodeAddress(msg.ReplyTo))
 	}
+	writeHeader(buf, "To", encodeAddress(msg.To))
+	writeHeader(buf, "Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
 
-	err = tmpl.Execute(buf, msg)
+	err := msg.writeBody(buf)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 
-	return buf.String()
+	return buf.WriteTo(w)
+}
+
+func (msg *Message) Boundary() string {
+	if msg.boundary == "" {
+		random := make([]byte, 16)
+		_, err := rand.Read(random)
+		if err != nil {
+			panic(err)
+		}
+		msg.boundary = hex.EncodeToString(random)
+	}
+
+	return msg.boundary
 }
 
-func (msg *Message) CompileBody() error {
-	message := gomail.NewMessage()
+func (msg *Message) writeBody(buf *bytes.Buffer) error {
+	body := msg.alternativeEntity()
+
+	var inline, attached []entity
+	for _, attachment := range msg.Attachments {
+		if attachment.Inline {
+			inline = append(inline, attachmentEntity(attachment))
+		} else {
+			attached = append(attached, attachmentEntity(attachment))
+		}
+	}
+
+	if len(inline) > 0 {
+		body = multipartEntity("related", "related-"+msg.Boundary(), body.mediaType, append([]entity{body}, inline...))
+	}
+
+	if len(attached) > 0 {
+		body = multipartEntity("mixed", "mixed-"+msg.Boundary(), "", append([]entity{body}, attached...))
+	}
+
+	for _, header := range body.headers {
+		writeHeader(buf, header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+
+	return body.write(buf)
+}
+
+// entity is a MIME entity that can either be written at the top level of a
+// message or nested inside a multipart body.
+type entity struct {
+	mediaType string
+	headers   [][2]string
+	write     func(io.Writer) error
+}
+
+func (e entity) mimeHeader() textproto.MIMEHeader {
+	header := textproto.MIMEHeader{}
+	for _, h := range e.headers {
+		header.Add(h[0], foldHeader(h[0], h[1]))
+	}
+
+	return header
+}
+
+func (msg *Message) alternativeEntity() entity {
+	if len(msg.Body) == 1 {
+		return textEntity(msg.Body[0])
+	}
+
+	var parts []entity
 	for _, part := range msg.Body {
-		message.AddAlternative(part.ContentType, part.Content)
+		parts = append(parts, textEntity(part))
 	}
 
-	m := message.Export()
-	body, err := ioutil.ReadAll(m.Body)
-	if err != nil {
-		panic(err)
+	return multipartEntity("alternative", msg.Boundary(), "", parts)
+}
+
+func textEntity(part Part) entity {
+	return entity{
+		mediaType: part.ContentType,
+		headers: [][2]string{
+			{"Content-Type", part.ContentType + "; charset=UTF-8"},
+			{"Content-Transfer-Encoding", "quoted-printable"},
+		},
+		write: func(w io.Writer) error {
+			return writeQuotedPrintable(w, part.Content)
+		},
+	}
+}
+
+func attachmentEntity(attachment Attachment) entity {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	if attachment.Inline {
+		disposition = "inline"
+	}
+
+	headers := [][2]string{
+		{"Content-Type", contentType},
+		{"Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename})},
+		{"Content-Transfer-Encoding", "base64"},
+	}
+	if attachment.ContentID != "" {
+		headers = append(headers, [2]string{"Content-ID", "<" + attachment.ContentID + ">"})
+	}
+
+	return entity{
+		mediaType: contentType,
+		headers:   headers,
+		write: func(w io.Writer) error {
+			return writeBase64(w, attachment.Content)
+		},
+	}
+}
+
+// rootType is only used by multipart/related, where RFC 2387 requires the
+// media type of the root part.
+func multipartEntity(subtype, boundary, rootType string, parts []entity) entity {
+	mediaType := "multipart/" + subtype
+
+	contentType := mediaType + "; boundary=" + boundary
+	if rootType != "" {
+		contentType += fmt.Sprintf("; type=%q", rootType)
+	}
+
+	return entity{
+		mediaType: mediaType,
+		headers:   [][2]string{{"Content-Type", contentType}},
+		write: func(w io.Writer) error {
+			writer := multipart.NewWriter(w)
+			err := writer.SetBoundary(boundary)
+			if err != nil {
+				return err
+			}
+
+			for _, part := range parts {
+				partWriter, err := writer.CreatePart(part.mimeHeader())
+				if err != nil {
+					return err
+				}
+
+				err = part.write(partWriter)
+				if err != nil {
+					return err
+				}
+			}
+
+			return writer.Close()
+		},
 	}
+}
+
+// writeBase64 wraps the encoded content at 76 characters as required by
+// RFC 2045.
+func writeBase64(w io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 0 {
+		n := 76
+		if len(encoded) < n {
+			n = len(encoded)
+		}
 
-	msg.CompiledBody = strings.Replace(string(body), "\r\n", "\n", -1)
-	msg.Date = m.Header.Get("Date")
-	msg.MimeVersion = m.Header.Get("Mime-Version")
-	msg.ContentType = m.Header.Get("Content-Type")
-	msg.ContentTransferEncoding = m.Header.Get("Content-Transfer-Encoding")
+		_, err := io.WriteString(w, encoded[:n]+"\r\n")
+		if err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
 
 	return nil
 }
 
-func (msg Message) Boundary() string {
-	_, params, err := mime.ParseMediaType(msg.ContentType)
+func writeQuotedPrintable(w io.Writer, content string) error {
+	writer := quotedprintable.NewWriter(w)
+
+	_, err := writer.Write([]byte(content))
 	if err != nil {
-		panic(err)
+		return err
+	}
+
+	return writer.Close()
+}
+
+func encodeAddress(value string) string {
+	address, err := netmail.ParseAddress(value)
+	if err != nil {
+		return mime.QEncoding.Encode("utf-8", value)
+	}
+
+	if address.Name == "" {
+		return address.Address
+	}
+
+	return address.String()
+}
+
+// writeHeader folds the header at whitespace so that lines stay within the
+// 78 character limit recommended by RFC 5322 wherever possible.
+func writeHeader(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name + ": " + foldHeader(name, value) + "\r\n")
+}
+
+func foldHeader(name, value string) string {
+	folded := ""
+	length := len(name) + 1
+	for i, word := range strings.Split(value, " ") {
+		if i > 0 {
+			if length+1+len(word) > maxLineLength {
+				folded += "\r\n"
+				length = 0
+			}
+			folded += " "
+		}
+		folded += word
+		length += 1 + len(word)
 	}
 
-	return params["boundary"]
+	return folded
 }
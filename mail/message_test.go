@@ -1,6 +1,12 @@
 package mail_test
 
 import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
 	"strings"
 	"time"
 
@@ -33,25 +39,25 @@ var _ = Describe("Message", func() {
 		})
 
 		It("returns a populated data mail field as a string", func() {
-			parts := strings.Split(msg.Data(), "\n")
+			parts := strings.Split(msg.Data(), "\r\n")
 			boundary := msg.Boundary()
 
-			Expect(parts).To(ConsistOf([]string{
+			Expect(parts).To(Equal([]string{
+				"Date: " + time.Now().Format(time.RFC1123Z),
+				"Mime-Version: 1.0",
 				"From: me@example.com",
 				"To: you@example.com",
 				"Subject: Super Urgent! Read Now!",
 				"Content-Type: multipart/alternative; boundary=" + boundary,
-				"Date: " + time.Now().Format(time.RFC822Z),
-				"Mime-Version: 1.0",
 				"",
 				"--" + boundary,
-				"Content-Type: text/plain; charset=UTF-8",
 				"Content-Transfer-Encoding: quoted-printable",
+				"Content-Type: text/plain; charset=UTF-8",
 				"",
 				"Banana",
 				"--" + boundary,
-				"Content-Type: text/html; charset=UTF-8",
 				"Content-Transfer-Encoding: quoted-printable",
+				"Content-Type: text/html; charset=UTF-8",
 				"",
 				"<header>banana</header>",
 				"--" + boundary + "--",
@@ -59,63 +65,30 @@ var _ = Describe("Message", func() {
 			}))
 		})
 
+		It("uses the same boundary every time the message is rendered", func() {
+			Expect(msg.Data()).To(Equal(msg.Data()))
+		})
+
 		Context("when optional fields are present", func() {
 			It("includes Reply-To in message body", func() {
 				msg.ReplyTo = "banana@chiquita.com"
-				parts := strings.Split(msg.Data(), "\n")
-				boundary := msg.Boundary()
+				parts := strings.Split(msg.Data(), "\r\n")
 
-				Expect(parts).To(ConsistOf([]string{
-					"From: me@example.com",
-					"Reply-To: banana@chiquita.com",
-					"To: you@example.com",
-					"Subject: Super Urgent! Read Now!",
-					"Content-Type: multipart/alternative; boundary=" + boundary,
-					"Date: " + time.Now().Format(time.RFC822Z),
-					"Mime-Version: 1.0",
-					"",
-					"--" + boundary,
-					"Content-Type: text/plain; charset=UTF-8",
-					"Content-Transfer-Encoding: quoted-printable",
-					"",
-					"Banana",
-					"--" + boundary,
-					"Content-Type: text/html; charset=UTF-8",
-					"Content-Transfer-Encoding: quoted-printable",
-					"",
-					"<header>banana</header>",
-					"--" + boundary + "--",
-					"",
-				}))
+				Expect(parts).To(ContainElement("Reply-To: banana@chiquita.com"))
+			})
+
+			It("includes the Message-ID", func() {
+				msg.MessageID = mail.NewMessageID("some-message-id", "example.com")
+				parts := strings.Split(msg.Data(), "\r\n")
+
+				Expect(parts).To(ContainElement("Message-ID: <some-message-id@example.com>"))
 			})
 
 			It("includes headers in the response if there are any", func() {
 				msg.Headers = append(msg.Headers, "X-ClientID: banana")
-				parts := strings.Split(msg.Data(), "\n")
-				boundary := msg.Boundary()
+				parts := strings.Split(msg.Data(), "\r\n")
 
-				Expect(parts).To(ConsistOf([]string{
-					"From: me@example.com",
-					"To: you@example.com",
-					"Subject: Super Urgent! Read Now!",
-					"X-ClientID: banana",
-					"Content-Type: multipart/alternative; boundary=" + boundary,
-					"Date: " + time.Now().Format(time.RFC822Z),
-					"Mime-Version: 1.0",
-					"",
-					"--" + boundary,
-					"Content-Type: text/plain; charset=UTF-8",
-					"Content-Transfer-Encoding: quoted-printable",
-					"",
-					"Banana",
-					"--" + boundary,
-					"Content-Type: text/html; charset=UTF-8",
-					"Content-Transfer-Encoding: quoted-printable",
-					"",
-					"<header>banana</header>",
-					"--" + boundary + "--",
-					"",
-				}))
+				Expect(parts[0]).To(Equal("X-ClientID: banana"))
 			})
 
 			It("includes only the parts necessary", func() {
@@ -126,20 +99,149 @@ var _ = Describe("Message", func() {
 					},
 				}
 
-				parts := strings.Split(msg.Data(), "\n")
+				parts := strings.Split(msg.Data(), "\r\n")
 
 				Expect(parts).To(Equal([]string{
-					"Date: " + time.Now().Format(time.RFC822Z),
+					"Date: " + time.Now().Format(time.RFC1123Z),
 					"Mime-Version: 1.0",
-					"Content-Type: text/html; charset=UTF-8",
-					"Content-Transfer-Encoding: quoted-printable",
 					"From: me@example.com",
 					"To: you@example.com",
 					"Subject: Super Urgent! Read Now!",
+					"Content-Type: text/html; charset=UTF-8",
+					"Content-Transfer-Encoding: quoted-printable",
 					"",
 					"<header>banana</header>",
 				}))
 			})
 		})
+
+		Context("when the headers contain non-ASCII characters", func() {
+			It("encodes the subject and display names per RFC 2047", func() {
+				msg.From = "Benachrichtigungsdienst Müller <me@example.com>"
+				msg.Subject = "お知らせ: Ihre Bestätigung"
+
+				parts := strings.Split(msg.Data(), "\r\n")
+
+				Expect(parts).To(ContainElement("From: =?utf-8?q?Benachrichtigungsdienst_M=C3=BCller?= <me@example.com>"))
+				Expect(parts).To(ContainElement("Subject: =?utf-8?q?=E3=81=8A=E7=9F=A5=E3=82=89=E3=81=9B:_Ihre_Best=C3=A4tigung?="))
+			})
+		})
+
+		It("folds long header lines", func() {
+			msg.Subject = strings.Repeat("banana ", 20)
+
+			for _, line := range strings.Split(msg.Data(), "\r\n") {
+				Expect(len(line)).To(BeNumerically("<=", 78))
+			}
+			Expect(msg.Data()).To(ContainSubstring("banana\r\n banana"))
+		})
+
+		It("encodes the body as quoted-printable", func() {
+			msg.Body = []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "100% größer\nzweite Zeile",
+				},
+			}
+
+			Expect(msg.Data()).To(HaveSuffix("\r\n\r\n100% gr=C3=B6=C3=9Fer\r\nzweite Zeile"))
+		})
+
+		Context("when the message has attachments", func() {
+			BeforeEach(func() {
+				msg.Attachments = []mail.Attachment{
+					{
+						Filename:    "invoice.pdf",
+						ContentType: "application/pdf",
+						Content:     []byte("%PDF-1.4 invoice"),
+					},
+					{
+						Filename:    "logo.png",
+						ContentType: "image/png",
+						Content:     []byte("png bytes"),
+						Inline:      true,
+						ContentID:   "logo",
+					},
+				}
+			})
+
+			It("nests the alternative body and inline images in multipart/related inside multipart/mixed", func() {
+				message, err := netmail.ReadMessage(strings.NewReader(msg.Data()))
+				Expect(err).NotTo(HaveOccurred())
+
+				mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mediaType).To(Equal("multipart/mixed"))
+
+				mixed := multipart.NewReader(message.Body, params["boundary"])
+
+				related, err := mixed.NextPart()
+				Expect(err).NotTo(HaveOccurred())
+				mediaType, params, err = mime.ParseMediaType(related.Header.Get("Content-Type"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mediaType).To(Equal("multipart/related"))
+				Expect(params["type"]).To(Equal("multipart/alternative"))
+
+				relatedReader := multipart.NewReader(related, params["boundary"])
+
+				alternative, err := relatedReader.NextPart()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(alternative.Header.Get("Content-Type")).To(Equal("multipart/alternative; boundary=" + msg.Boundary()))
+
+				logo, err := relatedReader.NextPart()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logo.Header.Get("Content-Type")).To(Equal("image/png"))
+				Expect(logo.Header.Get("Content-Disposition")).To(Equal(`inline; filename=logo.png`))
+				Expect(logo.Header.Get("Content-ID")).To(Equal("<logo>"))
+
+				content, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, logo))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("png bytes"))
+
+				_, err = relatedReader.NextPart()
+				Expect(err).To(Equal(io.EOF))
+
+				invoice, err := mixed.NextPart()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(invoice.Header.Get("Content-Type")).To(Equal("application/pdf"))
+				Expect(invoice.Header.Get("Content-Disposition")).To(Equal(`attachment; filename=invoice.pdf`))
+				Expect(invoice.Header.Get("Content-Transfer-Encoding")).To(Equal("base64"))
+
+				content, err = ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, invoice))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("%PDF-1.4 invoice"))
+
+				_, err = mixed.NextPart()
+				Expect(err).To(Equal(io.EOF))
+			})
+
+			It("only adds multipart/related when there are inline attachments", func() {
+				msg.Attachments = msg.Attachments[:1]
+
+				message, err := netmail.ReadMessage(strings.NewReader(msg.Data()))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+				Expect(err).NotTo(HaveOccurred())
+
+				part, err := multipart.NewReader(message.Body, params["boundary"]).NextPart()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(part.Header.Get("Content-Type")).To(Equal("multipart/alternative; boundary=" + msg.Boundary()))
+			})
+
+			It("wraps the encoded content at 76 characters", func() {
+				msg.Attachments[0].Content = []byte(strings.Repeat("banana", 100))
+
+				for _, line := range strings.Split(msg.Data(), "\r\n") {
+					Expect(len(line)).To(BeNumerically("<=", 78))
+				}
+			})
+
+			It("encodes non-ASCII filenames", func() {
+				msg.Attachments[0].Filename = "Rechnung März.pdf"
+
+				Expect(msg.Data()).To(ContainSubstring("Content-Disposition: attachment; filename*=utf-8''Rechnung%20M%C3%A4rz.pdf"))
+			})
+		})
 	})
 })
@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+type OutboxMessageNotFoundError struct {
+	ID int
+}
+
+func (e OutboxMessageNotFoundError) Error() string {
+	return fmt.Sprintf("Outbox message with ID %d could not be found", e.ID)
+}
+
+type CapturedMessage struct {
+	ID         int
+	CapturedAt time.Time
+	Message    Message
+	Raw        string
+}
+
+// Outbox is a transport used in test mode that keeps the most recent messages
+// in memory instead of delivering them. Once full, the oldest message is
+// dropped for every new one.
+type Outbox struct {
+	capacity int
+	clock    clock
+
+	mutex    sync.Mutex
+	lastID   int
+	messages []CapturedMessage
+}
+
+func NewOutbox(capacity int, clock clock) *Outbox {
+	if capacity <= 0 {
+		capacity = 100
+	}
+
+	return &Outbox{
+		capacity: capacity,
+		clock:    clock,
+	}
+}
+
+func (o *Outbox) Connect(logger lager.Logger) error {
+	return nil
+}
+
+func (o *Outbox) Send(msg Message, logger lager.Logger) error {
+	raw := msg.Data()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.lastID++
+	o.messages = append(o.messages, CapturedMessage{
+		ID:         o.lastID,
+		CapturedAt: o.clock.Now(),
+		Message:    msg,
+		Raw:        raw,
+	})
+
+	if len(o.messages) > o.capacity {
+		o.messages = o.messages[len(o.messages)-o.capacity:]
+	}
+
+	logger.Info("test-mode-captured", lager.Data{"outbox_id": o.lastID})
+
+	return nil
+}
+
+// List returns the captured messages, newest first.
+func (o *Outbox) List() []CapturedMessage {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	messages := make([]CapturedMessage, 0, len(o.messages))
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		messages = append(messages, o.messages[i])
+	}
+
+	return messages
+}
+
+func (o *Outbox) Find(id int) (CapturedMessage, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, message := range o.messages {
+		if message.ID == id {
+			return message, nil
+		}
+	}
+
+	return CapturedMessage{}, OutboxMessageNotFoundError{ID: id}
+}
+
+func (o *Outbox) Clear() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	count := len(o.messages)
+	o.messages = nil
+
+	return count
+}
@@ -0,0 +1,89 @@
+package mail_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outbox", func() {
+	var (
+		outbox *mail.Outbox
+		logger lager.Logger
+		now    time.Time
+	)
+
+	message := func(subject string) mail.Message {
+		return mail.Message{
+			From:    "me@example.com",
+			To:      "you@example.com",
+			Subject: subject,
+			Body: []mail.Part{
+				{ContentType: "text/plain", Content: "This email is important"},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, 0))
+
+		now = time.Date(2015, time.January, 20, 20, 23, 30, 0, time.UTC)
+		clock := mocks.NewClock()
+		clock.NowCall.Returns.Time = now
+
+		outbox = mail.NewOutbox(2, clock)
+	})
+
+	It("captures sent messages, newest first", func() {
+		Expect(outbox.Send(message("first"), logger)).To(Succeed())
+		Expect(outbox.Send(message("second"), logger)).To(Succeed())
+
+		messages := outbox.List()
+		Expect(messages).To(HaveLen(2))
+		Expect(messages[0].ID).To(Equal(2))
+		Expect(messages[0].Message.Subject).To(Equal("second"))
+		Expect(messages[0].CapturedAt).To(Equal(now))
+		Expect(messages[0].Raw).To(ContainSubstring("Subject: second\r\n"))
+		Expect(messages[1].ID).To(Equal(1))
+	})
+
+	It("drops the oldest message once it is full", func() {
+		Expect(outbox.Send(message("first"), logger)).To(Succeed())
+		Expect(outbox.Send(message("second"), logger)).To(Succeed())
+		Expect(outbox.Send(message("third"), logger)).To(Succeed())
+
+		messages := outbox.List()
+		Expect(messages).To(HaveLen(2))
+		Expect(messages[0].Message.Subject).To(Equal("third"))
+		Expect(messages[1].Message.Subject).To(Equal("second"))
+
+		_, err := outbox.Find(1)
+		Expect(err).To(MatchError(mail.OutboxMessageNotFoundError{ID: 1}))
+	})
+
+	It("finds a message by id", func() {
+		Expect(outbox.Send(message("first"), logger)).To(Succeed())
+
+		captured, err := outbox.Find(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(captured.Message.Subject).To(Equal("first"))
+	})
+
+	It("clears the captured messages", func() {
+		Expect(outbox.Send(message("first"), logger)).To(Succeed())
+		Expect(outbox.Send(message("second"), logger)).To(Succeed())
+
+		Expect(outbox.Clear()).To(Equal(2))
+		Expect(outbox.List()).To(BeEmpty())
+
+		Expect(outbox.Send(message("third"), logger)).To(Succeed())
+		Expect(outbox.List()[0].ID).To(Equal(3))
+	})
+})
@@ -0,0 +1,200 @@
+package mail
+
+import (
+	"errors"
+	"net/textproto"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+const serviceNotAvailable = 421
+
+type PoolConfig struct {
+	Size                     int
+	MaxMessagesPerConnection int
+	IdleTimeout              time.Duration
+}
+
+// Pool keeps authenticated SMTP connections open between messages, resetting
+// them with RSET and reusing them for up to MaxMessagesPerConnection sends.
+type Pool struct {
+	config     Config
+	poolConfig PoolConfig
+	idle       chan *pooledClient
+	slots      chan struct{}
+}
+
+type pooledClient struct {
+	client    *Client
+	sent      int
+	lastUsed  time.Time
+	idleSince time.Time
+}
+
+func NewPool(config Config, poolConfig PoolConfig) *Pool {
+	if poolConfig.Size <= 0 {
+		poolConfig.Size = 1
+	}
+
+	if poolConfig.MaxMessagesPerConnection <= 0 {
+		poolConfig.MaxMessagesPerConnection = 100
+	}
+
+	if poolConfig.IdleTimeout <= 0 {
+		poolConfig.IdleTimeout = 30 * time.Second
+	}
+
+	return &Pool{
+		config:     config,
+		poolConfig: poolConfig,
+		idle:       make(chan *pooledClient, poolConfig.Size),
+		slots:      make(chan struct{}, poolConfig.Size),
+	}
+}
+
+// Connect makes sure a connection is available so that connection errors are
+// reported before a message is sent.
+func (p *Pool) Connect(logger lager.Logger) error {
+	if p.config.TestMode {
+		return nil
+	}
+
+	pc, err := p.acquire(logger)
+	if err != nil {
+		return err
+	}
+
+	p.release(pc)
+
+	return nil
+}
+
+func (p *Pool) Send(msg Message, logger lager.Logger) error {
+	logger = createLoggerSession(logger)
+
+	if p.config.TestMode {
+		logger.Info("test-mode")
+		return nil
+	}
+
+	pc, err := p.acquire(logger)
+	if err != nil {
+		return err
+	}
+
+	// Only a connection that has carried a message before can have been
+	// dropped by the server while idle, so only those are retried on a
+	// connection error.
+	reused := !pc.lastUsed.IsZero()
+	err = pc.client.deliver(msg, logger)
+	if isServiceNotAvailable(err) || (reused && isConnectionError(err)) {
+		logger.Info("reconnecting", lager.Data{"error": err.Error()})
+		p.discard(pc)
+
+		pc, err = p.acquire(logger)
+		if err != nil {
+			return err
+		}
+
+		err = pc.client.deliver(msg, logger)
+	}
+
+	if err != nil {
+		logger.Error("failed", err)
+
+		if isServiceNotAvailable(err) || isConnectionError(err) {
+			p.discard(pc)
+			return err
+		}
+
+		pc.lastUsed = time.Now()
+		p.recycle(pc, logger)
+		return err
+	}
+
+	pc.sent++
+	pc.lastUsed = time.Now()
+	p.recycle(pc, logger)
+
+	return nil
+}
+
+func (p *Pool) acquire(logger lager.Logger) (*pooledClient, error) {
+	for {
+		select {
+		case pc := <-p.idle:
+			if p.fresh(pc) {
+				return pc, nil
+			}
+			continue
+		default:
+		}
+
+		select {
+		case pc := <-p.idle:
+			if p.fresh(pc) {
+				return pc, nil
+			}
+		case p.slots <- struct{}{}:
+			client := NewClient(p.config)
+			err := client.open(logger)
+			if err != nil {
+				<-p.slots
+				return nil, client.Error(logger, err)
+			}
+
+			return &pooledClient{client: client}, nil
+		}
+	}
+}
+
+func (p *Pool) fresh(pc *pooledClient) bool {
+	if time.Since(pc.idleSince) < p.poolConfig.IdleTimeout {
+		return true
+	}
+
+	p.close(pc)
+	return false
+}
+
+func (p *Pool) recycle(pc *pooledClient, logger lager.Logger) {
+	if pc.sent >= p.poolConfig.MaxMessagesPerConnection {
+		p.close(pc)
+		return
+	}
+
+	err := pc.client.Reset()
+	if err != nil {
+		logger.Error("reset-failed", err)
+		p.discard(pc)
+		return
+	}
+
+	p.release(pc)
+}
+
+func (p *Pool) release(pc *pooledClient) {
+	pc.idleSince = time.Now()
+	p.idle <- pc
+}
+
+func (p *Pool) close(pc *pooledClient) {
+	pc.client.Quit()
+	<-p.slots
+}
+
+func (p *Pool) discard(pc *pooledClient) {
+	pc.client.close()
+	<-p.slots
+}
+
+func isServiceNotAvailable(err error) bool {
+	var reply *textproto.Error
+	return errors.As(err, &reply) && reply.Code == serviceNotAvailable
+}
+
+func isConnectionError(err error) bool {
+	var reply *textproto.Error
+	return err != nil && !errors.As(err, &reply)
+}
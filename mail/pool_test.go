@@ -0,0 +1,177 @@
+package mail_test
+
+import (
+	"bytes"
+	"net"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pool", func() {
+	var (
+		mailServer *SMTPServer
+		pool       *mail.Pool
+		logger     lager.Logger
+		config     mail.Config
+		poolConfig mail.PoolConfig
+		msg        mail.Message
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, 0))
+		mailServer = NewSMTPServer("user", "pass")
+		mailServer.SupportsTLS = true
+
+		config = mail.Config{
+			User:          "user",
+			Pass:          "pass",
+			SkipVerifySSL: true,
+		}
+
+		config.Host, config.Port, err = net.SplitHostPort(mailServer.URL.Host)
+		Expect(err).NotTo(HaveOccurred())
+
+		poolConfig = mail.PoolConfig{
+			Size:                     1,
+			MaxMessagesPerConnection: 3,
+			IdleTimeout:              time.Minute,
+		}
+
+		msg = mail.Message{
+			From:    "me@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent! Read now!",
+			Body: []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "This email is the most important thing you will read all day!",
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		pool = mail.NewPool(config, poolConfig)
+	})
+
+	AfterEach(func() {
+		mailServer.Close()
+	})
+
+	It("reuses the same connection for several messages", func() {
+		Expect(pool.Send(msg, logger)).To(Succeed())
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(2))
+		Expect(mailServer.Connections).To(Equal(1))
+		Expect(mailServer.Deliveries[1].UsedTLS).To(BeTrue())
+	})
+
+	It("opens a new connection once a connection has sent the maximum number of messages", func() {
+		for i := 0; i < 4; i++ {
+			Expect(pool.Send(msg, logger)).To(Succeed())
+		}
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(4))
+		Expect(mailServer.Connections).To(Equal(2))
+	})
+
+	Context("when a connection has been idle for too long", func() {
+		BeforeEach(func() {
+			poolConfig.IdleTimeout = 10 * time.Millisecond
+		})
+
+		It("opens a new connection", func() {
+			Expect(pool.Send(msg, logger)).To(Succeed())
+			time.Sleep(20 * time.Millisecond)
+			Expect(pool.Send(msg, logger)).To(Succeed())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(2))
+			Expect(mailServer.Connections).To(Equal(2))
+		})
+	})
+
+	Context("when the server replies with 421", func() {
+		It("reconnects and sends the message", func() {
+			Expect(pool.Send(msg, logger)).To(Succeed())
+
+			mailServer.MailFromReply = "421 4.3.2 Service shutting down"
+			Expect(pool.Send(msg, logger)).To(Succeed())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(2))
+			Expect(mailServer.Connections).To(Equal(2))
+		})
+	})
+
+	Context("when the server drops the connection", func() {
+		It("reconnects and sends the message on a connection that has sent before", func() {
+			Expect(pool.Send(msg, logger)).To(Succeed())
+
+			mailServer.DropsMailFrom = true
+			Expect(pool.Send(msg, logger)).To(Succeed())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(2))
+			Expect(mailServer.Connections).To(Equal(2))
+		})
+
+		It("returns the error on a connection that has not sent anything yet", func() {
+			Expect(pool.Connect(logger)).To(Succeed())
+
+			mailServer.DropsMailFrom = true
+			Expect(pool.Send(msg, logger)).NotTo(Succeed())
+
+			Expect(mailServer.Deliveries).To(BeEmpty())
+			Expect(mailServer.Connections).To(Equal(1))
+		})
+	})
+
+	Context("when the server rejects the recipient", func() {
+		It("returns the error and keeps the connection for the next message", func() {
+			mailServer.RcptToReply = "550 5.1.1 mailbox does not exist"
+
+			err := pool.Send(msg, logger)
+			Expect(mail.IsPermanentFailure(err)).To(BeTrue())
+
+			mailServer.RcptToReply = ""
+			Expect(pool.Send(msg, logger)).To(Succeed())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(1))
+			Expect(mailServer.Connections).To(Equal(1))
+		})
+	})
+
+	Context("when in test mode", func() {
+		BeforeEach(func() {
+			config.TestMode = true
+		})
+
+		It("does not connect to the server", func() {
+			Expect(pool.Connect(logger)).To(Succeed())
+			Expect(pool.Send(msg, logger)).To(Succeed())
+
+			Consistently(func() int {
+				return mailServer.Connections
+			}, 50*time.Millisecond).Should(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,73 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// Exit codes from sysexits.h that sendmail uses to reject a message outright.
+var permanentSendmailExitCodes = map[int]bool{
+	65: true, // EX_DATAERR
+	67: true, // EX_NOUSER
+	68: true, // EX_NOHOST
+}
+
+type SendmailTransportConfig struct {
+	Path       string
+	DKIMSigner *DKIMSigner
+}
+
+// SendmailTransport pipes each message to a local sendmail compatible binary.
+type SendmailTransport struct {
+	path   string
+	signer *DKIMSigner
+}
+
+func NewSendmailTransport(config SendmailTransportConfig) SendmailTransport {
+	return SendmailTransport{
+		path:   config.Path,
+		signer: config.DKIMSigner,
+	}
+}
+
+func (t SendmailTransport) Connect(logger lager.Logger) error {
+	_, err := exec.LookPath(t.path)
+	return err
+}
+
+func (t SendmailTransport) Send(msg Message, logger lager.Logger) error {
+	logger = logger.Session("sendmail-transport")
+
+	data, err := serialize(msg, t.signer)
+	if err != nil {
+		return err
+	}
+
+	stderr := bytes.NewBuffer([]byte{})
+
+	cmd := exec.Command(t.path, "-i", "-f", msg.From, "--", msg.To)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			return DeliveryError{
+				Err:       fmt.Errorf("%s exited with %d: %s", t.path, exitError.ExitCode(), strings.TrimSpace(stderr.String())),
+				Permanent: permanentSendmailExitCodes[exitError.ExitCode()],
+			}
+		}
+
+		return err
+	}
+
+	logger.Info("delivered")
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package mail_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendmailTransport", func() {
+	var (
+		directory string
+		transport mail.SendmailTransport
+		logger    lager.Logger
+		msg       mail.Message
+	)
+
+	writeScript := func(script string) string {
+		path := filepath.Join(directory, "sendmail")
+		Expect(ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "sendmail")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, 0))
+
+		msg = mail.Message{
+			From:    "me@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent! Read now!",
+			Body: []mail.Part{
+				{ContentType: "text/plain", Content: "This email is important"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	It("pipes the message to the binary", func() {
+		transport = mail.NewSendmailTransport(mail.SendmailTransportConfig{
+			Path: writeScript(`echo "$@" > "$(dirname "$0")/args"; cat > "$(dirname "$0")/message"`),
+		})
+
+		Expect(transport.Connect(logger)).To(Succeed())
+		Expect(transport.Send(msg, logger)).To(Succeed())
+
+		args, err := ioutil.ReadFile(filepath.Join(directory, "args"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(args)).To(Equal("-i -f me@example.com -- you@example.com\n"))
+
+		message, err := ioutil.ReadFile(filepath.Join(directory, "message"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Split(string(message), "\r\n")).To(ContainElement("Subject: Urgent! Read now!"))
+		Expect(string(message)).To(HaveSuffix("This email is important"))
+	})
+
+	It("returns a permanent failure when the recipient is unknown", func() {
+		transport = mail.NewSendmailTransport(mail.SendmailTransportConfig{
+			Path: writeScript("cat > /dev/null; echo 'no such user' >&2; exit 67"),
+		})
+
+		err := transport.Send(msg, logger)
+		Expect(err).To(MatchError(ContainSubstring("exited with 67: no such user")))
+		Expect(mail.IsPermanentFailure(err)).To(BeTrue())
+	})
+
+	It("returns a transient failure for temporary errors", func() {
+		transport = mail.NewSendmailTransport(mail.SendmailTransportConfig{
+			Path: writeScript("cat > /dev/null; exit 75"),
+		})
+
+		err := transport.Send(msg, logger)
+		Expect(err).To(HaveOccurred())
+		Expect(mail.IsPermanentFailure(err)).To(BeFalse())
+	})
+
+	It("fails to connect when the binary does not exist", func() {
+		transport = mail.NewSendmailTransport(mail.SendmailTransportConfig{
+			Path: filepath.Join(directory, "missing"),
+		})
+
+		Expect(transport.Connect(logger)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"fmt"
+
+	"github.com/pivotal-golang/lager"
+)
+
+const (
+	TransportSMTP     = "smtp"
+	TransportHTTP     = "http"
+	TransportSendmail = "sendmail"
+	TransportFile     = "file"
+)
+
+var Transports = []string{TransportSMTP, TransportHTTP, TransportSendmail, TransportFile}
+
+type Transport interface {
+	Connect(lager.Logger) error
+	Send(Message, lager.Logger) error
+}
+
+// TransportRegistry maps transport names to constructors so that only the
+// configured transport is built.
+type TransportRegistry map[string]func() (Transport, error)
+
+func (r TransportRegistry) Build(name string) (Transport, error) {
+	build, ok := r[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown mail transport %q", name)
+	}
+
+	return build()
+}
+
+func serialize(msg Message, signer *DKIMSigner) ([]byte, error) {
+	data := []byte(msg.Data())
+	if signer == nil {
+		return data, nil
+	}
+
+	return signer.Sign(data)
+}
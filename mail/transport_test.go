@@ -0,0 +1,41 @@
+package mail_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransportRegistry", func() {
+	var registry mail.TransportRegistry
+
+	BeforeEach(func() {
+		registry = mail.TransportRegistry{
+			mail.TransportHTTP: func() (mail.Transport, error) {
+				return mail.NewHTTPTransport(mail.HTTPTransportConfig{URL: "http://mail.example.com"}), nil
+			},
+			mail.TransportFile: func() (mail.Transport, error) {
+				return nil, errors.New("no directory")
+			},
+		}
+	})
+
+	It("builds the named transport", func() {
+		transport, err := registry.Build(mail.TransportHTTP)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transport).To(BeAssignableToTypeOf(mail.HTTPTransport{}))
+	})
+
+	It("returns errors from the constructor", func() {
+		_, err := registry.Build(mail.TransportFile)
+		Expect(err).To(MatchError("no directory"))
+	})
+
+	It("returns an error for unknown transports", func() {
+		_, err := registry.Build("carrier-pigeon")
+		Expect(err).To(MatchError(`unknown mail transport "carrier-pigeon"`))
+	})
+})
@@ -0,0 +1,57 @@
+package postal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type clock interface {
+	Now() time.Time
+}
+
+// FileArchiver appends records as newline-delimited JSON to one file per
+// record type and day, e.g. messages-2015-01-20.ndjson.
+type FileArchiver struct {
+	directory string
+	clock     clock
+}
+
+func NewFileArchiver(directory string, clock clock) FileArchiver {
+	return FileArchiver{
+		directory: directory,
+		clock:     clock,
+	}
+}
+
+func (a FileArchiver) Archive(name string, records []interface{}) error {
+	err := os.MkdirAll(a.directory, 0755)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%s-%s.ndjson", name, a.clock.Now().UTC().Format("2006-01-02"))
+	file, err := os.OpenFile(filepath.Join(a.directory, filename), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	for _, record := range records {
+		err = encoder.Encode(record)
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,52 @@
+package postal_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileArchiver", func() {
+	var (
+		archiver  postal.FileArchiver
+		directory string
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "archive")
+		Expect(err).NotTo(HaveOccurred())
+
+		clock := mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Date(2015, time.January, 20, 20, 23, 30, 0, time.UTC)
+
+		archiver = postal.NewFileArchiver(filepath.Join(directory, "retention"), clock)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	It("appends each record as a line of JSON to a daily file", func() {
+		err := archiver.Archive("messages", []interface{}{
+			map[string]string{"id": "message-1"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = archiver.Archive("messages", []interface{}{
+			map[string]string{"id": "message-2"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		contents, err := ioutil.ReadFile(filepath.Join(directory, "retention", "messages-2015-01-20.ndjson"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("{\"id\":\"message-1\"}\n{\"id\":\"message-2\"}\n"))
+	})
+})
@@ -4,10 +4,12 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"log"
+	"net/http"
 	"os"
 	"path"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/mail"
@@ -16,6 +18,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
+	v1services "github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/pivotal-golang/conceal"
 	"github.com/pivotal-golang/lager"
 )
@@ -35,6 +38,9 @@ type Config struct {
 	Domain               string
 	QueueWaitMaxDuration int
 	CCHost               string
+	PublicURL            string
+	HTMLAllowedTags      []string
+	HTMLBaseURL          string
 }
 
 func database(db *sql.DB, dbLoggingEnabled bool, rootPath string) db.DatabaseInterface {
@@ -49,7 +55,7 @@ func database(db *sql.DB, dbLoggingEnabled bool, rootPath string) db.DatabaseInt
 	return database
 }
 
-func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
+func Boot(mailTransport mail.Transport, db *sql.DB, config Config) {
 	uaaClient := uaa.NewZonedUAAClient(config.UAAClientID, config.UAAClientSecret, config.VerifySSL, config.UAATokenValidator)
 
 	logger := lager.NewLogger("notifications")
@@ -75,16 +81,47 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 	receiptsRepo := v1models.NewReceiptsRepo()
 	unsubscribesRepo := v1models.NewUnsubscribesRepo()
 	globalUnsubscribesRepo := v1models.NewGlobalUnsubscribesRepo()
+	userLocalesRepo := v1models.NewUserLocalesRepo()
 	messagesRepo := v1models.NewMessagesRepo(guidGenerator.Generate)
 	clientsRepo := v1models.NewClientsRepo()
 	kindsRepo := v1models.NewKindsRepo()
 	templatesRepo := v1models.NewTemplatesRepo()
-	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+	templateVersionsRepo := v1models.NewTemplateVersionsRepo()
+	batchesRepo := v1models.NewBatchesRepo(guidGenerator.Generate)
+	webhooksRepo := v1models.NewWebhooksRepo(guidGenerator.Generate)
+	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo)
 	deliveryFailureHandler := common.NewDeliveryFailureHandler()
-	messageStatusUpdater := v1.NewMessageStatusUpdater(messagesRepo)
+	webhookNotifier := v1.NewWebhookNotifier(batchesRepo, webhooksRepo, gobbleQueue, gobble.Initializer{})
+	messageStatusUpdater := v1.NewMessageStatusUpdater(messagesRepo, webhookNotifier)
 	userLoader := common.NewUserLoader(uaaClient)
 	tokenLoader := uaa.NewTokenLoader(uaaClient)
-	packager := common.NewPackager(v1TemplateLoader, cloak)
+	htmlPipeline := common.NewHTMLPipeline(common.HTMLPipelineConfig{
+		AllowedTags: config.HTMLAllowedTags,
+		BaseURL:     config.HTMLBaseURL,
+	})
+	packager := common.NewPackager(v1TemplateLoader, cloak, config.PublicURL, htmlPipeline)
+
+	cloudController := cf.NewCloudController(config.CCHost, !config.VerifySSL)
+	fanOutJobProcessor := v1.NewFanOutJobProcessor(v1.FanOutJobProcessorConfig{
+		Database:     database,
+		TokenLoader:  tokenLoader,
+		AllUsers:     v1services.NewAllUsers(uaaClient),
+		FindsUserIDs: v1services.NewFindsUserIDs(cloudController, uaaClient),
+		Enqueuer:     v1services.NewEnqueuer(gobbleQueue, messagesRepo, batchesRepo, gobble.Initializer{}),
+
+		BatchesRepo:            batchesRepo,
+		DeliveryFailureHandler: deliveryFailureHandler,
+	})
+
+	webhookJobProcessor := v1.NewWebhookJobProcessor(v1.WebhookJobProcessorConfig{
+		Database:     database,
+		WebhooksRepo: webhooksRepo,
+		// Webhooks are client endpoints rather than platform components, so
+		// their certificates are verified whatever VERIFY_SSL says.
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	})
 
 	WorkerGenerator{
 		InstanceIndex: config.InstanceIndex,
@@ -98,7 +135,7 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 			Domain:  config.Domain,
 
 			Packager:    packager,
-			MailClient:  mailClient(),
+			MailClient:  mailTransport,
 			Database:    database,
 			TokenLoader: tokenLoader,
 			UserLoader:  userLoader,
@@ -107,6 +144,7 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			UserLocalesRepo:        userLocalesRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
@@ -116,6 +154,8 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 			UAAHost: config.UAAHost,
 			DBTrace: config.DBLoggingEnabled,
 
+			FanOutJobProcessor:     fanOutJobProcessor,
+			WebhookJobProcessor:    webhookJobProcessor,
 			DeliveryFailureHandler: deliveryFailureHandler,
 
 			Logger: logger.Session("worker", lager.Data{"worker_id": index}),
@@ -10,6 +10,7 @@ import (
 
 type Retryable interface {
 	Retry(duration time.Duration)
+	Bury(reason string)
 	State() (retryCount int, activeAt time.Time)
 }
 
@@ -19,9 +20,15 @@ func NewDeliveryFailureHandler() DeliveryFailureHandler {
 	return DeliveryFailureHandler{}
 }
 
-func (h DeliveryFailureHandler) Handle(job Retryable, logger lager.Logger) {
+func (h DeliveryFailureHandler) Handle(job Retryable, err error, logger lager.Logger) {
 	retryCount, _ := job.State()
 	if retryCount > 9 {
+		job.Bury(err.Error())
+		logger.Error("delivery-failed-giving-up", err, lager.Data{
+			"retry_count": retryCount,
+		})
+
+		metrics.GetOrRegisterCounter("notifications.worker.dead", nil).Inc(1)
 		return
 	}
 
@@ -0,0 +1,462 @@
+package common
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// DefaultAllowedTags are the elements that survive sanitization unless the
+// operator configures a different list. The document structure (html, head
+// and body) is always kept.
+var DefaultAllowedTags = []string{
+	"a", "abbr", "address", "article", "aside", "b", "blockquote", "br",
+	"caption", "center", "code", "col", "colgroup", "dd", "del", "div", "dl",
+	"dt", "em", "figcaption", "figure", "font", "footer", "h1", "h2", "h3",
+	"h4", "h5", "h6", "header", "hr", "i", "img", "ins", "kbd", "li", "main",
+	"meta", "nav", "ol", "p", "pre", "q", "s", "samp", "section", "small",
+	"span", "strike", "strong", "sub", "sup", "table", "tbody", "td", "tfoot",
+	"th", "thead", "title", "tr", "tt", "u", "ul",
+}
+
+var (
+	structuralTags = map[string]bool{"html": true, "head": true, "body": true}
+
+	// Elements whose content is not meant to be displayed are dropped
+	// entirely, any other element that is not allowed is replaced by its
+	// children.
+	droppedContentTags = map[string]bool{
+		"applet": true, "embed": true, "frame": true, "frameset": true,
+		"iframe": true, "noembed": true, "noframes": true, "noscript": true,
+		"object": true, "script": true, "select": true, "style": true,
+		"template": true, "textarea": true,
+	}
+
+	urlAttributes = map[string]bool{
+		"action": true, "background": true, "cite": true, "href": true,
+		"longdesc": true, "poster": true, "src": true,
+	}
+
+	safeURLSchemes = map[string]bool{
+		"": true, "http": true, "https": true, "mailto": true, "tel": true, "cid": true,
+	}
+
+	cssCommentRegexp = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	unsafeCSSRegexp  = regexp.MustCompile(`(?i)expression\s*\(|javascript:|vbscript:|behavior\s*:|-moz-binding`)
+)
+
+type HTMLPipelineConfig struct {
+	AllowedTags []string
+	BaseURL     string
+}
+
+// HTMLPipeline prepares rendered HTML for mail clients: it inlines <style>
+// rules into style attributes, strips anything that is not on the allowlist
+// along with event handlers and script URLs, and resolves relative URLs
+// against the configured base URL.
+type HTMLPipeline struct {
+	allowedTags map[string]bool
+	baseURL     *url.URL
+}
+
+func NewHTMLPipeline(config HTMLPipelineConfig) HTMLPipeline {
+	if len(config.AllowedTags) == 0 {
+		config.AllowedTags = DefaultAllowedTags
+	}
+
+	allowedTags := map[string]bool{}
+	for _, tag := range config.AllowedTags {
+		allowedTags[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+
+	var baseURL *url.URL
+	if config.BaseURL != "" {
+		parsed, err := url.Parse(config.BaseURL)
+		if err == nil && parsed.IsAbs() {
+			baseURL = parsed
+		}
+	}
+
+	return HTMLPipeline{
+		allowedTags: allowedTags,
+		baseURL:     baseURL,
+	}
+}
+
+func (p HTMLPipeline) Process(rawHTML string) (string, error) {
+	document, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return "", err
+	}
+
+	remainingCSS := inlineStyles(document)
+	p.sanitize(document)
+
+	if remainingCSS != "" {
+		appendStyle(document, remainingCSS)
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	err = html.Render(buffer, document)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func (p HTMLPipeline) sanitize(node *html.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		switch child.Type {
+		case html.CommentNode:
+			node.RemoveChild(child)
+		case html.ElementNode:
+			p.sanitize(child)
+
+			switch {
+			case structuralTags[child.Data] || p.allowedTags[child.Data]:
+				child.Attr = p.sanitizeAttributes(child.Attr)
+			case droppedContentTags[child.Data]:
+				node.RemoveChild(child)
+			default:
+				for grandchild := child.FirstChild; grandchild != nil; grandchild = child.FirstChild {
+					child.RemoveChild(grandchild)
+					node.InsertBefore(grandchild, child)
+				}
+				node.RemoveChild(child)
+			}
+		}
+
+		child = next
+	}
+}
+
+func (p HTMLPipeline) sanitizeAttributes(attributes []html.Attribute) []html.Attribute {
+	var sanitized []html.Attribute
+	for _, attribute := range attributes {
+		key := strings.ToLower(attribute.Key)
+
+		switch {
+		case strings.HasPrefix(key, "on"), key == "http-equiv":
+			continue
+		case key == "style":
+			if unsafeCSSRegexp.MatchString(attribute.Val) {
+				continue
+			}
+		case urlAttributes[key]:
+			value, ok := p.rewriteURL(key, attribute.Val)
+			if !ok {
+				continue
+			}
+			attribute.Val = value
+		}
+
+		sanitized = append(sanitized, attribute)
+	}
+
+	return sanitized
+}
+
+func (p HTMLPipeline) rewriteURL(attribute, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "#") {
+		return value, true
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return "", false
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme == "data" {
+		return value, attribute == "src" && strings.HasPrefix(strings.ToLower(parsed.Opaque), "image/")
+	}
+
+	if !safeURLSchemes[scheme] {
+		return "", false
+	}
+
+	if !parsed.IsAbs() && p.baseURL != nil {
+		return p.baseURL.ResolveReference(parsed).String(), true
+	}
+
+	return value, true
+}
+
+type cssDeclaration struct {
+	property string
+	value    string
+}
+
+type cssRule struct {
+	selector     string
+	declarations []cssDeclaration
+}
+
+type matchedRule struct {
+	specificity  int
+	order        int
+	declarations []cssDeclaration
+}
+
+// inlineStyles removes every <style> element, copies the rules that can be
+// expressed as style attributes onto the matching elements and returns the
+// CSS that has to stay in a stylesheet, such as media queries and pseudo
+// classes.
+func inlineStyles(document *html.Node) string {
+	var stylesheet strings.Builder
+	for _, style := range cascadia.MustCompile("style").MatchAll(document) {
+		for child := style.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode {
+				stylesheet.WriteString(child.Data + "\n")
+			}
+		}
+		style.Parent.RemoveChild(style)
+	}
+
+	rules, remaining := parseStylesheet(stylesheet.String())
+
+	matches := map[*html.Node][]matchedRule{}
+	var matchedNodes []*html.Node
+	for order, rule := range rules {
+		selector, err := cascadia.Compile(rule.selector)
+		if err != nil {
+			continue
+		}
+
+		for _, node := range selector.MatchAll(document) {
+			if _, ok := matches[node]; !ok {
+				matchedNodes = append(matchedNodes, node)
+			}
+			matches[node] = append(matches[node], matchedRule{
+				specificity:  specificity(rule.selector),
+				order:        order,
+				declarations: rule.declarations,
+			})
+		}
+	}
+
+	for _, node := range matchedNodes {
+		rules := matches[node]
+		sort.SliceStable(rules, func(i, j int) bool {
+			if rules[i].specificity != rules[j].specificity {
+				return rules[i].specificity < rules[j].specificity
+			}
+			return rules[i].order < rules[j].order
+		})
+
+		var declarations []cssDeclaration
+		for _, rule := range rules {
+			declarations = mergeDeclarations(declarations, rule.declarations)
+		}
+
+		for i, attribute := range node.Attr {
+			if strings.ToLower(attribute.Key) == "style" {
+				declarations = mergeDeclarations(declarations, parseDeclarations(attribute.Val))
+				node.Attr = append(node.Attr[:i], node.Attr[i+1:]...)
+				break
+			}
+		}
+
+		node.Attr = append(node.Attr, html.Attribute{Key: "style", Val: formatDeclarations(declarations)})
+	}
+
+	return remaining
+}
+
+func parseStylesheet(css string) ([]cssRule, string) {
+	css = cssCommentRegexp.ReplaceAllString(css, "")
+
+	var rules []cssRule
+	var remaining []string
+	for _, block := range cssBlocks(css) {
+		if strings.HasPrefix(block.prelude, "@") {
+			if atRule := sanitizeAtRule(block); atRule != "" {
+				remaining = append(remaining, atRule)
+			}
+			continue
+		}
+
+		declarations := parseDeclarations(block.body)
+		for _, selector := range strings.Split(block.prelude, ",") {
+			selector = strings.TrimSpace(selector)
+			if selector == "" {
+				continue
+			}
+
+			if strings.Contains(selector, ":") {
+				remaining = append(remaining, selector+" {"+formatDeclarations(declarations)+"}")
+				continue
+			}
+
+			rules = append(rules, cssRule{selector: selector, declarations: declarations})
+		}
+	}
+
+	return rules, strings.Join(remaining, "\n")
+}
+
+type cssBlock struct {
+	prelude string
+	body    string
+}
+
+// cssBlocks splits a stylesheet into its outermost blocks. Statements that
+// end without a block, such as @import and @charset, are dropped.
+func cssBlocks(css string) []cssBlock {
+	var blocks []cssBlock
+	for {
+		open := strings.Index(css, "{")
+		if open < 0 {
+			break
+		}
+
+		if semicolon := strings.Index(css[:open], ";"); semicolon >= 0 {
+			css = css[semicolon+1:]
+			continue
+		}
+
+		end := closingBrace(css, open)
+		blocks = append(blocks, cssBlock{
+			prelude: strings.TrimSpace(css[:open]),
+			body:    css[open+1 : end],
+		})
+		if end < len(css) {
+			end++
+		}
+
+		css = css[end:]
+	}
+
+	return blocks
+}
+
+// sanitizeAtRule filters the declarations of an at-rule such as @media or
+// @font-face, including those of the rules nested inside it.
+func sanitizeAtRule(block cssBlock) string {
+	if strings.HasPrefix(strings.ToLower(block.prelude), "@import") || unsafeCSSRegexp.MatchString(block.prelude) {
+		return ""
+	}
+
+	if !strings.Contains(block.body, "{") {
+		return block.prelude + " {" + formatDeclarations(parseDeclarations(block.body)) + "}"
+	}
+
+	var rules []string
+	for _, nested := range cssBlocks(block.body) {
+		if strings.HasPrefix(nested.prelude, "@") {
+			if atRule := sanitizeAtRule(nested); atRule != "" {
+				rules = append(rules, atRule)
+			}
+			continue
+		}
+
+		rules = append(rules, nested.prelude+" {"+formatDeclarations(parseDeclarations(nested.body))+"}")
+	}
+
+	return block.prelude + " {" + strings.Join(rules, " ") + "}"
+}
+
+func closingBrace(css string, open int) int {
+	depth := 0
+	for i := open; i < len(css); i++ {
+		switch css[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(css)
+}
+
+func parseDeclarations(body string) []cssDeclaration {
+	var declarations []cssDeclaration
+	for _, declaration := range strings.Split(body, ";") {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		property := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+		if property == "" || value == "" || unsafeCSSRegexp.MatchString(property+": "+value) {
+			continue
+		}
+
+		declarations = append(declarations, cssDeclaration{property: property, value: value})
+	}
+
+	return declarations
+}
+
+func mergeDeclarations(declarations, overrides []cssDeclaration) []cssDeclaration {
+	for _, override := range overrides {
+		replaced := false
+		for i, declaration := range declarations {
+			if declaration.property == override.property {
+				declarations[i].value = override.value
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			declarations = append(declarations, override)
+		}
+	}
+
+	return declarations
+}
+
+func formatDeclarations(declarations []cssDeclaration) string {
+	var formatted []string
+	for _, declaration := range declarations {
+		formatted = append(formatted, declaration.property+": "+declaration.value)
+	}
+
+	return strings.Join(formatted, "; ")
+}
+
+// specificity approximates the CSS specificity of a selector as ids, then
+// classes and attributes, then element names.
+func specificity(selector string) int {
+	ids := strings.Count(selector, "#")
+	classes := strings.Count(selector, ".") + strings.Count(selector, "[")
+
+	elements := 0
+	compounds := strings.FieldsFunc(selector, func(r rune) bool {
+		return r == ' ' || r == '>' || r == '+' || r == '~'
+	})
+	for _, compound := range compounds {
+		switch compound[0] {
+		case '.', '#', '[', '*':
+		default:
+			elements++
+		}
+	}
+
+	return ids*10000 + classes*100 + elements
+}
+
+func appendStyle(document *html.Node, css string) {
+	head := cascadia.MustCompile("head").MatchFirst(document)
+	if head == nil {
+		return
+	}
+
+	style := &html.Node{Type: html.ElementNode, Data: "style"}
+	style.AppendChild(&html.Node{Type: html.TextNode, Data: css})
+	head.AppendChild(style)
+}
@@ -0,0 +1,145 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTMLPipeline", func() {
+	var pipeline common.HTMLPipeline
+
+	BeforeEach(func() {
+		pipeline = common.NewHTMLPipeline(common.HTMLPipelineConfig{
+			BaseURL: "https://www.example.com/apps/",
+		})
+	})
+
+	Describe("Process", func() {
+		Context("when the html has style blocks", func() {
+			It("inlines the rules into style attributes in order of specificity", func() {
+				html, err := pipeline.Process(`<html><head><style>
+					/* brand colors */
+					#title { color: blue }
+					p, h1 { color: black; margin: 0 }
+					.highlight { color: red; font-weight: bold }
+				</style></head><body>
+					<h1 id="title" class="highlight">Hello</h1>
+					<p style="margin: 4px">World</p>
+				</body></html>`)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(html).To(ContainSubstring(`<h1 id="title" class="highlight" style="color: blue; margin: 0; font-weight: bold">Hello</h1>`))
+				Expect(html).To(ContainSubstring(`<p style="color: black; margin: 4px">World</p>`))
+				Expect(html).NotTo(ContainSubstring("brand colors"))
+			})
+
+			It("keeps media queries and pseudo classes in a style block", func() {
+				html, err := pipeline.Process(`<html><head><style>
+					a:hover { color: red }
+					@media (max-width: 600px) { p { font-size: 12px } }
+					p { font-size: 14px }
+				</style></head><body><p>Hello</p></body></html>`)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(html).To(ContainSubstring(`<p style="font-size: 14px">Hello</p>`))
+				Expect(html).To(ContainSubstring("<style>a:hover {color: red}\n@media (max-width: 600px) {p {font-size: 12px}}</style></head>"))
+			})
+
+			It("drops declarations that can run script", func() {
+				html, err := pipeline.Process(`<style>p { width: expression(alert(1)); color: red }</style><p style="background: url(javascript:alert(1))">Hello</p>`)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(html).To(ContainSubstring(`<p style="color: red">Hello</p>`))
+			})
+
+			It("drops declarations that can run script inside at-rules", func() {
+				html, err := pipeline.Process(`<html><head><style>
+					@media screen {
+						p { color: red; background: url(javascript:alert(1)) }
+						@supports (display: grid) { a { behavior: url(evil.htc); display: grid } }
+					}
+					@font-face { font-family: Brand; -moz-binding: url(evil.xml#xss) }
+				</style></head><body><p>Hello</p></body></html>`)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(html).To(ContainSubstring("<style>@media screen {p {color: red} @supports (display: grid) {a {display: grid}}}\n@font-face {font-family: Brand}</style></head>"))
+			})
+
+			It("drops @import rules", func() {
+				html, err := pipeline.Process(`<html><head><style>
+					@import url(https://evil.example.com/track.css);
+					@import "other.css" screen;
+					p { color: red }
+					@media print { @import url(print.css); p { color: black } }
+				</style></head><body><p>Hello</p></body></html>`)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(html).NotTo(ContainSubstring("@import"))
+				Expect(html).To(ContainSubstring(`<p style="color: red">Hello</p>`))
+				Expect(html).To(ContainSubstring("<style>@media print {p {color: black}}</style></head>"))
+			})
+		})
+
+		It("removes scripts, frames and event handlers", func() {
+			html, err := pipeline.Process(`<body onload="steal()">
+				<script>steal()</script>
+				<iframe src="https://evil.example.com"></iframe>
+				<p onclick="steal()">Hello <!-- comment --><a href="javascript:steal()">click</a></p>
+			</body>`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(html).NotTo(ContainSubstring("steal"))
+			Expect(html).NotTo(ContainSubstring("iframe"))
+			Expect(html).NotTo(ContainSubstring("comment"))
+			Expect(html).To(ContainSubstring("<p>Hello <a>click</a></p>"))
+		})
+
+		It("replaces elements that are not allowed with their content", func() {
+			html, err := pipeline.Process(`<form action="/login"><p>Sign <blink>in</blink></p><input name="password"></form>`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(html).To(ContainSubstring("<body><p>Sign in</p></body>"))
+		})
+
+		It("uses the configured allowlist", func() {
+			pipeline = common.NewHTMLPipeline(common.HTMLPipelineConfig{
+				AllowedTags: []string{"p", "Video"},
+			})
+
+			html, err := pipeline.Process(`<p><b>Hello</b></p><video src="https://example.com/intro.mp4"></video>`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(html).To(ContainSubstring(`<body><p>Hello</p><video src="https://example.com/intro.mp4"></video></body>`))
+		})
+
+		It("resolves relative URLs against the base URL", func() {
+			html, err := pipeline.Process(`<a href="status">Status</a>
+				<a href="/docs">Docs</a>
+				<a href="#top">Top</a>
+				<a href="mailto:support@example.com">Support</a>
+				<img src="cid:logo">
+				<img src="data:image/png;base64,iVBORw0KGgo=">
+				<img src="data:text/html;base64,PHNjcmlwdD4=">`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(html).To(ContainSubstring(`<a href="https://www.example.com/apps/status">Status</a>`))
+			Expect(html).To(ContainSubstring(`<a href="https://www.example.com/docs">Docs</a>`))
+			Expect(html).To(ContainSubstring(`<a href="#top">Top</a>`))
+			Expect(html).To(ContainSubstring(`<a href="mailto:support@example.com">Support</a>`))
+			Expect(html).To(ContainSubstring(`<img src="cid:logo"/>`))
+			Expect(html).To(ContainSubstring(`<img src="data:image/png;base64,iVBORw0KGgo="/>`))
+			Expect(html).To(ContainSubstring(`<img/>`))
+		})
+
+		It("leaves relative URLs alone when no base URL is configured", func() {
+			pipeline = common.NewHTMLPipeline(common.HTMLPipelineConfig{})
+
+			html, err := pipeline.Process(`<a href="/docs">Docs</a>`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(html).To(ContainSubstring(`<a href="/docs">Docs</a>`))
+		})
+	})
+})
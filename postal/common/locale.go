@@ -0,0 +1,121 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+type InvalidLocaleError struct {
+	Locale string
+}
+
+func (e InvalidLocaleError) Error() string {
+	return fmt.Sprintf("%q is not a valid BCP 47 language tag", e.Locale)
+}
+
+// CanonicalLocale parses a BCP 47 language tag and returns it in canonical
+// form, so that "en_us" and "EN-US" are both stored as "en-US".
+func CanonicalLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", InvalidLocaleError{Locale: locale}
+	}
+
+	return tag.String(), nil
+}
+
+// MatchLocale picks the available locale that best serves the preferred one.
+// A tag falls back along its chain of parents, so "fr-CA" is served by "fr",
+// and a matcher also knows about closely related languages. It returns false
+// when no available locale is a reasonable match and the default content
+// should be used instead.
+func MatchLocale(preferred string, available []string) (string, bool) {
+	if preferred == "" || len(available) == 0 {
+		return "", false
+	}
+
+	preferredTag, err := language.Parse(preferred)
+	if err != nil {
+		return "", false
+	}
+
+	locales := append([]string{}, available...)
+	sort.Strings(locales)
+
+	tags := []language.Tag{language.Und}
+	var candidates []string
+	for _, locale := range locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+
+		tags = append(tags, tag)
+		candidates = append(candidates, locale)
+	}
+
+	_, index, confidence := language.NewMatcher(tags).Match(preferredTag)
+	if index == 0 || confidence == language.No {
+		return "", false
+	}
+
+	return candidates[index-1], true
+}
+
+// Localize returns the variant of the templates that best matches the
+// locale. Fields the variant leaves empty fall back to the default templates.
+func (templates Templates) Localize(locale string) Templates {
+	var available []string
+	for tag := range templates.Localizations {
+		available = append(available, tag)
+	}
+
+	match, ok := MatchLocale(locale, available)
+	if !ok {
+		return templates
+	}
+
+	localized := templates
+	variant := templates.Localizations[match]
+	if variant.Subject != "" {
+		localized.Subject = variant.Subject
+	}
+	if variant.Text != "" {
+		localized.Text = variant.Text
+	}
+	if variant.HTML != "" {
+		localized.HTML = variant.HTML
+	}
+
+	return localized
+}
+
+// Localize returns the options with the translated content that best
+// matches the locale in place of the content of the notification.
+func (options Options) Localize(locale string) Options {
+	var available []string
+	for tag := range options.Localizations {
+		available = append(available, tag)
+	}
+
+	match, ok := MatchLocale(locale, available)
+	if !ok {
+		return options
+	}
+
+	localized := options
+	localization := options.Localizations[match]
+	if localization.Subject != "" {
+		localized.Subject = localization.Subject
+	}
+	if localization.Text != "" {
+		localized.Text = localization.Text
+	}
+	if localization.HTML.BodyContent != "" {
+		localized.HTML = localization.HTML
+	}
+
+	return localized
+}
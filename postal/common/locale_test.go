@@ -0,0 +1,45 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Locales", func() {
+	Describe("CanonicalLocale", func() {
+		It("returns the canonical form of the tag", func() {
+			locale, err := common.CanonicalLocale("en_us")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locale).To(Equal("en-US"))
+		})
+
+		It("returns an error when the tag is not valid", func() {
+			_, err := common.CanonicalLocale("not a locale")
+			Expect(err).To(MatchError(common.InvalidLocaleError{Locale: "not a locale"}))
+		})
+	})
+
+	Describe("MatchLocale", func() {
+		It("falls back along the chain of parent tags", func() {
+			locale, ok := common.MatchLocale("fr-CA", []string{"de", "fr"})
+			Expect(ok).To(BeTrue())
+			Expect(locale).To(Equal("fr"))
+		})
+
+		It("prefers the closest regional variant", func() {
+			locale, ok := common.MatchLocale("zh-TW", []string{"zh", "zh-Hant"})
+			Expect(ok).To(BeTrue())
+			Expect(locale).To(Equal("zh-Hant"))
+		})
+
+		It("reports when no locale is a reasonable match", func() {
+			_, ok := common.MatchLocale("ja", []string{"en", "fr"})
+			Expect(ok).To(BeFalse())
+
+			_, ok = common.MatchLocale("", []string{"en"})
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,165 @@
+package common
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	markdownHeadingRegexp       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	markdownUnorderedRegexp     = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	markdownOrderedRegexp       = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	markdownBlockquoteRegexp    = regexp.MustCompile(`^>\s?(.*)$`)
+	markdownRuleRegexp          = regexp.MustCompile(`^(?:-\s*){3,}$|^(?:\*\s*){3,}$`)
+	markdownLinkRegexp          = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownStrongRegexp        = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	markdownEmphasisRegexp      = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*`)
+	markdownHardBreakRegexp     = regexp.MustCompile(` {2,}\n`)
+	markdownStrikethroughRegexp = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+)
+
+// Markdown renders the common subset of Markdown that notification authors
+// reach for: headings, paragraphs, lists, blockquotes, fenced code, rules,
+// links, code spans, strong, emphasis and strikethrough. Any HTML in the
+// source is escaped, and links with schemes that mail clients should not
+// follow are rendered as plain text.
+func Markdown(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	var blocks []string
+	for i := 0; i < len(lines); {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "":
+			i++
+
+		case strings.HasPrefix(line, "```"):
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, html.EscapeString(lines[i]))
+			}
+			i++
+			blocks = append(blocks, "<pre><code>"+strings.Join(code, "\n")+"</code></pre>")
+
+		case markdownRuleRegexp.MatchString(line):
+			i++
+			blocks = append(blocks, "<hr>")
+
+		case markdownHeadingRegexp.MatchString(line):
+			matches := markdownHeadingRegexp.FindStringSubmatch(line)
+			level := strconv.Itoa(len(matches[1]))
+			i++
+			blocks = append(blocks, "<h"+level+">"+markdownInline(matches[2])+"</h"+level+">")
+
+		case markdownUnorderedRegexp.MatchString(line):
+			var items []string
+			items, i = markdownListItems(lines, i, markdownUnorderedRegexp)
+			blocks = append(blocks, "<ul>"+strings.Join(items, "")+"</ul>")
+
+		case markdownOrderedRegexp.MatchString(line):
+			var items []string
+			items, i = markdownListItems(lines, i, markdownOrderedRegexp)
+			blocks = append(blocks, "<ol>"+strings.Join(items, "")+"</ol>")
+
+		case markdownBlockquoteRegexp.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && markdownBlockquoteRegexp.MatchString(strings.TrimSpace(lines[i])); i++ {
+				quoted = append(quoted, markdownBlockquoteRegexp.FindStringSubmatch(strings.TrimSpace(lines[i]))[1])
+			}
+			blocks = append(blocks, "<blockquote>"+Markdown(strings.Join(quoted, "\n"))+"</blockquote>")
+
+		default:
+			var paragraph []string
+			for ; i < len(lines) && !markdownStartsBlock(lines[i]); i++ {
+				paragraph = append(paragraph, strings.TrimLeft(lines[i], " \t"))
+			}
+			blocks = append(blocks, "<p>"+markdownInline(strings.TrimRight(strings.Join(paragraph, "\n"), " \t"))+"</p>")
+		}
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+func markdownListItems(lines []string, i int, item *regexp.Regexp) ([]string, int) {
+	var items []string
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			break
+		}
+
+		if matches := item.FindStringSubmatch(line); matches != nil {
+			items = append(items, matches[1])
+			continue
+		}
+
+		if markdownStartsBlock(lines[i]) {
+			break
+		}
+
+		items[len(items)-1] += "\n" + line
+	}
+
+	for j, text := range items {
+		items[j] = "<li>" + markdownInline(text) + "</li>"
+	}
+
+	return items, i
+}
+
+func markdownStartsBlock(line string) bool {
+	line = strings.TrimSpace(line)
+
+	return line == "" ||
+		strings.HasPrefix(line, "```") ||
+		markdownRuleRegexp.MatchString(line) ||
+		markdownHeadingRegexp.MatchString(line) ||
+		markdownUnorderedRegexp.MatchString(line) ||
+		markdownOrderedRegexp.MatchString(line) ||
+		markdownBlockquoteRegexp.MatchString(line)
+}
+
+// markdownInline formats the spans of a block. Code spans are split out first
+// so that nothing inside them is formatted.
+func markdownInline(text string) string {
+	parts := strings.Split(text, "`")
+
+	var inline strings.Builder
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+
+		switch {
+		case i%2 == 1 && i < len(parts)-1:
+			inline.WriteString("<code>" + escaped + "</code>")
+		case i%2 == 1:
+			inline.WriteString("`" + markdownSpans(escaped))
+		default:
+			inline.WriteString(markdownSpans(escaped))
+		}
+	}
+
+	return inline.String()
+}
+
+func markdownSpans(text string) string {
+	text = markdownLinkRegexp.ReplaceAllStringFunc(text, func(link string) string {
+		matches := markdownLinkRegexp.FindStringSubmatch(link)
+
+		target, err := url.Parse(html.UnescapeString(matches[2]))
+		if err != nil || !safeURLSchemes[strings.ToLower(target.Scheme)] {
+			return matches[1]
+		}
+
+		return `<a href="` + matches[2] + `">` + matches[1] + "</a>"
+	})
+
+	text = markdownStrongRegexp.ReplaceAllString(text, "<strong>$1</strong>")
+	text = markdownEmphasisRegexp.ReplaceAllString(text, "<em>$1</em>")
+	text = markdownStrikethroughRegexp.ReplaceAllString(text, "<del>$1</del>")
+
+	return markdownHardBreakRegexp.ReplaceAllString(text, "<br>\n")
+}
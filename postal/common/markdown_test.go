@@ -0,0 +1,38 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Markdown", func() {
+	It("renders headings, paragraphs and lists", func() {
+		html := common.Markdown("# Deploy *finished*\n\nYour apps\nwere deployed:\n\n- api\n- worker\n\n1. Check the logs\n2. Celebrate\n\n---")
+
+		Expect(html).To(Equal("<h1>Deploy <em>finished</em></h1>\n" +
+			"<p>Your apps\nwere deployed:</p>\n" +
+			"<ul><li>api</li><li>worker</li></ul>\n" +
+			"<ol><li>Check the logs</li><li>Celebrate</li></ol>\n" +
+			"<hr>"))
+	})
+
+	It("renders inline formatting and links", func() {
+		html := common.Markdown("Run `cf push *app*` with **care** or ~~not~~ see [the docs](https://docs.example.com/?a=1&b=2)")
+
+		Expect(html).To(Equal(`<p>Run <code>cf push *app*</code> with <strong>care</strong> or <del>not</del> see <a href="https://docs.example.com/?a=1&amp;b=2">the docs</a></p>`))
+	})
+
+	It("renders blockquotes and fenced code", func() {
+		html := common.Markdown("> Heads up\n> it is **late**\n\n```\nif a < b {\n```")
+
+		Expect(html).To(Equal("<blockquote><p>Heads up\nit is <strong>late</strong></p></blockquote>\n<pre><code>if a &lt; b {</code></pre>"))
+	})
+
+	It("escapes html and drops links with unsafe schemes", func() {
+		html := common.Markdown(`<script>steal()</script> [click](javascript:steal)`)
+
+		Expect(html).To(Equal(`<p>&lt;script&gt;steal()&lt;/script&gt; click</p>`))
+	})
+})
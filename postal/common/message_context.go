@@ -1,7 +1,7 @@
 package common
 
 import (
-	"html"
+	htmltemplate "html/template"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
@@ -20,6 +20,25 @@ type Options struct {
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	Data              map[string]interface{}
+	Attachments       []Attachment
+	Localizations     map[string]Localization
+}
+
+// Localization holds translated content supplied with a notification. Fields
+// that are left empty keep the content of the notification itself.
+type Localization struct {
+	Subject string
+	Text    string
+	HTML    HTML
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	Inline      bool
+	ContentID   string
 }
 
 type Delivery struct {
@@ -35,13 +54,15 @@ type Delivery struct {
 	VCAPRequestID   string
 	RequestReceived time.Time
 	CampaignID      string
+	Locale          string
 }
 
 type Templates struct {
-	Name    string
-	Subject string
-	Text    string
-	HTML    string
+	Name          string
+	Subject       string
+	Text          string
+	HTML          string
+	Localizations map[string]Templates
 }
 
 type HTML struct {
@@ -77,6 +98,9 @@ type MessageContext struct {
 	OrganizationRole  string
 	RequestReceived   time.Time
 	Domain            string
+	Data              map[string]interface{}
+	Attachments       []Attachment
+	Critical          bool
 }
 
 func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.CloakInterface, templates Templates) MessageContext {
@@ -121,6 +145,8 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		OrganizationRole:  options.Role,
 		RequestReceived:   delivery.RequestReceived,
 		Domain:            domain,
+		Data:              options.Data,
+		Attachments:       options.Attachments,
 	}
 
 	if messageContext.Subject == "" {
@@ -136,17 +162,31 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 	return messageContext
 }
 
-func (context *MessageContext) Escape() {
-	context.From = html.EscapeString(context.From)
-	context.To = html.EscapeString(context.To)
-	context.ReplyTo = html.EscapeString(context.ReplyTo)
-	context.Subject = html.EscapeString(context.Subject)
-	context.Text = html.EscapeString(context.Text)
-	context.KindDescription = html.EscapeString(context.KindDescription)
-	context.SourceDescription = html.EscapeString(context.SourceDescription)
-	context.ClientID = html.EscapeString(context.ClientID)
-	context.MessageID = html.EscapeString(context.MessageID)
-	context.Space = html.EscapeString(context.Space)
-	context.Organization = html.EscapeString(context.Organization)
-	context.Endorsement = html.EscapeString(context.Endorsement)
+// htmlMessageContext is what HTML templates are executed against. The
+// notification HTML is trusted and marked as such, every other value is
+// escaped by html/template for the context it appears in.
+type htmlMessageContext struct {
+	MessageContext
+	HTML           htmltemplate.HTML
+	HTMLComponents htmlComponents
+}
+
+type htmlComponents struct {
+	BodyContent    htmltemplate.HTML
+	BodyAttributes htmltemplate.HTMLAttr
+	Head           htmltemplate.HTML
+	Doctype        htmltemplate.HTML
+}
+
+func newHTMLMessageContext(context MessageContext) htmlMessageContext {
+	return htmlMessageContext{
+		MessageContext: context,
+		HTML:           htmltemplate.HTML(context.HTML),
+		HTMLComponents: htmlComponents{
+			BodyContent:    htmltemplate.HTML(context.HTMLComponents.BodyContent),
+			BodyAttributes: htmltemplate.HTMLAttr(context.HTMLComponents.BodyAttributes),
+			Head:           htmltemplate.HTML(context.HTMLComponents.Head),
+			Doctype:        htmltemplate.HTML(context.HTMLComponents.Doctype),
+		},
+	}
 }
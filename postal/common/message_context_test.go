@@ -104,6 +104,26 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.Domain).To(Equal(domain))
 		})
 
+		It("carries the template data from the options", func() {
+			delivery.Options.Data = map[string]interface{}{
+				"app_name": "my-app",
+			}
+
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+			Expect(context.Data).To(Equal(map[string]interface{}{
+				"app_name": "my-app",
+			}))
+		})
+
+		It("carries the attachments from the options", func() {
+			delivery.Options.Attachments = []common.Attachment{
+				{Filename: "invoice.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4")},
+			}
+
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+			Expect(context.Attachments).To(Equal(delivery.Options.Attachments))
+		})
+
 		It("falls back to Kind if KindDescription is missing", func() {
 			delivery.Options.KindDescription = ""
 			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
@@ -124,52 +144,4 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.Subject).To(Equal("[no subject]"))
 		})
 	})
-
-	Describe("Escape", func() {
-		BeforeEach(func() {
-			options = common.Options{
-				ReplyTo:           "awesomeness",
-				Subject:           "the & subject",
-				KindDescription:   "the & kind description",
-				SourceDescription: "the & source description",
-				Text:              "user & supplied email text",
-				HTML:              common.HTML{BodyContent: "user & supplied html"},
-				KindID:            "the & kind",
-				Endorsement:       "this & is the endorsement",
-				Role:              "OrgRole",
-			}
-
-			delivery.Options = options
-			delivery.ClientID = "the\"client id"
-			delivery.MessageID = "some>id"
-			delivery.Space.Name = "the<space"
-			delivery.Organization.Name = "the>org"
-			delivery.Scope = ""
-		})
-
-		It("html escapes various fields on the message context", func() {
-			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
-			context.Escape()
-
-			Expect(context.From).To(Equal("no-reply@notifications.example.com"))
-			Expect(context.ReplyTo).To(Equal("awesomeness"))
-			Expect(context.To).To(Equal("bounce@example.com"))
-			Expect(context.Subject).To(Equal("the &amp; subject"))
-			Expect(context.Text).To(Equal("user &amp; supplied email text"))
-			Expect(context.HTML).To(Equal("user & supplied html"))
-			Expect(context.TextTemplate).To(Equal("the plainText email < template"))
-			Expect(context.HTMLTemplate).To(Equal("the html <h1> email < template</h1>"))
-			Expect(context.SubjectTemplate).To(Equal("the subject < template"))
-			Expect(context.KindDescription).To(Equal("the &amp; kind description"))
-			Expect(context.SourceDescription).To(Equal("the &amp; source description"))
-			Expect(context.UserGUID).To(Equal("the-user"))
-			Expect(context.ClientID).To(Equal("the&#34;client id"))
-			Expect(context.MessageID).To(Equal("some&gt;id"))
-			Expect(context.Space).To(Equal("the&lt;space"))
-			Expect(context.Organization).To(Equal("the&gt;org"))
-			Expect(context.Scope).To(Equal(""))
-			Expect(context.Endorsement).To(Equal("this &amp; is the endorsement"))
-			Expect(context.OrganizationRole).To(Equal("OrgRole"))
-		})
-	})
 })
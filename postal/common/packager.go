@@ -3,6 +3,7 @@ package common
 import (
 	"bytes"
 	"fmt"
+	htmltemplate "html/template"
 	"strings"
 	"text/template"
 	"time"
@@ -12,8 +13,8 @@ import (
 )
 
 const HTMLWrapperTemplate = `{{.HTMLComponents.Doctype}}
-<head>{{.HTMLComponents.Head}}</head>
 <html>
+	<head>{{.HTMLComponents.Head}}</head>
 	<body {{.HTMLComponents.BodyAttributes}}>
 		{{.HTMLComponents.BodyContent}}
 	</body>
@@ -23,15 +24,23 @@ type templatesLoader interface {
 	LoadTemplates(clientID, kindID, templateID string) (Templates, error)
 }
 
+type htmlProcessor interface {
+	Process(html string) (string, error)
+}
+
 type Packager struct {
-	templates templatesLoader
-	cloak     conceal.CloakInterface
+	templates     templatesLoader
+	cloak         conceal.CloakInterface
+	publicURL     string
+	htmlProcessor htmlProcessor
 }
 
-func NewPackager(templates templatesLoader, cloak conceal.CloakInterface) Packager {
+func NewPackager(templates templatesLoader, cloak conceal.CloakInterface, publicURL string, htmlProcessor htmlProcessor) Packager {
 	return Packager{
-		templates: templates,
-		cloak:     cloak,
+		templates:     templates,
+		cloak:         cloak,
+		publicURL:     strings.TrimSuffix(publicURL, "/"),
+		htmlProcessor: htmlProcessor,
 	}
 }
 
@@ -41,7 +50,9 @@ func (packager Packager) PrepareContext(delivery Delivery, sender, domain string
 		return MessageContext{}, err
 	}
 
-	return NewMessageContext(delivery, sender, domain, packager.cloak, templates), nil
+	delivery.Options = delivery.Options.Localize(delivery.Locale)
+
+	return NewMessageContext(delivery, sender, domain, packager.cloak, templates.Localize(delivery.Locale)), nil
 }
 
 func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
@@ -55,18 +66,42 @@ func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
 		return mail.Message{}, err
 	}
 
+	headers := []string{
+		fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
+		fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
+		fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
+		fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
+	}
+
+	// Every context carries an unsubscribe token, but only users can be
+	// unsubscribed, so emails sent straight to an address get no link.
+	if packager.publicURL != "" && context.UserGUID != "" && context.UnsubscribeID != "" && !context.Critical {
+		headers = append(headers,
+			fmt.Sprintf("List-Unsubscribe: <%s/unsubscribe/%s>", packager.publicURL, context.UnsubscribeID),
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		)
+	}
+
+	var attachments []mail.Attachment
+	for _, attachment := range context.Attachments {
+		attachments = append(attachments, mail.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+			Inline:      attachment.Inline,
+			ContentID:   attachment.ContentID,
+		})
+	}
+
 	return mail.Message{
-		From:    context.From,
-		ReplyTo: context.ReplyTo,
-		To:      context.To,
-		Subject: compiledSubject,
-		Body:    parts,
-		Headers: []string{
-			fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
-			fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
-			fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
-			fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
-		},
+		From:        context.From,
+		ReplyTo:     context.ReplyTo,
+		To:          context.To,
+		Subject:     compiledSubject,
+		MessageID:   mail.NewMessageID(context.MessageID, context.Domain),
+		Body:        parts,
+		Headers:     headers,
+		Attachments: attachments,
 	}, nil
 }
 
@@ -105,6 +140,11 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 			return parts, err
 		}
 
+		htmlPart, err = packager.htmlProcessor.Process(htmlPart)
+		if err != nil {
+			return parts, err
+		}
+
 		parts = append(parts, mail.Part{
 			ContentType: "text/html",
 			Content:     htmlPart,
@@ -114,20 +154,36 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	return parts, nil
 }
 
-func (packager Packager) compileTemplate(context MessageContext, theTemplate string, escapeContext bool) (string, error) {
-	buffer := bytes.NewBuffer([]byte{})
-
-	source, err := template.New("compileTemplate").Parse(theTemplate)
+func (packager Packager) compileTemplate(context MessageContext, theTemplate string, html bool) (string, error) {
+	compiledTemplate, err := renderTemplate("compileTemplate", theTemplate, context, html)
 	if err != nil {
 		return "", err
 	}
 
-	if escapeContext {
-		context.Escape()
+	return strings.TrimSuffix(compiledTemplate, "\n"), nil
+}
+
+// renderTemplate executes a template with the helper functions available.
+// HTML templates are executed by html/template, so values are escaped for
+// where they appear in the markup and the notification HTML is left as is.
+func renderTemplate(name, source string, context MessageContext, html bool) (string, error) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	if html {
+		tmpl, err := htmltemplate.New(name).Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return "", err
+		}
+
+		err = tmpl.Execute(buffer, newHTMLMessageContext(context))
+		return buffer.String(), err
 	}
 
-	source.Execute(buffer, context)
-	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return "", err
+	}
 
-	return compiledTemplate, nil
+	err = tmpl.Execute(buffer, context)
+	return buffer.String(), err
 }
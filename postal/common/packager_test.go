@@ -43,7 +43,7 @@ var _ = Describe("Packager", func() {
 			},
 		}
 
-		packager = common.NewPackager(templatesLoader, cloak)
+		packager = common.NewPackager(templatesLoader, cloak, "https://notifications.example.com/", common.NewHTMLPipeline(common.HTMLPipelineConfig{}))
 
 		requestReceivedTime, _ := time.Parse(time.RFC3339Nano, "2015-06-08T14:38:03.180764129-07:00")
 
@@ -54,6 +54,7 @@ var _ = Describe("Packager", func() {
 			Subject:   "we will be eaten",
 			ClientID:  "3&3",
 			MessageID: "4'4",
+			Domain:    "example.com",
 			Text:      "User <supplied> \"banana\" text",
 			UserGUID:  "user-123",
 			HTMLComponents: common.HTML{
@@ -119,6 +120,48 @@ var _ = Describe("Packager", func() {
 			}))
 		})
 
+		Context("when the recipient has a preferred locale", func() {
+			BeforeEach(func() {
+				templatesLoader.LoadTemplatesCall.Returns.Templates.Localizations = map[string]common.Templates{
+					"fr": {
+						Subject: "sujet: {{.Subject}}",
+						Text:    "Du {{.Text}} texte",
+					},
+					"de": {
+						Subject: "Betreff: {{.Subject}}",
+					},
+				}
+
+				delivery.Options.Localizations = map[string]common.Localization{
+					"fr-CA": {Subject: "Un sujet fou"},
+				}
+				delivery.Locale = "fr-CA"
+			})
+
+			It("uses the template variant and content that best match the locale", func() {
+				var err error
+				context, err = packager.PrepareContext(delivery, "some-sender@example.com", "example.com")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(context.SubjectTemplate).To(Equal("sujet: {{.Subject}}"))
+				Expect(context.TextTemplate).To(Equal("Du {{.Text}} texte"))
+				Expect(context.HTMLTemplate).To(Equal("<h1>{{.HTML}}</h1>"))
+				Expect(context.Subject).To(Equal("Un sujet fou"))
+				Expect(context.Text).To(Equal("some-text"))
+			})
+
+			It("uses the default template and content when nothing matches", func() {
+				delivery.Locale = "ja"
+
+				var err error
+				context, err = packager.PrepareContext(delivery, "some-sender@example.com", "example.com")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(context.SubjectTemplate).To(Equal("subject template: {{.Subject}}"))
+				Expect(context.Subject).To(Equal("Some crazy subject"))
+			})
+		})
+
 		Context("when the template cannot be loaded", func() {
 			It("returns an error", func() {
 				templatesLoader.LoadTemplatesCall.Returns.Error = errors.New("some error")
@@ -137,6 +180,7 @@ var _ = Describe("Packager", func() {
 			Expect(msg.ReplyTo).To(Equal("awesomeness"))
 			Expect(msg.To).To(Equal("endless monkeys"))
 			Expect(msg.Subject).To(Equal("The Subject: we will be eaten"))
+			Expect(msg.MessageID).To(Equal("<4'4@example.com>"))
 			Expect(msg.Body).To(ConsistOf([]mail.Part{
 				{
 					ContentType: "text/plain",
@@ -144,7 +188,7 @@ var _ = Describe("Packager", func() {
 				},
 				{
 					ContentType: "text/html",
-					Content:     "<!DOCTYPE html>\n<head><title>The title</title></head>\n<html>\n\t<body class=\"bananaBody\">\n\t\t<header>This is an endorsement for the development space and banana org.</header>\nBanana preamble <p>user supplied banana html</p> User &lt;supplied&gt; &#34;banana&#34; text 3&amp;3 4&#39;4 user-123\n\t</body>\n</html>",
+					Content:     "<!DOCTYPE html><html><head><title>The title</title></head>\n\t<body class=\"bananaBody\">\n\t\t<header>This is an endorsement for the development space and banana org.</header>\nBanana preamble <p>user supplied banana html</p> User &lt;supplied&gt; &#34;banana&#34; text 3&amp;3 4&#39;4 user-123\n\t\n</body></html>",
 				},
 			}))
 			Expect(msg.Headers).To(ContainElement("X-CF-Client-ID: 3&3"))
@@ -165,6 +209,61 @@ var _ = Describe("Packager", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(timestamp).To(BeTemporally("~", time.Now(), 2*time.Second))
 		})
+
+		It("includes the attachments", func() {
+			context.Attachments = []common.Attachment{
+				{Filename: "logo.png", ContentType: "image/png", Content: []byte("png bytes"), Inline: true, ContentID: "logo"},
+			}
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Attachments).To(Equal([]mail.Attachment{
+				{Filename: "logo.png", ContentType: "image/png", Content: []byte("png bytes"), Inline: true, ContentID: "logo"},
+			}))
+		})
+
+		Context("when the message can be unsubscribed from", func() {
+			BeforeEach(func() {
+				context.UnsubscribeID = "some-unsubscribe-token"
+			})
+
+			It("includes the one-click List-Unsubscribe headers", func() {
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg.Headers).To(ContainElement("List-Unsubscribe: <https://notifications.example.com/unsubscribe/some-unsubscribe-token>"))
+				Expect(msg.Headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+			})
+
+			It("omits the headers for critical notifications", func() {
+				context.Critical = true
+
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				for _, header := range msg.Headers {
+					Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+				}
+			})
+
+			It("omits the headers for emails sent to an address rather than a user", func() {
+				context.UserGUID = ""
+
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				for _, header := range msg.Headers {
+					Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+				}
+			})
+
+			It("omits the headers when no public URL is configured", func() {
+				packager = common.NewPackager(templatesLoader, cloak, "", common.NewHTMLPipeline(common.HTMLPipelineConfig{}))
+
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				for _, header := range msg.Headers {
+					Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+				}
+			})
+		})
 	})
 
 	Describe("CompileParts", func() {
@@ -176,14 +275,12 @@ var _ = Describe("Packager", func() {
 
 			textBody := `Banana preamble User <supplied> "banana" text 3&3 4'4 user-123
 This is an endorsement for the development space and banana org.`
-			htmlBody := `<!DOCTYPE html>
-<head><title>The title</title></head>
-<html>
+			htmlBody := `<!DOCTYPE html><html><head><title>The title</title></head>
 	<body class="bananaBody">
 		<header>This is an endorsement for the development space and banana org.</header>
 Banana preamble <p>user supplied banana html</p> User &lt;supplied&gt; &#34;banana&#34; text 3&amp;3 4&#39;4 user-123
-	</body>
-</html>`
+	
+</body></html>`
 
 			Expect(parts).To(ContainElement(mail.Part{
 				ContentType: "text/plain",
@@ -195,6 +292,80 @@ Banana preamble <p>user supplied banana html</p> User &lt;supplied&gt; &#34;bana
 			}))
 		})
 
+		Context("when template data is provided", func() {
+			It("exposes the data to the templates, escaping it for the html portion only", func() {
+				context.Data = map[string]interface{}{
+					"app_name": "<my-app>",
+				}
+				context.TextTemplate = "The app {{.Data.app_name}} is down"
+				context.HTMLTemplate = "<p>The app {{.Data.app_name}} is down</p>"
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parts).To(HaveLen(2))
+				Expect(parts[0].Content).To(Equal("The app <my-app> is down"))
+				Expect(parts[1].Content).To(ContainSubstring("<p>The app &lt;my-app&gt; is down</p>"))
+			})
+		})
+
+		Context("when the templates use the helper functions", func() {
+			It("makes the helpers available to the subject, text and html templates", func() {
+				context.Data = map[string]interface{}{
+					"apps":  []interface{}{"api", "worker"},
+					"notes": "Deploys are **paused**",
+				}
+				context.SubjectTemplate = `{{.Subject | title}}`
+				context.TextTemplate = `{{.RequestReceived | inZone "Europe/Paris" | formatTime "Jan 2, 2006 15:04 MST"}}: {{len .Data.apps}} {{pluralize "app" "apps" .Data.apps}} in {{.Space | upper}} for {{.Data.owner | default "nobody"}}`
+				context.HTMLTemplate = `<a href="https://{{.Domain}}/apps?space={{.Space | queryEscape}}">{{.Data.notes | markdown}}</a>`
+
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(msg.Subject).To(Equal("We Will Be Eaten"))
+				Expect(msg.Body[0].Content).To(Equal("Jun 8, 2015 23:38 CEST: 2 apps in DEVELOPMENT for nobody"))
+				Expect(msg.Body[1].Content).To(ContainSubstring(`<a href="https://example.com/apps?space=development"><p>Deploys are <strong>paused</strong></p></a>`))
+			})
+
+			It("escapes values for where they appear in the html", func() {
+				context.Data = map[string]interface{}{
+					"link":  "javascript:steal()",
+					"title": `" onmouseover="steal()`,
+				}
+				context.HTMLTemplate = `<a href="{{.Data.link}}" title="{{.Data.title}}">{{.HTML}}</a>`
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parts[1].Content).To(ContainSubstring(`<a href="#ZgotmplZ" title="&#34; onmouseover=&#34;steal()"><p>user supplied banana html</p></a>`))
+			})
+
+			It("returns an error when a template cannot be evaluated", func() {
+				context.SubjectTemplate = `Sent {{.Subject | formatTime "Kitchen"}}`
+
+				_, err := packager.Pack(context)
+				Expect(err).To(MatchError(ContainSubstring(`"we will be eaten" is not an RFC 3339 time`)))
+			})
+
+			It("returns an error when the html cannot be escaped", func() {
+				context.HTMLTemplate = `{{if .Text}}<a href="{{end}}">`
+
+				_, err := packager.CompileParts(context)
+				Expect(err).To(MatchError(ContainSubstring("{{if}} branches end in different contexts")))
+			})
+		})
+
+		It("runs the compiled html through the html pipeline", func() {
+			context.HTMLComponents.Head = "<style>p { color: red }</style>"
+			context.HTMLTemplate = `<p onclick="steal()">{{.HTML}}</p><script>steal()</script>`
+
+			parts, err := packager.CompileParts(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parts[1].Content).To(ContainSubstring(`<p style="color: red">`))
+			Expect(parts[1].Content).NotTo(ContainSubstring("steal()"))
+		})
+
 		Context("when no html is set", func() {
 			It("only sends a plaintext of the email", func() {
 				context.HTML = ""
@@ -224,14 +395,12 @@ This is an endorsement for the development space and banana org.`
 					panic(err)
 				}
 
-				htmlBody := `<!DOCTYPE html>
-<head><title>The title</title></head>
-<html>
+				htmlBody := `<!DOCTYPE html><html><head><title>The title</title></head>
 	<body class="bananaBody">
 		<header>This is an endorsement for the development space and banana org.</header>
 Banana preamble <p>user supplied banana html</p>  3&amp;3 4&#39;4 user-123
-	</body>
-</html>`
+	
+</body></html>`
 				Expect(parts).To(ConsistOf([]mail.Part{
 					{
 						ContentType: "text/html",
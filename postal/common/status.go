@@ -6,4 +6,5 @@ const (
 	StatusDelivered     = "delivered"
 	StatusQueued        = "queued"
 	StatusUndeliverable = "undeliverable"
+	StatusCanceled      = "canceled"
 )
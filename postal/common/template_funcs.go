@@ -0,0 +1,221 @@
+package common
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	_ "time/tzdata" // zones resolve on hosts without a zoneinfo database
+	"unicode"
+	"unicode/utf8"
+)
+
+var namedTimeLayouts = map[string]string{
+	"RFC822":   time.RFC822,
+	"RFC1123":  time.RFC1123,
+	"RFC3339":  time.RFC3339,
+	"Kitchen":  time.Kitchen,
+	"DateTime": "2006-01-02 15:04:05",
+	"DateOnly": "2006-01-02",
+	"TimeOnly": "15:04:05",
+}
+
+// templateFuncs are the helpers available to subject, text and HTML
+// templates. Values that are missing, such as absent keys of .Data, are
+// treated as empty rather than failing the template, and the value a helper
+// works on is always its last argument so that helpers can be chained in a
+// pipeline:
+//
+//	{{.RequestReceived | inZone "Europe/Paris" | formatTime "Jan 2, 2006 15:04 MST"}}
+//	{{.Data.app_name | default "your app" | upper}}
+var templateFuncs = template.FuncMap{
+	// Dates and times
+	"formatTime": formatTime,
+	"inZone":     inZone,
+
+	// Strings
+	"upper":     func(value interface{}) string { return strings.ToUpper(toString(value)) },
+	"lower":     func(value interface{}) string { return strings.ToLower(toString(value)) },
+	"title":     title,
+	"trim":      func(value interface{}) string { return strings.TrimSpace(toString(value)) },
+	"truncate":  truncate,
+	"pluralize": pluralize,
+
+	// Defaults and conditionals
+	"default":  defaultValue,
+	"coalesce": coalesce,
+	"empty":    func(value interface{}) bool { return !truth(value) },
+	"ternary":  ternary,
+
+	// URLs
+	"queryEscape": func(value interface{}) string { return url.QueryEscape(toString(value)) },
+	"pathEscape":  func(value interface{}) string { return url.PathEscape(toString(value)) },
+
+	// Markup
+	"markdown": func(value interface{}) htmltemplate.HTML { return htmltemplate.HTML(Markdown(toString(value))) },
+}
+
+// formatTime formats a time.Time or an RFC 3339 string with a Go reference
+// layout or one of the names in namedTimeLayouts.
+func formatTime(layout string, value interface{}) (string, error) {
+	t, err := toTime(value)
+	if err != nil || t.IsZero() {
+		return "", err
+	}
+
+	if named, ok := namedTimeLayouts[layout]; ok {
+		layout = named
+	}
+
+	return t.Format(layout), nil
+}
+
+// inZone converts a time to the named IANA time zone, such as
+// "America/New_York".
+func inZone(zone string, value interface{}) (time.Time, error) {
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := toTime(value)
+	if err != nil || t.IsZero() {
+		return time.Time{}, err
+	}
+
+	return t.In(location), nil
+}
+
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	case string:
+		if v == "" {
+			return time.Time{}, nil
+		}
+
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time", v)
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot use %T as a time", value)
+}
+
+// title capitalizes the first letter of every word, leaving the rest of the
+// word as it is.
+func title(value interface{}) string {
+	var titled strings.Builder
+	previous := ' '
+	for _, r := range toString(value) {
+		if unicode.IsSpace(previous) || previous == '-' {
+			titled.WriteRune(unicode.ToTitle(r))
+		} else {
+			titled.WriteRune(r)
+		}
+		previous = r
+	}
+
+	return titled.String()
+}
+
+// truncate shortens a string to at most length characters, ending it with an
+// ellipsis when anything was cut.
+func truncate(length int, value interface{}) string {
+	s := toString(value)
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+
+	if length <= 0 {
+		return ""
+	}
+
+	runes := []rune(s)
+	return strings.TrimRightFunc(string(runes[:length-1]), unicode.IsSpace) + "…"
+}
+
+// pluralize picks the singular form when count is one and the plural form
+// otherwise. The count may be any number or a collection, whose length is
+// used.
+func pluralize(singular, plural string, count interface{}) string {
+	if toNumber(count) == 1 {
+		return singular
+	}
+
+	return plural
+}
+
+func toNumber(value interface{}) float64 {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return float64(v.Len())
+	}
+
+	return 0
+}
+
+// defaultValue returns value unless it is empty, in which case it returns
+// fallback. Empty means nil, false, zero, or an empty string or collection.
+func defaultValue(fallback, value interface{}) interface{} {
+	if truth(value) {
+		return value
+	}
+
+	return fallback
+}
+
+// coalesce returns the first of its arguments that is not empty.
+func coalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if truth(value) {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// ternary returns ifTrue when the condition is not empty and ifFalse
+// otherwise.
+func ternary(ifTrue, ifFalse, condition interface{}) interface{} {
+	if truth(condition) {
+		return ifTrue
+	}
+
+	return ifFalse
+}
+
+func truth(value interface{}) bool {
+	truth, _ := template.IsTrue(value)
+	return truth
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case htmltemplate.HTML:
+		return string(v)
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,425 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+var (
+	parseErrorRegexp  = regexp.MustCompile(`^template: [^:]*:(\d+): (.*)$`)
+	execErrorRegexp   = regexp.MustCompile(`^template: [^:]*:(\d+):(\d+): executing "[^"]*" at (.*)$`)
+	escapeErrorRegexp = regexp.MustCompile(`^html/template:[^:]*:(\d+):(\d+): (.*)$`)
+)
+
+type TemplateError struct {
+	Field   string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e TemplateError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Field, e.Line, e.Column, e.Message)
+}
+
+type TemplateValidationError struct {
+	Errors []TemplateError
+}
+
+func (e TemplateValidationError) Error() string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// TemplateValidator checks templates with the engine the Packager uses: each
+// field is parsed with the helper functions, every field reference is
+// resolved against MessageContext and the template is executed against a
+// sample context, escaping the HTML field as html/template would.
+type TemplateValidator struct{}
+
+func (validator TemplateValidator) Validate(templates Templates) ([]string, error) {
+	fields := []struct {
+		name     string
+		source   string
+		html     bool
+		expected []string
+	}{
+		{"subject", templates.Subject, false, []string{"Subject"}},
+		{"text", templates.Text, false, []string{"Text", "Endorsement"}},
+		{"html", templates.HTML, true, []string{"HTML", "Endorsement"}},
+	}
+
+	var errs []TemplateError
+	var warnings []string
+	for _, field := range fields {
+		references, fieldErrs := validator.validateField(field.name, field.source, field.html)
+		if len(fieldErrs) > 0 {
+			errs = append(errs, fieldErrs...)
+			continue
+		}
+
+		if strings.TrimSpace(field.source) == "" {
+			continue
+		}
+
+		for _, name := range field.expected {
+			if !references[name] {
+				warnings = append(warnings, fmt.Sprintf("%s template does not include {{.%s}}", field.name, name))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, TemplateValidationError{errs}
+	}
+
+	return warnings, nil
+}
+
+func (validator TemplateValidator) validateField(name, source string, html bool) (map[string]bool, []TemplateError) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return nil, []TemplateError{parseError(name, source, err)}
+	}
+
+	checker := &fieldChecker{
+		name:          name,
+		source:        source,
+		root:          reflect.TypeOf(MessageContext{}),
+		references:    map[string]bool{},
+		dataNodes:     map[int]bool{},
+		dataVariables: map[string]bool{},
+	}
+	if tmpl.Tree != nil {
+		checker.walk(tmpl.Tree.Root, checker.root)
+	}
+
+	if len(checker.errors) > 0 {
+		return nil, checker.errors
+	}
+
+	// The sample context has no Data, so a failure at a node that works on a
+	// value reached through Data says nothing about the template.
+	_, err = renderTemplate(name, source, sampleMessageContext(), html)
+	if err != nil {
+		templateErr := execError(name, err)
+		if !checker.dataNodes[offset(source, templateErr.Line, templateErr.Column)] {
+			return nil, []TemplateError{templateErr}
+		}
+	}
+
+	return checker.references, nil
+}
+
+// The parser only reports the line of an error, so the column is that of the
+// first action on the line that reproduces the error when the template is cut
+// off right after it.
+func parseError(name, source string, err error) TemplateError {
+	templateErr := TemplateError{
+		Field:   name,
+		Line:    1,
+		Column:  1,
+		Message: err.Error(),
+	}
+
+	matches := parseErrorRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return templateErr
+	}
+
+	templateErr.Line, _ = strconv.Atoi(matches[1])
+	templateErr.Message = matches[2]
+
+	lineStart := 0
+	for i := 1; i < templateErr.Line; i++ {
+		next := strings.Index(source[lineStart:], "\n")
+		if next < 0 {
+			return templateErr
+		}
+		lineStart += next + 1
+	}
+
+	lineEnd := len(source)
+	if next := strings.Index(source[lineStart:], "\n"); next >= 0 {
+		lineEnd = lineStart + next
+	}
+
+	offset := lineStart
+	for {
+		start := strings.Index(source[offset:lineEnd], "{{")
+		if start < 0 {
+			return templateErr
+		}
+		start += offset
+
+		end := lineEnd
+		if closing := strings.Index(source[start:lineEnd], "}}"); closing >= 0 {
+			end = start + closing + 2
+		}
+
+		_, prefixErr := template.New(name).Funcs(templateFuncs).Parse(source[:end])
+		if prefixErr != nil && prefixErr.Error() == err.Error() {
+			templateErr.Column = start - lineStart + 1
+			return templateErr
+		}
+
+		offset = end
+	}
+}
+
+func offset(source string, line, column int) int {
+	lineStart := 0
+	for i := 1; i < line; i++ {
+		next := strings.Index(source[lineStart:], "\n")
+		if next < 0 {
+			return -1
+		}
+		lineStart += next + 1
+	}
+
+	return lineStart + column - 1
+}
+
+func execError(name string, err error) TemplateError {
+	matches := execErrorRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		matches = escapeErrorRegexp.FindStringSubmatch(err.Error())
+	}
+
+	if matches == nil {
+		return TemplateError{Field: name, Line: 1, Column: 1, Message: err.Error()}
+	}
+
+	line, _ := strconv.Atoi(matches[1])
+	column, _ := strconv.Atoi(matches[2])
+
+	return TemplateError{
+		Field:   name,
+		Line:    line,
+		Column:  column + 1,
+		Message: matches[3],
+	}
+}
+
+// fieldChecker walks a parse tree keeping track of the type of dot. Once the
+// type is unknown, references are no longer checked. Values reached through
+// Data have the type dataType, and the positions of the nodes that work on
+// them are kept in dataNodes.
+type fieldChecker struct {
+	name          string
+	source        string
+	root          reflect.Type
+	references    map[string]bool
+	dataNodes     map[int]bool
+	dataVariables map[string]bool
+	errors        []TemplateError
+}
+
+// dataType stands for any value reached through Data, whose type is only
+// known once a notification is sent.
+var dataType = reflect.TypeOf(struct{ data bool }{})
+
+func (c *fieldChecker) walk(node parse.Node, dot reflect.Type) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		c.pipe(n.Pipe, dot)
+	case *parse.IfNode:
+		c.pipe(n.Pipe, dot)
+		c.walk(n.List, dot)
+		c.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		c.walk(n.List, c.pipe(n.Pipe, dot))
+		c.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		c.walk(n.List, elementType(c.pipe(n.Pipe, dot)))
+		c.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			c.pipe(n.Pipe, dot)
+		}
+	}
+}
+
+func (c *fieldChecker) pipe(pipe *parse.PipeNode, dot reflect.Type) reflect.Type {
+	var result reflect.Type
+	for _, command := range pipe.Cmds {
+		fromData := result == dataType
+
+		result = nil
+		for _, arg := range command.Args {
+			result = c.arg(arg, dot)
+			fromData = fromData || result == dataType
+		}
+
+		if len(command.Args) > 1 {
+			result = nil
+		}
+
+		if fromData {
+			c.dataNodes[int(command.Position())] = true
+			result = dataType
+		}
+	}
+
+	if result == dataType {
+		for _, variable := range pipe.Decl {
+			c.dataVariables[variable.Ident[0]] = true
+		}
+	}
+
+	return result
+}
+
+func (c *fieldChecker) arg(node parse.Node, dot reflect.Type) reflect.Type {
+	typ := c.argType(node, dot)
+	if typ == dataType {
+		c.dataNodes[int(node.Position())] = true
+	}
+
+	return typ
+}
+
+func (c *fieldChecker) argType(node parse.Node, dot reflect.Type) reflect.Type {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return c.resolve(n, dot, n.Ident)
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" {
+			return c.resolve(n, c.root, n.Ident[1:])
+		}
+		if c.dataVariables[n.Ident[0]] {
+			return dataType
+		}
+	case *parse.ChainNode:
+		return c.resolve(n, c.arg(n.Node, dot), n.Field)
+	case *parse.PipeNode:
+		return c.pipe(n, dot)
+	}
+
+	return nil
+}
+
+func (c *fieldChecker) resolve(node parse.Node, typ reflect.Type, idents []string) reflect.Type {
+	for _, ident := range idents {
+		if typ == nil || typ == dataType {
+			return typ
+		}
+
+		if typ == c.root {
+			c.references[ident] = true
+
+			if ident == "Data" {
+				typ = dataType
+				continue
+			}
+		}
+
+		if method, ok := reflect.PtrTo(typ).MethodByName(ident); ok {
+			if method.Type.NumOut() == 0 {
+				return nil
+			}
+			typ = method.Type.Out(0)
+			continue
+		}
+
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Interface:
+			return nil
+		case reflect.Map:
+			typ = typ.Elem()
+			continue
+		case reflect.Struct:
+			if field, ok := typ.FieldByName(ident); ok && field.PkgPath == "" {
+				typ = field.Type
+				continue
+			}
+		}
+
+		c.errors = append(c.errors, c.errorAt(node, fmt.Sprintf("can't evaluate field %s in type %s", ident, typ)))
+		return nil
+	}
+
+	return typ
+}
+
+func (c *fieldChecker) errorAt(node parse.Node, message string) TemplateError {
+	position := int(node.Position())
+	if position > len(c.source) {
+		position = len(c.source)
+	}
+
+	line := 1 + strings.Count(c.source[:position], "\n")
+	column := position - strings.LastIndex(c.source[:position], "\n")
+
+	return TemplateError{
+		Field:   c.name,
+		Line:    line,
+		Column:  column,
+		Message: message,
+	}
+}
+
+func elementType(typ reflect.Type) reflect.Type {
+	if typ == nil || typ == dataType {
+		return typ
+	}
+
+	switch typ.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return typ.Elem()
+	}
+
+	return nil
+}
+
+func sampleMessageContext() MessageContext {
+	return MessageContext{
+		From:              "no-reply@example.com",
+		ReplyTo:           "reply-to@example.com",
+		To:                "user@example.com",
+		Subject:           "Sample subject",
+		Text:              "Sample text",
+		HTML:              "<p>Sample HTML</p>",
+		HTMLComponents:    HTML{BodyContent: "<p>Sample HTML</p>"},
+		KindDescription:   "Sample notification",
+		SourceDescription: "Sample client",
+		UserGUID:          "user-guid",
+		ClientID:          "client-id",
+		MessageID:         "message-id",
+		Space:             "sample-space",
+		SpaceGUID:         "space-guid",
+		Organization:      "sample-organization",
+		OrganizationGUID:  "organization-guid",
+		UnsubscribeID:     "unsubscribe-id",
+		Scope:             "sample.scope",
+		Endorsement:       "This is a sample endorsement.",
+		OrganizationRole:  "OrgManager",
+		RequestReceived:   time.Now(),
+		Domain:            "example.com",
+		Data:              map[string]interface{}{},
+		Attachments:       []Attachment{{Filename: "sample.txt", ContentType: "text/plain"}},
+	}
+}
@@ -0,0 +1,129 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateValidator", func() {
+	var validator common.TemplateValidator
+
+	It("accepts the default template without warnings", func() {
+		warnings, err := validator.Validate(common.Templates{
+			Subject: "CF Notification: {{.Subject}}",
+			Text:    "{{.Endorsement}}\n{{.Text}}",
+			HTML:    "<p>{{.Endorsement}}</p>{{.HTML}}",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("reports the action that fails to parse", func() {
+		_, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "{{.Endorsement}}\n{{.Text}}\n{{if .Text}}unclosed",
+			HTML:    "<style>p { color: red }</style>{{.Endorsement}} {{.HTML}",
+		})
+		Expect(err).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+			{Field: "text", Line: 3, Column: 1, Message: "unexpected EOF"},
+			{Field: "html", Line: 1, Column: 49, Message: "bad character U+007D '}'"},
+		}}))
+	})
+
+	It("checks field references against the message context", func() {
+		_, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject}} from {{.Spcae}}",
+			Text:    "{{.Endorsement}} {{.Text}} {{.HTMLComponents.Title}} {{.Data.anything.goes}}",
+			HTML:    "{{.Endorsement}}{{.HTML}}{{range .Attachments}}{{.Filename}}{{.Size}}{{end}}{{.RequestReceived.Format \"2006\"}}",
+		})
+		Expect(err).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+			{Field: "subject", Line: 1, Column: 21, Message: "can't evaluate field Spcae in type common.MessageContext"},
+			{Field: "text", Line: 1, Column: 45, Message: "can't evaluate field Title in type common.HTML"},
+			{Field: "html", Line: 1, Column: 63, Message: "can't evaluate field Size in type common.Attachment"},
+		}}))
+	})
+
+	It("executes the template against a sample context", func() {
+		_, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "{{.Endorsement}} {{.Text}} {{index .Attachments 3}}",
+			HTML:    "{{.Endorsement}} {{.HTML}} {{template \"footer\"}}",
+		})
+		Expect(err).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+			{Field: "text", Line: 1, Column: 30, Message: "<index .Attachments 3>: error calling index: index out of range: 3"},
+			{Field: "html", Line: 1, Column: 39, Message: `no such template "footer"`},
+		}}))
+	})
+
+	It("accepts templates that use the helper functions", func() {
+		warnings, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject | title}}",
+			Text:    "{{.Endorsement}}\n{{.Text}}\n{{.RequestReceived | inZone \"UTC\" | formatTime \"RFC1123\"}} {{.Data.name | default \"there\" | upper}}",
+			HTML:    "<p>{{.Endorsement}}</p>{{.HTML}}<a href=\"https://{{.Domain}}/?space={{.Space | queryEscape}}\">{{.Data.notes | markdown}}</a>",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("accepts templates that work on values the notification data may not have", func() {
+		_, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject}} {{gt (len .Data.apps) 1 | ternary \"are\" \"is\"}}",
+			Text:    "{{.Endorsement}} {{.Text}} {{$apps := .Data.apps}}{{index $apps 0}} {{.Data.owner.name | len}}",
+			HTML:    "{{.Endorsement}} {{.HTML}} {{truncate $.Data.length .Text}}",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports failures that do not depend on the notification data", func() {
+		_, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject}} {{\"see .Data\" | formatTime \"Kitchen\"}}",
+			Text:    "{{.Endorsement}} {{.Text}} {{.Data.name}} {{len .Space | printf \"%d\" | formatTime \"Kitchen\"}}",
+		})
+		Expect(err).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+			{Field: "subject", Line: 1, Column: 30, Message: `<formatTime "Kitchen">: error calling formatTime: "see .Data" is not an RFC 3339 time`},
+			{Field: "text", Line: 1, Column: 72, Message: `<formatTime "Kitchen">: error calling formatTime: "12" is not an RFC 3339 time`},
+		}}))
+	})
+
+	It("reports helper functions that do not exist or fail", func() {
+		_, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject | shout}}",
+			Text:    "{{.Endorsement}} {{.Text}} {{.RequestReceived | inZone \"Mars/Olympus_Mons\"}}",
+		})
+		Expect(err).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+			{Field: "subject", Line: 1, Column: 1, Message: `function "shout" not defined`},
+			{Field: "text", Line: 1, Column: 49, Message: `<inZone "Mars/Olympus_Mons">: error calling inZone: unknown time zone Mars/Olympus_Mons`},
+		}}))
+	})
+
+	It("reports html that cannot be escaped for the context it appears in", func() {
+		_, err := validator.Validate(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "{{.Endorsement}} {{.Text}}",
+			HTML:    "{{.Endorsement}}\n{{.HTML}} {{if .Text}}<a href=\"{{end}}\">",
+		})
+		Expect(err).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+
+		errs := err.(common.TemplateValidationError).Errors
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("html"))
+		Expect(errs[0].Line).To(Equal(2))
+		Expect(errs[0].Column).To(Equal(16))
+		Expect(errs[0].Message).To(HavePrefix("{{if}} branches end in different contexts"))
+	})
+
+	It("warns about common fields that are not used", func() {
+		warnings, err := validator.Validate(common.Templates{
+			Subject: "Hello",
+			Text:    "{{.Text}}",
+			HTML:    "",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(Equal([]string{
+			"subject template does not include {{.Subject}}",
+			"text template does not include {{.Endorsement}}",
+		}))
+	})
+})
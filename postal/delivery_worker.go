@@ -6,6 +6,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/pivotal-golang/lager"
 	"github.com/rcrowley/go-metrics"
@@ -24,11 +25,11 @@ type campaignJobProcessor interface {
 }
 
 type messageStatusUpdater interface {
-	Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger)
+	Update(conn db.ConnectionInterface, messageID, messageStatus, reason string, logger lager.Logger)
 }
 
 type deliveryFailureHandler interface {
-	Handle(job common.Retryable, logger lager.Logger)
+	Handle(job common.Retryable, err error, logger lager.Logger)
 }
 
 type DeliveryWorkerConfig struct {
@@ -39,6 +40,8 @@ type DeliveryWorkerConfig struct {
 	DBTrace                bool
 	Database               db.DatabaseInterface
 	CampaignJobProcessor   campaignJobProcessor
+	FanOutJobProcessor     DeliveryJobProcessor
+	WebhookJobProcessor    DeliveryJobProcessor
 	DeliveryFailureHandler deliveryFailureHandler
 	MessageStatusUpdater   messageStatusUpdater
 }
@@ -48,6 +51,8 @@ type DeliveryWorker struct {
 
 	uaaHost                string
 	DeliveryJobProcessor   DeliveryJobProcessor
+	FanOutJobProcessor     DeliveryJobProcessor
+	WebhookJobProcessor    DeliveryJobProcessor
 	V2DeliveryJobProcessor v2DeliveryJobProcessor
 	logger                 lager.Logger
 	database               db.DatabaseInterface
@@ -59,6 +64,8 @@ type DeliveryWorker struct {
 func NewDeliveryWorker(v1DeliveryJobProcessor DeliveryJobProcessor, config DeliveryWorkerConfig) DeliveryWorker {
 	worker := DeliveryWorker{
 		DeliveryJobProcessor:   v1DeliveryJobProcessor,
+		FanOutJobProcessor:     config.FanOutJobProcessor,
+		WebhookJobProcessor:    config.WebhookJobProcessor,
 		uaaHost:                config.UAAHost,
 		logger:                 config.Logger,
 		database:               config.Database,
@@ -82,7 +89,18 @@ func (worker DeliveryWorker) Deliver(job *gobble.Job) {
 	if err != nil {
 		metrics.GetOrRegisterCounter("notifications.worker.panic.json", nil).Inc(1)
 
-		worker.deliveryFailureHandler.Handle(job, worker.logger)
+		worker.logger.Error("payload-unmarshal-failed", err)
+		job.Bury(err.Error())
+		return
+	}
+
+	if typedJob.JobType == services.FanOutJobType && worker.FanOutJobProcessor != nil {
+		worker.FanOutJobProcessor.Process(job, worker.logger)
+		return
+	}
+
+	if typedJob.JobType == v1.WebhookJobType && worker.WebhookJobProcessor != nil {
+		worker.WebhookJobProcessor.Process(job, worker.logger)
 		return
 	}
 
@@ -7,7 +7,9 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/pivotal-golang/lager"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -23,6 +25,8 @@ var _ = Describe("DeliveryWorker", func() {
 		queue                  *mocks.Queue
 		deliveryFailureHandler *mocks.DeliveryFailureHandler
 		v1DeliveryJobProcessor *mocks.V1DeliveryJobProcessor
+		fanOutJobProcessor     *mocks.V1DeliveryJobProcessor
+		webhookJobProcessor    *mocks.V1DeliveryJobProcessor
 		connection             *mocks.Connection
 		messageStatusUpdater   *mocks.MessageStatusUpdater
 	)
@@ -37,6 +41,8 @@ var _ = Describe("DeliveryWorker", func() {
 		database := mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = connection
 		messageStatusUpdater = mocks.NewMessageStatusUpdater()
+		fanOutJobProcessor = mocks.NewV1DeliveryJobProcessor()
+		webhookJobProcessor = mocks.NewV1DeliveryJobProcessor()
 
 		config := postal.DeliveryWorkerConfig{
 			ID:                     42,
@@ -46,6 +52,8 @@ var _ = Describe("DeliveryWorker", func() {
 			Database:               database,
 			UAAHost:                "my-uaa-host",
 			MessageStatusUpdater:   messageStatusUpdater,
+			FanOutJobProcessor:     fanOutJobProcessor,
+			WebhookJobProcessor:    webhookJobProcessor,
 		}
 
 		v1DeliveryJobProcessor = mocks.NewV1DeliveryJobProcessor()
@@ -92,6 +100,34 @@ var _ = Describe("DeliveryWorker", func() {
 
 			Expect(v1DeliveryJobProcessor.ProcessCall.Receives.Job).To(Equal(job))
 			Expect(v1DeliveryJobProcessor.ProcessCall.Receives.Logger).ToNot(BeNil())
+			Expect(fanOutJobProcessor.ProcessCall.CallCount).To(Equal(0))
+		})
+
+		It("should hand fan-out jobs to the fan-out processor", func() {
+			job = gobble.NewJob(services.FanOut{
+				JobType: services.FanOutJobType,
+				BatchID: "some-batch-id",
+			})
+
+			worker.Deliver(job)
+
+			Expect(fanOutJobProcessor.ProcessCall.Receives.Job).To(Equal(job))
+			Expect(fanOutJobProcessor.ProcessCall.Receives.Logger).ToNot(BeNil())
+			Expect(v1DeliveryJobProcessor.ProcessCall.CallCount).To(Equal(0))
+		})
+
+		It("should hand webhook jobs to the webhook processor", func() {
+			job = gobble.NewJob(v1.WebhookDelivery{
+				JobType:   v1.WebhookJobType,
+				WebhookID: "some-webhook-id",
+			})
+
+			worker.Deliver(job)
+
+			Expect(webhookJobProcessor.ProcessCall.Receives.Job).To(Equal(job))
+			Expect(webhookJobProcessor.ProcessCall.Receives.Logger).ToNot(BeNil())
+			Expect(v1DeliveryJobProcessor.ProcessCall.CallCount).To(Equal(0))
+			Expect(fanOutJobProcessor.ProcessCall.CallCount).To(Equal(0))
 		})
 
 		Context("when the job cannot be unmarshalled", func() {
@@ -104,10 +140,10 @@ var _ = Describe("DeliveryWorker", func() {
 				worker.Deliver(job)
 			})
 
-			It("should use the deliveryFailureHandler", func() {
-				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeTrue())
-				Expect(deliveryFailureHandler.HandleCall.Receives.Job).ToNot(BeNil())
-				Expect(deliveryFailureHandler.HandleCall.Receives.Logger).ToNot(BeNil())
+			It("buries the job without retrying it", func() {
+				Expect(job.ShouldBury).To(BeTrue())
+				Expect(job.LastError).NotTo(BeEmpty())
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
 			})
 		})
 	})
@@ -2,44 +2,245 @@ package postal
 
 import (
 	"log"
+	"sort"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
-type messagesDeleter interface {
-	DeleteBefore(models.ConnectionInterface, time.Time) (int, error)
+const collectBatchSize = 500
+
+// Scheduled messages are only collected when a lifetime is configured for
+// them explicitly, since they are expected to sit untouched until send_at.
+var defaultCollectedStatuses = []string{
+	common.StatusQueued,
+	common.StatusDelivered,
+	common.StatusFailed,
+	common.StatusUndeliverable,
+	common.StatusCanceled,
+}
+
+type messagesCollector interface {
+	FindExpired(models.ConnectionInterface, string, time.Time, int) ([]models.Message, error)
+	DeleteByIDs(models.ConnectionInterface, []string) (int, error)
+}
+
+type receiptsCollector interface {
+	FindExpired(models.ConnectionInterface, time.Time, int) ([]models.Receipt, error)
+	DeleteByIDs(models.ConnectionInterface, []int) (int, error)
+}
+
+type batchesCollector interface {
+	FindExpired(models.ConnectionInterface, time.Time, int) ([]models.Batch, error)
+	DeleteByIDs(models.ConnectionInterface, []string) (int, error)
+}
+
+type archiver interface {
+	Archive(name string, records []interface{}) error
+}
+
+type MessageGCConfig struct {
+	Lifetime        time.Duration
+	StatusLifetimes map[string]time.Duration
+	ReceiptLifetime time.Duration
+	PollingInterval time.Duration
+	Database        db.DatabaseInterface
+	MessagesRepo    messagesCollector
+	ReceiptsRepo    receiptsCollector
+	BatchesRepo     batchesCollector
+	Archiver        archiver
+	Logger          *log.Logger
 }
 
 type MessageGC struct {
-	messages        messagesDeleter
+	messages        messagesCollector
+	receipts        receiptsCollector
+	batches         batchesCollector
+	archiver        archiver
 	db              db.DatabaseInterface
-	lifetime        time.Duration
+	lifetimes       map[string]time.Duration
+	batchLifetime   time.Duration
+	receiptLifetime time.Duration
 	logger          *log.Logger
 	timer           <-chan time.Time
 	pollingInterval time.Duration
 }
 
-func NewMessageGC(lifetime time.Duration, db db.DatabaseInterface, messages messagesDeleter, pollingInterval time.Duration, logger *log.Logger) MessageGC {
+func NewMessageGC(config MessageGCConfig) MessageGC {
+	lifetimes := map[string]time.Duration{}
+	for _, status := range defaultCollectedStatuses {
+		lifetimes[status] = config.Lifetime
+	}
+	for status, lifetime := range config.StatusLifetimes {
+		lifetimes[status] = lifetime
+	}
+
 	return MessageGC{
-		messages:        messages,
-		db:              db,
-		lifetime:        lifetime,
-		logger:          logger,
-		pollingInterval: pollingInterval,
+		messages:        config.MessagesRepo,
+		receipts:        config.ReceiptsRepo,
+		batches:         config.BatchesRepo,
+		archiver:        config.Archiver,
+		db:              config.Database,
+		lifetimes:       lifetimes,
+		batchLifetime:   config.Lifetime,
+		receiptLifetime: config.ReceiptLifetime,
+		logger:          config.Logger,
+		pollingInterval: config.PollingInterval,
 		timer:           time.After(0),
 	}
 }
 
 func (gc MessageGC) Collect() {
-	threshold := time.Now().Add(-1 * gc.lifetime)
-	_, err := gc.messages.DeleteBefore(gc.db.Connection(), threshold)
-	if err != nil {
-		gc.logger.Printf("MessageGC.Collect() failed: " + err.Error())
+	conn := gc.db.Connection()
+	now := time.Now()
+
+	statuses := []string{}
+	for status := range gc.lifetimes {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	for _, status := range statuses {
+		lifetime := gc.lifetimes[status]
+		if lifetime <= 0 {
+			continue
+		}
+
+		err := gc.collectMessages(conn, status, now.Add(-1*lifetime))
+		if err != nil {
+			gc.logger.Printf("MessageGC.Collect() failed for %q messages: %s", status, err)
+		}
+	}
+
+	// Batches go with the message lifetime, and only once their messages
+	// have been collected.
+	if gc.batches != nil && gc.batchLifetime > 0 {
+		err := gc.collectBatches(conn, now.Add(-1*gc.batchLifetime))
+		if err != nil {
+			gc.logger.Printf("MessageGC.Collect() failed for batches: %s", err)
+		}
+	}
+
+	if gc.receipts != nil && gc.receiptLifetime > 0 {
+		err := gc.collectReceipts(conn, now.Add(-1*gc.receiptLifetime))
+		if err != nil {
+			gc.logger.Printf("MessageGC.Collect() failed for receipts: %s", err)
+		}
+	}
+}
+
+func (gc MessageGC) collectMessages(conn models.ConnectionInterface, status string, threshold time.Time) error {
+	for {
+		messages, err := gc.messages.FindExpired(conn, status, threshold, collectBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		var records []interface{}
+		var ids []string
+		for _, message := range messages {
+			records = append(records, message)
+			ids = append(ids, message.ID)
+		}
+
+		err = gc.archive("messages", records)
+		if err != nil {
+			return err
+		}
+
+		_, err = gc.messages.DeleteByIDs(conn, ids)
+		if err != nil {
+			return err
+		}
+
+		if len(messages) < collectBatchSize {
+			return nil
+		}
+	}
+}
+
+func (gc MessageGC) collectBatches(conn models.ConnectionInterface, threshold time.Time) error {
+	for {
+		batches, err := gc.batches.FindExpired(conn, threshold, collectBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(batches) == 0 {
+			return nil
+		}
+
+		var records []interface{}
+		var ids []string
+		for _, batch := range batches {
+			records = append(records, batch)
+			ids = append(ids, batch.ID)
+		}
+
+		err = gc.archive("batches", records)
+		if err != nil {
+			return err
+		}
+
+		_, err = gc.batches.DeleteByIDs(conn, ids)
+		if err != nil {
+			return err
+		}
+
+		if len(batches) < collectBatchSize {
+			return nil
+		}
 	}
 }
 
+func (gc MessageGC) collectReceipts(conn models.ConnectionInterface, threshold time.Time) error {
+	for {
+		receipts, err := gc.receipts.FindExpired(conn, threshold, collectBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(receipts) == 0 {
+			return nil
+		}
+
+		var records []interface{}
+		var ids []int
+		for _, receipt := range receipts {
+			records = append(records, receipt)
+			ids = append(ids, receipt.Primary)
+		}
+
+		err = gc.archive("receipts", records)
+		if err != nil {
+			return err
+		}
+
+		_, err = gc.receipts.DeleteByIDs(conn, ids)
+		if err != nil {
+			return err
+		}
+
+		if len(receipts) < collectBatchSize {
+			return nil
+		}
+	}
+}
+
+func (gc MessageGC) archive(name string, records []interface{}) error {
+	if gc.archiver == nil {
+		return nil
+	}
+
+	return gc.archiver.Archive(name, records)
+}
+
 func (gc MessageGC) Run() {
 	go func() {
 		for {
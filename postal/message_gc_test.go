@@ -9,6 +9,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -16,29 +17,44 @@ import (
 
 var _ = Describe("MessageGC", func() {
 	var (
-		messageGC       postal.MessageGC
-		repo            *mocks.MessagesRepo
-		database        *mocks.Database
-		conn            db.ConnectionInterface
-		loggerBuffer    *bytes.Buffer
-		lifetime        time.Duration
-		pollingInterval time.Duration
+		messageGC    postal.MessageGC
+		messagesRepo *mocks.MessagesRepo
+		receiptsRepo *mocks.ReceiptsRepo
+		batchesRepo  *mocks.BatchesRepo
+		archiver     *mocks.Archiver
+		database     *mocks.Database
+		conn         db.ConnectionInterface
+		loggerBuffer *bytes.Buffer
+		config       postal.MessageGCConfig
 	)
 
 	BeforeEach(func() {
 		loggerBuffer = bytes.NewBuffer([]byte{})
-		logger := log.New(loggerBuffer, "", 0)
 
 		conn = mocks.NewConnection()
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		repo = mocks.NewMessagesRepo()
-
-		lifetime = 2 * time.Minute
-		pollingInterval = 500 * time.Millisecond
+		messagesRepo = mocks.NewMessagesRepo()
+		receiptsRepo = mocks.NewReceiptsRepo()
+		batchesRepo = mocks.NewBatchesRepo()
+		archiver = mocks.NewArchiver()
+
+		config = postal.MessageGCConfig{
+			StatusLifetimes: map[string]time.Duration{
+				"delivered": 2 * time.Minute,
+			},
+			PollingInterval: 500 * time.Millisecond,
+			Database:        database,
+			MessagesRepo:    messagesRepo,
+			ReceiptsRepo:    receiptsRepo,
+			BatchesRepo:     batchesRepo,
+			Logger:          log.New(loggerBuffer, "", 0),
+		}
+	})
 
-		messageGC = postal.NewMessageGC(lifetime, database, repo, pollingInterval, logger)
+	JustBeforeEach(func() {
+		messageGC = postal.NewMessageGC(config)
 	})
 
 	Describe("Run", func() {
@@ -46,33 +62,138 @@ var _ = Describe("MessageGC", func() {
 			messageGC.Run()
 
 			Eventually(func() int {
-				return repo.DeleteBeforeCall.CallCount
+				return messagesRepo.FindExpiredCall.CallCount
 			}).Should(BeNumerically(">=", 2))
 
-			call1 := repo.DeleteBeforeCall.InvocationTimes[0]
-			call2 := repo.DeleteBeforeCall.InvocationTimes[1]
-			Expect(call2).To(BeTemporally(">", call1.Add(pollingInterval-50*time.Millisecond)))
-			Expect(call2).To(BeTemporally("<", call1.Add(pollingInterval+50*time.Millisecond)))
+			call1 := messagesRepo.FindExpiredCall.InvocationTimes[0]
+			call2 := messagesRepo.FindExpiredCall.InvocationTimes[1]
+			Expect(call2).To(BeTemporally(">", call1.Add(config.PollingInterval-50*time.Millisecond)))
+			Expect(call2).To(BeTemporally("<", call1.Add(config.PollingInterval+50*time.Millisecond)))
 		})
 	})
 
 	Describe("Collect", func() {
-		It("Deletes message statuses older than the specified time", func() {
+		BeforeEach(func() {
+			messagesRepo.FindExpiredCall.Returns.Messages = []models.Message{
+				{ID: "message-1", Status: "delivered"},
+				{ID: "message-2", Status: "delivered"},
+			}
+		})
+
+		It("deletes message statuses older than the lifetime for their status", func() {
 			messageGC.Collect()
 
-			Expect(repo.DeleteBeforeCall.Receives.Connection).To(Equal(conn))
-			Expect(repo.DeleteBeforeCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-2*time.Minute), 10*time.Second))
+			Expect(messagesRepo.FindExpiredCall.CallCount).To(Equal(1))
+			Expect(messagesRepo.FindExpiredCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.FindExpiredCall.Receives.Status).To(Equal("delivered"))
+			Expect(messagesRepo.FindExpiredCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-2*time.Minute), 10*time.Second))
+			Expect(messagesRepo.FindExpiredCall.Receives.Limit).To(Equal(500))
+
+			Expect(messagesRepo.DeleteByIDsCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.DeleteByIDsCall.Receives.IDs).To(Equal([]string{"message-1", "message-2"}))
+		})
+
+		It("applies the default lifetime to every status except scheduled", func() {
+			config.Lifetime = 24 * time.Hour
+			messageGC = postal.NewMessageGC(config)
+
+			messageGC.Collect()
+
+			Expect(messagesRepo.FindExpiredCall.CallCount).To(Equal(5))
+		})
+
+		It("does not archive anything when no archiver is configured", func() {
+			messageGC.Collect()
+
+			Expect(archiver.ArchiveCall.CallCount).To(Equal(0))
+		})
+
+		Context("when an archiver is configured", func() {
+			BeforeEach(func() {
+				config.Archiver = archiver
+			})
+
+			It("archives the messages before deleting them", func() {
+				messageGC.Collect()
+
+				Expect(archiver.ArchiveCall.Receives.Name).To(Equal("messages"))
+				Expect(archiver.ArchiveCall.Receives.Records).To(Equal([]interface{}{
+					models.Message{ID: "message-1", Status: "delivered"},
+					models.Message{ID: "message-2", Status: "delivered"},
+				}))
+				Expect(messagesRepo.DeleteByIDsCall.CallCount).To(Equal(1))
+			})
+
+			It("does not delete the messages when archiving fails", func() {
+				archiver.ArchiveCall.Returns.Error = errors.New("disk is full")
+
+				messageGC.Collect()
+
+				Expect(messagesRepo.DeleteByIDsCall.CallCount).To(Equal(0))
+				Expect(loggerBuffer.String()).To(ContainSubstring("disk is full"))
+			})
+		})
+
+		Context("when a receipt lifetime is configured", func() {
+			BeforeEach(func() {
+				config.ReceiptLifetime = 90 * 24 * time.Hour
+				config.Archiver = archiver
+				receiptsRepo.FindExpiredCall.Returns.Receipts = []models.Receipt{
+					{Primary: 12, UserGUID: "user-123"},
+				}
+			})
+
+			It("archives and deletes expired receipts", func() {
+				messageGC.Collect()
+
+				Expect(receiptsRepo.FindExpiredCall.Receives.Connection).To(Equal(conn))
+				Expect(receiptsRepo.FindExpiredCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-90*24*time.Hour), 10*time.Second))
+				Expect(archiver.ArchiveCall.Receives.Name).To(Equal("receipts"))
+				Expect(receiptsRepo.DeleteByIDsCall.Receives.IDs).To(Equal([]int{12}))
+			})
+		})
+
+		Context("when a message lifetime is configured", func() {
+			BeforeEach(func() {
+				config.Lifetime = 24 * time.Hour
+				config.Archiver = archiver
+				batchesRepo.FindExpiredCall.Returns.Batches = []models.Batch{
+					{ID: "batch-1", Status: "enqueued"},
+				}
+			})
+
+			It("archives and deletes expired batches", func() {
+				messageGC.Collect()
+
+				Expect(batchesRepo.FindExpiredCall.Receives.Connection).To(Equal(conn))
+				Expect(batchesRepo.FindExpiredCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-24*time.Hour), 10*time.Second))
+				Expect(batchesRepo.FindExpiredCall.Receives.Limit).To(Equal(500))
+				Expect(archiver.ArchiveCall.Receives.Name).To(Equal("batches"))
+				Expect(batchesRepo.DeleteByIDsCall.Receives.IDs).To(Equal([]string{"batch-1"}))
+			})
+		})
+
+		It("keeps batches when no message lifetime is configured", func() {
+			messageGC.Collect()
+
+			Expect(batchesRepo.FindExpiredCall.CallCount).To(Equal(0))
+		})
+
+		It("keeps receipts when no receipt lifetime is configured", func() {
+			messageGC.Collect()
+
+			Expect(receiptsRepo.FindExpiredCall.CallCount).To(Equal(0))
 		})
 
 		Context("When the repo errors unexpectantly", func() {
 			It("logs the error", func() {
-				repo.DeleteBeforeCall.Returns.Error = errors.New("messages table is totally corrupt")
+				messagesRepo.FindExpiredCall.Returns.Error = errors.New("messages table is totally corrupt")
 
 				messageGC.Collect()
 
 				Expect(loggerBuffer.String()).To(ContainSubstring("messages table is totally corrupt"))
+				Expect(messagesRepo.DeleteByIDsCall.CallCount).To(Equal(0))
 			})
 		})
-
 	})
 })
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/db"
@@ -27,11 +28,12 @@ type userLoader interface {
 }
 
 type messageStatusUpdater interface {
-	Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger)
+	Update(conn db.ConnectionInterface, messageID, messageStatus, reason string, logger lager.Logger)
+	RecordAttempt(conn db.ConnectionInterface, messageID, email string, logger lager.Logger)
 }
 
 type deliveryFailureHandler interface {
-	Handle(job common.Retryable, logger lager.Logger)
+	Handle(job common.Retryable, err error, logger lager.Logger)
 }
 
 type kindsFinder interface {
@@ -50,6 +52,10 @@ type globalUnsubscribesGetter interface {
 	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
 }
 
+type userLocalesGetter interface {
+	Get(connection models.ConnectionInterface, userGUID string) (string, error)
+}
+
 type DeliveryJobProcessorConfig struct {
 	DBTrace bool
 	UAAHost string
@@ -66,6 +72,7 @@ type DeliveryJobProcessorConfig struct {
 	ReceiptsRepo           receiptsCreator
 	UnsubscribesRepo       unsubscribesGetter
 	GlobalUnsubscribesRepo globalUnsubscribesGetter
+	UserLocalesRepo        userLocalesGetter
 	MessageStatusUpdater   messageStatusUpdater
 	DeliveryFailureHandler deliveryFailureHandler
 }
@@ -86,6 +93,7 @@ type DeliveryJobProcessor struct {
 	receiptsRepo           receiptsCreator
 	unsubscribesRepo       unsubscribesGetter
 	globalUnsubscribesRepo globalUnsubscribesGetter
+	userLocalesRepo        userLocalesGetter
 	messageStatusUpdater   messageStatusUpdater
 	deliveryFailureHandler deliveryFailureHandler
 }
@@ -107,6 +115,7 @@ func NewDeliveryJobProcessor(config DeliveryJobProcessorConfig) DeliveryJobProce
 		receiptsRepo:           config.ReceiptsRepo,
 		unsubscribesRepo:       config.UnsubscribesRepo,
 		globalUnsubscribesRepo: config.GlobalUnsubscribesRepo,
+		userLocalesRepo:        config.UserLocalesRepo,
 		messageStatusUpdater:   config.MessageStatusUpdater,
 		deliveryFailureHandler: config.DeliveryFailureHandler,
 	}
@@ -118,7 +127,8 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 	if err != nil {
 		metrics.GetOrRegisterCounter("notifications.worker.panic.json", nil).Inc(1)
 
-		p.deliveryFailureHandler.Handle(job, logger)
+		logger.Error("payload-unmarshal-failed", err)
+		job.Bury(err.Error())
 		return nil
 	}
 
@@ -133,7 +143,7 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 
 	err = p.receiptsRepo.CreateReceipts(p.database.Connection(), []string{delivery.UserGUID}, delivery.ClientID, delivery.Options.KindID)
 	if err != nil {
-		p.deliveryFailureHandler.Handle(job, logger)
+		p.deliveryFailureHandler.Handle(job, err, logger)
 		return nil
 	}
 
@@ -142,13 +152,18 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 
 		token, err = p.tokenLoader.Load(p.uaaHost)
 		if err != nil {
-			p.deliveryFailureHandler.Handle(job, logger)
+			p.deliveryFailureHandler.Handle(job, err, logger)
 			return nil
 		}
 
 		users, err := p.userLoader.Load([]string{delivery.UserGUID}, token)
-		if err != nil || len(users) < 1 {
-			p.deliveryFailureHandler.Handle(job, logger)
+		if err != nil {
+			p.deliveryFailureHandler.Handle(job, err, logger)
+			return nil
+		}
+
+		if len(users) < 1 {
+			p.deliveryFailureHandler.Handle(job, fmt.Errorf("user %q could not be found", delivery.UserGUID), logger)
 			return nil
 		}
 
@@ -158,18 +173,31 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 		}
 	}
 
+	if delivery.UserGUID != "" {
+		delivery.Locale, err = p.userLocalesRepo.Get(p.database.Connection(), delivery.UserGUID)
+		if err != nil {
+			p.deliveryFailureHandler.Handle(job, err, logger)
+			return nil
+		}
+	}
+
 	logger = logger.WithData(lager.Data{
 		"recipient": delivery.Email,
 	})
 
-	if p.shouldDeliver(delivery, logger) {
-		status := p.process(delivery, logger)
+	critical := p.isCritical(p.database.Connection(), delivery.Options.KindID, delivery.ClientID)
 
-		if status != common.StatusDelivered {
-			p.deliveryFailureHandler.Handle(job, logger)
-			return nil
-		} else {
+	if p.shouldDeliver(delivery, critical, logger) {
+		status, err := p.process(delivery, critical, logger)
+
+		switch status {
+		case common.StatusDelivered:
 			metrics.GetOrRegisterCounter("notifications.worker.delivered", nil).Inc(1)
+		case common.StatusUndeliverable:
+			metrics.GetOrRegisterCounter("notifications.worker.undeliverable", nil).Inc(1)
+		default:
+			p.deliveryFailureHandler.Handle(job, err, logger)
+			return nil
 		}
 	} else {
 		metrics.GetOrRegisterCounter("notifications.worker.unsubscribed", nil).Inc(1)
@@ -178,31 +206,42 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 	return nil
 }
 
-func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Logger) string {
+func (p DeliveryJobProcessor) process(delivery common.Delivery, critical bool, logger lager.Logger) (string, error) {
 	context, err := p.packager.PrepareContext(delivery, p.sender, p.domain)
 	if err != nil {
 		panic(err)
 	}
+	context.Critical = critical
 
 	message, err := p.packager.Pack(context)
 	if err != nil {
 		logger.Info("template-pack-failed")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusFailed, "", logger)
-		return common.StatusFailed
+		// A template that fails once fails on every retry, so the message is
+		// not retried.
+		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, err.Error(), logger)
+		return common.StatusUndeliverable, err
 	}
 
-	status := p.sendMail(delivery.MessageID, message, logger)
-	p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, status, "", logger)
+	p.messageStatusUpdater.RecordAttempt(p.database.Connection(), delivery.MessageID, delivery.Email, logger)
+
+	status, err := p.sendMail(delivery.MessageID, message, logger)
+
+	var reason string
+	if err != nil {
+		reason = err.Error()
+	}
+	p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, status, reason, logger)
 
-	return status
+	return status, err
 }
 
-func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lager.Logger) bool {
-	conn := p.database.Connection()
-	if p.isCritical(conn, delivery.Options.KindID, delivery.ClientID) {
+func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, critical bool, logger lager.Logger) bool {
+	if critical {
 		return true
 	}
 
+	conn := p.database.Connection()
+
 	globallyUnsubscribed, err := p.globalUnsubscribesRepo.Get(conn, delivery.UserGUID)
 	if err != nil || globallyUnsubscribed {
 		logger.Info("user-unsubscribed")
@@ -232,24 +271,29 @@ func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lag
 	return true
 }
 
-func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, logger lager.Logger) string {
+func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, logger lager.Logger) (string, error) {
 	err := p.mailClient.Connect(logger)
 	if err != nil {
 		logger.Error("smtp-connection-error", err)
-		return common.StatusFailed
+		return common.StatusFailed, err
 	}
 
 	logger.Info("delivery-start")
 
 	err = p.mailClient.Send(message, logger)
 	if err != nil {
+		if mail.IsPermanentFailure(err) {
+			logger.Error("delivery-rejected-smtp-error", err)
+			return common.StatusUndeliverable, err
+		}
+
 		logger.Error("delivery-failed-smtp-error", err)
-		return common.StatusFailed
+		return common.StatusFailed, err
 	}
 
 	logger.Info("message-sent")
 
-	return common.StatusDelivered
+	return common.StatusDelivered, nil
 }
 
 func (p DeliveryJobProcessor) isCritical(conn db.ConnectionInterface, kindID, clientID string) bool {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/md5"
 	"errors"
+	"net/textproto"
 	"strings"
 	"time"
 
@@ -30,6 +31,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		delivery               common.Delivery
 		unsubscribesRepo       *mocks.UnsubscribesRepo
 		globalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
+		userLocalesRepo        *mocks.UserLocalesRepo
 		kindsRepo              *mocks.KindsRepo
 		database               *mocks.Database
 		conn                   *mocks.Connection
@@ -54,6 +56,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		mailClient = mocks.NewMailClient()
 		unsubscribesRepo = mocks.NewUnsubscribesRepo()
 		globalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
+		userLocalesRepo = mocks.NewUserLocalesRepo()
 
 		kindsRepo = mocks.NewKindsRepo()
 		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
@@ -97,7 +100,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Sender:  "from@example.com",
 			Domain:  "example.com",
 
-			Packager:    common.NewPackager(templateLoader, cloak),
+			Packager:    common.NewPackager(templateLoader, cloak, "", common.NewHTMLPipeline(common.HTMLPipelineConfig{})),
 			MailClient:  mailClient,
 			Database:    database,
 			TokenLoader: tokenLoader,
@@ -107,6 +110,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			UserLocalesRepo:        userLocalesRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
@@ -193,7 +197,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Sender:  "from@example.com",
 				Domain:  "example.com",
 
-				Packager:    common.NewPackager(templateLoader, cloak),
+				Packager:    common.NewPackager(templateLoader, cloak, "", common.NewHTMLPipeline(common.HTMLPipelineConfig{})),
 				MailClient:  mailClient,
 				Database:    database,
 				TokenLoader: tokenLoader,
@@ -203,6 +207,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				ReceiptsRepo:           receiptsRepo,
 				UnsubscribesRepo:       unsubscribesRepo,
 				GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+				UserLocalesRepo:        userLocalesRepo,
 				MessageStatusUpdater:   messageStatusUpdater,
 				DeliveryFailureHandler: deliveryFailureHandler,
 			})
@@ -227,6 +232,15 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Expect(messageStatusUpdater.UpdateCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 		})
 
+		It("records the delivery attempt with the recipient's email", func() {
+			processor.Process(job, logger)
+
+			Expect(messageStatusUpdater.RecordAttemptCall.CallCount).To(Equal(1))
+			Expect(messageStatusUpdater.RecordAttemptCall.Receives.Connection).To(Equal(conn))
+			Expect(messageStatusUpdater.RecordAttemptCall.Receives.MessageID).To(Equal(messageID))
+			Expect(messageStatusUpdater.RecordAttemptCall.Receives.Email).To(Equal(fakeUserEmail))
+		})
+
 		It("creates a reciept for the delivery", func() {
 			processor.Process(job, logger)
 
@@ -242,10 +256,36 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				processor.Process(job, logger)
 
 				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError("something happened"))
 				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 			})
 		})
 
+		Context("when the recipient has a preferred locale", func() {
+			BeforeEach(func() {
+				userLocalesRepo.GetCall.Returns.Locale = "fr-CA"
+				templateLoader.LoadTemplatesCall.Returns.Templates.Localizations = map[string]common.Templates{
+					"fr": {Subject: "sujet: {{.Subject}}"},
+				}
+			})
+
+			It("delivers the template variant for that locale", func() {
+				processor.Process(job, logger)
+
+				Expect(userLocalesRepo.GetCall.Receives.Connection).To(Equal(conn))
+				Expect(userLocalesRepo.GetCall.Receives.UserID).To(Equal("user-123"))
+				Expect(mailClient.SendCall.Receives.Message.Subject).To(Equal("sujet: the subject"))
+			})
+
+			It("retries the job when the locale cannot be loaded", func() {
+				userLocalesRepo.GetCall.Returns.Error = errors.New("locale lookup failed")
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError("locale lookup failed"))
+			})
+		})
+
 		Context("when loading a zoned token fails", func() {
 			It("retries the job", func() {
 				job := gobble.NewJob(delivery)
@@ -254,6 +294,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				processor.Process(job, logger)
 
 				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError("failed to load a zoned UAA token"))
 				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 			})
 		})
@@ -307,6 +348,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 					processor.Process(job, logger)
 
 					Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+					Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError("Error sending message!!!"))
 					Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 				})
 
@@ -337,10 +379,73 @@ var _ = Describe("DeliveryJobProcessor", func() {
 					Expect(messageStatusUpdater.UpdateCall.Receives.Connection).To(Equal(conn))
 					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
 					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+					Expect(messageStatusUpdater.UpdateCall.Receives.Reason).To(Equal("Error sending message!!!"))
 					Expect(messageStatusUpdater.UpdateCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 				})
 			})
 
+			Context("because the server replied with a transient SMTP error", func() {
+				var smtpErr error
+
+				BeforeEach(func() {
+					smtpErr = &textproto.Error{Code: 451, Msg: "try again later"}
+					mailClient.SendCall.Returns.Error = smtpErr
+				})
+
+				It("marks the job for retry", func() {
+					processor.Process(job, logger)
+
+					Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+					Expect(messageStatusUpdater.UpdateCall.Receives.Reason).To(Equal(smtpErr.Error()))
+				})
+			})
+
+			Context("because the server permanently rejected the message", func() {
+				var smtpErr error
+
+				BeforeEach(func() {
+					smtpErr = &textproto.Error{Code: 550, Msg: "mailbox does not exist"}
+					mailClient.SendCall.Returns.Error = smtpErr
+				})
+
+				It("does not retry the job", func() {
+					processor.Process(job, logger)
+
+					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				})
+
+				It("marks the message as undeliverable with the reason", func() {
+					processor.Process(job, logger)
+
+					Expect(messageStatusUpdater.UpdateCall.Receives.Connection).To(Equal(conn))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+					Expect(messageStatusUpdater.UpdateCall.Receives.Reason).To(Equal(smtpErr.Error()))
+				})
+
+				It("logs an SMTP rejection error", func() {
+					processor.Process(job, logger)
+
+					lines, err := parseLogLines(buffer.Bytes())
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(lines).To(ContainElement(logLine{
+						Source:   "notifications",
+						Message:  "notifications.worker.delivery-rejected-smtp-error",
+						LogLevel: int(lager.ERROR),
+						Data: map[string]interface{}{
+							"session":         "1",
+							"error":           smtpErr.Error(),
+							"recipient":       "user-123@example.com",
+							"worker_id":       float64(1234),
+							"message_id":      "randomly-generated-guid",
+							"vcap_request_id": "some-request-id",
+						},
+					}))
+				})
+			})
+
 			Context("and the error is a connect error", func() {
 				It("logs an SMTP connection error", func() {
 					mailClient.ConnectCall.Returns.Error = errors.New("server timeout")
@@ -588,11 +693,10 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				}).ToNot(Panic())
 			})
 
-			It("marks the job for retry later", func() {
+			It("does not retry the job", func() {
 				processor.Process(job, logger)
 
-				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
-				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
 			})
 
 			It("logs that the packer errored", func() {
@@ -615,14 +719,51 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				}))
 			})
 
-			It("updates the message status as failed", func() {
+			It("updates the message status as undeliverable", func() {
 				processor.Process(job, logger)
 
 				Expect(messageStatusUpdater.UpdateCall.Receives.Connection).To(Equal(conn))
 				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
-				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
 				Expect(messageStatusUpdater.UpdateCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 			})
+
+			It("does not record a delivery attempt", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.RecordAttemptCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the template cannot be evaluated", func() {
+			BeforeEach(func() {
+				templateLoader.LoadTemplatesCall.Returns.Templates = common.Templates{
+					Text:    "{{.Text}}",
+					HTML:    "<p>{{.HTML}}</p>",
+					Subject: `{{.Subject | formatTime "Kitchen"}}`,
+				}
+				job = gobble.NewJob(delivery)
+			})
+
+			It("updates the message status as undeliverable with the error as the reason", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+				Expect(messageStatusUpdater.UpdateCall.Receives.Reason).To(ContainSubstring(`"the subject" is not an RFC 3339 time`))
+			})
+
+			It("does not send the email", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("does not retry the job", func() {
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+			})
 		})
 
 		Context("when the job contains malformed JSON", func() {
@@ -636,11 +777,12 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				}).ToNot(Panic())
 			})
 
-			It("marks the job for retry later", func() {
+			It("moves the job to the dead jobs table without retrying", func() {
 				processor.Process(job, logger)
 
-				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
-				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
+				Expect(job.ShouldBury).To(BeTrue())
+				Expect(job.LastError).To(ContainSubstring("unexpected end of JSON input"))
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
 			})
 		})
 	})
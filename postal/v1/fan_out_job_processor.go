@@ -0,0 +1,226 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/pivotal-golang/lager"
+	"github.com/rcrowley/go-metrics"
+)
+
+const DefaultFanOutChunkSize = 500
+
+type allUsersPager interface {
+	UserGUIDsPage(token string, startIndex, count int) ([]string, int, error)
+}
+
+type findsUserIDs interface {
+	UserIDsBelongingToOrganization(orgGUID, role, token string) ([]string, error)
+	UserIDsBelongingToScope(token, scope string) ([]string, error)
+}
+
+type batchEnqueuer interface {
+	EnqueueBatch(conn services.ConnectionInterface, batch models.Batch, users []services.User, options services.Options, space cf.CloudControllerSpace, org cf.CloudControllerOrganization, clientID, uaaHost, scope, vcapRequestID string, reqReceived time.Time) ([]services.Response, error)
+}
+
+type batchesRepo interface {
+	FindByID(conn models.ConnectionInterface, batchID string) (models.Batch, error)
+	Update(conn models.ConnectionInterface, batch models.Batch) (models.Batch, error)
+}
+
+type FanOutJobProcessorConfig struct {
+	ChunkSize int
+
+	Database     db.DatabaseInterface
+	TokenLoader  tokenLoader
+	AllUsers     allUsersPager
+	FindsUserIDs findsUserIDs
+	Enqueuer     batchEnqueuer
+
+	BatchesRepo            batchesRepo
+	DeliveryFailureHandler deliveryFailureHandler
+}
+
+type FanOutJobProcessor struct {
+	chunkSize int
+
+	database     db.DatabaseInterface
+	tokenLoader  tokenLoader
+	allUsers     allUsersPager
+	findsUserIDs findsUserIDs
+	enqueuer     batchEnqueuer
+
+	batchesRepo            batchesRepo
+	deliveryFailureHandler deliveryFailureHandler
+}
+
+func NewFanOutJobProcessor(config FanOutJobProcessorConfig) FanOutJobProcessor {
+	chunkSize := config.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultFanOutChunkSize
+	}
+
+	return FanOutJobProcessor{
+		chunkSize: chunkSize,
+
+		database:     config.Database,
+		tokenLoader:  config.TokenLoader,
+		allUsers:     config.AllUsers,
+		findsUserIDs: config.FindsUserIDs,
+		enqueuer:     config.Enqueuer,
+
+		batchesRepo:            config.BatchesRepo,
+		deliveryFailureHandler: config.DeliveryFailureHandler,
+	}
+}
+
+func (p FanOutJobProcessor) Process(job *gobble.Job, logger lager.Logger) error {
+	var fanOut services.FanOut
+	err := job.Unmarshal(&fanOut)
+	if err != nil {
+		metrics.GetOrRegisterCounter("notifications.worker.panic.json", nil).Inc(1)
+
+		logger.Error("payload-unmarshal-failed", err)
+		job.Bury(err.Error())
+		return nil
+	}
+
+	logger = logger.WithData(lager.Data{
+		"batch_id":        fanOut.BatchID,
+		"vcap_request_id": fanOut.VCAPRequestID,
+	})
+
+	conn := p.database.Connection()
+
+	batch, err := p.batchesRepo.FindByID(conn, fanOut.BatchID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			logger.Error("batch-not-found", err)
+			job.Bury(err.Error())
+			return nil
+		}
+
+		p.deliveryFailureHandler.Handle(job, err, logger)
+		return nil
+	}
+
+	if batch.Status == services.BatchStatusEnqueued {
+		return nil
+	}
+
+	err = p.fanOut(conn, fanOut, &batch)
+	if err != nil {
+		p.deliveryFailureHandler.Handle(job, err, logger)
+
+		if job.ShouldBury {
+			batch.Status = services.BatchStatusFailed
+			batch.Error = err.Error()
+			if _, err := p.batchesRepo.Update(conn, batch); err != nil {
+				logger.Error("batch-update-failed", err)
+			}
+		}
+		return nil
+	}
+
+	batch.Status = services.BatchStatusEnqueued
+	_, err = p.batchesRepo.Update(conn, batch)
+	if err != nil {
+		p.deliveryFailureHandler.Handle(job, err, logger)
+		return nil
+	}
+
+	logger.Info("fan-out-complete", lager.Data{
+		"enqueued": batch.Enqueued,
+	})
+
+	return nil
+}
+
+func (p FanOutJobProcessor) fanOut(conn db.ConnectionInterface, fanOut services.FanOut, batch *models.Batch) error {
+	token, err := p.tokenLoader.Load(fanOut.UAAHost)
+	if err != nil {
+		return err
+	}
+
+	var userGUIDs []string
+
+	switch fanOut.Audience {
+	case services.AudienceEveryone:
+		// UAA only pages by offset, so users created or deleted while the
+		// fan-out is running shift the pages that follow: a deletion can
+		// skip a user and a creation can notify a user twice.
+		for {
+			guids, totalResults, err := p.allUsers.UserGUIDsPage(token, batch.Enqueued+1, p.chunkSize)
+			if err != nil {
+				return err
+			}
+
+			if len(guids) == 0 {
+				return nil
+			}
+
+			batch.Total = totalResults
+
+			err = p.enqueueChunk(conn, fanOut, batch, guids, "")
+			if err != nil {
+				return err
+			}
+
+			if batch.Enqueued >= totalResults {
+				return nil
+			}
+		}
+	case services.AudienceOrganization:
+		userGUIDs, err = p.findsUserIDs.UserIDsBelongingToOrganization(fanOut.GUID, fanOut.Options.Role, token)
+	case services.AudienceScope:
+		userGUIDs, err = p.findsUserIDs.UserIDsBelongingToScope(token, fanOut.GUID)
+	}
+	if err != nil {
+		return err
+	}
+
+	batch.Total = len(userGUIDs)
+
+	var scope string
+	if fanOut.Audience == services.AudienceScope {
+		scope = fanOut.GUID
+	}
+
+	for batch.Enqueued < len(userGUIDs) {
+		end := batch.Enqueued + p.chunkSize
+		if end > len(userGUIDs) {
+			end = len(userGUIDs)
+		}
+
+		err = p.enqueueChunk(conn, fanOut, batch, userGUIDs[batch.Enqueued:end], scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p FanOutJobProcessor) enqueueChunk(conn db.ConnectionInterface, fanOut services.FanOut, batch *models.Batch, userGUIDs []string, scope string) error {
+	var users []services.User
+	for _, guid := range userGUIDs {
+		users = append(users, services.User{GUID: guid})
+	}
+
+	progress := *batch
+	progress.Enqueued += len(users)
+	progress.Status = services.BatchStatusEnqueuing
+
+	_, err := p.enqueuer.EnqueueBatch(conn, progress, users, fanOut.Options, cf.CloudControllerSpace{}, fanOut.Organization, fanOut.ClientID, fanOut.UAAHost, scope, fanOut.VCAPRequestID, fanOut.RequestReceived)
+	if err != nil {
+		return err
+	}
+
+	*batch = progress
+
+	return nil
+}
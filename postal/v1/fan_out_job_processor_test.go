@@ -0,0 +1,260 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FanOutJobProcessor", func() {
+	var (
+		processor              v1.FanOutJobProcessor
+		logger                 lager.Logger
+		conn                   *mocks.Connection
+		tokenLoader            *mocks.TokenLoader
+		allUsers               *mocks.AllUsers
+		findsUserIDs           *mocks.FindsUserIDs
+		enqueuer               *mocks.Enqueuer
+		batchesRepo            *mocks.BatchesRepo
+		deliveryFailureHandler *mocks.DeliveryFailureHandler
+		fanOut                 services.FanOut
+		requestReceived        time.Time
+	)
+
+	BeforeEach(func() {
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(bytes.NewBuffer([]byte{}), lager.DEBUG))
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		tokenLoader = mocks.NewTokenLoader()
+		tokenLoader.LoadCall.Returns.Token = "some-token"
+
+		allUsers = mocks.NewAllUsers()
+		findsUserIDs = mocks.NewFindsUserIDs()
+		enqueuer = mocks.NewEnqueuer()
+		deliveryFailureHandler = mocks.NewDeliveryFailureHandler()
+
+		batchesRepo = mocks.NewBatchesRepo()
+		batchesRepo.FindByIDCall.Returns.Batch = models.Batch{
+			ID:     "some-batch-id",
+			Status: services.BatchStatusPending,
+		}
+
+		requestReceived, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:32:11.660762586-07:00")
+		fanOut = services.FanOut{
+			JobType:         services.FanOutJobType,
+			BatchID:         "some-batch-id",
+			Audience:        services.AudienceEveryone,
+			Options:         services.Options{KindID: "some-kind"},
+			ClientID:        "some-client",
+			UAAHost:         "some-uaa-host",
+			VCAPRequestID:   "some-request-id",
+			RequestReceived: requestReceived,
+		}
+
+		processor = v1.NewFanOutJobProcessor(v1.FanOutJobProcessorConfig{
+			ChunkSize:              2,
+			Database:               database,
+			TokenLoader:            tokenLoader,
+			AllUsers:               allUsers,
+			FindsUserIDs:           findsUserIDs,
+			Enqueuer:               enqueuer,
+			BatchesRepo:            batchesRepo,
+			DeliveryFailureHandler: deliveryFailureHandler,
+		})
+	})
+
+	Context("when fanning out to everyone", func() {
+		BeforeEach(func() {
+			allUsers.UserGUIDsPageCall.Returns.Pages = [][]string{
+				{"user-1", "user-2"},
+				{"user-3"},
+			}
+			allUsers.UserGUIDsPageCall.Returns.TotalResults = 3
+		})
+
+		It("pages through the users and enqueues each page", func() {
+			err := processor.Process(gobble.NewJob(fanOut), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("some-uaa-host"))
+			Expect(allUsers.UserGUIDsPageCall.Receives.Token).To(Equal("some-token"))
+			Expect(allUsers.UserGUIDsPageCall.Receives.StartIndexes).To(Equal([]int{1, 3}))
+			Expect(allUsers.UserGUIDsPageCall.Receives.Count).To(Equal(2))
+
+			Expect(enqueuer.EnqueueBatchCall.Receives.Users).To(Equal([][]services.User{
+				{{GUID: "user-1"}, {GUID: "user-2"}},
+				{{GUID: "user-3"}},
+			}))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Connection).To(Equal(conn))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Options).To(Equal(services.Options{KindID: "some-kind"}))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Client).To(Equal("some-client"))
+			Expect(enqueuer.EnqueueBatchCall.Receives.UAAHost).To(Equal("some-uaa-host"))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Scope).To(Equal(""))
+			Expect(enqueuer.EnqueueBatchCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+			Expect(enqueuer.EnqueueBatchCall.Receives.RequestReceived).To(Equal(requestReceived))
+		})
+
+		It("records progress on the batch with each page and marks it enqueued", func() {
+			err := processor.Process(gobble.NewJob(fanOut), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batchesRepo.FindByIDCall.Receives.BatchID).To(Equal("some-batch-id"))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Batches).To(Equal([]models.Batch{
+				{ID: "some-batch-id", Status: services.BatchStatusEnqueuing, Total: 3, Enqueued: 2},
+				{ID: "some-batch-id", Status: services.BatchStatusEnqueuing, Total: 3, Enqueued: 3},
+			}))
+			Expect(batchesRepo.UpdateCall.Receives.Batches).To(Equal([]models.Batch{
+				{ID: "some-batch-id", Status: services.BatchStatusEnqueued, Total: 3, Enqueued: 3},
+			}))
+		})
+
+		It("resumes from the number of users already enqueued", func() {
+			batchesRepo.FindByIDCall.Returns.Batch.Enqueued = 2
+			allUsers.UserGUIDsPageCall.Returns.Pages = [][]string{{"user-3"}}
+
+			err := processor.Process(gobble.NewJob(fanOut), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(allUsers.UserGUIDsPageCall.Receives.StartIndexes).To(Equal([]int{3}))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Users).To(Equal([][]services.User{
+				{{GUID: "user-3"}},
+			}))
+		})
+	})
+
+	Context("when fanning out to an organization", func() {
+		BeforeEach(func() {
+			fanOut.Audience = services.AudienceOrganization
+			fanOut.GUID = "some-org-guid"
+			fanOut.Organization = cf.CloudControllerOrganization{GUID: "some-org-guid", Name: "some-org"}
+			fanOut.Options.Role = "OrgManager"
+
+			findsUserIDs.UserIDsBelongingToOrganizationCall.Returns.UserIDs = []string{"user-1", "user-2", "user-3"}
+		})
+
+		It("enqueues the organization users in chunks", func() {
+			err := processor.Process(gobble.NewJob(fanOut), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.OrgGUID).To(Equal("some-org-guid"))
+			Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.Role).To(Equal("OrgManager"))
+			Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.Token).To(Equal("some-token"))
+
+			Expect(enqueuer.EnqueueBatchCall.Receives.Users).To(Equal([][]services.User{
+				{{GUID: "user-1"}, {GUID: "user-2"}},
+				{{GUID: "user-3"}},
+			}))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{GUID: "some-org-guid", Name: "some-org"}))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Scope).To(Equal(""))
+		})
+	})
+
+	Context("when fanning out to a scope", func() {
+		BeforeEach(func() {
+			fanOut.Audience = services.AudienceScope
+			fanOut.GUID = "great.scope"
+
+			findsUserIDs.UserIDsBelongingToScopeCall.Returns.UserIDs = []string{"user-1"}
+		})
+
+		It("enqueues the users with the scope", func() {
+			err := processor.Process(gobble.NewJob(fanOut), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(findsUserIDs.UserIDsBelongingToScopeCall.Receives.Scope).To(Equal("great.scope"))
+			Expect(findsUserIDs.UserIDsBelongingToScopeCall.Receives.Token).To(Equal("some-token"))
+
+			Expect(enqueuer.EnqueueBatchCall.Receives.Users).To(Equal([][]services.User{
+				{{GUID: "user-1"}},
+			}))
+			Expect(enqueuer.EnqueueBatchCall.Receives.Scope).To(Equal("great.scope"))
+		})
+	})
+
+	Context("when the batch has already been enqueued", func() {
+		It("does nothing", func() {
+			batchesRepo.FindByIDCall.Returns.Batch.Status = services.BatchStatusEnqueued
+
+			err := processor.Process(gobble.NewJob(fanOut), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tokenLoader.LoadCall.Receives.UAAHost).To(BeEmpty())
+			Expect(enqueuer.EnqueueBatchCall.CallCount).To(Equal(0))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("buries jobs that cannot be unmarshalled", func() {
+			job := &gobble.Job{Payload: "%%"}
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(job.ShouldBury).To(BeTrue())
+			Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+		})
+
+		It("buries jobs whose batch cannot be found", func() {
+			batchesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+			job := gobble.NewJob(fanOut)
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(job.ShouldBury).To(BeTrue())
+			Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+		})
+
+		It("retries the job when the users cannot be enqueued", func() {
+			allUsers.UserGUIDsPageCall.Returns.Pages = [][]string{{"user-1"}}
+			allUsers.UserGUIDsPageCall.Returns.TotalResults = 1
+			enqueuer.EnqueueBatchCall.Returns.Err = errors.New("BOOM!")
+			job := gobble.NewJob(fanOut)
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+			Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+			Expect(batchesRepo.UpdateCall.Receives.Batches).To(BeEmpty())
+		})
+
+		It("marks the batch failed once the job has exhausted its retries", func() {
+			tokenLoader.LoadCall.Returns.Error = errors.New("BOOM!")
+
+			processor = v1.NewFanOutJobProcessor(v1.FanOutJobProcessorConfig{
+				Database:               mocks.NewDatabase(),
+				TokenLoader:            tokenLoader,
+				BatchesRepo:            batchesRepo,
+				DeliveryFailureHandler: common.NewDeliveryFailureHandler(),
+			})
+
+			job := gobble.NewJob(fanOut)
+			job.RetryCount = 10
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(job.ShouldBury).To(BeTrue())
+			Expect(batchesRepo.UpdateCall.Receives.Batches).To(Equal([]models.Batch{
+				{ID: "some-batch-id", Status: services.BatchStatusFailed, Error: "BOOM!"},
+			}))
+		})
+	})
+})
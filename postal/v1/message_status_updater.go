@@ -1,33 +1,100 @@
 package v1
 
 import (
+	"time"
+	"unicode/utf8"
+
 	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/pivotal-golang/lager"
 )
 
+// maxReasonLength is the size of the reason column on messages.
+const maxReasonLength = 1024
+
 type MessageStatusUpdater struct {
-	messagesRepo MessageUpserter
+	messagesRepo    messagesRepoUpdater
+	webhookNotifier webhookNotifier
 }
 
-type MessageUpserter interface {
+type messagesRepoUpdater interface {
+	FindByID(conn models.ConnectionInterface, messageID string) (models.Message, error)
 	Upsert(conn models.ConnectionInterface, message models.Message) (models.Message, error)
+	RecordAttempt(conn models.ConnectionInterface, messageID, email string) error
+}
+
+type webhookNotifier interface {
+	Notify(conn db.ConnectionInterface, message models.Message) error
 }
 
-func NewMessageStatusUpdater(messagesRepo MessageUpserter) MessageStatusUpdater {
+func NewMessageStatusUpdater(messagesRepo messagesRepoUpdater, webhookNotifier webhookNotifier) MessageStatusUpdater {
 	return MessageStatusUpdater{
-		messagesRepo: messagesRepo,
+		messagesRepo:    messagesRepo,
+		webhookNotifier: webhookNotifier,
 	}
 }
 
-func (mu MessageStatusUpdater) Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger) {
-	_, err := mu.messagesRepo.Upsert(conn, models.Message{
-		ID:         messageID,
-		Status:     messageStatus,
-	})
+func (mu MessageStatusUpdater) Update(conn db.ConnectionInterface, messageID, messageStatus, reason string, logger lager.Logger) {
+	previous, err := mu.messagesRepo.FindByID(conn, messageID)
+	if _, ok := err.(models.NotFoundError); err != nil && !ok {
+		logger.Session("message-updater").Error("failed-message-status-lookup", err, lager.Data{
+			"status": messageStatus,
+		})
+		return
+	}
+
+	message := models.Message{
+		ID:     messageID,
+		Status: messageStatus,
+		Reason: truncateReason(reason),
+	}
+
+	if messageStatus == common.StatusDelivered {
+		deliveredAt := time.Now().Truncate(1 * time.Second).UTC()
+		message.DeliveredAt = &deliveredAt
+	}
+
+	message, err = mu.messagesRepo.Upsert(conn, message)
 	if err != nil {
 		logger.Session("message-updater").Error("failed-message-status-upsert", err, lager.Data{
 			"status": messageStatus,
 		})
+		return
+	}
+
+	// Webhooks hear about transitions only, so retries that fail the same way
+	// again do not repeat the event.
+	if previous.Status == message.Status {
+		return
+	}
+
+	err = mu.webhookNotifier.Notify(conn, message)
+	if err != nil {
+		logger.Session("message-updater").Error("failed-webhook-enqueue", err, lager.Data{
+			"status": messageStatus,
+		})
+	}
+}
+
+// truncateReason cuts reasons such as long SMTP replies down to what the
+// column holds, without splitting a multi-byte character.
+func truncateReason(reason string) string {
+	if len(reason) <= maxReasonLength {
+		return reason
+	}
+
+	cut := maxReasonLength
+	for cut > 0 && !utf8.RuneStart(reason[cut]) {
+		cut--
+	}
+
+	return reason[:cut]
+}
+
+func (mu MessageStatusUpdater) RecordAttempt(conn db.ConnectionInterface, messageID, email string, logger lager.Logger) {
+	err := mu.messagesRepo.RecordAttempt(conn, messageID, email)
+	if err != nil {
+		logger.Session("message-updater").Error("failed-message-attempt-record", err)
 	}
 }
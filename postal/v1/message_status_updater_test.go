@@ -3,6 +3,8 @@ package v1_test
 import (
 	"bytes"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/postal/v1"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
@@ -17,6 +19,7 @@ var _ = Describe("MessageStatusUpdater", func() {
 	var (
 		updater      v1.MessageStatusUpdater
 		messagesRepo *mocks.MessagesRepo
+		notifier     *mocks.WebhookNotifier
 		logger       lager.Logger
 		buffer       *bytes.Buffer
 		conn         *mocks.Connection
@@ -27,33 +30,85 @@ var _ = Describe("MessageStatusUpdater", func() {
 		messagesRepo = mocks.NewMessagesRepo()
 		messagesRepo.UpsertCall.Returns.Messages = []models.Message{
 			{
-				ID:     "some-message-id",
-				Status: "message-status",
+				ID:      "some-message-id",
+				Status:  "message-status",
+				BatchID: "some-batch-id",
 			},
 		}
+		notifier = mocks.NewWebhookNotifier()
 
 		buffer = bytes.NewBuffer([]byte{})
 		logger = lager.NewLogger("notifications")
 		logger.RegisterSink(lager.NewWriterSink(buffer, lager.INFO))
 
-		updater = v1.NewMessageStatusUpdater(messagesRepo)
+		updater = v1.NewMessageStatusUpdater(messagesRepo, notifier)
 	})
 
 	It("updates the status of the message", func() {
-		updater.Update(conn, "some-message-id", "message-status", "campaign-id", logger)
+		updater.Update(conn, "some-message-id", "message-status", "some-reason", logger)
 
 		Expect(messagesRepo.UpsertCall.Receives.Connection).To(Equal(conn))
 		Expect(messagesRepo.UpsertCall.Receives.Messages[0]).To(Equal(models.Message{
 			ID:     "some-message-id",
 			Status: "message-status",
+			Reason: "some-reason",
 		}))
 	})
 
+	It("truncates reasons that do not fit in the reason column", func() {
+		reason := strings.Repeat("a", 1023) + "é and the rest of a long SMTP reply"
+		updater.Update(conn, "some-message-id", "failed", reason, logger)
+
+		Expect(messagesRepo.UpsertCall.Receives.Messages[0].Reason).To(Equal(strings.Repeat("a", 1023)))
+	})
+
+	It("records when the message was delivered", func() {
+		updater.Update(conn, "some-message-id", "delivered", "", logger)
+
+		message := messagesRepo.UpsertCall.Receives.Messages[0]
+		Expect(message.Status).To(Equal("delivered"))
+		Expect(message.DeliveredAt).NotTo(BeNil())
+		Expect(*message.DeliveredAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+	})
+
+	It("notifies the client's webhooks of the new status", func() {
+		updater.Update(conn, "some-message-id", "message-status", "some-reason", logger)
+
+		Expect(notifier.NotifyCall.Receives.Connection).To(Equal(conn))
+		Expect(notifier.NotifyCall.Receives.Message).To(Equal(models.Message{
+			ID:      "some-message-id",
+			Status:  "message-status",
+			BatchID: "some-batch-id",
+		}))
+	})
+
+	It("does not notify the webhooks when the status has not changed", func() {
+		messagesRepo.FindByIDCall.Returns.Message = models.Message{
+			ID:     "some-message-id",
+			Status: "message-status",
+		}
+
+		updater.Update(conn, "some-message-id", "message-status", "another-reason", logger)
+
+		Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("some-message-id"))
+		Expect(messagesRepo.UpsertCall.CallCount).To(Equal(1))
+		Expect(notifier.NotifyCall.CallCount).To(Equal(0))
+	})
+
+	It("notifies the webhooks of messages it has not seen before", func() {
+		messagesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		updater.Update(conn, "some-message-id", "message-status", "some-reason", logger)
+
+		Expect(messagesRepo.UpsertCall.CallCount).To(Equal(1))
+		Expect(notifier.NotifyCall.CallCount).To(Equal(1))
+	})
+
 	Context("failure cases", func() {
 		It("logs the error when the repository fails to upsert", func() {
 			messagesRepo.UpsertCall.Returns.Error = errors.New("failed to upsert")
 
-			updater.Update(conn, "some-message-id", "message-status", "campaign-id", logger)
+			updater.Update(conn, "some-message-id", "message-status", "some-reason", logger)
 
 			lines, err := parseLogLines(buffer.Bytes())
 			Expect(err).NotTo(HaveOccurred())
@@ -72,5 +127,63 @@ var _ = Describe("MessageStatusUpdater", func() {
 				},
 			}))
 		})
+
+		It("logs the error and leaves the message alone when the current status cannot be read", func() {
+			messagesRepo.FindByIDCall.Returns.Error = errors.New("database is down")
+
+			updater.Update(conn, "some-message-id", "message-status", "some-reason", logger)
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0].Message).To(Equal("notifications.message-updater.failed-message-status-lookup"))
+			Expect(lines[0].Data["error"]).To(Equal("database is down"))
+			Expect(messagesRepo.UpsertCall.CallCount).To(Equal(0))
+			Expect(notifier.NotifyCall.CallCount).To(Equal(0))
+		})
+
+		It("does not notify webhooks when the upsert fails", func() {
+			messagesRepo.UpsertCall.Returns.Error = errors.New("failed to upsert")
+
+			updater.Update(conn, "some-message-id", "message-status", "some-reason", logger)
+
+			Expect(notifier.NotifyCall.CallCount).To(Equal(0))
+		})
+
+		It("logs the error when the webhooks cannot be notified", func() {
+			notifier.NotifyCall.Returns.Error = errors.New("queue is down")
+
+			updater.Update(conn, "some-message-id", "message-status", "some-reason", logger)
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0].Message).To(Equal("notifications.message-updater.failed-webhook-enqueue"))
+			Expect(lines[0].Data["error"]).To(Equal("queue is down"))
+		})
+	})
+
+	Describe("RecordAttempt", func() {
+		It("records the attempt and the recipient's email", func() {
+			updater.RecordAttempt(conn, "some-message-id", "user@example.com", logger)
+
+			Expect(messagesRepo.RecordAttemptCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.RecordAttemptCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(messagesRepo.RecordAttemptCall.Receives.Email).To(Equal("user@example.com"))
+		})
+
+		It("logs the error when the repository fails", func() {
+			messagesRepo.RecordAttemptCall.Returns.Error = errors.New("failed to record")
+
+			updater.RecordAttempt(conn, "some-message-id", "user@example.com", logger)
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0].Message).To(Equal("notifications.message-updater.failed-message-attempt-record"))
+		})
 	})
 })
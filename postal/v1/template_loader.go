@@ -18,20 +18,26 @@ type templateFinder interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
 }
 
+type templateVersionFinder interface {
+	Find(connection models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+}
+
 type TemplatesLoader struct {
 	database db.DatabaseInterface
 
-	clientsRepo   clientFinder
-	kindsRepo     kindFinder
-	templatesRepo templateFinder
+	clientsRepo          clientFinder
+	kindsRepo            kindFinder
+	templatesRepo        templateFinder
+	templateVersionsRepo templateVersionFinder
 }
 
-func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder) TemplatesLoader {
+func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder, templateVersionsRepo templateVersionFinder) TemplatesLoader {
 	return TemplatesLoader{
-		database:      database,
-		clientsRepo:   clientsRepo,
-		kindsRepo:     kindsRepo,
-		templatesRepo: templatesRepo,
+		database:             database,
+		clientsRepo:          clientsRepo,
+		kindsRepo:            kindsRepo,
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
 	}
 }
 
@@ -44,8 +50,8 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 			return common.Templates{}, err
 		}
 
-		if kind.TemplateID != models.DefaultTemplateID {
-			return loader.loadTemplate(conn, kind.TemplateID)
+		if kind.TemplateID != models.DefaultTemplateID || kind.TemplateVersion != models.LatestTemplateVersion {
+			return loader.loadTemplate(conn, kind.TemplateToUse(), kind.TemplateVersion)
 		}
 	}
 
@@ -54,18 +60,50 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 		return common.Templates{}, err
 	}
 
-	return loader.loadTemplate(conn, client.TemplateID)
+	return loader.loadTemplate(conn, client.TemplateID, client.TemplateVersion)
 }
 
-func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID string) (common.Templates, error) {
+func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID string, version int) (common.Templates, error) {
+	if version != models.LatestTemplateVersion {
+		templateVersion, err := loader.templateVersionsRepo.Find(conn, templateID, version)
+		if err != nil {
+			return common.Templates{}, err
+		}
+
+		return newTemplates(templateVersion.Template())
+	}
+
 	template, err := loader.templatesRepo.FindByID(conn, templateID)
 	if err != nil {
 		return common.Templates{}, err
 	}
 
-	return common.Templates{
+	return newTemplates(template)
+}
+
+func newTemplates(template models.Template) (common.Templates, error) {
+	localizations, err := template.ParseLocalizations()
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	templates := common.Templates{
 		Subject: template.Subject,
 		Text:    template.Text,
 		HTML:    template.HTML,
-	}, nil
+	}
+
+	for locale, localization := range localizations {
+		if templates.Localizations == nil {
+			templates.Localizations = map[string]common.Templates{}
+		}
+
+		templates.Localizations[locale] = common.Templates{
+			Subject: localization.Subject,
+			Text:    localization.Text,
+			HTML:    localization.HTML,
+		}
+	}
+
+	return templates, nil
 }
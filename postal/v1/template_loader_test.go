@@ -1,6 +1,7 @@
 package v1_test
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/cloudfoundry-incubator/notifications/db"
@@ -19,6 +20,7 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo   *mocks.ClientsRepository
 		kindsRepo     *mocks.KindsRepo
 		templatesRepo *mocks.TemplatesRepo
+		versionsRepo  *mocks.TemplateVersionsRepo
 		conn          db.ConnectionInterface
 		database      *mocks.Database
 	)
@@ -27,12 +29,13 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		versionsRepo = mocks.NewTemplateVersionsRepo()
 
 		conn = mocks.NewConnection()
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, versionsRepo)
 	})
 
 	Describe("LoadTemplates", func() {
@@ -120,6 +123,100 @@ var _ = Describe("TemplateLoader", func() {
 				Expect(templatesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
 				Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("my-client-template"))
 			})
+
+			It("includes the localized variants of the template", func() {
+				templatesRepo.FindByIDCall.Returns.Template.Localizations = `{"fr":{"subject":"sujet","text":"texte","html":"<p>modèle</p>"}}`
+
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Localizations).To(Equal(map[string]common.Templates{
+					"fr": {
+						Subject: "sujet",
+						Text:    "texte",
+						HTML:    "<p>modèle</p>",
+					},
+				}))
+			})
+
+			It("returns an error when the localizations cannot be parsed", func() {
+				templatesRepo.FindByIDCall.Returns.Template.Localizations = "%%%"
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				Expect(err).To(BeAssignableToTypeOf(&json.SyntaxError{}))
+			})
+		})
+
+		Context("when the kind is pinned to a template version", func() {
+			BeforeEach(func() {
+				versionsRepo.FindCall.Returns.TemplateVersion = models.TemplateVersion{
+					TemplateID: models.DefaultTemplateID,
+					Version:    3,
+					HTML:       "<p>version 3</p>",
+					Text:       "version 3",
+					Subject:    "version 3 subject",
+				}
+
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:              "my-kind-id",
+						ClientID:        "my-client-id",
+						TemplateID:      models.DefaultTemplateID,
+						TemplateVersion: 3,
+					},
+				}
+			})
+
+			It("returns the content of that version", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					HTML:    "<p>version 3</p>",
+					Text:    "version 3",
+					Subject: "version 3 subject",
+				}))
+
+				Expect(versionsRepo.FindCall.Receives.Connection).To(Equal(conn))
+				Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal(models.DefaultTemplateID))
+				Expect(versionsRepo.FindCall.Receives.Version).To(Equal(3))
+			})
+		})
+
+		Context("when the client is pinned to a template version", func() {
+			BeforeEach(func() {
+				versionsRepo.FindCall.Returns.TemplateVersion = models.TemplateVersion{
+					TemplateID: "my-client-template",
+					Version:    2,
+					HTML:       "<p>version 2</p>",
+					Text:       "version 2",
+					Subject:    "version 2 subject",
+				}
+
+				clientsRepo.FindCall.Returns.Client = models.Client{
+					ID:              "my-client-id",
+					TemplateID:      "my-client-template",
+					TemplateVersion: 2,
+				}
+			})
+
+			It("returns the content of that version", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					HTML:    "<p>version 2</p>",
+					Text:    "version 2",
+					Subject: "version 2 subject",
+				}))
+
+				Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal("my-client-template"))
+				Expect(versionsRepo.FindCall.Receives.Version).To(Equal(2))
+			})
+
+			It("bubbles up errors finding the version", func() {
+				versionsRepo.FindCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
 		})
 
 		Context("when the neither client nor kind has a template", func() {
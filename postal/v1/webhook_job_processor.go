@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/lager"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	DefaultWebhookMaxRetries    = 8
+	DefaultWebhookRetryInterval = 30 * time.Second
+
+	WebhookSignatureHeader = "X-Notifications-Signature"
+	WebhookIDHeader        = "X-Notifications-Webhook-Id"
+)
+
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type WebhookJobProcessorConfig struct {
+	MaxRetries    int
+	RetryInterval time.Duration
+
+	Database     db.DatabaseInterface
+	WebhooksRepo webhooksFinder
+	HTTPClient   httpClient
+}
+
+type WebhookJobProcessor struct {
+	maxRetries    int
+	retryInterval time.Duration
+
+	database     db.DatabaseInterface
+	webhooksRepo webhooksFinder
+	httpClient   httpClient
+}
+
+func NewWebhookJobProcessor(config WebhookJobProcessorConfig) WebhookJobProcessor {
+	maxRetries := config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultWebhookMaxRetries
+	}
+
+	retryInterval := config.RetryInterval
+	if retryInterval <= 0 {
+		retryInterval = DefaultWebhookRetryInterval
+	}
+
+	return WebhookJobProcessor{
+		maxRetries:    maxRetries,
+		retryInterval: retryInterval,
+
+		database:     config.Database,
+		webhooksRepo: config.WebhooksRepo,
+		httpClient:   config.HTTPClient,
+	}
+}
+
+func (p WebhookJobProcessor) Process(job *gobble.Job, logger lager.Logger) error {
+	var delivery WebhookDelivery
+	err := job.Unmarshal(&delivery)
+	if err != nil {
+		metrics.GetOrRegisterCounter("notifications.worker.panic.json", nil).Inc(1)
+
+		logger.Error("payload-unmarshal-failed", err)
+		job.Bury(err.Error())
+		return nil
+	}
+
+	logger = logger.Session("webhook", lager.Data{
+		"webhook_id": delivery.WebhookID,
+		"message_id": delivery.Event.MessageID,
+		"status":     delivery.Event.Status,
+	})
+
+	webhook, err := p.webhooksRepo.FindByID(p.database.Connection(), delivery.WebhookID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			logger.Info("webhook-unregistered")
+			return nil
+		}
+
+		p.retry(job, err, logger)
+		return nil
+	}
+
+	err = p.post(webhook, delivery.Event)
+	if err != nil {
+		p.retry(job, err, logger)
+		return nil
+	}
+
+	logger.Info("webhook-delivered")
+	metrics.GetOrRegisterCounter("notifications.webhooks.delivered", nil).Inc(1)
+
+	return nil
+}
+
+func (p WebhookJobProcessor) post(webhook models.Webhook, event WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookIDHeader, webhook.ID)
+	request.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, body))
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (p WebhookJobProcessor) retry(job common.Retryable, err error, logger lager.Logger) {
+	retryCount, _ := job.State()
+	if retryCount >= p.maxRetries {
+		job.Bury(err.Error())
+		logger.Error("webhook-failed-giving-up", err, lager.Data{
+			"retry_count": retryCount,
+		})
+
+		metrics.GetOrRegisterCounter("notifications.webhooks.dead", nil).Inc(1)
+		return
+	}
+
+	job.Retry(p.retryInterval << uint(retryCount))
+
+	retryCount, activeAt := job.State()
+	logger.Info("webhook-failed-retrying", lager.Data{
+		"error":       err.Error(),
+		"retry_count": retryCount,
+		"active_at":   activeAt.Format(time.RFC3339),
+	})
+
+	metrics.GetOrRegisterCounter("notifications.webhooks.retry", nil).Inc(1)
+}
+
+// SignWebhookPayload returns the value of the signature header sent with a
+// webhook body: the hex encoded HMAC-SHA256 of the body, keyed by the secret.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,177 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookJobProcessor", func() {
+	var (
+		processor    v1.WebhookJobProcessor
+		logger       lager.Logger
+		conn         *mocks.Connection
+		webhooksRepo *mocks.WebhooksRepo
+		server       *httptest.Server
+		job          *gobble.Job
+		statusCode   int
+		requests     []*http.Request
+		bodies       [][]byte
+	)
+
+	BeforeEach(func() {
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(bytes.NewBuffer([]byte{}), lager.DEBUG))
+
+		statusCode = http.StatusOK
+		requests = nil
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, err := ioutil.ReadAll(req.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			requests = append(requests, req)
+			bodies = append(bodies, body)
+			w.WriteHeader(statusCode)
+		}))
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		webhooksRepo = mocks.NewWebhooksRepo()
+		webhooksRepo.FindByIDCall.Returns.Webhook = models.Webhook{
+			ID:       "some-webhook-id",
+			ClientID: "some-client",
+			URL:      server.URL + "/hooks",
+			Secret:   "some-secret",
+		}
+
+		job = gobble.NewJob(v1.WebhookDelivery{
+			JobType:   v1.WebhookJobType,
+			WebhookID: "some-webhook-id",
+			Event: v1.WebhookEvent{
+				MessageID:     "some-message-id",
+				Status:        "delivered",
+				ClientID:      "some-client",
+				KindID:        "some-kind",
+				BatchID:       "some-batch-id",
+				VCAPRequestID: "some-request-id",
+				Timestamp:     time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC),
+			},
+		})
+
+		processor = v1.NewWebhookJobProcessor(v1.WebhookJobProcessorConfig{
+			MaxRetries:    3,
+			RetryInterval: time.Minute,
+			Database:      database,
+			WebhooksRepo:  webhooksRepo,
+			HTTPClient:    http.DefaultClient,
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts the signed event to the webhook url", func() {
+		err := processor.Process(job, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(webhooksRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
+		Expect(webhooksRepo.FindByIDCall.Receives.WebhookID).To(Equal("some-webhook-id"))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Method).To(Equal("POST"))
+		Expect(requests[0].URL.Path).To(Equal("/hooks"))
+		Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(requests[0].Header.Get("X-Notifications-Webhook-Id")).To(Equal("some-webhook-id"))
+		Expect(requests[0].Header.Get("X-Notifications-Signature")).To(Equal(v1.SignWebhookPayload("some-secret", bodies[0])))
+
+		Expect(bodies[0]).To(MatchJSON(`{
+			"message_id": "some-message-id",
+			"status": "delivered",
+			"client_id": "some-client",
+			"kind_id": "some-kind",
+			"batch_id": "some-batch-id",
+			"vcap_request_id": "some-request-id",
+			"timestamp": "2015-06-08T14:32:11Z"
+		}`))
+
+		Expect(job.ShouldRetry).To(BeFalse())
+		Expect(job.ShouldBury).To(BeFalse())
+	})
+
+	It("drops the job when the webhook has been unregistered", func() {
+		webhooksRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		err := processor.Process(job, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(requests).To(BeEmpty())
+		Expect(job.ShouldRetry).To(BeFalse())
+		Expect(job.ShouldBury).To(BeFalse())
+	})
+
+	Context("when the delivery fails", func() {
+		BeforeEach(func() {
+			statusCode = http.StatusInternalServerError
+		})
+
+		It("retries the job with an exponential backoff", func() {
+			job.RetryCount = 2
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(job.ShouldRetry).To(BeTrue())
+			Expect(job.RetryCount).To(Equal(3))
+			Expect(job.ActiveAt).To(BeTemporally("~", time.Now().Add(4*time.Minute), 5*time.Second))
+		})
+
+		It("buries the job once it runs out of retries", func() {
+			job.RetryCount = 3
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(job.ShouldRetry).To(BeFalse())
+			Expect(job.ShouldBury).To(BeTrue())
+			Expect(job.LastError).To(Equal("webhook responded with status 500"))
+		})
+
+		It("retries when the webhook cannot be loaded", func() {
+			webhooksRepo.FindByIDCall.Returns.Error = errors.New("db is down")
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(requests).To(BeEmpty())
+			Expect(job.ShouldRetry).To(BeTrue())
+		})
+	})
+
+	It("buries jobs that cannot be unmarshalled", func() {
+		job = &gobble.Job{Payload: "%%"}
+
+		err := processor.Process(job, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.ShouldBury).To(BeTrue())
+	})
+
+	It("signs payloads with an HMAC-SHA256 of the body", func() {
+		Expect(v1.SignWebhookPayload("key", []byte("The quick brown fox jumps over the lazy dog"))).To(Equal("sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"))
+	})
+})
@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+const WebhookJobType = "webhook"
+
+type WebhookEvent struct {
+	MessageID     string    `json:"message_id"`
+	Status        string    `json:"status"`
+	Reason        string    `json:"reason,omitempty"`
+	ClientID      string    `json:"client_id"`
+	KindID        string    `json:"kind_id"`
+	BatchID       string    `json:"batch_id"`
+	VCAPRequestID string    `json:"vcap_request_id"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type WebhookDelivery struct {
+	JobType   string
+	WebhookID string
+	Event     WebhookEvent
+}
+
+type batchFinder interface {
+	FindByID(conn models.ConnectionInterface, batchID string) (models.Batch, error)
+}
+
+type webhooksFinder interface {
+	FindByID(conn models.ConnectionInterface, webhookID string) (models.Webhook, error)
+	FindAllByClientID(conn models.ConnectionInterface, clientID string) ([]models.Webhook, error)
+}
+
+type jobQueue interface {
+	Enqueue(job *gobble.Job, connection gobble.ConnectionInterface) (*gobble.Job, error)
+}
+
+type gobbleInitializer interface {
+	InitializeDBMap(*gorp.DbMap)
+}
+
+type WebhookNotifier struct {
+	batchesRepo       batchFinder
+	webhooksRepo      webhooksFinder
+	queue             jobQueue
+	gobbleInitializer gobbleInitializer
+}
+
+func NewWebhookNotifier(batchesRepo batchFinder, webhooksRepo webhooksFinder, queue jobQueue, gobbleInitializer gobbleInitializer) WebhookNotifier {
+	return WebhookNotifier{
+		batchesRepo:       batchesRepo,
+		webhooksRepo:      webhooksRepo,
+		queue:             queue,
+		gobbleInitializer: gobbleInitializer,
+	}
+}
+
+func (n WebhookNotifier) Notify(conn db.ConnectionInterface, message models.Message) error {
+	if message.BatchID == "" {
+		return nil
+	}
+
+	batch, err := n.batchesRepo.FindByID(conn, message.BatchID)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := n.webhooksRepo.FindAllByClientID(conn, batch.ClientID)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	n.gobbleInitializer.InitializeDBMap(conn.GetDbMap())
+
+	event := WebhookEvent{
+		MessageID:     message.ID,
+		Status:        message.Status,
+		Reason:        message.Reason,
+		ClientID:      batch.ClientID,
+		KindID:        batch.KindID,
+		BatchID:       batch.ID,
+		VCAPRequestID: message.VCAPRequestID,
+		Timestamp:     message.UpdatedAt,
+	}
+
+	for _, webhook := range webhooks {
+		_, err := n.queue.Enqueue(gobble.NewJob(WebhookDelivery{
+			JobType:   WebhookJobType,
+			WebhookID: webhook.ID,
+			Event:     event,
+		}), conn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package v1_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"gopkg.in/gorp.v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookNotifier", func() {
+	var (
+		notifier          v1.WebhookNotifier
+		batchesRepo       *mocks.BatchesRepo
+		webhooksRepo      *mocks.WebhooksRepo
+		queue             *mocks.Queue
+		gobbleInitializer *mocks.GobbleInitializer
+		conn              *mocks.Connection
+		dbMap             *gorp.DbMap
+		message           models.Message
+	)
+
+	BeforeEach(func() {
+		dbMap = &gorp.DbMap{}
+		conn = mocks.NewConnection()
+		conn.GetDbMapCall.Returns.DbMap = dbMap
+
+		batchesRepo = mocks.NewBatchesRepo()
+		batchesRepo.FindByIDCall.Returns.Batch = models.Batch{
+			ID:       "some-batch-id",
+			ClientID: "some-client",
+			KindID:   "some-kind",
+		}
+
+		webhooksRepo = mocks.NewWebhooksRepo()
+		webhooksRepo.FindAllByClientIDCall.Returns.Webhooks = []models.Webhook{
+			{ID: "first-webhook", ClientID: "some-client", URL: "https://example.com/first"},
+			{ID: "second-webhook", ClientID: "some-client", URL: "https://example.com/second"},
+		}
+
+		queue = mocks.NewQueue()
+		gobbleInitializer = mocks.NewGobbleInitializer()
+
+		message = models.Message{
+			ID:            "some-message-id",
+			Status:        "delivered",
+			VCAPRequestID: "some-request-id",
+			BatchID:       "some-batch-id",
+			UpdatedAt:     time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC),
+		}
+
+		notifier = v1.NewWebhookNotifier(batchesRepo, webhooksRepo, queue, gobbleInitializer)
+	})
+
+	It("enqueues a webhook job for each of the client's webhooks", func() {
+		err := notifier.Notify(conn, message)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(batchesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
+		Expect(batchesRepo.FindByIDCall.Receives.BatchID).To(Equal("some-batch-id"))
+		Expect(webhooksRepo.FindAllByClientIDCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(gobbleInitializer.InitializeDBMapCall.Receives.DbMap).To(Equal(dbMap))
+
+		Expect(queue.EnqueueCall.Receives.Connection).To(Equal(conn))
+		Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(2))
+
+		var deliveries []v1.WebhookDelivery
+		for _, job := range queue.EnqueueCall.Receives.Jobs {
+			var delivery v1.WebhookDelivery
+			Expect(job.Unmarshal(&delivery)).To(Succeed())
+			deliveries = append(deliveries, delivery)
+		}
+
+		event := v1.WebhookEvent{
+			MessageID:     "some-message-id",
+			Status:        "delivered",
+			ClientID:      "some-client",
+			KindID:        "some-kind",
+			BatchID:       "some-batch-id",
+			VCAPRequestID: "some-request-id",
+			Timestamp:     time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC),
+		}
+		Expect(deliveries).To(Equal([]v1.WebhookDelivery{
+			{JobType: "webhook", WebhookID: "first-webhook", Event: event},
+			{JobType: "webhook", WebhookID: "second-webhook", Event: event},
+		}))
+	})
+
+	It("does nothing when the client has no webhooks", func() {
+		webhooksRepo.FindAllByClientIDCall.Returns.Webhooks = []models.Webhook{}
+
+		err := notifier.Notify(conn, message)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queue.EnqueueCall.Receives.Jobs).To(BeEmpty())
+	})
+
+	It("does nothing when the message does not belong to a batch", func() {
+		message.BatchID = ""
+
+		err := notifier.Notify(conn, message)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(webhooksRepo.FindAllByClientIDCall.Receives.ClientID).To(BeEmpty())
+		Expect(queue.EnqueueCall.Receives.Jobs).To(BeEmpty())
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the batch cannot be found", func() {
+			batchesRepo.FindByIDCall.Returns.Error = errors.New("no batch")
+
+			err := notifier.Notify(conn, message)
+			Expect(err).To(MatchError(errors.New("no batch")))
+		})
+
+		It("returns an error when the webhooks cannot be found", func() {
+			webhooksRepo.FindAllByClientIDCall.Returns.Error = errors.New("no webhooks")
+
+			err := notifier.Notify(conn, message)
+			Expect(err).To(MatchError(errors.New("no webhooks")))
+		})
+
+		It("returns an error when the job cannot be enqueued", func() {
+			queue.EnqueueCall.Returns.Error = errors.New("queue is down")
+
+			err := notifier.Notify(conn, message)
+			Expect(err).To(MatchError(errors.New("queue is down")))
+		})
+	})
+})
@@ -1,13 +1,17 @@
 package mocks
 
 type AllUsers struct {
-	AllUserGUIDsCall struct {
-		Receives struct {
-			Token string
+	UserGUIDsPageCall struct {
+		CallCount int
+		Receives  struct {
+			Token        string
+			StartIndexes []int
+			Count        int
 		}
 		Returns struct {
-			GUIDs []string
-			Error error
+			Pages        [][]string
+			TotalResults int
+			Error        error
 		}
 	}
 }
@@ -16,7 +20,16 @@ func NewAllUsers() *AllUsers {
 	return &AllUsers{}
 }
 
-func (au *AllUsers) AllUserGUIDs(token string) ([]string, error) {
-	au.AllUserGUIDsCall.Receives.Token = token
-	return au.AllUserGUIDsCall.Returns.GUIDs, au.AllUserGUIDsCall.Returns.Error
+func (au *AllUsers) UserGUIDsPage(token string, startIndex, count int) ([]string, int, error) {
+	au.UserGUIDsPageCall.Receives.Token = token
+	au.UserGUIDsPageCall.Receives.StartIndexes = append(au.UserGUIDsPageCall.Receives.StartIndexes, startIndex)
+	au.UserGUIDsPageCall.Receives.Count = count
+
+	var page []string
+	if au.UserGUIDsPageCall.CallCount < len(au.UserGUIDsPageCall.Returns.Pages) {
+		page = au.UserGUIDsPageCall.Returns.Pages[au.UserGUIDsPageCall.CallCount]
+	}
+	au.UserGUIDsPageCall.CallCount++
+
+	return page, au.UserGUIDsPageCall.Returns.TotalResults, au.UserGUIDsPageCall.Returns.Error
 }
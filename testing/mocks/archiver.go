@@ -0,0 +1,26 @@
+package mocks
+
+type Archiver struct {
+	ArchiveCall struct {
+		CallCount int
+		Receives  struct {
+			Name    string
+			Records []interface{}
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewArchiver() *Archiver {
+	return &Archiver{}
+}
+
+func (a *Archiver) Archive(name string, records []interface{}) error {
+	a.ArchiveCall.Receives.Name = name
+	a.ArchiveCall.Receives.Records = records
+	a.ArchiveCall.CallCount++
+
+	return a.ArchiveCall.Returns.Error
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type BatchFinder struct {
+	FindCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			BatchID  string
+		}
+		Returns struct {
+			Batch services.Batch
+			Error error
+		}
+	}
+}
+
+func NewBatchFinder() *BatchFinder {
+	return &BatchFinder{}
+}
+
+func (f *BatchFinder) Find(database services.DatabaseInterface, batchID string) (services.Batch, error) {
+	f.FindCall.Receives.Database = database
+	f.FindCall.Receives.BatchID = batchID
+
+	return f.FindCall.Returns.Batch, f.FindCall.Returns.Error
+}
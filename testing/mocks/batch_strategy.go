@@ -0,0 +1,25 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type BatchStrategy struct {
+	DispatchCall struct {
+		Receives struct {
+			Dispatch services.Dispatch
+		}
+		Returns struct {
+			Response services.BatchResponse
+			Error    error
+		}
+	}
+}
+
+func NewBatchStrategy() *BatchStrategy {
+	return &BatchStrategy{}
+}
+
+func (s *BatchStrategy) Dispatch(dispatch services.Dispatch) (services.BatchResponse, error) {
+	s.DispatchCall.Receives.Dispatch = dispatch
+
+	return s.DispatchCall.Returns.Response, s.DispatchCall.Returns.Error
+}
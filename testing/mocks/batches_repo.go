@@ -0,0 +1,108 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type BatchesRepo struct {
+	CreateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Batch      models.Batch
+		}
+		Returns struct {
+			Batch models.Batch
+			Error error
+		}
+	}
+
+	FindByIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			BatchID    string
+		}
+		Returns struct {
+			Batch models.Batch
+			Error error
+		}
+	}
+
+	UpdateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Batches    []models.Batch
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	FindExpiredCall struct {
+		CallCount int
+		Receives  struct {
+			Connection    models.ConnectionInterface
+			ThresholdTime time.Time
+			Limit         int
+		}
+		Returns struct {
+			Batches []models.Batch
+			Error   error
+		}
+	}
+
+	DeleteByIDsCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+			IDs        []string
+		}
+		Returns struct {
+			RowsAffected int
+			Error        error
+		}
+	}
+}
+
+func NewBatchesRepo() *BatchesRepo {
+	return &BatchesRepo{}
+}
+
+func (r *BatchesRepo) Create(conn models.ConnectionInterface, batch models.Batch) (models.Batch, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Batch = batch
+
+	return r.CreateCall.Returns.Batch, r.CreateCall.Returns.Error
+}
+
+func (r *BatchesRepo) FindByID(conn models.ConnectionInterface, batchID string) (models.Batch, error) {
+	r.FindByIDCall.Receives.Connection = conn
+	r.FindByIDCall.Receives.BatchID = batchID
+
+	return r.FindByIDCall.Returns.Batch, r.FindByIDCall.Returns.Error
+}
+
+func (r *BatchesRepo) Update(conn models.ConnectionInterface, batch models.Batch) (models.Batch, error) {
+	r.UpdateCall.Receives.Connection = conn
+	r.UpdateCall.Receives.Batches = append(r.UpdateCall.Receives.Batches, batch)
+
+	return batch, r.UpdateCall.Returns.Error
+}
+
+func (r *BatchesRepo) FindExpired(conn models.ConnectionInterface, thresholdTime time.Time, limit int) ([]models.Batch, error) {
+	r.FindExpiredCall.Receives.Connection = conn
+	r.FindExpiredCall.Receives.ThresholdTime = thresholdTime
+	r.FindExpiredCall.Receives.Limit = limit
+	r.FindExpiredCall.CallCount++
+
+	return r.FindExpiredCall.Returns.Batches, r.FindExpiredCall.Returns.Error
+}
+
+func (r *BatchesRepo) DeleteByIDs(conn models.ConnectionInterface, ids []string) (int, error) {
+	r.DeleteByIDsCall.Receives.Connection = conn
+	r.DeleteByIDsCall.Receives.IDs = ids
+	r.DeleteByIDsCall.CallCount++
+
+	return r.DeleteByIDsCall.Returns.RowsAffected, r.DeleteByIDsCall.Returns.Error
+}
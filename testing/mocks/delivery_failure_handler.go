@@ -10,6 +10,7 @@ type DeliveryFailureHandler struct {
 		WasCalled bool
 		Receives  struct {
 			Job    common.Retryable
+			Error  error
 			Logger lager.Logger
 		}
 	}
@@ -19,8 +20,9 @@ func NewDeliveryFailureHandler() *DeliveryFailureHandler {
 	return &DeliveryFailureHandler{}
 }
 
-func (h *DeliveryFailureHandler) Handle(job common.Retryable, logger lager.Logger) {
+func (h *DeliveryFailureHandler) Handle(job common.Retryable, err error, logger lager.Logger) {
 	h.HandleCall.WasCalled = true
 	h.HandleCall.Receives.Job = job
+	h.HandleCall.Receives.Error = err
 	h.HandleCall.Receives.Logger = logger
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 )
 
@@ -27,6 +28,27 @@ type Enqueuer struct {
 			Err       error
 		}
 	}
+
+	EnqueueBatchCall struct {
+		CallCount int
+		Receives  struct {
+			Connection      services.ConnectionInterface
+			Batches         []models.Batch
+			Users           [][]services.User
+			Options         services.Options
+			Space           cf.CloudControllerSpace
+			Org             cf.CloudControllerOrganization
+			Client          string
+			Scope           string
+			VCAPRequestID   string
+			RequestReceived time.Time
+			UAAHost         string
+		}
+		Returns struct {
+			Responses []services.Response
+			Err       error
+		}
+	}
 }
 
 func NewEnqueuer() *Enqueuer {
@@ -59,3 +81,32 @@ func (m *Enqueuer) Enqueue(
 	m.EnqueueCall.WasCalled = true
 	return m.EnqueueCall.Returns.Responses, m.EnqueueCall.Returns.Err
 }
+
+func (m *Enqueuer) EnqueueBatch(
+	conn services.ConnectionInterface,
+	batch models.Batch,
+	users []services.User,
+	options services.Options,
+	space cf.CloudControllerSpace,
+	org cf.CloudControllerOrganization,
+	client string,
+	uaaHost string,
+	scope string,
+	vcapRequestID string,
+	reqReceived time.Time) ([]services.Response, error) {
+
+	m.EnqueueBatchCall.Receives.Connection = conn
+	m.EnqueueBatchCall.Receives.Batches = append(m.EnqueueBatchCall.Receives.Batches, batch)
+	m.EnqueueBatchCall.Receives.Users = append(m.EnqueueBatchCall.Receives.Users, users)
+	m.EnqueueBatchCall.Receives.Options = options
+	m.EnqueueBatchCall.Receives.Space = space
+	m.EnqueueBatchCall.Receives.Org = org
+	m.EnqueueBatchCall.Receives.Client = client
+	m.EnqueueBatchCall.Receives.UAAHost = uaaHost
+	m.EnqueueBatchCall.Receives.Scope = scope
+	m.EnqueueBatchCall.Receives.VCAPRequestID = vcapRequestID
+	m.EnqueueBatchCall.Receives.RequestReceived = reqReceived
+
+	m.EnqueueBatchCall.CallCount++
+	return m.EnqueueBatchCall.Returns.Responses, m.EnqueueBatchCall.Returns.Err
+}
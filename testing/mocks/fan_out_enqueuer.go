@@ -0,0 +1,29 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type FanOutEnqueuer struct {
+	EnqueueCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			FanOut     services.FanOut
+		}
+		Returns struct {
+			Response services.BatchResponse
+			Error    error
+		}
+	}
+}
+
+func NewFanOutEnqueuer() *FanOutEnqueuer {
+	return &FanOutEnqueuer{}
+}
+
+func (m *FanOutEnqueuer) Enqueue(conn services.ConnectionInterface, fanOut services.FanOut) (services.BatchResponse, error) {
+	m.EnqueueCall.WasCalled = true
+	m.EnqueueCall.Receives.Connection = conn
+	m.EnqueueCall.Receives.FanOut = fanOut
+
+	return m.EnqueueCall.Returns.Response, m.EnqueueCall.Returns.Error
+}
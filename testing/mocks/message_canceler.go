@@ -0,0 +1,44 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type MessageCanceler struct {
+	CancelCall struct {
+		Receives struct {
+			Database  services.DatabaseInterface
+			MessageID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	CancelByVCAPRequestIDCall struct {
+		Receives struct {
+			Database      services.DatabaseInterface
+			VCAPRequestID string
+		}
+		Returns struct {
+			Messages []services.Message
+			Error    error
+		}
+	}
+}
+
+func NewMessageCanceler() *MessageCanceler {
+	return &MessageCanceler{}
+}
+
+func (c *MessageCanceler) Cancel(database services.DatabaseInterface, messageID string) error {
+	c.CancelCall.Receives.Database = database
+	c.CancelCall.Receives.MessageID = messageID
+
+	return c.CancelCall.Returns.Error
+}
+
+func (c *MessageCanceler) CancelByVCAPRequestID(database services.DatabaseInterface, vcapRequestID string) ([]services.Message, error) {
+	c.CancelByVCAPRequestIDCall.Receives.Database = database
+	c.CancelByVCAPRequestIDCall.Receives.VCAPRequestID = vcapRequestID
+
+	return c.CancelByVCAPRequestIDCall.Returns.Messages, c.CancelByVCAPRequestIDCall.Returns.Error
+}
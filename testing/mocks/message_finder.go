@@ -13,6 +13,17 @@ type MessageFinder struct {
 			Error   error
 		}
 	}
+
+	ListCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			Filter   services.MessageFilter
+		}
+		Returns struct {
+			Messages []services.Message
+			Error    error
+		}
+	}
 }
 
 func NewMessageFinder() *MessageFinder {
@@ -25,3 +36,10 @@ func (f *MessageFinder) Find(database services.DatabaseInterface, messageID stri
 
 	return f.FindCall.Returns.Message, f.FindCall.Returns.Error
 }
+
+func (f *MessageFinder) List(database services.DatabaseInterface, filter services.MessageFilter) ([]services.Message, error) {
+	f.ListCall.Receives.Database = database
+	f.ListCall.Receives.Filter = filter
+
+	return f.ListCall.Returns.Messages, f.ListCall.Returns.Error
+}
@@ -11,20 +11,38 @@ type MessageStatusUpdater struct {
 			Connection    db.ConnectionInterface
 			MessageID     string
 			MessageStatus string
-			CampaignID    string
+			Reason        string
 			Logger        lager.Logger
 		}
 	}
+
+	RecordAttemptCall struct {
+		CallCount int
+		Receives  struct {
+			Connection db.ConnectionInterface
+			MessageID  string
+			Email      string
+			Logger     lager.Logger
+		}
+	}
 }
 
 func NewMessageStatusUpdater() *MessageStatusUpdater {
 	return &MessageStatusUpdater{}
 }
 
-func (msu *MessageStatusUpdater) Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger) {
+func (msu *MessageStatusUpdater) Update(conn db.ConnectionInterface, messageID, messageStatus, reason string, logger lager.Logger) {
 	msu.UpdateCall.Receives.Connection = conn
 	msu.UpdateCall.Receives.MessageID = messageID
 	msu.UpdateCall.Receives.MessageStatus = messageStatus
-	msu.UpdateCall.Receives.CampaignID = campaignID
+	msu.UpdateCall.Receives.Reason = reason
 	msu.UpdateCall.Receives.Logger = logger
 }
+
+func (msu *MessageStatusUpdater) RecordAttempt(conn db.ConnectionInterface, messageID, email string, logger lager.Logger) {
+	msu.RecordAttemptCall.CallCount++
+	msu.RecordAttemptCall.Receives.Connection = conn
+	msu.RecordAttemptCall.Receives.MessageID = messageID
+	msu.RecordAttemptCall.Receives.Email = email
+	msu.RecordAttemptCall.Receives.Logger = logger
+}
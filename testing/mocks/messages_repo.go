@@ -41,12 +41,70 @@ type MessagesRepo struct {
 		}
 	}
 
-	DeleteBeforeCall struct {
+	FindAllByVCAPRequestIDCall struct {
+		Receives struct {
+			Connection    models.ConnectionInterface
+			VCAPRequestID string
+		}
+		Returns struct {
+			Messages []models.Message
+			Error    error
+		}
+	}
+
+	CountByStatusForBatchCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			BatchID    string
+		}
+		Returns struct {
+			Counts map[string]int
+			Error  error
+		}
+	}
+
+	SearchCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Query      models.MessageQuery
+		}
+		Returns struct {
+			Messages []models.Message
+			Error    error
+		}
+	}
+
+	RecordAttemptCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			MessageID  string
+			Email      string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	FindExpiredCall struct {
 		InvocationTimes []time.Time
 		CallCount       int
 		Receives        struct {
 			Connection    models.ConnectionInterface
+			Status        string
 			ThresholdTime time.Time
+			Limit         int
+		}
+		Returns struct {
+			Messages []models.Message
+			Error    error
+		}
+	}
+
+	DeleteByIDsCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+			IDs        []string
 		}
 		Returns struct {
 			RowsAffected int
@@ -83,11 +141,50 @@ func (mr *MessagesRepo) FindByID(conn models.ConnectionInterface, messageID stri
 	return mr.FindByIDCall.Returns.Message, mr.FindByIDCall.Returns.Error
 }
 
-func (mr *MessagesRepo) DeleteBefore(conn models.ConnectionInterface, thresholdTime time.Time) (int, error) {
-	mr.DeleteBeforeCall.Receives.Connection = conn
-	mr.DeleteBeforeCall.Receives.ThresholdTime = thresholdTime
-	mr.DeleteBeforeCall.InvocationTimes = append(mr.DeleteBeforeCall.InvocationTimes, time.Now())
-	mr.DeleteBeforeCall.CallCount++
+func (mr *MessagesRepo) FindAllByVCAPRequestID(conn models.ConnectionInterface, vcapRequestID string) ([]models.Message, error) {
+	mr.FindAllByVCAPRequestIDCall.Receives.Connection = conn
+	mr.FindAllByVCAPRequestIDCall.Receives.VCAPRequestID = vcapRequestID
+
+	return mr.FindAllByVCAPRequestIDCall.Returns.Messages, mr.FindAllByVCAPRequestIDCall.Returns.Error
+}
+
+func (mr *MessagesRepo) FindExpired(conn models.ConnectionInterface, status string, thresholdTime time.Time, limit int) ([]models.Message, error) {
+	mr.FindExpiredCall.Receives.Connection = conn
+	mr.FindExpiredCall.Receives.Status = status
+	mr.FindExpiredCall.Receives.ThresholdTime = thresholdTime
+	mr.FindExpiredCall.Receives.Limit = limit
+	mr.FindExpiredCall.InvocationTimes = append(mr.FindExpiredCall.InvocationTimes, time.Now())
+	mr.FindExpiredCall.CallCount++
+
+	return mr.FindExpiredCall.Returns.Messages, mr.FindExpiredCall.Returns.Error
+}
+
+func (mr *MessagesRepo) DeleteByIDs(conn models.ConnectionInterface, ids []string) (int, error) {
+	mr.DeleteByIDsCall.Receives.Connection = conn
+	mr.DeleteByIDsCall.Receives.IDs = ids
+	mr.DeleteByIDsCall.CallCount++
+
+	return mr.DeleteByIDsCall.Returns.RowsAffected, mr.DeleteByIDsCall.Returns.Error
+}
+
+func (mr *MessagesRepo) CountByStatusForBatch(conn models.ConnectionInterface, batchID string) (map[string]int, error) {
+	mr.CountByStatusForBatchCall.Receives.Connection = conn
+	mr.CountByStatusForBatchCall.Receives.BatchID = batchID
+
+	return mr.CountByStatusForBatchCall.Returns.Counts, mr.CountByStatusForBatchCall.Returns.Error
+}
+
+func (mr *MessagesRepo) Search(conn models.ConnectionInterface, query models.MessageQuery) ([]models.Message, error) {
+	mr.SearchCall.Receives.Connection = conn
+	mr.SearchCall.Receives.Query = query
+
+	return mr.SearchCall.Returns.Messages, mr.SearchCall.Returns.Error
+}
+
+func (mr *MessagesRepo) RecordAttempt(conn models.ConnectionInterface, messageID, email string) error {
+	mr.RecordAttemptCall.Receives.Connection = conn
+	mr.RecordAttemptCall.Receives.MessageID = messageID
+	mr.RecordAttemptCall.Receives.Email = email
 
-	return mr.DeleteBeforeCall.Returns.RowsAffected, mr.DeleteBeforeCall.Returns.Error
+	return mr.RecordAttemptCall.Returns.Error
 }
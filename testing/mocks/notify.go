@@ -23,6 +23,22 @@ type Notify struct {
 			Error    error
 		}
 	}
+
+	ExecuteBatchCall struct {
+		Receives struct {
+			Connection    notify.ConnectionInterface
+			Request       *http.Request
+			Context       stack.Context
+			GUID          string
+			Strategy      notify.BatchDispatcher
+			Validator     notify.ValidatorInterface
+			VCAPRequestID string
+		}
+		Returns struct {
+			Response []byte
+			Error    error
+		}
+	}
 }
 
 func NewNotify() *Notify {
@@ -42,3 +58,17 @@ func (n *Notify) Execute(connection notify.ConnectionInterface, req *http.Reques
 
 	return n.ExecuteCall.Returns.Response, n.ExecuteCall.Returns.Error
 }
+
+func (n *Notify) ExecuteBatch(connection notify.ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy notify.BatchDispatcher, validator notify.ValidatorInterface, vcapRequestID string) ([]byte, error) {
+
+	n.ExecuteBatchCall.Receives.Connection = connection
+	n.ExecuteBatchCall.Receives.Request = req
+	n.ExecuteBatchCall.Receives.Context = context
+	n.ExecuteBatchCall.Receives.GUID = guid
+	n.ExecuteBatchCall.Receives.Strategy = strategy
+	n.ExecuteBatchCall.Receives.Validator = validator
+	n.ExecuteBatchCall.Receives.VCAPRequestID = vcapRequestID
+
+	return n.ExecuteBatchCall.Returns.Response, n.ExecuteBatchCall.Returns.Error
+}
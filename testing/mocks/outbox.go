@@ -0,0 +1,48 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/mail"
+
+type Outbox struct {
+	ListCall struct {
+		Returns struct {
+			Messages []mail.CapturedMessage
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			ID int
+		}
+		Returns struct {
+			Message mail.CapturedMessage
+			Error   error
+		}
+	}
+
+	ClearCall struct {
+		WasCalled bool
+		Returns   struct {
+			Count int
+		}
+	}
+}
+
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+func (o *Outbox) List() []mail.CapturedMessage {
+	return o.ListCall.Returns.Messages
+}
+
+func (o *Outbox) Find(id int) (mail.CapturedMessage, error) {
+	o.FindCall.Receives.ID = id
+
+	return o.FindCall.Returns.Message, o.FindCall.Returns.Error
+}
+
+func (o *Outbox) Clear() int {
+	o.ClearCall.WasCalled = true
+
+	return o.ClearCall.Returns.Count
+}
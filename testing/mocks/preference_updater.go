@@ -11,6 +11,7 @@ type PreferenceUpdater struct {
 			Connection        services.ConnectionInterface
 			Preferences       []models.Preference
 			GlobalUnsubscribe bool
+			Locale            *string
 			UserID            string
 		}
 		Returns struct {
@@ -23,10 +24,11 @@ func NewPreferenceUpdater() *PreferenceUpdater {
 	return &PreferenceUpdater{}
 }
 
-func (pu *PreferenceUpdater) Update(conn services.ConnectionInterface, preferences []models.Preference, globalUnsubscribe bool, userID string) error {
+func (pu *PreferenceUpdater) Update(conn services.ConnectionInterface, preferences []models.Preference, globalUnsubscribe bool, locale *string, userID string) error {
 	pu.UpdateCall.Receives.Connection = conn
 	pu.UpdateCall.Receives.Preferences = preferences
 	pu.UpdateCall.Receives.GlobalUnsubscribe = globalUnsubscribe
+	pu.UpdateCall.Receives.Locale = locale
 	pu.UpdateCall.Receives.UserID = userID
 
 	return pu.UpdateCall.Returns.Error
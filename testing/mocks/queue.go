@@ -28,6 +28,22 @@ type Queue struct {
 		}
 	}
 
+	DequeueByKeyCall struct {
+		Receives struct {
+			Keys []string
+		}
+		Returns struct {
+			Dequeued bool
+			Error    error
+		}
+	}
+
+	BuryCall struct {
+		Receives struct {
+			Job *gobble.Job
+		}
+	}
+
 	LenCall struct {
 		Returns struct {
 			Length int
@@ -44,6 +60,54 @@ type Queue struct {
 		}
 	}
 
+	DeadJobsCall struct {
+		Receives struct {
+			Offset int
+			Limit  int
+		}
+		Returns struct {
+			DeadJobs []gobble.DeadJob
+			Error    error
+		}
+	}
+
+	DeadJobCall struct {
+		Receives struct {
+			ID int
+		}
+		Returns struct {
+			DeadJob gobble.DeadJob
+			Error   error
+		}
+	}
+
+	RequeueDeadJobCall struct {
+		Receives struct {
+			ID int
+		}
+		Returns struct {
+			Job   *gobble.Job
+			Error error
+		}
+	}
+
+	DeleteDeadJobCall struct {
+		Receives struct {
+			ID int
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	PurgeDeadJobsCall struct {
+		WasCalled bool
+		Returns   struct {
+			Count int
+			Error error
+		}
+	}
+
 	RetryQueueLengthsCall struct {
 		Returns struct {
 			Lengths map[int]int
@@ -71,10 +135,20 @@ func (q *Queue) Dequeue(job *gobble.Job) {
 	q.DequeueCall.Receives.Job = job
 }
 
+func (q *Queue) DequeueByKey(key string) (bool, error) {
+	q.DequeueByKeyCall.Receives.Keys = append(q.DequeueByKeyCall.Receives.Keys, key)
+
+	return q.DequeueByKeyCall.Returns.Dequeued, q.DequeueByKeyCall.Returns.Error
+}
+
 func (q *Queue) Requeue(job *gobble.Job) {
 	q.RequeueCall.Receives.Job = job
 }
 
+func (q *Queue) Bury(job *gobble.Job) {
+	q.BuryCall.Receives.Job = job
+}
+
 func (q *Queue) Len() (int, error) {
 	return q.LenCall.Returns.Length, q.LenCall.Returns.Error
 }
@@ -88,3 +162,34 @@ func (q *Queue) Reserve(id string) <-chan *gobble.Job {
 func (q *Queue) RetryQueueLengths() (map[int]int, error) {
 	return q.RetryQueueLengthsCall.Returns.Lengths, q.RetryQueueLengthsCall.Returns.Error
 }
+
+func (q *Queue) DeadJobs(offset, limit int) ([]gobble.DeadJob, error) {
+	q.DeadJobsCall.Receives.Offset = offset
+	q.DeadJobsCall.Receives.Limit = limit
+
+	return q.DeadJobsCall.Returns.DeadJobs, q.DeadJobsCall.Returns.Error
+}
+
+func (q *Queue) DeadJob(id int) (gobble.DeadJob, error) {
+	q.DeadJobCall.Receives.ID = id
+
+	return q.DeadJobCall.Returns.DeadJob, q.DeadJobCall.Returns.Error
+}
+
+func (q *Queue) RequeueDeadJob(id int) (*gobble.Job, error) {
+	q.RequeueDeadJobCall.Receives.ID = id
+
+	return q.RequeueDeadJobCall.Returns.Job, q.RequeueDeadJobCall.Returns.Error
+}
+
+func (q *Queue) DeleteDeadJob(id int) error {
+	q.DeleteDeadJobCall.Receives.ID = id
+
+	return q.DeleteDeadJobCall.Returns.Error
+}
+
+func (q *Queue) PurgeDeadJobs() (int, error) {
+	q.PurgeDeadJobsCall.WasCalled = true
+
+	return q.PurgeDeadJobsCall.Returns.Count, q.PurgeDeadJobsCall.Returns.Error
+}
@@ -1,6 +1,10 @@
 package mocks
 
-import "github.com/cloudfoundry-incubator/notifications/v1/models"
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 type ReceiptsRepo struct {
 	CreateReceiptsCall struct {
@@ -14,6 +18,31 @@ type ReceiptsRepo struct {
 			Error error
 		}
 	}
+
+	FindExpiredCall struct {
+		CallCount int
+		Receives  struct {
+			Connection    models.ConnectionInterface
+			ThresholdTime time.Time
+			Limit         int
+		}
+		Returns struct {
+			Receipts []models.Receipt
+			Error    error
+		}
+	}
+
+	DeleteByIDsCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+			IDs        []int
+		}
+		Returns struct {
+			RowsAffected int
+			Error        error
+		}
+	}
 }
 
 func NewReceiptsRepo() *ReceiptsRepo {
@@ -28,3 +57,20 @@ func (rr *ReceiptsRepo) CreateReceipts(conn models.ConnectionInterface, userGUID
 
 	return rr.CreateReceiptsCall.Returns.Error
 }
+
+func (rr *ReceiptsRepo) FindExpired(conn models.ConnectionInterface, thresholdTime time.Time, limit int) ([]models.Receipt, error) {
+	rr.FindExpiredCall.Receives.Connection = conn
+	rr.FindExpiredCall.Receives.ThresholdTime = thresholdTime
+	rr.FindExpiredCall.Receives.Limit = limit
+	rr.FindExpiredCall.CallCount++
+
+	return rr.FindExpiredCall.Returns.Receipts, rr.FindExpiredCall.Returns.Error
+}
+
+func (rr *ReceiptsRepo) DeleteByIDs(conn models.ConnectionInterface, ids []int) (int, error) {
+	rr.DeleteByIDsCall.Receives.Connection = conn
+	rr.DeleteByIDsCall.Receives.IDs = ids
+	rr.DeleteByIDsCall.CallCount++
+
+	return rr.DeleteByIDsCall.Returns.RowsAffected, rr.DeleteByIDsCall.Returns.Error
+}
@@ -8,6 +8,7 @@ type TemplateAssigner struct {
 			Connection collections.ConnectionInterface
 			ClientID   string
 			TemplateID string
+			Version    int
 		}
 		Returns struct {
 			Error error
@@ -20,6 +21,7 @@ type TemplateAssigner struct {
 			ClientID       string
 			NotificationID string
 			TemplateID     string
+			Version        int
 		}
 		Returns struct {
 			Error error
@@ -31,19 +33,21 @@ func NewTemplateAssigner() *TemplateAssigner {
 	return &TemplateAssigner{}
 }
 
-func (a *TemplateAssigner) AssignToClient(connection collections.ConnectionInterface, clientID, templateID string) error {
+func (a *TemplateAssigner) AssignToClient(connection collections.ConnectionInterface, clientID, templateID string, version int) error {
 	a.AssignToClientCall.Receives.Connection = connection
 	a.AssignToClientCall.Receives.ClientID = clientID
 	a.AssignToClientCall.Receives.TemplateID = templateID
+	a.AssignToClientCall.Receives.Version = version
 
 	return a.AssignToClientCall.Returns.Error
 }
 
-func (a *TemplateAssigner) AssignToNotification(connection collections.ConnectionInterface, clientID, notificationID, templateID string) error {
+func (a *TemplateAssigner) AssignToNotification(connection collections.ConnectionInterface, clientID, notificationID, templateID string, version int) error {
 	a.AssignToNotificationCall.Receives.Connection = connection
 	a.AssignToNotificationCall.Receives.ClientID = clientID
 	a.AssignToNotificationCall.Receives.NotificationID = notificationID
 	a.AssignToNotificationCall.Receives.TemplateID = templateID
+	a.AssignToNotificationCall.Receives.Version = version
 
 	return a.AssignToNotificationCall.Returns.Error
 }
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type TemplatePreviewer struct {
+	PreviewCall struct {
+		Receives struct {
+			Template models.Template
+			Delivery common.Delivery
+		}
+		Returns struct {
+			Message mail.Message
+			Error   error
+		}
+	}
+}
+
+func NewTemplatePreviewer() *TemplatePreviewer {
+	return &TemplatePreviewer{}
+}
+
+func (p *TemplatePreviewer) Preview(template models.Template, delivery common.Delivery) (mail.Message, error) {
+	p.PreviewCall.Receives.Template = template
+	p.PreviewCall.Receives.Delivery = delivery
+
+	return p.PreviewCall.Returns.Message, p.PreviewCall.Returns.Error
+}
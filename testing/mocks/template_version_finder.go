@@ -0,0 +1,29 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateVersionFinder struct {
+	FindVersionCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			TemplateVersion collections.TemplateVersion
+			Error           error
+		}
+	}
+}
+
+func NewTemplateVersionFinder() *TemplateVersionFinder {
+	return &TemplateVersionFinder{}
+}
+
+func (f *TemplateVersionFinder) FindVersion(connection collections.ConnectionInterface, templateID string, version int) (collections.TemplateVersion, error) {
+	f.FindVersionCall.Receives.Connection = connection
+	f.FindVersionCall.Receives.TemplateID = templateID
+	f.FindVersionCall.Receives.Version = version
+
+	return f.FindVersionCall.Returns.TemplateVersion, f.FindVersionCall.Returns.Error
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateVersionLister struct {
+	ListVersionsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			TemplateVersions []collections.TemplateVersion
+			Error            error
+		}
+	}
+}
+
+func NewTemplateVersionLister() *TemplateVersionLister {
+	return &TemplateVersionLister{}
+}
+
+func (l *TemplateVersionLister) ListVersions(connection collections.ConnectionInterface, templateID string) ([]collections.TemplateVersion, error) {
+	l.ListVersionsCall.Receives.Connection = connection
+	l.ListVersionsCall.Receives.TemplateID = templateID
+
+	return l.ListVersionsCall.Returns.TemplateVersions, l.ListVersionsCall.Returns.Error
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateVersionRestorer struct {
+	RestoreVersionCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+			Author     string
+		}
+		Returns struct {
+			TemplateVersion collections.TemplateVersion
+			Error           error
+		}
+	}
+}
+
+func NewTemplateVersionRestorer() *TemplateVersionRestorer {
+	return &TemplateVersionRestorer{}
+}
+
+func (r *TemplateVersionRestorer) RestoreVersion(connection collections.ConnectionInterface, templateID string, version int, author string) (collections.TemplateVersion, error) {
+	r.RestoreVersionCall.Receives.Connection = connection
+	r.RestoreVersionCall.Receives.TemplateID = templateID
+	r.RestoreVersionCall.Receives.Version = version
+	r.RestoreVersionCall.Receives.Author = author
+
+	return r.RestoreVersionCall.Returns.TemplateVersion, r.RestoreVersionCall.Returns.Error
+}
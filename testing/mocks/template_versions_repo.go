@@ -0,0 +1,84 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type TemplateVersionsRepo struct {
+	CreateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Template   models.Template
+			Author     string
+		}
+		Returns struct {
+			TemplateVersion models.TemplateVersion
+			Error           error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			TemplateVersion models.TemplateVersion
+			Error           error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			TemplateVersions []models.TemplateVersion
+			Error            error
+		}
+	}
+
+	DestroyAllCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplateVersionsRepo() *TemplateVersionsRepo {
+	return &TemplateVersionsRepo{}
+}
+
+func (r *TemplateVersionsRepo) Create(conn models.ConnectionInterface, template models.Template, author string) (models.TemplateVersion, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Template = template
+	r.CreateCall.Receives.Author = author
+
+	return r.CreateCall.Returns.TemplateVersion, r.CreateCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) Find(conn models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.TemplateID = templateID
+	r.FindCall.Receives.Version = version
+
+	return r.FindCall.Returns.TemplateVersion, r.FindCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) List(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.TemplateID = templateID
+
+	return r.ListCall.Returns.TemplateVersions, r.ListCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) DestroyAll(conn models.ConnectionInterface, templateID string) error {
+	r.DestroyAllCall.Receives.Connection = conn
+	r.DestroyAllCall.Receives.TemplateID = templateID
+
+	return r.DestroyAllCall.Returns.Error
+}
@@ -3,13 +3,16 @@ package mocks
 import "github.com/cloudfoundry-incubator/notifications/uaa"
 
 type ZonedUAAClient struct {
-	AllUsersCall struct {
+	UsersPageCall struct {
 		Receives struct {
-			Token string
+			Token      string
+			StartIndex int
+			Count      int
 		}
 		Returns struct {
-			Users []uaa.User
-			Error error
+			Users        []uaa.User
+			TotalResults int
+			Error        error
 		}
 	}
 
@@ -50,10 +53,12 @@ func NewZonedUAAClient() *ZonedUAAClient {
 	return &ZonedUAAClient{}
 }
 
-func (c *ZonedUAAClient) AllUsers(token string) ([]uaa.User, error) {
-	c.AllUsersCall.Receives.Token = token
+func (c *ZonedUAAClient) UsersPage(token string, startIndex, count int) ([]uaa.User, int, error) {
+	c.UsersPageCall.Receives.Token = token
+	c.UsersPageCall.Receives.StartIndex = startIndex
+	c.UsersPageCall.Receives.Count = count
 
-	return c.AllUsersCall.Returns.Users, c.AllUsersCall.Returns.Error
+	return c.UsersPageCall.Returns.Users, c.UsersPageCall.Returns.TotalResults, c.UsersPageCall.Returns.Error
 }
 
 func (c *ZonedUAAClient) UsersGUIDsByScope(token, scope string) ([]string, error) {
@@ -0,0 +1,47 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type Unsubscriber struct {
+	LookupCall struct {
+		Receives struct {
+			Connection services.ConnectionInterface
+			Token      string
+		}
+		Returns struct {
+			Target services.UnsubscribeTarget
+			Error  error
+		}
+	}
+
+	UnsubscribeCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			Token      string
+		}
+		Returns struct {
+			Target services.UnsubscribeTarget
+			Error  error
+		}
+	}
+}
+
+func NewUnsubscriber() *Unsubscriber {
+	return &Unsubscriber{}
+}
+
+func (u *Unsubscriber) Lookup(conn services.ConnectionInterface, token string) (services.UnsubscribeTarget, error) {
+	u.LookupCall.Receives.Connection = conn
+	u.LookupCall.Receives.Token = token
+
+	return u.LookupCall.Returns.Target, u.LookupCall.Returns.Error
+}
+
+func (u *Unsubscriber) Unsubscribe(conn services.ConnectionInterface, token string) (services.UnsubscribeTarget, error) {
+	u.UnsubscribeCall.WasCalled = true
+	u.UnsubscribeCall.Receives.Connection = conn
+	u.UnsubscribeCall.Receives.Token = token
+
+	return u.UnsubscribeCall.Returns.Target, u.UnsubscribeCall.Returns.Error
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type WebhookNotifier struct {
+	NotifyCall struct {
+		CallCount int
+		Receives  struct {
+			Connection db.ConnectionInterface
+			Message    models.Message
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{}
+}
+
+func (n *WebhookNotifier) Notify(conn db.ConnectionInterface, message models.Message) error {
+	n.NotifyCall.CallCount++
+	n.NotifyCall.Receives.Connection = conn
+	n.NotifyCall.Receives.Message = message
+
+	return n.NotifyCall.Returns.Error
+}
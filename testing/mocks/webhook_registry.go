@@ -0,0 +1,68 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type WebhookRegistry struct {
+	RegisterCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			ClientID string
+			URL      string
+			Secret   string
+		}
+		Returns struct {
+			Webhook services.Webhook
+			Error   error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			ClientID string
+		}
+		Returns struct {
+			Webhooks []services.Webhook
+			Error    error
+		}
+	}
+
+	UnregisterCall struct {
+		Receives struct {
+			Database  services.DatabaseInterface
+			ClientID  string
+			WebhookID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewWebhookRegistry() *WebhookRegistry {
+	return &WebhookRegistry{}
+}
+
+func (r *WebhookRegistry) Register(database services.DatabaseInterface, clientID, url, secret string) (services.Webhook, error) {
+	r.RegisterCall.Receives.Database = database
+	r.RegisterCall.Receives.ClientID = clientID
+	r.RegisterCall.Receives.URL = url
+	r.RegisterCall.Receives.Secret = secret
+
+	return r.RegisterCall.Returns.Webhook, r.RegisterCall.Returns.Error
+}
+
+func (r *WebhookRegistry) List(database services.DatabaseInterface, clientID string) ([]services.Webhook, error) {
+	r.ListCall.Receives.Database = database
+	r.ListCall.Receives.ClientID = clientID
+
+	return r.ListCall.Returns.Webhooks, r.ListCall.Returns.Error
+}
+
+func (r *WebhookRegistry) Unregister(database services.DatabaseInterface, clientID, webhookID string) error {
+	r.UnregisterCall.Receives.Database = database
+	r.UnregisterCall.Receives.ClientID = clientID
+	r.UnregisterCall.Receives.WebhookID = webhookID
+
+	return r.UnregisterCall.Returns.Error
+}
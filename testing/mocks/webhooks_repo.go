@@ -0,0 +1,82 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type WebhooksRepo struct {
+	CreateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Webhook    models.Webhook
+		}
+		Returns struct {
+			Webhook models.Webhook
+			Error   error
+		}
+	}
+
+	FindByIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			WebhookID  string
+		}
+		Returns struct {
+			Webhook models.Webhook
+			Error   error
+		}
+	}
+
+	FindAllByClientIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			Webhooks []models.Webhook
+			Error    error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			WebhookID  string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewWebhooksRepo() *WebhooksRepo {
+	return &WebhooksRepo{}
+}
+
+func (r *WebhooksRepo) Create(conn models.ConnectionInterface, webhook models.Webhook) (models.Webhook, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Webhook = webhook
+
+	return r.CreateCall.Returns.Webhook, r.CreateCall.Returns.Error
+}
+
+func (r *WebhooksRepo) FindByID(conn models.ConnectionInterface, webhookID string) (models.Webhook, error) {
+	r.FindByIDCall.Receives.Connection = conn
+	r.FindByIDCall.Receives.WebhookID = webhookID
+
+	return r.FindByIDCall.Returns.Webhook, r.FindByIDCall.Returns.Error
+}
+
+func (r *WebhooksRepo) FindAllByClientID(conn models.ConnectionInterface, clientID string) ([]models.Webhook, error) {
+	r.FindAllByClientIDCall.Receives.Connection = conn
+	r.FindAllByClientIDCall.Receives.ClientID = clientID
+
+	return r.FindAllByClientIDCall.Returns.Webhooks, r.FindAllByClientIDCall.Returns.Error
+}
+
+func (r *WebhooksRepo) Delete(conn models.ConnectionInterface, clientID, webhookID string) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.ClientID = clientID
+	r.DeleteCall.Receives.WebhookID = webhookID
+
+	return r.DeleteCall.Returns.Error
+}
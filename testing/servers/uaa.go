@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -230,11 +231,21 @@ var UAAGetUser = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request)
 })
 
 var UAAGetUsers = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+	startIndex, err := strconv.Atoi(req.URL.Query().Get("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+
+	resources := []map[string]interface{}{}
+	if startIndex <= len(allUsersResponse) {
+		resources = allUsersResponse[startIndex-1:]
+	}
+
 	response, err := json.Marshal(map[string]interface{}{
-		"resources":    allUsersResponse,
-		"startIndex":   1,
+		"resources":    resources,
+		"startIndex":   startIndex,
 		"itemsPerPage": 100,
-		"totalResults": 1,
+		"totalResults": len(allUsersResponse),
 		"schemas":      []string{"urn:scim:schemas:core:1.0"},
 	})
 	if err != nil {
@@ -87,22 +87,25 @@ func (z ZonedUAAClient) tokenHost(token string) (string, error) {
 	return tokenIssuerURL.Scheme + "://" + tokenIssuerURL.Host, nil
 }
 
-func (z ZonedUAAClient) AllUsers(token string) ([]User, error) {
+func (z ZonedUAAClient) UsersPage(token string, startIndex, count int) ([]User, int, error) {
 	uaaHost, err := z.tokenHost(token)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	uaaSSOGolangClient := uaaSSOGolang.NewUAA("", uaaHost, z.clientID, z.clientSecret, "")
 	uaaSSOGolangClient.VerifySSL = z.verifySSL
-	users, err := uaaSSOGolangClient.AllUsers()
+	uaaSSOGolangClient.SetToken(token)
+
+	uri := fmt.Sprintf("%s/Users?startIndex=%d&count=%d", uaaHost, startIndex, count)
+	users, totalResults, err := uaaSSOGolang.PaginatedUsersFromQuery(uaaSSOGolangClient, uri)
 
 	var myUsers []User
 	for _, user := range users {
 		myUsers = append(myUsers, newUserFromSSOGolangUser(user))
 	}
 
-	return myUsers, err
+	return myUsers, totalResults, err
 }
 
 func (z ZonedUAAClient) UsersGUIDsByScope(token string, scope string) ([]string, error) {
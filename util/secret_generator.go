@@ -0,0 +1,27 @@
+package util
+
+import (
+	"encoding/hex"
+	"io"
+)
+
+type SecretGenerator struct {
+	reader io.Reader
+}
+
+func NewSecretGenerator(reader io.Reader) SecretGenerator {
+	return SecretGenerator{
+		reader: reader,
+	}
+}
+
+func (g SecretGenerator) Generate() (string, error) {
+	var buf [32]byte
+
+	_, err := io.ReadFull(g.reader, buf[:])
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf[:]), nil
+}
@@ -0,0 +1,30 @@
+package util_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/util"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SecretGenerator", func() {
+	It("generates a hex encoded secret from 32 bytes of the reader", func() {
+		reader := bytes.NewReader([]byte("abcdefghijklmnopqrstuvwxyz1234567890"))
+		generator := util.NewSecretGenerator(reader)
+
+		secret, err := generator.Generate()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("6162636465666768696a6b6c6d6e6f707172737475767778797a313233343536"))
+	})
+
+	It("returns an error if the reader errors", func() {
+		reader := errorReader{}
+		generator := util.NewSecretGenerator(reader)
+
+		_, err := generator.Generate()
+		Expect(err).To(MatchError(errors.New("failed to read")))
+	})
+})
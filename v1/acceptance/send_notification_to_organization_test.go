@@ -15,7 +15,6 @@ import (
 var _ = Describe("Sending notifications to all users in an organization", func() {
 	It("sends a notification to each user in an organization", func() {
 		var templateID string
-		indexedResponses := map[string]support.NotifyResponse{}
 		clientID := "notifications-sender"
 		clientToken := GetClientTokenFor(clientID)
 		client := support.NewClient(Servers.Notifications.URL())
@@ -54,36 +53,16 @@ var _ = Describe("Sending notifications to all users in an organization", func()
 		})
 
 		By("sending a notification to an organization", func() {
-			status, responses, err := client.Notify.Organization(clientToken.Access, "org-123", support.Notify{
+			status, response, err := client.Notify.Organization(clientToken.Access, "org-123", support.Notify{
 				KindID:  "organization-test",
 				HTML:    "this is an organization test",
 				Text:    "this is an organization test",
 				Subject: "organization-subject",
 			})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(status).To(Equal(http.StatusOK))
-			Expect(responses).To(HaveLen(3))
-
-			for _, response := range responses {
-				indexedResponses[response.Recipient] = response
-			}
-
-			response := indexedResponses["user-456"]
-			Expect(response.Recipient).To(Equal("user-456"))
-			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
-			Expect(response.VCAPRequestID).To(Equal("some-totally-fake-vcap-request-id"))
-
-			response = indexedResponses["user-789"]
-			Expect(response.Recipient).To(Equal("user-789"))
-			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
-			Expect(response.VCAPRequestID).To(Equal("some-totally-fake-vcap-request-id"))
-
-			response = indexedResponses["user-000"]
-			Expect(response.Recipient).To(Equal("user-000"))
-			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(GUIDRegex.MatchString(response.BatchID)).To(BeTrue())
+			Expect(response.Status).To(Equal("pending"))
 			Expect(response.VCAPRequestID).To(Equal("some-totally-fake-vcap-request-id"))
 		})
 
@@ -101,7 +80,7 @@ var _ = Describe("Sending notifications to all users in an organization", func()
 
 			data := strings.Split(string(delivery.Data), "\n")
 			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
-			Expect(data).To(ContainElement("X-CF-Notification-ID: " + indexedResponses["user-456"].NotificationID))
+			Expect(data).To(ContainElement(MatchRegexp(`^X-CF-Notification-ID: \S+$`)))
 			Expect(data).To(ContainElement("Subject: Coca cola organization-subject"))
 			Expect(data).To(ContainElement("\t\t<h1>Rat</h1>this is an organization test<section>You received this message="))
 			Expect(data).To(ContainElement(` because you belong to the &#34;notifications-service&#34; organization.</se=`))
@@ -51,6 +51,12 @@ type NotifyResponse struct {
 	VCAPRequestID  string `json:"vcap_request_id"`
 }
 
+type BatchResponse struct {
+	BatchID       string `json:"batch_id"`
+	Status        string `json:"status"`
+	VCAPRequestID string `json:"vcap_request_id"`
+}
+
 type Message struct {
 	Status string `json:"status"`
 }
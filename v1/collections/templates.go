@@ -2,6 +2,7 @@ package collections
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
@@ -37,38 +38,63 @@ type kindsRepository interface {
 type templatesRepository interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
 	Create(connection models.ConnectionInterface, template models.Template) (models.Template, error)
+	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
 	Destroy(connection models.ConnectionInterface, templateID string) error
 }
 
+type templateVersionsRepository interface {
+	Create(connection models.ConnectionInterface, template models.Template, author string) (models.TemplateVersion, error)
+	Find(connection models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+	List(connection models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error)
+	DestroyAll(connection models.ConnectionInterface, templateID string) error
+}
+
 type TemplateAssociation struct {
 	ClientID       string
 	NotificationID string
 }
 
 type Template struct {
-	ID       string
-	Name     string
-	Text     string
-	HTML     string
-	Subject  string
-	Metadata string
+	ID            string
+	Name          string
+	Text          string
+	HTML          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	Author        string
+}
+
+type TemplateVersion struct {
+	TemplateID    string
+	Version       int
+	Name          string
+	Text          string
+	HTML          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	Author        string
+	CreatedAt     time.Time
 }
 
 type TemplatesCollection struct {
-	clientsRepo   clientsRepository
-	kindsRepo     kindsRepository
-	templatesRepo templatesRepository
+	clientsRepo          clientsRepository
+	kindsRepo            kindsRepository
+	templatesRepo        templatesRepository
+	templateVersionsRepo templateVersionsRepository
 }
 
-func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository) TemplatesCollection {
+func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository, templateVersionsRepo templateVersionsRepository) TemplatesCollection {
 	return TemplatesCollection{
-		clientsRepo:   clientsRepo,
-		kindsRepo:     kindsRepo,
-		templatesRepo: templatesRepo,
+		clientsRepo:          clientsRepo,
+		kindsRepo:            kindsRepo,
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
 	}
 }
 
-func (c TemplatesCollection) AssignToClient(conn ConnectionInterface, clientID, templateID string) error {
+func (c TemplatesCollection) AssignToClient(conn ConnectionInterface, clientID, templateID string, version int) error {
 	if templateID == "" {
 		templateID = models.DefaultTemplateID
 	}
@@ -78,12 +104,13 @@ func (c TemplatesCollection) AssignToClient(conn ConnectionInterface, clientID,
 		return err
 	}
 
-	err = c.findTemplate(conn, templateID)
+	err = c.findTemplate(conn, templateID, version)
 	if err != nil {
 		return err
 	}
 
 	client.TemplateID = templateID
+	client.TemplateVersion = version
 
 	_, err = c.clientsRepo.Update(conn, client)
 	if err != nil {
@@ -93,7 +120,7 @@ func (c TemplatesCollection) AssignToClient(conn ConnectionInterface, clientID,
 	return nil
 }
 
-func (c TemplatesCollection) AssignToNotification(conn ConnectionInterface, clientID, notificationID, templateID string) error {
+func (c TemplatesCollection) AssignToNotification(conn ConnectionInterface, clientID, notificationID, templateID string, version int) error {
 	if templateID == "" {
 		templateID = models.DefaultTemplateID
 	}
@@ -108,12 +135,13 @@ func (c TemplatesCollection) AssignToNotification(conn ConnectionInterface, clie
 		return err
 	}
 
-	err = c.findTemplate(conn, templateID)
+	err = c.findTemplate(conn, templateID, version)
 	if err != nil {
 		return err
 	}
 
 	kind.TemplateID = templateID
+	kind.TemplateVersion = version
 
 	_, err = c.kindsRepo.Update(conn, kind)
 	if err != nil {
@@ -123,7 +151,7 @@ func (c TemplatesCollection) AssignToNotification(conn ConnectionInterface, clie
 	return nil
 }
 
-func (c TemplatesCollection) findTemplate(conn ConnectionInterface, templateID string) error {
+func (c TemplatesCollection) findTemplate(conn ConnectionInterface, templateID string, version int) error {
 	if templateID == "" {
 		return nil
 	}
@@ -136,6 +164,18 @@ func (c TemplatesCollection) findTemplate(conn ConnectionInterface, templateID s
 		return err
 	}
 
+	if version == models.LatestTemplateVersion {
+		return nil
+	}
+
+	_, err = c.templateVersionsRepo.Find(conn, templateID, version)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return TemplateAssignmentError{fmt.Errorf("No version %d of template with id %q", version, templateID)}
+		}
+		return err
+	}
+
 	return nil
 }
 
@@ -174,27 +214,137 @@ func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, template
 }
 
 func (c TemplatesCollection) Create(connection ConnectionInterface, template Template) (Template, error) {
-	tmpl, err := c.templatesRepo.Create(connection, models.Template{
-		Name:     template.Name,
-		Text:     template.Text,
-		HTML:     template.HTML,
-		Subject:  template.Subject,
-		Metadata: template.Metadata,
+	transaction := connection.Transaction()
+	if err := transaction.Begin(); err != nil {
+		return Template{}, err
+	}
+
+	tmpl, err := c.templatesRepo.Create(transaction, models.Template{
+		Name:          template.Name,
+		Text:          template.Text,
+		HTML:          template.HTML,
+		Subject:       template.Subject,
+		Metadata:      template.Metadata,
+		Localizations: template.Localizations,
 	})
 	if err != nil {
+		transaction.Rollback()
+		return Template{}, err
+	}
+
+	_, err = c.templateVersionsRepo.Create(transaction, tmpl, template.Author)
+	if err != nil {
+		transaction.Rollback()
+		return Template{}, err
+	}
+
+	if err := transaction.Commit(); err != nil {
 		return Template{}, err
 	}
 
 	return Template{
-		ID:       tmpl.ID,
-		Name:     tmpl.Name,
-		Text:     tmpl.Text,
-		HTML:     tmpl.HTML,
-		Subject:  tmpl.Subject,
-		Metadata: tmpl.Metadata,
+		ID:            tmpl.ID,
+		Name:          tmpl.Name,
+		Text:          tmpl.Text,
+		HTML:          tmpl.HTML,
+		Subject:       tmpl.Subject,
+		Metadata:      tmpl.Metadata,
+		Localizations: tmpl.Localizations,
+		Author:        template.Author,
 	}, nil
 }
 
 func (c TemplatesCollection) Delete(connection ConnectionInterface, templateID string) error {
-	return c.templatesRepo.Destroy(connection, templateID)
+	transaction := connection.Transaction()
+	if err := transaction.Begin(); err != nil {
+		return err
+	}
+
+	if err := c.templatesRepo.Destroy(transaction, templateID); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if err := c.templateVersionsRepo.DestroyAll(transaction, templateID); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	return transaction.Commit()
+}
+
+func (c TemplatesCollection) ListVersions(connection ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	_, err := c.templatesRepo.FindByID(connection, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := c.templateVersionsRepo.List(connection, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	templateVersions := []TemplateVersion{}
+	for _, version := range versions {
+		templateVersions = append(templateVersions, newTemplateVersion(version))
+	}
+
+	return templateVersions, nil
+}
+
+func (c TemplatesCollection) FindVersion(connection ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion, err := c.templateVersionsRepo.Find(connection, templateID, version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return newTemplateVersion(templateVersion), nil
+}
+
+// RestoreVersion copies the content of an earlier version back into the
+// template. The restore is itself recorded as a new version so that the
+// history is never rewritten.
+func (c TemplatesCollection) RestoreVersion(connection ConnectionInterface, templateID string, version int, author string) (TemplateVersion, error) {
+	templateVersion, err := c.templateVersionsRepo.Find(connection, templateID, version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	transaction := connection.Transaction()
+	if err := transaction.Begin(); err != nil {
+		return TemplateVersion{}, err
+	}
+
+	template, err := c.templatesRepo.Update(transaction, templateID, templateVersion.Template())
+	if err != nil {
+		transaction.Rollback()
+		return TemplateVersion{}, err
+	}
+
+	restoredVersion, err := c.templateVersionsRepo.Create(transaction, template, author)
+	if err != nil {
+		transaction.Rollback()
+		return TemplateVersion{}, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return newTemplateVersion(restoredVersion), nil
+}
+
+func newTemplateVersion(version models.TemplateVersion) TemplateVersion {
+	return TemplateVersion{
+		TemplateID:    version.TemplateID,
+		Version:       version.Version,
+		Name:          version.Name,
+		Text:          version.Text,
+		HTML:          version.HTML,
+		Subject:       version.Subject,
+		Metadata:      version.Metadata,
+		Localizations: version.Localizations,
+		Author:        version.Author,
+		CreatedAt:     version.CreatedAt,
+	}
 }
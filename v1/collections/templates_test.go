@@ -2,6 +2,7 @@ package collections_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
@@ -16,19 +17,24 @@ var _ = Describe("TemplatesCollection", func() {
 		kindsRepo     *mocks.KindsRepo
 		clientsRepo   *mocks.ClientsRepository
 		templatesRepo *mocks.TemplatesRepo
+		versionsRepo  *mocks.TemplateVersionsRepo
 		conn          *mocks.Connection
+		transaction   *mocks.Transaction
 
 		collection collections.TemplatesCollection
 	)
 
 	BeforeEach(func() {
+		transaction = mocks.NewTransaction()
 		conn = mocks.NewConnection()
+		conn.TransactionCall.Returns.Transaction = transaction
 
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		versionsRepo = mocks.NewTemplateVersionsRepo()
 
-		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, versionsRepo)
 	})
 
 	Describe("AssignToClient", func() {
@@ -39,7 +45,7 @@ var _ = Describe("TemplatesCollection", func() {
 		})
 
 		It("assigns the template to the given client", func() {
-			err := collection.AssignToClient(conn, "my-client", "my-template")
+			err := collection.AssignToClient(conn, "my-client", "my-template", 0)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(clientsRepo.FindCall.Receives.Connection).To(Equal(conn))
@@ -52,18 +58,40 @@ var _ = Describe("TemplatesCollection", func() {
 			}))
 		})
 
+		It("pins the client to a version of the template", func() {
+			err := collection.AssignToClient(conn, "my-client", "my-template", 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal("my-template"))
+			Expect(versionsRepo.FindCall.Receives.Version).To(Equal(2))
+
+			Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{
+				ID:              "my-client",
+				TemplateID:      "my-template",
+				TemplateVersion: 2,
+			}))
+		})
+
 		Context("when the request includes a non-existant id", func() {
+			It("reports that the template version cannot be found", func() {
+				versionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				err := collection.AssignToClient(conn, "my-client", "my-template", 7)
+				Expect(err).To(MatchError(collections.TemplateAssignmentError{Err: errors.New("No version 7 of template with id \"my-template\"")}))
+			})
+
 			It("reports that the client cannot be found", func() {
 				clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
 
-				err := collection.AssignToClient(conn, "missing-client", "my-template")
+				err := collection.AssignToClient(conn, "missing-client", "my-template", 0)
 				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
 			})
 
 			It("reports that the template cannot be found", func() {
 				templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
 
-				err := collection.AssignToClient(conn, "my-client", "non-existant-template")
+				err := collection.AssignToClient(conn, "my-client", "non-existant-template", 0)
 				Expect(err).To(MatchError(collections.TemplateAssignmentError{Err: errors.New("No template with id \"non-existant-template\"")}))
 			})
 		})
@@ -77,7 +105,7 @@ var _ = Describe("TemplatesCollection", func() {
 			})
 
 			It("allows template id of empty string to reset the assignment", func() {
-				err := collection.AssignToClient(conn, "my-client", "")
+				err := collection.AssignToClient(conn, "my-client", "", 0)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(clientsRepo.FindCall.Receives.Connection).To(Equal(conn))
@@ -91,7 +119,7 @@ var _ = Describe("TemplatesCollection", func() {
 			})
 
 			It("allows template id of default template id to reset the assignment", func() {
-				err := collection.AssignToClient(conn, "my-client", models.DefaultTemplateID)
+				err := collection.AssignToClient(conn, "my-client", models.DefaultTemplateID, 0)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(clientsRepo.FindCall.Receives.Connection).To(Equal(conn))
@@ -110,7 +138,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("returns any errors it doesn't understand", func() {
 					clientsRepo.FindCall.Returns.Error = errors.New("database connection failure")
 
-					err := collection.AssignToClient(conn, "my-client", "my-template")
+					err := collection.AssignToClient(conn, "my-client", "my-template", 0)
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(Equal("database connection failure"))
 				})
@@ -119,7 +147,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("returns any errors it doesn't understand (part 2)", func() {
 					templatesRepo.FindByIDCall.Returns.Error = errors.New("database failure")
 
-					err := collection.AssignToClient(conn, "my-client", "my-template")
+					err := collection.AssignToClient(conn, "my-client", "my-template", 0)
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(Equal("database failure"))
 
@@ -130,7 +158,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("Returns the error", func() {
 					clientsRepo.UpdateCall.Returns.Error = errors.New("database fail")
 
-					err := collection.AssignToClient(conn, "my-client", "my-template")
+					err := collection.AssignToClient(conn, "my-client", "my-template", 0)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -155,7 +183,7 @@ var _ = Describe("TemplatesCollection", func() {
 		})
 
 		It("assigns the template to the given kind", func() {
-			err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template")
+			err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template", 0)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(kindsRepo.UpdateCall.Receives.Kind).To(Equal(models.Kind{
@@ -165,18 +193,33 @@ var _ = Describe("TemplatesCollection", func() {
 			}))
 		})
 
+		It("pins the kind to a version of the template", func() {
+			err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template", 4)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal("my-template"))
+			Expect(versionsRepo.FindCall.Receives.Version).To(Equal(4))
+
+			Expect(kindsRepo.UpdateCall.Receives.Kind).To(Equal(models.Kind{
+				ID:              "my-kind",
+				ClientID:        "my-client",
+				TemplateID:      "my-template",
+				TemplateVersion: 4,
+			}))
+		})
+
 		Context("when the request includes a non-existant id", func() {
 			It("reports that the client cannot be found", func() {
 				kindsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
 
-				err := collection.AssignToNotification(conn, "bad-client", "my-kind", "my-template")
+				err := collection.AssignToNotification(conn, "bad-client", "my-kind", "my-template", 0)
 				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
 			})
 
 			It("reports that the kind cannot be found", func() {
 				kindsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
 
-				err := collection.AssignToNotification(conn, "my-client", "bad-kind", "my-template")
+				err := collection.AssignToNotification(conn, "my-client", "bad-kind", "my-template", 0)
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{Err: errors.New("not found")}))
 			})
@@ -184,7 +227,7 @@ var _ = Describe("TemplatesCollection", func() {
 			It("reports that the template cannot be found", func() {
 				templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
 
-				err := collection.AssignToNotification(conn, "my-client", "my-kind", "non-existant-template")
+				err := collection.AssignToNotification(conn, "my-client", "my-kind", "non-existant-template", 0)
 				Expect(err).To(MatchError(collections.TemplateAssignmentError{Err: errors.New("No template with id \"non-existant-template\"")}))
 			})
 		})
@@ -195,7 +238,7 @@ var _ = Describe("TemplatesCollection", func() {
 			})
 
 			It("allows template id of empty string to reset the assignment", func() {
-				err := collection.AssignToNotification(conn, "my-client", "my-kind", "")
+				err := collection.AssignToNotification(conn, "my-client", "my-kind", "", 0)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(kindsRepo.UpdateCall.Receives.Kind).To(Equal(models.Kind{
@@ -206,7 +249,7 @@ var _ = Describe("TemplatesCollection", func() {
 			})
 
 			It("allows template id of default template id to reset the assignment", func() {
-				err := collection.AssignToNotification(conn, "my-client", "my-kind", models.DefaultTemplateID)
+				err := collection.AssignToNotification(conn, "my-client", "my-kind", models.DefaultTemplateID, 0)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(kindsRepo.UpdateCall.Receives.Kind).To(Equal(models.Kind{
@@ -222,7 +265,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("returns any errors it doesn't understand", func() {
 					clientsRepo.FindCall.Returns.Error = errors.New("database connection failure")
 
-					err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template")
+					err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template", 0)
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(Equal("database connection failure"))
 				})
@@ -231,7 +274,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("returns any errors it doesn't understand (part 2)", func() {
 					templatesRepo.FindByIDCall.Returns.Error = errors.New("database failure")
 
-					err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template")
+					err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template", 0)
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(Equal("database failure"))
 
@@ -242,7 +285,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("Returns the error", func() {
 					kindsRepo.UpdateCall.Returns.Error = errors.New("database fail")
 
-					err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template")
+					err := collection.AssignToNotification(conn, "my-client", "my-kind", "my-template", 0)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -353,7 +396,7 @@ var _ = Describe("TemplatesCollection", func() {
 				Metadata: "some-metadata",
 			}))
 
-			Expect(templatesRepo.CreateCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.CreateCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{
 				Name:     "some-template-name",
 				Text:     "some-text",
@@ -361,6 +404,25 @@ var _ = Describe("TemplatesCollection", func() {
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
 			}))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("records the first version of the template", func() {
+			templatesRepo.CreateCall.Returns.Template = models.Template{
+				ID:   "some-template-guid",
+				Name: "some-template-name",
+			}
+
+			template, err := collection.Create(conn, collections.Template{
+				Name:   "some-template-name",
+				Author: "some-client",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(template.Author).To(Equal("some-client"))
+
+			Expect(versionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(versionsRepo.CreateCall.Receives.Template).To(Equal(templatesRepo.CreateCall.Returns.Template))
+			Expect(versionsRepo.CreateCall.Receives.Author).To(Equal("some-client"))
 		})
 
 		It("propagates errors from repo", func() {
@@ -368,16 +430,31 @@ var _ = Describe("TemplatesCollection", func() {
 
 			_, err := collection.Create(conn, collections.Template{})
 			Expect(err).To(Equal(errors.New("Boom!")))
+			Expect(versionsRepo.CreateCall.Receives.Connection).To(BeNil())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls back when the first version cannot be recorded", func() {
+			versionsRepo.CreateCall.Returns.Error = errors.New("Boom!")
+
+			_, err := collection.Create(conn, collections.Template{})
+			Expect(err).To(MatchError(errors.New("Boom!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
 		})
 	})
 
 	Describe("Delete", func() {
-		It("calls destroy on its repo", func() {
+		It("destroys the template and its versions in a transaction", func() {
 			err := collection.Delete(conn, "templateID")
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(templatesRepo.DestroyCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.DestroyCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.DestroyCall.Receives.TemplateID).To(Equal("templateID"))
+			Expect(versionsRepo.DestroyAllCall.Receives.Connection).To(Equal(transaction))
+			Expect(versionsRepo.DestroyAllCall.Receives.TemplateID).To(Equal("templateID"))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
 		})
 
 		It("returns an error if repo destroy returns an error", func() {
@@ -385,6 +462,146 @@ var _ = Describe("TemplatesCollection", func() {
 
 			err := collection.Delete(conn, "templateID")
 			Expect(err).To(MatchError(errors.New("Boom!!")))
+			Expect(versionsRepo.DestroyAllCall.Receives.TemplateID).To(BeEmpty())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls back when the versions cannot be destroyed", func() {
+			versionsRepo.DestroyAllCall.Returns.Error = errors.New("Boom!!")
+
+			err := collection.Delete(conn, "templateID")
+			Expect(err).To(MatchError(errors.New("Boom!!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Describe("ListVersions", func() {
+		It("returns the versions of the template", func() {
+			createdAt := time.Now().Truncate(time.Second).UTC()
+			versionsRepo.ListCall.Returns.TemplateVersions = []models.TemplateVersion{
+				{TemplateID: "some-template", Version: 1, Text: "first", Author: "some-client", CreatedAt: createdAt},
+				{TemplateID: "some-template", Version: 2, Text: "second", Author: "other-client", CreatedAt: createdAt},
+			}
+
+			versions, err := collection.ListVersions(conn, "some-template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]collections.TemplateVersion{
+				{TemplateID: "some-template", Version: 1, Text: "first", Author: "some-client", CreatedAt: createdAt},
+				{TemplateID: "some-template", Version: 2, Text: "second", Author: "other-client", CreatedAt: createdAt},
+			}))
+
+			Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("some-template"))
+			Expect(versionsRepo.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepo.ListCall.Receives.TemplateID).To(Equal("some-template"))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.ListVersions(conn, "missing-template")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("FindVersion", func() {
+		It("returns the requested version", func() {
+			versionsRepo.FindCall.Returns.TemplateVersion = models.TemplateVersion{
+				TemplateID: "some-template",
+				Version:    3,
+				Subject:    "some-subject",
+			}
+
+			version, err := collection.FindVersion(conn, "some-template", 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(collections.TemplateVersion{
+				TemplateID: "some-template",
+				Version:    3,
+				Subject:    "some-subject",
+			}))
+
+			Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal("some-template"))
+			Expect(versionsRepo.FindCall.Receives.Version).To(Equal(3))
+		})
+
+		It("propagates errors from the repo", func() {
+			versionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.FindVersion(conn, "some-template", 3)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("RestoreVersion", func() {
+		BeforeEach(func() {
+			versionsRepo.FindCall.Returns.TemplateVersion = models.TemplateVersion{
+				TemplateID: "some-template",
+				Version:    2,
+				Name:       "some-name",
+				Subject:    "old-subject",
+				Text:       "old-text",
+				HTML:       "old-html",
+				Metadata:   "{}",
+			}
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:       "some-template",
+				Name:     "some-name",
+				Subject:  "old-subject",
+				Text:     "old-text",
+				HTML:     "old-html",
+				Metadata: "{}",
+			}
+			versionsRepo.CreateCall.Returns.TemplateVersion = models.TemplateVersion{
+				TemplateID: "some-template",
+				Version:    5,
+				Subject:    "old-subject",
+				Author:     "some-client",
+			}
+		})
+
+		It("copies the version into the template and records it as a new version", func() {
+			version, err := collection.RestoreVersion(conn, "some-template", 2, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(collections.TemplateVersion{
+				TemplateID: "some-template",
+				Version:    5,
+				Subject:    "old-subject",
+				Author:     "some-client",
+			}))
+
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("some-template"))
+			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
+				ID:       "some-template",
+				Name:     "some-name",
+				Subject:  "old-subject",
+				Text:     "old-text",
+				HTML:     "old-html",
+				Metadata: "{}",
+			}))
+
+			Expect(versionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(versionsRepo.CreateCall.Receives.Template).To(Equal(templatesRepo.UpdateCall.Returns.Template))
+			Expect(versionsRepo.CreateCall.Receives.Author).To(Equal("some-client"))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("returns an error when the version does not exist", func() {
+			versionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.RestoreVersion(conn, "some-template", 9, "some-client")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("rolls back when the new version cannot be recorded", func() {
+			versionsRepo.CreateCall.Returns.Error = errors.New("Boom!")
+
+			_, err := collection.RestoreVersion(conn, "some-template", 2, "some-client")
+			Expect(err).To(MatchError(errors.New("Boom!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
 		})
 	})
 })
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type Batch struct {
+	ID            string    `db:"id"`
+	ClientID      string    `db:"client_id"`
+	KindID        string    `db:"kind_id"`
+	VCAPRequestID string    `db:"vcap_request_id"`
+	Audience      string    `db:"audience"`
+	GUID          string    `db:"guid"`
+	Status        string    `db:"status"`
+	Total         int       `db:"total"`
+	Enqueued      int       `db:"enqueued"`
+	Error         string    `db:"error"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (b *Batch) PreInsert(s gorp.SqlExecutor) error {
+	now := time.Now().Truncate(1 * time.Second).UTC()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+
+	return nil
+}
+
+func (b *Batch) PreUpdate(s gorp.SqlExecutor) error {
+	b.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BatchesRepo struct {
+	generateID IDGeneratorFunc
+}
+
+func NewBatchesRepo(guidGenerator IDGeneratorFunc) BatchesRepo {
+	return BatchesRepo{
+		generateID: guidGenerator,
+	}
+}
+
+func (repo BatchesRepo) Create(conn ConnectionInterface, batch Batch) (Batch, error) {
+	if batch.ID == "" {
+		var err error
+		batch.ID, err = repo.generateID()
+		if err != nil {
+			return Batch{}, err
+		}
+	}
+
+	err := conn.Insert(&batch)
+	if err != nil {
+		return Batch{}, err
+	}
+	return batch, nil
+}
+
+func (repo BatchesRepo) FindByID(conn ConnectionInterface, batchID string) (Batch, error) {
+	batch := Batch{}
+	err := conn.SelectOne(&batch, "SELECT * FROM `batches` WHERE `id`=?", batchID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Batch{}, NotFoundError{fmt.Errorf("Batch with ID %q could not be found", batchID)}
+		}
+		return Batch{}, err
+	}
+	return batch, nil
+}
+
+func (repo BatchesRepo) Update(conn ConnectionInterface, batch Batch) (Batch, error) {
+	_, err := conn.Update(&batch)
+	if err != nil {
+		return batch, err
+	}
+
+	return repo.FindByID(conn, batch.ID)
+}
+
+// FindExpired returns batches that finished enqueuing before the threshold and
+// no longer have any messages, so that batches outlive the messages they
+// report on but are not kept forever.
+func (repo BatchesRepo) FindExpired(conn ConnectionInterface, threshold time.Time, limit int) ([]Batch, error) {
+	batches := []Batch{}
+	_, err := conn.Select(&batches, "SELECT * FROM `batches` WHERE `updated_at` < ? AND `status` IN ('enqueued', 'failed') AND NOT EXISTS (SELECT 1 FROM `messages` WHERE `messages`.`batch_id` = `batches`.`id`) ORDER BY `updated_at` LIMIT ?", threshold.UTC(), limit)
+	if err != nil {
+		return []Batch{}, err
+	}
+	return batches, nil
+}
+
+func (repo BatchesRepo) DeleteByIDs(conn ConnectionInterface, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	result, err := conn.Exec("DELETE FROM `batches` WHERE `id` IN ("+strings.Join(placeholders, ", ")+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
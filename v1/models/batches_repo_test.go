@@ -0,0 +1,130 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchesRepo", func() {
+	var (
+		repo          models.BatchesRepo
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid"}
+
+		repo = models.NewBatchesRepo(guidGenerator.Generate)
+	})
+
+	Describe("Create", func() {
+		It("inserts a batch into the database", func() {
+			batch, err := repo.Create(conn, models.Batch{
+				ClientID:      "some-client-id",
+				KindID:        "some-kind-id",
+				VCAPRequestID: "some-request-id",
+				Audience:      "organization",
+				GUID:          "some-org-guid",
+				Status:        "pending",
+				Total:         12,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch.ID).To(Equal("first-random-guid"))
+
+			batchFound, err := repo.FindByID(conn, batch.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batchFound).To(Equal(batch))
+		})
+
+		It("returns an error when the guid generator errors", func() {
+			guidGenerator.GenerateCall.Returns.Error = errors.New("something bad")
+
+			_, err := repo.Create(conn, models.Batch{})
+			Expect(err).To(MatchError(errors.New("something bad")))
+		})
+	})
+
+	Describe("FindByID", func() {
+		It("returns a not found error when the batch does not exist", func() {
+			_, err := repo.FindByID(conn, "missing-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New(`Batch with ID "missing-id" could not be found`)}))
+		})
+	})
+
+	Describe("Update", func() {
+		It("updates the batch", func() {
+			batch, err := repo.Create(conn, models.Batch{Status: "pending"})
+			Expect(err).NotTo(HaveOccurred())
+
+			batch.Status = "enqueuing"
+			batch.Enqueued = 100
+
+			batch, err = repo.Update(conn, batch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch.Status).To(Equal("enqueuing"))
+			Expect(batch.Enqueued).To(Equal(100))
+		})
+	})
+
+	Describe("FindExpired", func() {
+		It("returns finished batches last updated before the threshold that have no messages left", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"finished-batch", "enqueuing-batch", "batch-with-messages"}
+
+			finished, err := repo.Create(conn, models.Batch{Status: "enqueued"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Batch{Status: "enqueuing"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Batch{Status: "failed"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = conn.Insert(&models.Message{ID: "some-message-id", BatchID: "batch-with-messages", Status: "delivered"})
+			Expect(err).NotTo(HaveOccurred())
+
+			batches, err := repo.FindExpired(conn, time.Now().Add(1*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(Equal([]models.Batch{finished}))
+
+			batches, err = repo.FindExpired(conn, time.Now().Add(-1*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteByIDs", func() {
+		It("deletes the given batches", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"batch-1", "batch-2"}
+
+			_, err := repo.Create(conn, models.Batch{Status: "enqueued"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Batch{Status: "enqueued"})
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := repo.DeleteByIDs(conn, []string{"batch-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+
+			_, err = repo.FindByID(conn, "batch-1")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+
+			_, err = repo.FindByID(conn, "batch-2")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -7,11 +7,13 @@ import (
 )
 
 type Client struct {
-	Primary     int       `db:"primary"`
-	ID          string    `db:"id"`
-	Description string    `db:"description"`
-	CreatedAt   time.Time `db:"created_at"`
-	TemplateID  string    `db:"template_id"`
+	Primary         int       `db:"primary"`
+	ID              string    `db:"id"`
+	Description     string    `db:"description"`
+	CreatedAt       time.Time `db:"created_at"`
+	TemplateID      string    `db:"template_id"`
+	TemplateVersion int       `db:"template_version"`
+	GenerateText    *bool     `db:"generate_text"`
 }
 
 func (c Client) TemplateToUse() string {
@@ -22,6 +24,13 @@ func (c Client) TemplateToUse() string {
 	return DefaultTemplateID
 }
 
+// ShouldGenerateText reports whether a plain-text part should be generated
+// for notifications that only carry HTML. Clients opt in unless they have
+// explicitly disabled it.
+func (c Client) ShouldGenerateText() bool {
+	return c.GenerateText == nil || *c.GenerateText
+}
+
 func (c *Client) PreInsert(s gorp.SqlExecutor) error {
 	c.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
 
@@ -29,5 +38,10 @@ func (c *Client) PreInsert(s gorp.SqlExecutor) error {
 		c.TemplateID = DefaultTemplateID
 	}
 
+	if c.GenerateText == nil {
+		generateText := true
+		c.GenerateText = &generateText
+	}
+
 	return nil
 }
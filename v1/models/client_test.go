@@ -31,4 +31,16 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Describe("ShouldGenerateText", func() {
+		It("generates text unless the client has disabled it", func() {
+			Expect(models.Client{}.ShouldGenerateText()).To(BeTrue())
+
+			generateText := true
+			Expect(models.Client{GenerateText: &generateText}.ShouldGenerateText()).To(BeTrue())
+
+			generateText = false
+			Expect(models.Client{GenerateText: &generateText}.ShouldGenerateText()).To(BeFalse())
+		})
+	})
 })
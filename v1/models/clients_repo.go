@@ -36,13 +36,20 @@ func (repo ClientsRepo) FindAll(conn ConnectionInterface) ([]Client, error) {
 }
 
 func (repo ClientsRepo) Update(conn ConnectionInterface, client Client) (Client, error) {
-	if client.TemplateID == DoNotSetTemplateID {
+	if client.TemplateID == DoNotSetTemplateID || client.GenerateText == nil {
 		existingClient, err := repo.Find(conn, client.ID)
 		if err != nil {
 			return client, err
 		}
 
-		client.TemplateID = existingClient.TemplateID
+		if client.TemplateID == DoNotSetTemplateID {
+			client.TemplateID = existingClient.TemplateID
+			client.TemplateVersion = existingClient.TemplateVersion
+		}
+
+		if client.GenerateText == nil {
+			client.GenerateText = existingClient.GenerateText
+		}
 	}
 
 	_, err := conn.Update(&client)
@@ -137,6 +137,27 @@ var _ = Describe("ClientsRepo", func() {
 				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Client with ID \"my-client\" could not be found")}))
 			})
 		})
+		Context("when generate text is not meant to be updated", func() {
+			It("keeps the existing setting when the field is nil", func() {
+				generateText := false
+				client, err := repo.Upsert(conn, models.Client{
+					ID:           "my-client",
+					GenerateText: &generateText,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				client.GenerateText = nil
+				client.Description = "My Client"
+
+				_, err = repo.Update(conn, client)
+				Expect(err).NotTo(HaveOccurred())
+
+				client, err = repo.Find(conn, "my-client")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.Description).To(Equal("My Client"))
+				Expect(client.ShouldGenerateText()).To(BeFalse())
+			})
+		})
 	})
 
 	Describe("Upsert", func() {
@@ -33,6 +33,10 @@ func Setup(database *db.DB) {
 	database.TableMap().AddTableWithName(Receipt{}, "receipts").SetKeys(true, "Primary").SetUniqueTogether("user_guid", "client_id", "kind_id")
 	database.TableMap().AddTableWithName(Unsubscribe{}, "unsubscribes").SetKeys(true, "Primary").SetUniqueTogether("user_id", "client_id", "kind_id")
 	database.TableMap().AddTableWithName(GlobalUnsubscribe{}, "global_unsubscribes").SetKeys(true, "Primary").ColMap("UserID").SetUnique(true)
+	database.TableMap().AddTableWithName(UserLocale{}, "user_locales").SetKeys(true, "Primary").ColMap("UserID").SetUnique(true)
 	database.TableMap().AddTableWithName(Template{}, "templates").SetKeys(true, "Primary").ColMap("Name").SetUnique(true)
+	database.TableMap().AddTableWithName(TemplateVersion{}, "template_versions").SetKeys(true, "Primary").SetUniqueTogether("template_id", "version")
 	database.TableMap().AddTableWithName(Message{}, "messages").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(Batch{}, "batches").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(Webhook{}, "webhooks").SetKeys(false, "ID")
 }
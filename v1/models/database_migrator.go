@@ -27,6 +27,7 @@ func (d DatabaseMigrator) Migrate(db *sql.DB, migrationsPath string) {
 
 func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath string) {
 	repo := NewTemplatesRepo()
+	versionsRepo := NewTemplateVersionsRepo()
 	bytes, err := ioutil.ReadFile(defaultTemplatePath)
 	if err != nil {
 		panic(err)
@@ -52,31 +53,51 @@ func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath s
 			panic(err)
 		}
 
-		_, err = repo.Create(conn, Template{
-			ID:       DefaultTemplateID,
-			Name:     template.Name,
-			Subject:  template.Subject,
-			HTML:     template.HTML,
-			Text:     template.Text,
-			Metadata: string(template.Metadata),
+		createdTemplate, err := repo.Create(conn, Template{
+			ID:            DefaultTemplateID,
+			Name:          template.Name,
+			Subject:       template.Subject,
+			HTML:          template.HTML,
+			Text:          template.Text,
+			Metadata:      string(template.Metadata),
+			Localizations: "{}",
 		})
 		if err != nil {
 			panic(err)
 		}
 
+		_, err = versionsRepo.Create(conn, createdTemplate, "")
+		if err != nil {
+			panic(err)
+		}
+
 		return
 	}
 
 	if !existingTemplate.Overridden {
-		existingTemplate.Name = template.Name
-		existingTemplate.Subject = template.Subject
-		existingTemplate.HTML = template.HTML
-		existingTemplate.Text = template.Text
-		existingTemplate.Metadata = string(template.Metadata)
-		existingTemplate.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
-		_, err = conn.Update(&existingTemplate)
+		seededTemplate := existingTemplate
+		seededTemplate.Name = template.Name
+		seededTemplate.Subject = template.Subject
+		seededTemplate.HTML = template.HTML
+		seededTemplate.Text = template.Text
+		seededTemplate.Metadata = string(template.Metadata)
+		if sameContent(seededTemplate, existingTemplate) {
+			return
+		}
+
+		seededTemplate.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+		_, err = conn.Update(&seededTemplate)
+		if err != nil {
+			panic(err)
+		}
+
+		_, err = versionsRepo.Create(conn, seededTemplate, "")
 		if err != nil {
 			panic(err)
 		}
 	}
 }
+
+func sameContent(a, b Template) bool {
+	return a.Name == b.Name && a.Subject == b.Subject && a.Text == b.Text && a.HTML == b.HTML && a.Metadata == b.Metadata && a.Localizations == b.Localizations
+}
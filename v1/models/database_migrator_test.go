@@ -87,6 +87,17 @@ var _ = Describe("DatabaseMigrator", func() {
 			Expect(template.Metadata).To(Equal("{}"))
 		})
 
+		It("records a version only when the seeded content changes", func() {
+			dbMigrator.Seed(database, defaultTemplatePath)
+			dbMigrator.Seed(database, defaultTemplatePath)
+
+			versions, err := models.NewTemplateVersionsRepo().List(connection, models.DefaultTemplateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].Version).To(Equal(1))
+			Expect(versions[0].Subject).To(Equal("CF Notification: {{.Subject}}"))
+		})
+
 		It("can be called multiple times without panicking", func() {
 			Expect(func() {
 				dbMigrator.Seed(database, defaultTemplatePath)
@@ -7,14 +7,15 @@ import (
 )
 
 type Kind struct {
-	Primary     int       `db:"primary"`
-	ID          string    `db:"id"`
-	Description string    `db:"description"`
-	Critical    bool      `db:"critical"`
-	ClientID    string    `db:"client_id"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
-	TemplateID  string    `db:"template_id"`
+	Primary         int       `db:"primary"`
+	ID              string    `db:"id"`
+	Description     string    `db:"description"`
+	Critical        bool      `db:"critical"`
+	ClientID        string    `db:"client_id"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+	TemplateID      string    `db:"template_id"`
+	TemplateVersion int       `db:"template_version"`
 }
 
 func (k Kind) TemplateToUse() string {
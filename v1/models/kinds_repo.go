@@ -70,6 +70,7 @@ func (repo KindsRepo) Update(conn ConnectionInterface, kind Kind) (Kind, error)
 	kind.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
 	if kind.TemplateID == DoNotSetTemplateID {
 		kind.TemplateID = existingKind.TemplateID
+		kind.TemplateVersion = existingKind.TemplateVersion
 	}
 
 	_, err = conn.Update(&kind)
@@ -7,13 +7,38 @@ import (
 )
 
 type Message struct {
-	ID         string    `db:"id"`
-	Status     string    `db:"status"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID            string     `db:"id" json:"id"`
+	Status        string     `db:"status" json:"status"`
+	Reason        string     `db:"reason" json:"reason"`
+	VCAPRequestID string     `db:"vcap_request_id" json:"vcap_request_id"`
+	BatchID       string     `db:"batch_id" json:"batch_id"`
+	UserGUID      string     `db:"user_guid" json:"user_guid"`
+	Email         string     `db:"email" json:"email"`
+	ClientID      string     `db:"client_id" json:"client_id"`
+	KindID        string     `db:"kind_id" json:"kind_id"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+	DeliveredAt   *time.Time `db:"delivered_at" json:"delivered_at"`
+}
+
+type MessageQuery struct {
+	UserGUID      string
+	Email         string
+	ClientID      string
+	KindID        string
+	VCAPRequestID string
+	Status        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Offset        int
+	Limit         int
 }
 
 func (m *Message) PreInsert(s gorp.SqlExecutor) error {
-	m.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	now := time.Now().Truncate(1 * time.Second).UTC()
+	m.CreatedAt = now
+	m.UpdatedAt = now
 
 	return nil
 }
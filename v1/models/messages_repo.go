@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -46,6 +47,86 @@ func (repo MessagesRepo) FindByID(conn ConnectionInterface, messageID string) (M
 	return message, nil
 }
 
+func (repo MessagesRepo) FindAllByVCAPRequestID(conn ConnectionInterface, vcapRequestID string) ([]Message, error) {
+	messages := []Message{}
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `vcap_request_id`=? ORDER BY `id`", vcapRequestID)
+	if err != nil {
+		return []Message{}, err
+	}
+	return messages, nil
+}
+
+func (repo MessagesRepo) Search(conn ConnectionInterface, query MessageQuery) ([]Message, error) {
+	var (
+		clauses []string
+		args    []interface{}
+	)
+
+	for _, filter := range []struct {
+		column string
+		value  string
+	}{
+		{"user_guid", query.UserGUID},
+		{"email", query.Email},
+		{"client_id", query.ClientID},
+		{"kind_id", query.KindID},
+		{"vcap_request_id", query.VCAPRequestID},
+		{"status", query.Status},
+	} {
+		if filter.value != "" {
+			clauses = append(clauses, fmt.Sprintf("`%s`=?", filter.column))
+			args = append(args, filter.value)
+		}
+	}
+
+	if !query.CreatedAfter.IsZero() {
+		clauses = append(clauses, "`created_at` >= ?")
+		args = append(args, query.CreatedAfter.UTC())
+	}
+
+	if !query.CreatedBefore.IsZero() {
+		clauses = append(clauses, "`created_at` < ?")
+		args = append(args, query.CreatedBefore.UTC())
+	}
+
+	statement := "SELECT * FROM `messages`"
+	if len(clauses) > 0 {
+		statement += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	statement += " ORDER BY `created_at` DESC, `id` LIMIT ? OFFSET ?"
+	args = append(args, query.Limit, query.Offset)
+
+	messages := []Message{}
+	_, err := conn.Select(&messages, statement, args...)
+	if err != nil {
+		return []Message{}, err
+	}
+	return messages, nil
+}
+
+func (repo MessagesRepo) RecordAttempt(conn ConnectionInterface, messageID, email string) error {
+	_, err := conn.Exec("UPDATE `messages` SET `attempts` = `attempts` + 1, `email` = IF(? = '', `email`, ?) WHERE `id`=?", email, email, messageID)
+	return err
+}
+
+func (repo MessagesRepo) CountByStatusForBatch(conn ConnectionInterface, batchID string) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+
+	_, err := conn.Select(&rows, "SELECT `status`, COUNT(*) AS `count` FROM `messages` WHERE `batch_id`=? GROUP BY `status`", batchID)
+	if err != nil {
+		return map[string]int{}, err
+	}
+
+	counts := map[string]int{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
 func (repo MessagesRepo) Update(conn ConnectionInterface, message Message) (Message, error) {
 	_, err := conn.Update(&message)
 	if err != nil {
@@ -56,20 +137,63 @@ func (repo MessagesRepo) Update(conn ConnectionInterface, message Message) (Mess
 }
 
 func (repo MessagesRepo) Upsert(conn ConnectionInterface, message Message) (Message, error) {
-	_, err := repo.FindByID(conn, message.ID)
+	existing, err := repo.FindByID(conn, message.ID)
 
 	switch err.(type) {
 	case NotFoundError:
 		return repo.Create(conn, message)
 	case nil:
+		if message.VCAPRequestID == "" {
+			message.VCAPRequestID = existing.VCAPRequestID
+		}
+		if message.BatchID == "" {
+			message.BatchID = existing.BatchID
+		}
+		if message.UserGUID == "" {
+			message.UserGUID = existing.UserGUID
+		}
+		if message.Email == "" {
+			message.Email = existing.Email
+		}
+		if message.ClientID == "" {
+			message.ClientID = existing.ClientID
+		}
+		if message.KindID == "" {
+			message.KindID = existing.KindID
+		}
+		if message.DeliveredAt == nil {
+			message.DeliveredAt = existing.DeliveredAt
+		}
+		message.Attempts = existing.Attempts
+		message.CreatedAt = existing.CreatedAt
 		return repo.Update(conn, message)
 	default:
 		return message, err
 	}
 }
 
-func (repo MessagesRepo) DeleteBefore(conn ConnectionInterface, threshold time.Time) (int, error) {
-	result, err := conn.Exec("DELETE FROM `messages` WHERE `updated_at` < ?", threshold.UTC())
+func (repo MessagesRepo) FindExpired(conn ConnectionInterface, status string, threshold time.Time, limit int) ([]Message, error) {
+	messages := []Message{}
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `status` = ? AND `updated_at` < ? ORDER BY `updated_at` LIMIT ?", status, threshold.UTC(), limit)
+	if err != nil {
+		return []Message{}, err
+	}
+	return messages, nil
+}
+
+func (repo MessagesRepo) DeleteByIDs(conn ConnectionInterface, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	result, err := conn.Exec("DELETE FROM `messages` WHERE `id` IN ("+strings.Join(placeholders, ", ")+")", args...)
 	if err != nil {
 		return 0, err
 	}
@@ -109,32 +109,204 @@ var _ = Describe("MessagesRepo", func() {
 				Expect(messageFound.ID).To(Equal(message.ID))
 				Expect(messageFound.Status).To(Equal(message.Status))
 			})
+
+			It("keeps the existing VCAP request ID when none is given", func() {
+				message.VCAPRequestID = "some-request-id"
+				message, err := repo.Create(conn, message)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Upsert(conn, models.Message{ID: message.ID, Status: common.StatusFailed})
+				Expect(err).NotTo(HaveOccurred())
+
+				messageFound, err := repo.FindByID(conn, message.ID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(messageFound.Status).To(Equal(common.StatusFailed))
+				Expect(messageFound.VCAPRequestID).To(Equal("some-request-id"))
+			})
+
+			It("keeps the existing metadata when a status is recorded", func() {
+				message, err := repo.Create(conn, models.Message{
+					Status:   common.StatusQueued,
+					UserGUID: "some-user",
+					ClientID: "some-client",
+					KindID:   "some-kind",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(repo.RecordAttempt(conn, message.ID, "user@example.com")).To(Succeed())
+
+				deliveredAt := time.Now().Truncate(time.Second).UTC()
+				_, err = repo.Upsert(conn, models.Message{ID: message.ID, Status: common.StatusDelivered, DeliveredAt: &deliveredAt})
+				Expect(err).NotTo(HaveOccurred())
+
+				messageFound, err := repo.FindByID(conn, message.ID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(messageFound.Status).To(Equal(common.StatusDelivered))
+				Expect(messageFound.UserGUID).To(Equal("some-user"))
+				Expect(messageFound.Email).To(Equal("user@example.com"))
+				Expect(messageFound.ClientID).To(Equal("some-client"))
+				Expect(messageFound.KindID).To(Equal("some-kind"))
+				Expect(messageFound.Attempts).To(Equal(1))
+				Expect(messageFound.CreatedAt).To(Equal(message.CreatedAt))
+				Expect(*messageFound.DeliveredAt).To(BeTemporally("==", deliveredAt))
+			})
 		})
 	})
 
-	Describe("DeleteBefore", func() {
-		It("Deletes messages older than the input time", func() {
-			message, err := repo.Create(conn, message)
+	Describe("Search", func() {
+		var first, second, third models.Message
+
+		BeforeEach(func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+			var err error
+			first, err = repo.Create(conn, models.Message{Status: common.StatusDelivered, UserGUID: "some-user", ClientID: "some-client", KindID: "billing"})
 			Expect(err).NotTo(HaveOccurred())
 
-			itemsDeleted, err := repo.DeleteBefore(conn, time.Now().Add(1*time.Hour))
-			Expect(err).ToNot(HaveOccurred())
-			Expect(itemsDeleted).To(Equal(1))
+			second, err = repo.Create(conn, models.Message{Status: common.StatusFailed, UserGUID: "some-user", ClientID: "some-client", KindID: "alerts"})
+			Expect(err).NotTo(HaveOccurred())
 
-			_, err = repo.FindByID(conn, message.ID)
-			Expect(err).To(MatchError(models.NotFoundError{Err: fmt.Errorf("Message with ID %q could not be found", message.ID)}))
+			third, err = repo.Create(conn, models.Message{Status: common.StatusDelivered, Email: "user@example.com", ClientID: "other-client", KindID: "billing"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the messages matching every filter", func() {
+			messages, err := repo.Search(conn, models.MessageQuery{UserGUID: "some-user", KindID: "billing", Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(Equal([]models.Message{first}))
 
+			messages, err = repo.Search(conn, models.MessageQuery{Email: "user@example.com", Status: common.StatusDelivered, Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(Equal([]models.Message{third}))
 		})
 
-		It("Does not delete messages younger than the input time", func() {
-			message, err := repo.Create(conn, message)
+		It("filters by creation time", func() {
+			messages, err := repo.Search(conn, models.MessageQuery{CreatedAfter: time.Now().Add(-time.Hour), CreatedBefore: time.Now().Add(time.Hour), Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(ConsistOf(first, second, third))
+
+			messages, err = repo.Search(conn, models.MessageQuery{CreatedAfter: time.Now().Add(time.Hour), Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+
+		It("paginates the results", func() {
+			messages, err := repo.Search(conn, models.MessageQuery{ClientID: "some-client", Limit: 1})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
 
-			itemsDeleted, err := repo.DeleteBefore(conn, time.Now().Add(-1*time.Hour))
+			rest, err := repo.Search(conn, models.MessageQuery{ClientID: "some-client", Limit: 10, Offset: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rest).To(HaveLen(1))
+			Expect(append(messages, rest...)).To(ConsistOf(first, second))
+		})
+	})
+
+	Describe("RecordAttempt", func() {
+		It("increments the attempts and records the email", func() {
+			message, err := repo.Create(conn, models.Message{Status: common.StatusQueued, UserGUID: "some-user"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.RecordAttempt(conn, message.ID, "user@example.com")).To(Succeed())
+			Expect(repo.RecordAttempt(conn, message.ID, "")).To(Succeed())
+
+			messageFound, err := repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messageFound.Attempts).To(Equal(2))
+			Expect(messageFound.Email).To(Equal("user@example.com"))
+		})
+	})
+
+	Describe("FindAllByVCAPRequestID", func() {
+		It("finds the messages created for the request", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+			first, err := repo.Create(conn, models.Message{Status: common.StatusQueued, VCAPRequestID: "some-request-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Message{Status: common.StatusQueued, VCAPRequestID: "other-request-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			third, err := repo.Create(conn, models.Message{Status: common.StatusQueued, VCAPRequestID: "some-request-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			messages, err := repo.FindAllByVCAPRequestID(conn, "some-request-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(Equal([]models.Message{first, third}))
+		})
+
+		It("returns an empty list when there are no messages for the request", func() {
+			messages, err := repo.FindAllByVCAPRequestID(conn, "missing-request-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+	})
+
+	Describe("CountByStatusForBatch", func() {
+		It("counts the messages in the batch by status", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid", "fourth-random-guid"}
+
+			for _, m := range []models.Message{
+				{Status: common.StatusQueued, BatchID: "some-batch-id"},
+				{Status: common.StatusDelivered, BatchID: "some-batch-id"},
+				{Status: common.StatusDelivered, BatchID: "some-batch-id"},
+				{Status: common.StatusDelivered, BatchID: "other-batch-id"},
+			} {
+				_, err := repo.Create(conn, m)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			counts, err := repo.CountByStatusForBatch(conn, "some-batch-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int{
+				common.StatusQueued:    1,
+				common.StatusDelivered: 2,
+			}))
+		})
+	})
+
+	Describe("FindExpired", func() {
+		It("returns messages in the given status last updated before the threshold", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid"}
+
+			delivered, err := repo.Create(conn, models.Message{Status: common.StatusDelivered})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Message{Status: common.StatusFailed})
+			Expect(err).NotTo(HaveOccurred())
+
+			messages, err := repo.FindExpired(conn, common.StatusDelivered, time.Now().Add(1*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(Equal([]models.Message{delivered}))
+		})
+
+		It("does not return messages updated after the threshold", func() {
+			_, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			messages, err := repo.FindExpired(conn, common.StatusDelivered, time.Now().Add(-1*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteByIDs", func() {
+		It("deletes the given messages", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid"}
+
+			first, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			itemsDeleted, err := repo.DeleteByIDs(conn, []string{first.ID})
 			Expect(err).ToNot(HaveOccurred())
-			Expect(itemsDeleted).To(Equal(0))
+			Expect(itemsDeleted).To(Equal(1))
+
+			_, err = repo.FindByID(conn, first.ID)
+			Expect(err).To(MatchError(models.NotFoundError{Err: fmt.Errorf("Message with ID %q could not be found", first.ID)}))
 
-			_, err = repo.FindByID(conn, message.ID)
+			_, err = repo.FindByID(conn, second.ID)
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
@@ -7,16 +7,18 @@ import (
 )
 
 type Receipt struct {
-	Primary   int       `db:"primary"`
-	UserGUID  string    `db:"user_guid"`
-	ClientID  string    `db:"client_id"`
-	KindID    string    `db:"kind_id"`
-	Count     int       `db:"count"`
-	CreatedAt time.Time `db:"created_at"`
+	Primary   int       `db:"primary" json:"primary"`
+	UserGUID  string    `db:"user_guid" json:"user_guid"`
+	ClientID  string    `db:"client_id" json:"client_id"`
+	KindID    string    `db:"kind_id" json:"kind_id"`
+	Count     int       `db:"count" json:"count"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
 func (r *Receipt) PreInsert(s gorp.SqlExecutor) error {
 	r.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	r.UpdatedAt = r.CreatedAt
 
 	if r.Count == 0 {
 		r.Count = 1
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type ReceiptsRepo struct{}
 
@@ -9,8 +12,9 @@ func NewReceiptsRepo() ReceiptsRepo {
 }
 
 func (repo ReceiptsRepo) upsert(conn ConnectionInterface, receipt Receipt) error {
-	query := "INSERT INTO `receipts` (`user_guid`, `client_id`, `kind_id`, `count`, `created_at`) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `count`=`count`+1"
-	_, err := conn.Exec(query, receipt.UserGUID, receipt.ClientID, receipt.KindID, 1, time.Now().Truncate(1*time.Second).UTC())
+	query := "INSERT INTO `receipts` (`user_guid`, `client_id`, `kind_id`, `count`, `created_at`, `updated_at`) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `count`=`count`+1, `updated_at`=VALUES(`updated_at`)"
+	now := time.Now().Truncate(1 * time.Second).UTC()
+	_, err := conn.Exec(query, receipt.UserGUID, receipt.ClientID, receipt.KindID, 1, now, now)
 	if err != nil {
 		return err
 	}
@@ -32,3 +36,35 @@ func (repo ReceiptsRepo) CreateReceipts(conn ConnectionInterface, userGUIDs []st
 	}
 	return nil
 }
+
+func (repo ReceiptsRepo) FindExpired(conn ConnectionInterface, threshold time.Time, limit int) ([]Receipt, error) {
+	receipts := []Receipt{}
+	_, err := conn.Select(&receipts, "SELECT * FROM `receipts` WHERE `updated_at` < ? ORDER BY `updated_at` LIMIT ?", threshold.UTC(), limit)
+	if err != nil {
+		return []Receipt{}, err
+	}
+	return receipts, nil
+}
+
+func (repo ReceiptsRepo) DeleteByIDs(conn ConnectionInterface, ids []int) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	result, err := conn.Exec("DELETE FROM `receipts` WHERE `primary` IN ("+strings.Join(placeholders, ", ")+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
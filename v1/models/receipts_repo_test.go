@@ -1,6 +1,8 @@
 package models_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -166,4 +168,40 @@ var _ = Describe("Receipts Repo", func() {
 			Expect(firstReceipt.Primary).ToNot(Equal(differentKindReceipt.Primary))
 		})
 	})
+
+	Describe("FindExpired", func() {
+		It("returns receipts last updated before the threshold", func() {
+			err := repo.CreateReceipts(conn, []string{"user-123"}, "client-abc", "be-kind")
+			Expect(err).NotTo(HaveOccurred())
+
+			receipt, err := findReceipt(conn, "user-123", "client-abc", "be-kind")
+			Expect(err).NotTo(HaveOccurred())
+
+			receipts, err := repo.FindExpired(conn, time.Now().Add(1*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(receipts).To(Equal([]models.Receipt{receipt}))
+
+			receipts, err = repo.FindExpired(conn, time.Now().Add(-1*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(receipts).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteByIDs", func() {
+		It("deletes the given receipts", func() {
+			err := repo.CreateReceipts(conn, []string{"user-123", "user-456"}, "client-abc", "be-kind")
+			Expect(err).NotTo(HaveOccurred())
+
+			receipt, err := findReceipt(conn, "user-123", "client-abc", "be-kind")
+			Expect(err).NotTo(HaveOccurred())
+
+			itemsDeleted, err := repo.DeleteByIDs(conn, []int{receipt.Primary})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(itemsDeleted).To(Equal(1))
+
+			rowCount, err := conn.SelectInt("SELECT COUNT(*) FROM `receipts`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(int(rowCount)).To(Equal(1))
+		})
+	})
 })
@@ -2,6 +2,7 @@ package models
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/util"
@@ -14,16 +15,26 @@ const (
 )
 
 type Template struct {
-	Primary    int       `db:"primary"`
-	ID         string    `db:"id"`
-	Name       string    `db:"name"`
-	Subject    string    `db:"subject"`
-	Text       string    `db:"text"`
-	HTML       string    `db:"html"`
-	Metadata   string    `db:"metadata"`
-	CreatedAt  time.Time `db:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at"`
-	Overridden bool      `db:"overridden"`
+	Primary       int       `db:"primary"`
+	ID            string    `db:"id"`
+	Name          string    `db:"name"`
+	Subject       string    `db:"subject"`
+	Text          string    `db:"text"`
+	HTML          string    `db:"html"`
+	Metadata      string    `db:"metadata"`
+	Localizations string    `db:"localizations"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+	Overridden    bool      `db:"overridden"`
+}
+
+// TemplateLocalization is the content of a template for one locale. The
+// localizations of a template are stored as a JSON object keyed by BCP 47
+// language tag.
+type TemplateLocalization struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
 }
 
 func (t *Template) PreInsert(s gorp.SqlExecutor) error {
@@ -42,3 +53,19 @@ func (t *Template) PreInsert(s gorp.SqlExecutor) error {
 
 	return nil
 }
+
+// ParseLocalizations decodes the localizations of the template. Templates
+// written before localizations were supported have none.
+func (t Template) ParseLocalizations() (map[string]TemplateLocalization, error) {
+	localizations := map[string]TemplateLocalization{}
+	if t.Localizations == "" {
+		return localizations, nil
+	}
+
+	err := json.Unmarshal([]byte(t.Localizations), &localizations)
+	if err != nil {
+		return nil, err
+	}
+
+	return localizations, nil
+}
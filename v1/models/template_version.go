@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// LatestTemplateVersion is stored on clients and kinds that follow the
+// current content of their template rather than a pinned version.
+const LatestTemplateVersion = 0
+
+type TemplateVersion struct {
+	Primary       int       `db:"primary"`
+	TemplateID    string    `db:"template_id"`
+	Version       int       `db:"version"`
+	Name          string    `db:"name"`
+	Subject       string    `db:"subject"`
+	Text          string    `db:"text"`
+	HTML          string    `db:"html"`
+	Metadata      string    `db:"metadata"`
+	Localizations string    `db:"localizations"`
+	Author        string    `db:"author"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func (v *TemplateVersion) PreInsert(s gorp.SqlExecutor) error {
+	if (v.CreatedAt == time.Time{}) {
+		v.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+
+	return nil
+}
+
+func (v TemplateVersion) Template() Template {
+	return Template{
+		ID:            v.TemplateID,
+		Name:          v.Name,
+		Subject:       v.Subject,
+		Text:          v.Text,
+		HTML:          v.HTML,
+		Metadata:      v.Metadata,
+		Localizations: v.Localizations,
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type TemplateVersionsRepo struct{}
+
+func NewTemplateVersionsRepo() TemplateVersionsRepo {
+	return TemplateVersionsRepo{}
+}
+
+// Create records the content of the template as the next version. Versions
+// are never updated once written. The latest version is read with a locking
+// read so that, within a transaction, it is the latest committed one and
+// concurrent updates of the template wait for each other instead of picking
+// the same number.
+func (repo TemplateVersionsRepo) Create(conn ConnectionInterface, template Template, author string) (TemplateVersion, error) {
+	latest := TemplateVersion{}
+	err := conn.SelectOne(&latest, "SELECT * FROM `template_versions` WHERE `template_id` = ? ORDER BY `version` DESC LIMIT 1 FOR UPDATE", template.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return TemplateVersion{}, err
+	}
+
+	version := TemplateVersion{
+		TemplateID:    template.ID,
+		Version:       latest.Version + 1,
+		Name:          template.Name,
+		Subject:       template.Subject,
+		Text:          template.Text,
+		HTML:          template.HTML,
+		Metadata:      template.Metadata,
+		Localizations: template.Localizations,
+		Author:        author,
+	}
+
+	err = conn.Insert(&version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
+
+func (repo TemplateVersionsRepo) List(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+	_, err := conn.Select(&versions, "SELECT * FROM `template_versions` WHERE `template_id` = ? ORDER BY `version`", templateID)
+	if err != nil {
+		return []TemplateVersion{}, err
+	}
+
+	return versions, nil
+}
+
+func (repo TemplateVersionsRepo) Find(conn ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion := TemplateVersion{}
+	err := conn.SelectOne(&templateVersion, "SELECT * FROM `template_versions` WHERE `template_id` = ? AND `version` = ?", templateID, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return TemplateVersion{}, NotFoundError{fmt.Errorf("Version %d of template %q could not be found", version, templateID)}
+		}
+		return TemplateVersion{}, err
+	}
+
+	return templateVersion, nil
+}
+
+func (repo TemplateVersionsRepo) DestroyAll(conn ConnectionInterface, templateID string) error {
+	_, err := conn.Exec("DELETE FROM `template_versions` WHERE `template_id` = ?", templateID)
+	return err
+}
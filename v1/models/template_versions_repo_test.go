@@ -0,0 +1,130 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepo", func() {
+	var (
+		repo     models.TemplateVersionsRepo
+		conn     db.ConnectionInterface
+		template models.Template
+	)
+
+	BeforeEach(func() {
+		repo = models.NewTemplateVersionsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		template = models.Template{
+			ID:       "raptor_template",
+			Name:     "Raptors On The Run",
+			Subject:  "{{.Subject}}",
+			Text:     "run and hide",
+			HTML:     "<h1>containment unit breached!</h1>",
+			Metadata: "{}",
+		}
+	})
+
+	Describe("Create", func() {
+		It("numbers the versions of each template from 1", func() {
+			version, err := repo.Create(conn, template, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Version).To(Equal(1))
+			Expect(version.TemplateID).To(Equal("raptor_template"))
+			Expect(version.Author).To(Equal("some-client"))
+			Expect(version.CreatedAt).NotTo(BeZero())
+
+			template.Text = "run faster"
+			version, err = repo.Create(conn, template, "other-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Version).To(Equal(2))
+
+			template.ID = "other_template"
+			version, err = repo.Create(conn, template, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Version).To(Equal(1))
+		})
+
+		It("numbers the version after one committed by a concurrent transaction", func() {
+			_, err := repo.Create(conn, template, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			first := conn.Transaction()
+			Expect(first.Begin()).To(Succeed())
+			second := conn.Transaction()
+			Expect(second.Begin()).To(Succeed())
+
+			_, err = repo.List(second, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+
+			version, err := repo.Create(first, template, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Version).To(Equal(2))
+			Expect(first.Commit()).To(Succeed())
+
+			version, err = repo.Create(second, template, "other-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Version).To(Equal(3))
+			Expect(second.Commit()).To(Succeed())
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the versions of the template, oldest first", func() {
+			_, err := repo.Create(conn, template, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			template.Text = "run faster"
+			_, err = repo.Create(conn, template, "other-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].Version).To(Equal(1))
+			Expect(versions[0].Text).To(Equal("run and hide"))
+			Expect(versions[1].Version).To(Equal(2))
+			Expect(versions[1].Text).To(Equal("run faster"))
+			Expect(versions[1].Author).To(Equal("other-client"))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns the requested version", func() {
+			_, err := repo.Create(conn, template, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			version, err := repo.Find(conn, "raptor_template", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Template()).To(Equal(template))
+		})
+
+		It("returns a not found error when the version does not exist", func() {
+			_, err := repo.Find(conn, "raptor_template", 3)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New(`Version 3 of template "raptor_template" could not be found`)}))
+		})
+	})
+
+	Describe("DestroyAll", func() {
+		It("deletes every version of the template", func() {
+			_, err := repo.Create(conn, template, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.DestroyAll(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type UserLocale struct {
+	Primary   int       `db:"primary"`
+	UserID    string    `db:"user_id"`
+	Locale    string    `db:"locale"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
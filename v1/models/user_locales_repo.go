@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type UserLocalesRepo struct{}
+
+func NewUserLocalesRepo() UserLocalesRepo {
+	return UserLocalesRepo{}
+}
+
+// Set stores the preferred locale of a user. Setting an empty locale removes
+// the preference so that the user receives the default content again.
+func (repo UserLocalesRepo) Set(conn ConnectionInterface, userGUID, locale string) error {
+	userLocale, err := repo.find(conn, userGUID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		userLocale = UserLocale{
+			UserID: userGUID,
+		}
+	}
+
+	userLocale.Locale = locale
+	userLocale.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	switch {
+	case locale == "" && userLocale.Primary != 0:
+		_, err = conn.Delete(&userLocale)
+	case locale != "" && userLocale.Primary == 0:
+		err = conn.Insert(&userLocale)
+	case locale != "":
+		_, err = conn.Update(&userLocale)
+	}
+
+	return err
+}
+
+func (repo UserLocalesRepo) Get(conn ConnectionInterface, userGUID string) (string, error) {
+	userLocale, err := repo.find(conn, userGUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return userLocale.Locale, nil
+}
+
+func (repo UserLocalesRepo) find(conn ConnectionInterface, userGUID string) (UserLocale, error) {
+	userLocale := UserLocale{}
+	err := conn.SelectOne(&userLocale, "SELECT * FROM `user_locales` WHERE `user_id` = ?", userGUID)
+	if err != nil {
+		return UserLocale{}, err
+	}
+
+	return userLocale, nil
+}
@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserLocalesRepo", func() {
+	var repo models.UserLocalesRepo
+	var conn *db.Connection
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection().(*db.Connection)
+		repo = models.NewUserLocalesRepo()
+	})
+
+	Describe("Set/Get", func() {
+		It("stores the locale of a user, allowing it to be changed and removed later", func() {
+			locale, err := repo.Get(conn, "my-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locale).To(BeEmpty())
+
+			err = repo.Set(conn, "my-user", "fr-CA")
+			Expect(err).NotTo(HaveOccurred())
+
+			locale, err = repo.Get(conn, "my-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locale).To(Equal("fr-CA"))
+
+			err = repo.Set(conn, "my-user", "de")
+			Expect(err).NotTo(HaveOccurred())
+
+			locale, err = repo.Get(conn, "my-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locale).To(Equal("de"))
+
+			err = repo.Set(conn, "my-user", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			locale, err = repo.Get(conn, "my-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locale).To(BeEmpty())
+
+			count, err := conn.SelectInt("SELECT COUNT(*) FROM `user_locales`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(0)))
+		})
+	})
+})
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type Webhook struct {
+	ID        string    `db:"id"`
+	ClientID  string    `db:"client_id"`
+	URL       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (w *Webhook) PreInsert(s gorp.SqlExecutor) error {
+	w.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
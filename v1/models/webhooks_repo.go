@@ -0,0 +1,71 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type WebhooksRepo struct {
+	generateID IDGeneratorFunc
+}
+
+func NewWebhooksRepo(guidGenerator IDGeneratorFunc) WebhooksRepo {
+	return WebhooksRepo{
+		generateID: guidGenerator,
+	}
+}
+
+func (repo WebhooksRepo) Create(conn ConnectionInterface, webhook Webhook) (Webhook, error) {
+	if webhook.ID == "" {
+		var err error
+		webhook.ID, err = repo.generateID()
+		if err != nil {
+			return Webhook{}, err
+		}
+	}
+
+	err := conn.Insert(&webhook)
+	if err != nil {
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (repo WebhooksRepo) FindByID(conn ConnectionInterface, webhookID string) (Webhook, error) {
+	webhook := Webhook{}
+	err := conn.SelectOne(&webhook, "SELECT * FROM `webhooks` WHERE `id`=?", webhookID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Webhook{}, NotFoundError{fmt.Errorf("Webhook with ID %q could not be found", webhookID)}
+		}
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (repo WebhooksRepo) FindAllByClientID(conn ConnectionInterface, clientID string) ([]Webhook, error) {
+	webhooks := []Webhook{}
+	_, err := conn.Select(&webhooks, "SELECT * FROM `webhooks` WHERE `client_id`=? ORDER BY `created_at`, `id`", clientID)
+	if err != nil {
+		return []Webhook{}, err
+	}
+	return webhooks, nil
+}
+
+func (repo WebhooksRepo) Delete(conn ConnectionInterface, clientID, webhookID string) error {
+	result, err := conn.Exec("DELETE FROM `webhooks` WHERE `id`=? AND `client_id`=?", webhookID, clientID)
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return NotFoundError{fmt.Errorf("Webhook with ID %q could not be found", webhookID)}
+	}
+
+	return nil
+}
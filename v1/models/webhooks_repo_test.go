@@ -0,0 +1,111 @@
+package models_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhooksRepo", func() {
+	var (
+		repo          models.WebhooksRepo
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+		repo = models.NewWebhooksRepo(guidGenerator.Generate)
+	})
+
+	Describe("Create", func() {
+		It("inserts a webhook into the database", func() {
+			webhook, err := repo.Create(conn, models.Webhook{
+				ClientID: "some-client-id",
+				URL:      "https://example.com/hooks",
+				Secret:   "some-secret",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhook.ID).To(Equal("first-random-guid"))
+			Expect(webhook.CreatedAt).NotTo(BeZero())
+
+			webhookFound, err := repo.FindByID(conn, webhook.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhookFound).To(Equal(webhook))
+		})
+
+		It("returns an error when the guid generator errors", func() {
+			guidGenerator.GenerateCall.Returns.Error = errors.New("something bad")
+
+			_, err := repo.Create(conn, models.Webhook{})
+			Expect(err).To(MatchError(errors.New("something bad")))
+		})
+	})
+
+	Describe("FindByID", func() {
+		It("returns a not found error when the webhook does not exist", func() {
+			_, err := repo.FindByID(conn, "missing-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New(`Webhook with ID "missing-id" could not be found`)}))
+		})
+	})
+
+	Describe("FindAllByClientID", func() {
+		It("returns the webhooks registered by the client", func() {
+			first, err := repo.Create(conn, models.Webhook{ClientID: "some-client-id", URL: "https://example.com/first"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Webhook{ClientID: "other-client-id", URL: "https://example.com/other"})
+			Expect(err).NotTo(HaveOccurred())
+
+			third, err := repo.Create(conn, models.Webhook{ClientID: "some-client-id", URL: "https://example.com/third"})
+			Expect(err).NotTo(HaveOccurred())
+
+			webhooks, err := repo.FindAllByClientID(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhooks).To(ConsistOf(first, third))
+		})
+
+		It("returns an empty list when the client has no webhooks", func() {
+			webhooks, err := repo.FindAllByClientID(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhooks).To(BeEmpty())
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the webhook", func() {
+			webhook, err := repo.Create(conn, models.Webhook{ClientID: "some-client-id", URL: "https://example.com/hooks"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, "some-client-id", webhook.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.FindByID(conn, webhook.ID)
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+
+		It("does not delete a webhook belonging to another client", func() {
+			webhook, err := repo.Create(conn, models.Webhook{ClientID: "some-client-id", URL: "https://example.com/hooks"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, "other-client-id", webhook.ID)
+			Expect(err).To(MatchError(models.NotFoundError{Err: fmt.Errorf("Webhook with ID %q could not be found", webhook.ID)}))
+
+			_, err = repo.FindByID(conn, webhook.ID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -3,30 +3,30 @@ package services
 import "github.com/cloudfoundry-incubator/notifications/uaa"
 
 type AllUsers struct {
-	uaa uaaAllUsers
+	uaa uaaUsersPager
 }
 
-type uaaAllUsers interface {
-	AllUsers(token string) ([]uaa.User, error)
+type uaaUsersPager interface {
+	UsersPage(token string, startIndex, count int) ([]uaa.User, int, error)
 }
 
-func NewAllUsers(uaa uaaAllUsers) AllUsers {
+func NewAllUsers(uaa uaaUsersPager) AllUsers {
 	return AllUsers{
 		uaa: uaa,
 	}
 }
 
-func (allUsers AllUsers) AllUserGUIDs(token string) ([]string, error) {
+func (allUsers AllUsers) UserGUIDsPage(token string, startIndex, count int) ([]string, int, error) {
 	var guids []string
 
-	usersMap, err := allUsers.uaa.AllUsers(token)
+	users, totalResults, err := allUsers.uaa.UsersPage(token, startIndex, count)
 	if err != nil {
-		return guids, err
+		return guids, 0, err
 	}
 
-	for _, user := range usersMap {
+	for _, user := range users {
 		guids = append(guids, user.ID)
 	}
 
-	return guids, nil
+	return guids, totalResults, nil
 }
@@ -11,10 +11,9 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("AllUserGUIDs", func() {
+var _ = Describe("UserGUIDsPage", func() {
 	var allUsers services.AllUsers
 	var uaaClient *mocks.ZonedUAAClient
-	var users []uaa.User
 
 	BeforeEach(func() {
 		uaaClient = mocks.NewZonedUAAClient()
@@ -23,7 +22,7 @@ var _ = Describe("AllUserGUIDs", func() {
 
 	Context("when the request succeeds", func() {
 		BeforeEach(func() {
-			users = []uaa.User{
+			uaaClient.UsersPageCall.Returns.Users = []uaa.User{
 				{
 					Emails: []string{"user-123@example.com"},
 					ID:     "user-123",
@@ -37,25 +36,26 @@ var _ = Describe("AllUserGUIDs", func() {
 					ID:     "user-999",
 				},
 			}
-
-			uaaClient.AllUsersCall.Returns.Users = users
+			uaaClient.UsersPageCall.Returns.TotalResults = 250
 		})
 
-		It("returns the UAAUsers, UserGUIDs, and an error", func() {
-			guids, err := allUsers.AllUserGUIDs("token")
+		It("returns the user GUIDs on the page and the total number of users", func() {
+			guids, total, err := allUsers.UserGUIDsPage("token", 101, 100)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(guids).To(ConsistOf("user-456", "user-999", "user-123"))
+			Expect(guids).To(Equal([]string{"user-123", "user-456", "user-999"}))
+			Expect(total).To(Equal(250))
 
-			Expect(uaaClient.AllUsersCall.Receives.Token).To(Equal("token"))
+			Expect(uaaClient.UsersPageCall.Receives.Token).To(Equal("token"))
+			Expect(uaaClient.UsersPageCall.Receives.StartIndex).To(Equal(101))
+			Expect(uaaClient.UsersPageCall.Receives.Count).To(Equal(100))
 		})
 	})
 
 	Context("when the request to UAA fails", func() {
 		It("bubbles up the error", func() {
-			uaaClient.AllUsersCall.Returns.Error = errors.New("BOOM!")
+			uaaClient.UsersPageCall.Returns.Error = errors.New("BOOM!")
 
-			_, err := allUsers.AllUserGUIDs("token")
-			Expect(err).To(HaveOccurred())
+			_, _, err := allUsers.UserGUIDsPage("token", 1, 100)
 			Expect(err).To(MatchError(errors.New("BOOM!")))
 		})
 	})
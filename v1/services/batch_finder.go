@@ -0,0 +1,83 @@
+package services
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type Batch struct {
+	ID            string
+	ClientID      string
+	KindID        string
+	VCAPRequestID string
+	Audience      string
+	GUID          string
+	Status        string
+	Error         string
+	Total         int
+	Enqueued      int
+	Counts        map[string]int
+	CreatedAt     time.Time
+}
+
+type batchesRepoFinder interface {
+	FindByID(models.ConnectionInterface, string) (models.Batch, error)
+}
+
+type messagesRepoCounter interface {
+	CountByStatusForBatch(models.ConnectionInterface, string) (map[string]int, error)
+}
+
+type BatchFinder struct {
+	batchesRepo  batchesRepoFinder
+	messagesRepo messagesRepoCounter
+}
+
+func NewBatchFinder(batchesRepo batchesRepoFinder, messagesRepo messagesRepoCounter) BatchFinder {
+	return BatchFinder{
+		batchesRepo:  batchesRepo,
+		messagesRepo: messagesRepo,
+	}
+}
+
+func (finder BatchFinder) Find(database DatabaseInterface, batchID string) (Batch, error) {
+	conn := database.Connection()
+
+	batch, err := finder.batchesRepo.FindByID(conn, batchID)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	counts, err := finder.messagesRepo.CountByStatusForBatch(conn, batchID)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	statusCounts := map[string]int{
+		StatusQueued:        0,
+		StatusScheduled:     0,
+		StatusDelivered:     0,
+		StatusFailed:        0,
+		StatusUndeliverable: 0,
+		StatusCanceled:      0,
+	}
+	for status, count := range counts {
+		statusCounts[status] += count
+	}
+
+	return Batch{
+		ID:            batch.ID,
+		ClientID:      batch.ClientID,
+		KindID:        batch.KindID,
+		VCAPRequestID: batch.VCAPRequestID,
+		Audience:      batch.Audience,
+		GUID:          batch.GUID,
+		Status:        batch.Status,
+		Error:         batch.Error,
+		Total:         batch.Total,
+		Enqueued:      batch.Enqueued,
+		Counts:        statusCounts,
+		CreatedAt:     batch.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchFinder.Find", func() {
+	var (
+		finder       services.BatchFinder
+		batchesRepo  *mocks.BatchesRepo
+		messagesRepo *mocks.MessagesRepo
+		database     *mocks.Database
+		conn         *mocks.Connection
+		createdAt    time.Time
+	)
+
+	BeforeEach(func() {
+		createdAt = time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC)
+
+		batchesRepo = mocks.NewBatchesRepo()
+		batchesRepo.FindByIDCall.Returns.Batch = models.Batch{
+			ID:            "some-batch-id",
+			ClientID:      "some-client",
+			KindID:        "some-kind",
+			VCAPRequestID: "some-request-id",
+			Audience:      "organization",
+			GUID:          "some-org-guid",
+			Status:        "enqueued",
+			Total:         5,
+			Enqueued:      5,
+			CreatedAt:     createdAt,
+		}
+
+		messagesRepo = mocks.NewMessagesRepo()
+		messagesRepo.CountByStatusForBatchCall.Returns.Counts = map[string]int{
+			"queued":    2,
+			"delivered": 3,
+		}
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		finder = services.NewBatchFinder(batchesRepo, messagesRepo)
+	})
+
+	It("returns the batch with counts for every status", func() {
+		batch, err := finder.Find(database, "some-batch-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(batch).To(Equal(services.Batch{
+			ID:            "some-batch-id",
+			ClientID:      "some-client",
+			KindID:        "some-kind",
+			VCAPRequestID: "some-request-id",
+			Audience:      "organization",
+			GUID:          "some-org-guid",
+			Status:        "enqueued",
+			Total:         5,
+			Enqueued:      5,
+			Counts: map[string]int{
+				"queued":        2,
+				"scheduled":     0,
+				"delivered":     3,
+				"failed":        0,
+				"undeliverable": 0,
+				"canceled":      0,
+			},
+			CreatedAt: createdAt,
+		}))
+
+		Expect(batchesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
+		Expect(batchesRepo.FindByIDCall.Receives.BatchID).To(Equal("some-batch-id"))
+		Expect(messagesRepo.CountByStatusForBatchCall.Receives.Connection).To(Equal(conn))
+		Expect(messagesRepo.CountByStatusForBatchCall.Receives.BatchID).To(Equal("some-batch-id"))
+	})
+
+	Context("when the batch cannot be found", func() {
+		It("returns the error", func() {
+			batchesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := finder.Find(database, "some-batch-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Context("when the messages cannot be counted", func() {
+		It("returns the error", func() {
+			messagesRepo.CountByStatusForBatchCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := finder.Find(database, "some-batch-id")
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+})
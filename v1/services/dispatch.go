@@ -24,17 +24,35 @@ type HTML struct {
 	Doctype        string
 }
 
+type Localization struct {
+	Subject string
+	Text    string
+	HTML    HTML
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	Inline      bool
+	ContentID   string
+}
+
 type DispatchVCAPRequest struct {
 	ID          string
 	ReceiptTime time.Time
 }
 
 type DispatchMessage struct {
-	To      string
-	ReplyTo string
-	Subject string
-	Text    string
-	HTML    HTML
+	To            string
+	ReplyTo       string
+	Subject       string
+	Text          string
+	HTML          HTML
+	Data          map[string]interface{}
+	SendAt        time.Time
+	Attachments   []Attachment
+	Localizations map[string]Localization
 }
 
 type DispatchClient struct {
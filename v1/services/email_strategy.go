@@ -43,6 +43,10 @@ func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		Endorsement:       EmailEndorsement,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		Data:              dispatch.Message.Data,
+		SendAt:            dispatch.Message.SendAt,
+		Attachments:       dispatch.Message.Attachments,
+		Localizations:     dispatch.Message.Localizations,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -10,8 +10,6 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
-const StatusQueued = "queued"
-
 type Options struct {
 	ReplyTo           string
 	Subject           string
@@ -24,6 +22,10 @@ type Options struct {
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	Data              map[string]interface{}
+	SendAt            time.Time
+	Attachments       []Attachment
+	Localizations     map[string]Localization
 }
 
 type Delivery struct {
@@ -52,16 +54,23 @@ type gobbleInitializer interface {
 	InitializeDBMap(*gorp.DbMap)
 }
 
+type batchesRepoWriter interface {
+	batchesRepoCreator
+	Update(models.ConnectionInterface, models.Batch) (models.Batch, error)
+}
+
 type Enqueuer struct {
 	queue             queueInterface
 	messagesRepo      messagesRepoUpserter
+	batchesRepo       batchesRepoWriter
 	gobbleInitializer gobbleInitializer
 }
 
-func NewEnqueuer(queue queueInterface, messagesRepo messagesRepoUpserter, gobbleInitializer gobbleInitializer) Enqueuer {
+func NewEnqueuer(queue queueInterface, messagesRepo messagesRepoUpserter, batchesRepo batchesRepoWriter, gobbleInitializer gobbleInitializer) Enqueuer {
 	return Enqueuer{
 		queue:             queue,
 		messagesRepo:      messagesRepo,
+		batchesRepo:       batchesRepo,
 		gobbleInitializer: gobbleInitializer,
 	}
 }
@@ -78,6 +87,41 @@ func (enqueuer Enqueuer) Enqueue(
 	vcapRequestID string,
 	reqReceived time.Time) ([]Response, error) {
 
+	return enqueuer.enqueue(conn, models.Batch{}, users, options, space, organization, clientID, uaaHost, scope, vcapRequestID, reqReceived)
+}
+
+// EnqueueBatch enqueues users for an existing batch and saves the batch, with
+// the progress the caller recorded on it, in the same transaction as the
+// jobs. A fan-out that is retried therefore never enqueues a chunk twice.
+func (enqueuer Enqueuer) EnqueueBatch(
+	conn ConnectionInterface,
+	batch models.Batch,
+	users []User,
+	options Options,
+	space cf.CloudControllerSpace,
+	organization cf.CloudControllerOrganization,
+	clientID,
+	uaaHost,
+	scope,
+	vcapRequestID string,
+	reqReceived time.Time) ([]Response, error) {
+
+	return enqueuer.enqueue(conn, batch, users, options, space, organization, clientID, uaaHost, scope, vcapRequestID, reqReceived)
+}
+
+func (enqueuer Enqueuer) enqueue(
+	conn ConnectionInterface,
+	batch models.Batch,
+	users []User,
+	options Options,
+	space cf.CloudControllerSpace,
+	organization cf.CloudControllerOrganization,
+	clientID,
+	uaaHost,
+	scope,
+	vcapRequestID string,
+	reqReceived time.Time) ([]Response, error) {
+
 	var responses []Response
 
 	transaction := conn.Transaction()
@@ -87,9 +131,42 @@ func (enqueuer Enqueuer) Enqueue(
 		return []Response{}, err
 	}
 
+	batchID := batch.ID
+	if batchID == "" {
+		audience, guid := audienceFor(users, space)
+
+		batch, err := enqueuer.batchesRepo.Create(transaction, models.Batch{
+			ClientID:      clientID,
+			KindID:        options.KindID,
+			VCAPRequestID: vcapRequestID,
+			Audience:      audience,
+			GUID:          guid,
+			Status:        BatchStatusEnqueued,
+			Total:         len(users),
+			Enqueued:      len(users),
+		})
+		if err != nil {
+			transaction.Rollback()
+			return []Response{}, err
+		}
+
+		batchID = batch.ID
+	}
+
+	status := StatusQueued
+	if options.SendAt.After(reqReceived) {
+		status = StatusScheduled
+	}
+
 	for _, user := range users {
 		message, err := enqueuer.messagesRepo.Upsert(transaction, models.Message{
-			Status: StatusQueued,
+			Status:        status,
+			VCAPRequestID: vcapRequestID,
+			BatchID:       batchID,
+			UserGUID:      user.GUID,
+			Email:         user.Email,
+			ClientID:      clientID,
+			KindID:        options.KindID,
 		})
 		if err != nil {
 			transaction.Rollback()
@@ -110,6 +187,11 @@ func (enqueuer Enqueuer) Enqueue(
 			RequestReceived: reqReceived,
 		})
 
+		job.Key = message.ID
+		if status == StatusScheduled {
+			job.ActiveAt = options.SendAt
+		}
+
 		_, err = enqueuer.queue.Enqueue(job, transaction)
 		if err != nil {
 			transaction.Rollback()
@@ -126,12 +208,37 @@ func (enqueuer Enqueuer) Enqueue(
 			NotificationID: message.ID,
 			Recipient:      recipient,
 			VCAPRequestID:  vcapRequestID,
+			BatchID:        batchID,
 		})
 	}
 
+	if batch.ID != "" {
+		_, err := enqueuer.batchesRepo.Update(transaction, batch)
+		if err != nil {
+			transaction.Rollback()
+			return []Response{}, err
+		}
+	}
+
 	if err := transaction.Commit(); err != nil {
 		return []Response{}, err
 	}
 
 	return responses, nil
 }
+
+func audienceFor(users []User, space cf.CloudControllerSpace) (string, string) {
+	if space.GUID != "" {
+		return AudienceSpace, space.GUID
+	}
+
+	if len(users) == 1 && users[0].Email != "" {
+		return AudienceEmail, users[0].Email
+	}
+
+	if len(users) == 1 {
+		return AudienceUser, users[0].GUID
+	}
+
+	return "", ""
+}
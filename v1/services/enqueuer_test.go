@@ -24,6 +24,7 @@ var _ = Describe("Enqueuer", func() {
 		org               cf.CloudControllerOrganization
 		reqReceived       time.Time
 		messagesRepo      *mocks.MessagesRepo
+		batchesRepo       *mocks.BatchesRepo
 	)
 
 	BeforeEach(func() {
@@ -61,7 +62,10 @@ var _ = Describe("Enqueuer", func() {
 			},
 		}
 
-		enqueuer = services.NewEnqueuer(queue, messagesRepo, gobbleInitializer)
+		batchesRepo = mocks.NewBatchesRepo()
+		batchesRepo.CreateCall.Returns.Batch = models.Batch{ID: "some-batch-id"}
+
+		enqueuer = services.NewEnqueuer(queue, messagesRepo, batchesRepo, gobbleInitializer)
 	})
 
 	Describe("Enqueue", func() {
@@ -77,24 +81,28 @@ var _ = Describe("Enqueuer", func() {
 					Recipient:      "user-1",
 					NotificationID: "first-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-2@example.com",
 					NotificationID: "second-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-3",
 					NotificationID: "third-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-4",
 					NotificationID: "fourth-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 			}))
 		})
@@ -172,19 +180,155 @@ var _ = Describe("Enqueuer", func() {
 		})
 
 		It("upserts a StatusQueued for each of the jobs", func() {
-			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
-			enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			users := []services.User{{GUID: "user-1"}, {Email: "user-2@example.com"}, {GUID: "user-3"}, {GUID: "user-4"}}
+			enqueuer.Enqueue(conn, users, services.Options{KindID: "the-kind"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
 
 			messages := messagesRepo.UpsertCall.Receives.Messages
 			Expect(messages).To(HaveLen(4))
 			Expect(messages).To(Equal([]models.Message{
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
+				{Status: services.StatusQueued, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-1", ClientID: "the-client", KindID: "the-kind"},
+				{Status: services.StatusQueued, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", Email: "user-2@example.com", ClientID: "the-client", KindID: "the-kind"},
+				{Status: services.StatusQueued, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-3", ClientID: "the-client", KindID: "the-kind"},
+				{Status: services.StatusQueued, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-4", ClientID: "the-client", KindID: "the-kind"},
 			}))
 		})
 
+		It("keys each job by its message ID", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+			Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(2))
+			Expect(queue.EnqueueCall.Receives.Jobs[0].Key).To(Equal("first-random-guid"))
+			Expect(queue.EnqueueCall.Receives.Jobs[1].Key).To(Equal("second-random-guid"))
+		})
+
+		It("records the batch ID on each message when enqueuing a batch", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			_, err := enqueuer.EnqueueBatch(conn, models.Batch{ID: "some-batch-id"}, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages).To(Equal([]models.Message{
+				{Status: services.StatusQueued, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-1", ClientID: "the-client"},
+				{Status: services.StatusQueued, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-2", ClientID: "the-client"},
+			}))
+			Expect(batchesRepo.CreateCall.Receives.Connection).To(BeNil())
+		})
+
+		It("saves the progress of the batch in the same transaction as the jobs", func() {
+			batch := models.Batch{ID: "some-batch-id", Status: services.BatchStatusEnqueuing, Total: 10, Enqueued: 2}
+			_, err := enqueuer.EnqueueBatch(conn, batch, []services.User{{GUID: "user-1"}, {GUID: "user-2"}}, services.Options{}, space, org, "the-client", "my-uaa-host", "", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batchesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
+			Expect(batchesRepo.UpdateCall.Receives.Batches).To(Equal([]models.Batch{batch}))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("rolls back the jobs when the progress of the batch cannot be saved", func() {
+			batchesRepo.UpdateCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := enqueuer.EnqueueBatch(conn, models.Batch{ID: "some-batch-id"}, []services.User{{GUID: "user-1"}}, services.Options{}, space, org, "the-client", "my-uaa-host", "", "some-request-id", reqReceived)
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("does not update the batch it creates for a single send", func() {
+			_, err := enqueuer.Enqueue(conn, []services.User{{GUID: "user-1"}}, services.Options{}, space, org, "the-client", "my-uaa-host", "", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batchesRepo.UpdateCall.Receives.Batches).To(BeEmpty())
+		})
+
+		It("records a batch for the recipients", func() {
+			users := []services.User{{GUID: "user-1"}}
+			_, err := enqueuer.Enqueue(conn, users, services.Options{KindID: "the-kind"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batchesRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(batchesRepo.CreateCall.Receives.Batch).To(Equal(models.Batch{
+				ClientID:      "the-client",
+				KindID:        "the-kind",
+				VCAPRequestID: "some-request-id",
+				Audience:      services.AudienceUser,
+				GUID:          "user-1",
+				Status:        services.BatchStatusEnqueued,
+				Total:         1,
+				Enqueued:      1,
+			}))
+		})
+
+		It("records the space as the batch target when sending to a space", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			space.GUID = "some-space-guid"
+			_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batchesRepo.CreateCall.Receives.Batch.Audience).To(Equal(services.AudienceSpace))
+			Expect(batchesRepo.CreateCall.Receives.Batch.GUID).To(Equal("some-space-guid"))
+			Expect(batchesRepo.CreateCall.Receives.Batch.Total).To(Equal(2))
+		})
+
+		It("records the email address as the batch target when sending to an email", func() {
+			users := []services.User{{Email: "user@example.com"}}
+			_, err := enqueuer.Enqueue(conn, users, services.Options{}, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, "the-client", "my-uaa-host", "", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batchesRepo.CreateCall.Receives.Batch.Audience).To(Equal(services.AudienceEmail))
+			Expect(batchesRepo.CreateCall.Receives.Batch.GUID).To(Equal("user@example.com"))
+		})
+
+		It("rolls back the transaction when the batch cannot be created", func() {
+			batchesRepo.CreateCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := enqueuer.Enqueue(conn, []services.User{{GUID: "user-1"}}, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages).To(BeEmpty())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		Context("when the options include a send_at time in the future", func() {
+			var sendAt time.Time
+
+			BeforeEach(func() {
+				sendAt = reqReceived.Add(6 * time.Hour)
+			})
+
+			It("upserts a StatusScheduled for each of the jobs", func() {
+				users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+				enqueuer.Enqueue(conn, users, services.Options{SendAt: sendAt}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(messagesRepo.UpsertCall.Receives.Messages).To(Equal([]models.Message{
+					{Status: services.StatusScheduled, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-1", ClientID: "the-client"},
+					{Status: services.StatusScheduled, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-2", ClientID: "the-client"},
+				}))
+			})
+
+			It("enqueues jobs that become active at the send_at time", func() {
+				users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+				enqueuer.Enqueue(conn, users, services.Options{SendAt: sendAt}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(2))
+				for _, job := range queue.EnqueueCall.Receives.Jobs {
+					Expect(job.ActiveAt).To(Equal(sendAt))
+				}
+			})
+		})
+
+		Context("when the options include a send_at time in the past", func() {
+			It("enqueues the jobs for immediate delivery", func() {
+				users := []services.User{{GUID: "user-1"}}
+				enqueuer.Enqueue(conn, users, services.Options{SendAt: reqReceived.Add(-time.Hour)}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(messagesRepo.UpsertCall.Receives.Messages).To(Equal([]models.Message{
+					{Status: services.StatusQueued, VCAPRequestID: "some-request-id", BatchID: "some-batch-id", UserGUID: "user-1", ClientID: "the-client"},
+				}))
+				Expect(queue.EnqueueCall.Receives.Jobs[0].ActiveAt).To(BeZero())
+			})
+		})
+
 		Context("using a transaction", func() {
 			var users []services.User
 
@@ -22,6 +22,22 @@ func (e CriticalKindError) Error() string {
 	return e.Err.Error()
 }
 
+type InvalidUnsubscribeTokenError struct {
+	Err error
+}
+
+func (e InvalidUnsubscribeTokenError) Error() string {
+	return e.Err.Error()
+}
+
+type MessageNotCancelableError struct {
+	Err error
+}
+
+func (e MessageNotCancelableError) Error() string {
+	return e.Err.Error()
+}
+
 type ClientMissingError struct {
 	Err error
 }
@@ -69,3 +85,11 @@ type DefaultScopeError struct{}
 func (d DefaultScopeError) Error() string {
 	return "You cannot send a notification to a default scope"
 }
+
+type TemplateRenderError struct {
+	Err error
+}
+
+func (e TemplateRenderError) Error() string {
+	return e.Err.Error()
+}
@@ -1,34 +1,26 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/cf"
-
 const EveryoneEndorsement = "This message was sent to everyone."
 
-type allUserGUIDsGetter interface {
-	AllUserGUIDs(token string) (userGUIDs []string, err error)
-}
-
 type loadsTokens interface {
 	Load(host string) (token string, err error)
 }
 
+type fanOutEnqueuer interface {
+	Enqueue(conn ConnectionInterface, fanOut FanOut) (BatchResponse, error)
+}
+
 type EveryoneStrategy struct {
-	tokenLoader loadsTokens
-	allUsers    allUserGUIDsGetter
-	enqueuer    enqueuer
+	fanOutEnqueuer fanOutEnqueuer
 }
 
-func NewEveryoneStrategy(tokenLoader loadsTokens, allUsers allUserGUIDsGetter, enqueuer enqueuer) EveryoneStrategy {
+func NewEveryoneStrategy(fanOutEnqueuer fanOutEnqueuer) EveryoneStrategy {
 	return EveryoneStrategy{
-		tokenLoader: tokenLoader,
-		allUsers:    allUsers,
-		enqueuer:    enqueuer,
+		fanOutEnqueuer: fanOutEnqueuer,
 	}
 }
 
-func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
-	var responses []Response
-
+func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) (BatchResponse, error) {
 	options := Options{
 		ReplyTo:           dispatch.Message.ReplyTo,
 		Subject:           dispatch.Message.Subject,
@@ -39,6 +31,9 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		Data:              dispatch.Message.Data,
+		SendAt:            dispatch.Message.SendAt,
+		Localizations:     dispatch.Message.Localizations,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -47,31 +42,12 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		},
 	}
 
-	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
-	if err != nil {
-		return responses, err
-	}
-
-	// split this up so that it only loads user guids
-	userGUIDs, err := strategy.allUsers.AllUserGUIDs(token)
-	if err != nil {
-		return responses, err
-	}
-
-	var users []User
-	for _, guid := range userGUIDs {
-		users = append(users, User{GUID: guid})
-	}
-
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		cf.CloudControllerOrganization{},
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		"",
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return strategy.fanOutEnqueuer.Enqueue(dispatch.Connection, FanOut{
+		Audience:        AudienceEveryone,
+		Options:         options,
+		ClientID:        dispatch.Client.ID,
+		UAAHost:         dispatch.UAAHost,
+		VCAPRequestID:   dispatch.VCAPRequest.ID,
+		RequestReceived: dispatch.VCAPRequest.ReceiptTime,
+	})
 }
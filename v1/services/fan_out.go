@@ -0,0 +1,101 @@
+package services
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+const FanOutJobType = "fanout"
+
+const (
+	AudienceUser         = "user"
+	AudienceSpace        = "space"
+	AudienceEmail        = "email"
+	AudienceEveryone     = "everyone"
+	AudienceOrganization = "organization"
+	AudienceScope        = "scope"
+)
+
+type FanOut struct {
+	JobType         string
+	BatchID         string
+	Audience        string
+	GUID            string
+	Organization    cf.CloudControllerOrganization
+	Options         Options
+	ClientID        string
+	UAAHost         string
+	VCAPRequestID   string
+	RequestReceived time.Time
+}
+
+type BatchResponse struct {
+	BatchID       string `json:"batch_id"`
+	Status        string `json:"status"`
+	VCAPRequestID string `json:"vcap_request_id"`
+}
+
+type batchesRepoCreator interface {
+	Create(models.ConnectionInterface, models.Batch) (models.Batch, error)
+}
+
+type FanOutEnqueuer struct {
+	queue             queueInterface
+	batchesRepo       batchesRepoCreator
+	gobbleInitializer gobbleInitializer
+}
+
+func NewFanOutEnqueuer(queue queueInterface, batchesRepo batchesRepoCreator, gobbleInitializer gobbleInitializer) FanOutEnqueuer {
+	return FanOutEnqueuer{
+		queue:             queue,
+		batchesRepo:       batchesRepo,
+		gobbleInitializer: gobbleInitializer,
+	}
+}
+
+func (enqueuer FanOutEnqueuer) Enqueue(conn ConnectionInterface, fanOut FanOut) (BatchResponse, error) {
+	transaction := conn.Transaction()
+	enqueuer.gobbleInitializer.InitializeDBMap(transaction.GetDbMap())
+
+	if err := transaction.Begin(); err != nil {
+		return BatchResponse{}, err
+	}
+
+	batch, err := enqueuer.batchesRepo.Create(transaction, models.Batch{
+		ClientID:      fanOut.ClientID,
+		KindID:        fanOut.Options.KindID,
+		VCAPRequestID: fanOut.VCAPRequestID,
+		Audience:      fanOut.Audience,
+		GUID:          fanOut.GUID,
+		Status:        BatchStatusPending,
+	})
+	if err != nil {
+		transaction.Rollback()
+		return BatchResponse{}, err
+	}
+
+	fanOut.JobType = FanOutJobType
+	fanOut.BatchID = batch.ID
+
+	job := gobble.NewJob(fanOut)
+	job.Key = batch.ID
+
+	_, err = enqueuer.queue.Enqueue(job, transaction)
+	if err != nil {
+		transaction.Rollback()
+		return BatchResponse{}, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return BatchResponse{}, err
+	}
+
+	return BatchResponse{
+		BatchID:       batch.ID,
+		Status:        batch.Status,
+		VCAPRequestID: fanOut.VCAPRequestID,
+	}, nil
+}
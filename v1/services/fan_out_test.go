@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FanOutEnqueuer", func() {
+	var (
+		enqueuer          services.FanOutEnqueuer
+		queue             *mocks.Queue
+		batchesRepo       *mocks.BatchesRepo
+		gobbleInitializer *mocks.GobbleInitializer
+		conn              *mocks.Connection
+		transaction       *mocks.Transaction
+		fanOut            services.FanOut
+	)
+
+	BeforeEach(func() {
+		queue = mocks.NewQueue()
+
+		transaction = mocks.NewTransaction()
+		conn = mocks.NewConnection()
+		conn.TransactionCall.Returns.Transaction = transaction
+		transaction.Connection = conn
+
+		gobbleInitializer = mocks.NewGobbleInitializer()
+
+		batchesRepo = mocks.NewBatchesRepo()
+		batchesRepo.CreateCall.Returns.Batch = models.Batch{
+			ID:     "some-batch-id",
+			Status: services.BatchStatusPending,
+		}
+
+		requestReceived, _ := time.Parse(time.RFC3339Nano, "2015-06-08T14:40:12.207187819-07:00")
+		fanOut = services.FanOut{
+			Audience:        services.AudienceOrganization,
+			GUID:            "some-org-guid",
+			Organization:    cf.CloudControllerOrganization{GUID: "some-org-guid", Name: "some-org"},
+			Options:         services.Options{KindID: "some-kind"},
+			ClientID:        "some-client",
+			UAAHost:         "some-uaa-host",
+			VCAPRequestID:   "some-request-id",
+			RequestReceived: requestReceived,
+		}
+
+		enqueuer = services.NewFanOutEnqueuer(queue, batchesRepo, gobbleInitializer)
+	})
+
+	Describe("Enqueue", func() {
+		It("creates a pending batch", func() {
+			response, err := enqueuer.Enqueue(conn, fanOut)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(services.BatchResponse{
+				BatchID:       "some-batch-id",
+				Status:        "pending",
+				VCAPRequestID: "some-request-id",
+			}))
+
+			Expect(batchesRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(batchesRepo.CreateCall.Receives.Batch).To(Equal(models.Batch{
+				ClientID:      "some-client",
+				KindID:        "some-kind",
+				VCAPRequestID: "some-request-id",
+				Audience:      "organization",
+				GUID:          "some-org-guid",
+				Status:        "pending",
+			}))
+		})
+
+		It("enqueues a fan-out job keyed by the batch ID", func() {
+			_, err := enqueuer.Enqueue(conn, fanOut)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(1))
+			Expect(queue.EnqueueCall.Receives.Connection).To(Equal(transaction))
+
+			job := queue.EnqueueCall.Receives.Jobs[0]
+			Expect(job.Key).To(Equal("some-batch-id"))
+
+			var enqueued services.FanOut
+			Expect(job.Unmarshal(&enqueued)).To(Succeed())
+
+			fanOut.JobType = services.FanOutJobType
+			fanOut.BatchID = "some-batch-id"
+			Expect(enqueued).To(Equal(fanOut))
+		})
+
+		It("initializes the DbMap and commits the transaction", func() {
+			_, err := enqueuer.Enqueue(conn, fanOut)
+			Expect(err).NotTo(HaveOccurred())
+
+			isSamePtr := (gobbleInitializer.InitializeDBMapCall.Receives.DbMap == transaction.GetDbMapCall.Returns.DbMap)
+			Expect(isSamePtr).To(BeTrue())
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
+		Context("when the batch cannot be created", func() {
+			It("rolls back the transaction and returns the error", func() {
+				batchesRepo.CreateCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := enqueuer.Enqueue(conn, fanOut)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+
+				Expect(queue.EnqueueCall.Receives.Jobs).To(BeEmpty())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when the job cannot be enqueued", func() {
+			It("rolls back the transaction and returns the error", func() {
+				queue.EnqueueCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := enqueuer.Enqueue(conn, fanOut)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when the transaction cannot be committed", func() {
+			It("returns the error", func() {
+				transaction.CommitCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := enqueuer.Enqueue(conn, fanOut)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+		})
+	})
+})
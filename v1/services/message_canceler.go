@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type messagesRepoCanceler interface {
+	FindByID(models.ConnectionInterface, string) (models.Message, error)
+	FindAllByVCAPRequestID(models.ConnectionInterface, string) ([]models.Message, error)
+	Update(models.ConnectionInterface, models.Message) (models.Message, error)
+}
+
+type jobDequeuer interface {
+	DequeueByKey(key string) (bool, error)
+}
+
+type webhookNotifier interface {
+	Notify(conn db.ConnectionInterface, message models.Message) error
+}
+
+type MessageCanceler struct {
+	repo            messagesRepoCanceler
+	queue           jobDequeuer
+	webhookNotifier webhookNotifier
+}
+
+func NewMessageCanceler(repo messagesRepoCanceler, queue jobDequeuer, webhookNotifier webhookNotifier) MessageCanceler {
+	return MessageCanceler{
+		repo:            repo,
+		queue:           queue,
+		webhookNotifier: webhookNotifier,
+	}
+}
+
+func (canceler MessageCanceler) Cancel(database DatabaseInterface, messageID string) error {
+	conn := database.Connection()
+
+	message, err := canceler.repo.FindByID(conn, messageID)
+	if err != nil {
+		return err
+	}
+
+	return canceler.cancel(conn, message)
+}
+
+func (canceler MessageCanceler) CancelByVCAPRequestID(database DatabaseInterface, vcapRequestID string) ([]Message, error) {
+	conn := database.Connection()
+
+	messages, err := canceler.repo.FindAllByVCAPRequestID(conn, vcapRequestID)
+	if err != nil {
+		return []Message{}, err
+	}
+
+	if len(messages) == 0 {
+		return []Message{}, models.NotFoundError{Err: fmt.Errorf("No messages for VCAP request ID %q could be found", vcapRequestID)}
+	}
+
+	var results []Message
+	for _, message := range messages {
+		err := canceler.cancel(conn, message)
+		switch err.(type) {
+		case nil:
+			message.Status = StatusCanceled
+		case MessageNotCancelableError:
+		default:
+			return []Message{}, err
+		}
+
+		results = append(results, Message{
+			ID:     message.ID,
+			Status: message.Status,
+		})
+	}
+
+	return results, nil
+}
+
+func (canceler MessageCanceler) cancel(conn models.ConnectionInterface, message models.Message) error {
+	switch message.Status {
+	case StatusDelivered, StatusUndeliverable, StatusCanceled:
+		return MessageNotCancelableError{Err: fmt.Errorf("Message with ID %q is %s and cannot be canceled", message.ID, message.Status)}
+	}
+
+	dequeued, err := canceler.queue.DequeueByKey(message.ID)
+	if err != nil {
+		return err
+	}
+
+	if !dequeued {
+		return MessageNotCancelableError{Err: fmt.Errorf("Message with ID %q is being delivered and cannot be canceled", message.ID)}
+	}
+
+	message.Status = StatusCanceled
+	message.Reason = ""
+
+	message, err = canceler.repo.Update(conn, message)
+	if err != nil {
+		return err
+	}
+
+	return canceler.webhookNotifier.Notify(conn, message)
+}
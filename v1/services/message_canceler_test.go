@@ -0,0 +1,194 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessageCanceler", func() {
+	var (
+		canceler     services.MessageCanceler
+		messagesRepo *mocks.MessagesRepo
+		queue        *mocks.Queue
+		notifier     *mocks.WebhookNotifier
+		database     *mocks.Database
+		conn         *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		messagesRepo = mocks.NewMessagesRepo()
+		queue = mocks.NewQueue()
+		queue.DequeueByKeyCall.Returns.Dequeued = true
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		notifier = mocks.NewWebhookNotifier()
+
+		canceler = services.NewMessageCanceler(messagesRepo, queue, notifier)
+	})
+
+	Describe("Cancel", func() {
+		BeforeEach(func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:            "some-message-id",
+				Status:        services.StatusScheduled,
+				VCAPRequestID: "some-request-id",
+			}
+		})
+
+		It("removes the job and marks the message as canceled", func() {
+			err := canceler.Cancel(database, "some-message-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(queue.DequeueByKeyCall.Receives.Keys).To(Equal([]string{"some-message-id"}))
+
+			Expect(messagesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.UpdateCall.Receives.Messages).To(Equal([]models.Message{
+				{
+					ID:            "some-message-id",
+					Status:        services.StatusCanceled,
+					VCAPRequestID: "some-request-id",
+				},
+			}))
+		})
+
+		It("notifies the client's webhooks that the message was canceled", func() {
+			messagesRepo.UpdateCall.Returns.Message = models.Message{
+				ID:      "some-message-id",
+				Status:  services.StatusCanceled,
+				BatchID: "some-batch-id",
+			}
+
+			err := canceler.Cancel(database, "some-message-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(notifier.NotifyCall.Receives.Connection).To(Equal(conn))
+			Expect(notifier.NotifyCall.Receives.Message).To(Equal(models.Message{
+				ID:      "some-message-id",
+				Status:  services.StatusCanceled,
+				BatchID: "some-batch-id",
+			}))
+		})
+
+		It("cancels messages that are waiting to be retried", func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:     "some-message-id",
+				Status: "failed",
+				Reason: "connection refused",
+			}
+
+			err := canceler.Cancel(database, "some-message-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.UpdateCall.Receives.Messages).To(Equal([]models.Message{
+				{
+					ID:     "some-message-id",
+					Status: services.StatusCanceled,
+				},
+			}))
+		})
+
+		It("does not cancel messages that have already been delivered", func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:     "some-message-id",
+				Status: services.StatusDelivered,
+			}
+
+			err := canceler.Cancel(database, "some-message-id")
+			Expect(err).To(MatchError(services.MessageNotCancelableError{Err: errors.New(`Message with ID "some-message-id" is delivered and cannot be canceled`)}))
+
+			Expect(queue.DequeueByKeyCall.Receives.Keys).To(BeEmpty())
+			Expect(messagesRepo.UpdateCall.Receives.Messages).To(BeEmpty())
+		})
+
+		It("does not cancel messages whose job is reserved by a worker", func() {
+			queue.DequeueByKeyCall.Returns.Dequeued = false
+
+			err := canceler.Cancel(database, "some-message-id")
+			Expect(err).To(MatchError(services.MessageNotCancelableError{Err: errors.New(`Message with ID "some-message-id" is being delivered and cannot be canceled`)}))
+
+			Expect(messagesRepo.UpdateCall.Receives.Messages).To(BeEmpty())
+		})
+
+		Context("when an error occurs", func() {
+			It("returns errors from finding the message", func() {
+				messagesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				err := canceler.Cancel(database, "some-message-id")
+				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			})
+
+			It("returns errors from the queue", func() {
+				queue.DequeueByKeyCall.Returns.Error = errors.New("queue is down")
+
+				err := canceler.Cancel(database, "some-message-id")
+				Expect(err).To(MatchError("queue is down"))
+				Expect(messagesRepo.UpdateCall.Receives.Messages).To(BeEmpty())
+			})
+
+			It("returns errors from updating the message", func() {
+				messagesRepo.UpdateCall.Returns.Error = errors.New("update failed")
+
+				err := canceler.Cancel(database, "some-message-id")
+				Expect(err).To(MatchError("update failed"))
+				Expect(notifier.NotifyCall.CallCount).To(Equal(0))
+			})
+
+			It("returns errors from notifying the webhooks", func() {
+				notifier.NotifyCall.Returns.Error = errors.New("queue is down")
+
+				err := canceler.Cancel(database, "some-message-id")
+				Expect(err).To(MatchError("queue is down"))
+			})
+		})
+	})
+
+	Describe("CancelByVCAPRequestID", func() {
+		BeforeEach(func() {
+			messagesRepo.FindAllByVCAPRequestIDCall.Returns.Messages = []models.Message{
+				{ID: "first-message-id", Status: services.StatusQueued, VCAPRequestID: "some-request-id"},
+				{ID: "second-message-id", Status: services.StatusDelivered, VCAPRequestID: "some-request-id"},
+				{ID: "third-message-id", Status: services.StatusScheduled, VCAPRequestID: "some-request-id"},
+			}
+		})
+
+		It("cancels every message that can be canceled and reports the resulting statuses", func() {
+			messages, err := canceler.CancelByVCAPRequestID(database, "some-request-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.FindAllByVCAPRequestIDCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.FindAllByVCAPRequestIDCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+			Expect(queue.DequeueByKeyCall.Receives.Keys).To(Equal([]string{"first-message-id", "third-message-id"}))
+
+			Expect(messages).To(Equal([]services.Message{
+				{ID: "first-message-id", Status: services.StatusCanceled},
+				{ID: "second-message-id", Status: services.StatusDelivered},
+				{ID: "third-message-id", Status: services.StatusCanceled},
+			}))
+		})
+
+		It("returns a not found error when there are no messages for the request", func() {
+			messagesRepo.FindAllByVCAPRequestIDCall.Returns.Messages = []models.Message{}
+
+			_, err := canceler.CancelByVCAPRequestID(database, "missing-request-id")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+
+		It("returns unexpected errors", func() {
+			queue.DequeueByKeyCall.Returns.Error = errors.New("queue is down")
+
+			_, err := canceler.CancelByVCAPRequestID(database, "some-request-id")
+			Expect(err).To(MatchError("queue is down"))
+		})
+	})
+})
@@ -1,13 +1,43 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/v1/models"
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 type Message struct {
-	Status string
+	ID            string
+	Status        string
+	Reason        string
+	VCAPRequestID string
+	BatchID       string
+	UserGUID      string
+	Email         string
+	ClientID      string
+	KindID        string
+	Attempts      int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeliveredAt   time.Time
+}
+
+type MessageFilter struct {
+	UserGUID      string
+	Email         string
+	ClientID      string
+	KindID        string
+	VCAPRequestID string
+	Status        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Offset        int
+	Limit         int
 }
 
 type messagesRepoFinder interface {
 	FindByID(models.ConnectionInterface, string) (models.Message, error)
+	Search(models.ConnectionInterface, models.MessageQuery) ([]models.Message, error)
 }
 
 type MessageFinder struct {
@@ -26,5 +56,53 @@ func (finder MessageFinder) Find(database DatabaseInterface, messageID string) (
 		return Message{}, err
 	}
 
-	return Message{Status: message.Status}, nil
+	return newMessage(message), nil
+}
+
+func (finder MessageFinder) List(database DatabaseInterface, filter MessageFilter) ([]Message, error) {
+	messages, err := finder.repo.Search(database.Connection(), models.MessageQuery{
+		UserGUID:      filter.UserGUID,
+		Email:         filter.Email,
+		ClientID:      filter.ClientID,
+		KindID:        filter.KindID,
+		VCAPRequestID: filter.VCAPRequestID,
+		Status:        filter.Status,
+		CreatedAfter:  filter.CreatedAfter,
+		CreatedBefore: filter.CreatedBefore,
+		Offset:        filter.Offset,
+		Limit:         filter.Limit,
+	})
+	if err != nil {
+		return []Message{}, err
+	}
+
+	results := []Message{}
+	for _, message := range messages {
+		results = append(results, newMessage(message))
+	}
+
+	return results, nil
+}
+
+func newMessage(message models.Message) Message {
+	var deliveredAt time.Time
+	if message.DeliveredAt != nil {
+		deliveredAt = *message.DeliveredAt
+	}
+
+	return Message{
+		ID:            message.ID,
+		Status:        message.Status,
+		Reason:        message.Reason,
+		VCAPRequestID: message.VCAPRequestID,
+		BatchID:       message.BatchID,
+		UserGUID:      message.UserGUID,
+		Email:         message.Email,
+		ClientID:      message.ClientID,
+		KindID:        message.KindID,
+		Attempts:      message.Attempts,
+		CreatedAt:     message.CreatedAt,
+		UpdatedAt:     message.UpdatedAt,
+		DeliveredAt:   deliveredAt,
+	}
 }
@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
@@ -12,7 +13,7 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("MessageFinder.Find", func() {
+var _ = Describe("MessageFinder", func() {
 	var (
 		finder       services.MessageFinder
 		messagesRepo *mocks.MessagesRepo
@@ -31,12 +32,14 @@ var _ = Describe("MessageFinder.Find", func() {
 
 	Context("when a message exists with the given id", func() {
 		It("returns the right Message struct", func() {
-			messagesRepo.FindByIDCall.Returns.Message = models.Message{Status: common.StatusDelivered}
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{ID: "a-message-id", Status: common.StatusDelivered, Reason: "some-reason"}
 
 			message, err := finder.Find(database, "a-message-id")
 
 			Expect(err).NotTo(HaveOccurred())
+			Expect(message.ID).To(Equal("a-message-id"))
 			Expect(message.Status).To(Equal(common.StatusDelivered))
+			Expect(message.Reason).To(Equal("some-reason"))
 
 			Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
 			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("a-message-id"))
@@ -51,4 +54,82 @@ var _ = Describe("MessageFinder.Find", func() {
 			Expect(err).To(MatchError(errors.New("some error")))
 		})
 	})
+
+	Describe("List", func() {
+		It("searches the messages matching the filter", func() {
+			createdAt := time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC)
+			deliveredAt := createdAt.Add(time.Minute)
+			messagesRepo.SearchCall.Returns.Messages = []models.Message{
+				{
+					ID:            "first-message-id",
+					Status:        common.StatusDelivered,
+					VCAPRequestID: "some-request-id",
+					BatchID:       "some-batch-id",
+					UserGUID:      "some-user",
+					Email:         "user@example.com",
+					ClientID:      "some-client",
+					KindID:        "some-kind",
+					Attempts:      2,
+					CreatedAt:     createdAt,
+					UpdatedAt:     deliveredAt,
+					DeliveredAt:   &deliveredAt,
+				},
+				{
+					ID:        "second-message-id",
+					Status:    common.StatusQueued,
+					UserGUID:  "some-user",
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				},
+			}
+
+			messages, err := finder.List(database, services.MessageFilter{
+				UserGUID:     "some-user",
+				Status:       "delivered",
+				CreatedAfter: createdAt,
+				Offset:       10,
+				Limit:        5,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(Equal([]services.Message{
+				{
+					ID:            "first-message-id",
+					Status:        common.StatusDelivered,
+					VCAPRequestID: "some-request-id",
+					BatchID:       "some-batch-id",
+					UserGUID:      "some-user",
+					Email:         "user@example.com",
+					ClientID:      "some-client",
+					KindID:        "some-kind",
+					Attempts:      2,
+					CreatedAt:     createdAt,
+					UpdatedAt:     deliveredAt,
+					DeliveredAt:   deliveredAt,
+				},
+				{
+					ID:        "second-message-id",
+					Status:    common.StatusQueued,
+					UserGUID:  "some-user",
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				},
+			}))
+
+			Expect(messagesRepo.SearchCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.SearchCall.Receives.Query).To(Equal(models.MessageQuery{
+				UserGUID:     "some-user",
+				Status:       "delivered",
+				CreatedAfter: createdAt,
+				Offset:       10,
+				Limit:        5,
+			}))
+		})
+
+		It("bubbles up errors from the repo", func() {
+			messagesRepo.SearchCall.Returns.Error = errors.New("some error")
+
+			_, err := finder.List(database, services.MessageFilter{})
+			Expect(err).To(MatchError(errors.New("some error")))
+		})
+	})
 })
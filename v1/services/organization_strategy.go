@@ -7,10 +7,6 @@ const (
 	OrganizationRoleEndorsement = `You received this message because you are an {{.OrganizationRole}} in the "{{.Organization}}" organization.`
 )
 
-type orgUserIDFinder interface {
-	UserIDsBelongingToOrganization(orgGUID, role, token string) (userIDs []string, err error)
-}
-
 type loadsOrganizations interface {
 	Load(orgGUID, token string) (cf.CloudControllerOrganization, error)
 }
@@ -18,21 +14,18 @@ type loadsOrganizations interface {
 type OrganizationStrategy struct {
 	tokenLoader        loadsTokens
 	organizationLoader loadsOrganizations
-	findsUserIDs       orgUserIDFinder
-	enqueuer           enqueuer
+	fanOutEnqueuer     fanOutEnqueuer
 }
 
-func NewOrganizationStrategy(tokenLoader loadsTokens, organizationLoader loadsOrganizations, findsUserIDs orgUserIDFinder, queue enqueuer) OrganizationStrategy {
+func NewOrganizationStrategy(tokenLoader loadsTokens, organizationLoader loadsOrganizations, fanOutEnqueuer fanOutEnqueuer) OrganizationStrategy {
 	return OrganizationStrategy{
 		tokenLoader:        tokenLoader,
 		organizationLoader: organizationLoader,
-		findsUserIDs:       findsUserIDs,
-		enqueuer:           queue,
+		fanOutEnqueuer:     fanOutEnqueuer,
 	}
 }
 
-func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
-	responses := []Response{}
+func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) (BatchResponse, error) {
 	options := Options{
 		To:                dispatch.Message.To,
 		ReplyTo:           dispatch.Message.ReplyTo,
@@ -43,6 +36,9 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 		Endorsement:       OrganizationEndorsement,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		Data:              dispatch.Message.Data,
+		SendAt:            dispatch.Message.SendAt,
+		Localizations:     dispatch.Message.Localizations,
 		Role:              dispatch.Role,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
@@ -58,33 +54,22 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
 	if err != nil {
-		return responses, err
+		return BatchResponse{}, err
 	}
 
 	organization, err := strategy.organizationLoader.Load(dispatch.GUID, token)
 	if err != nil {
-		return responses, err
-	}
-
-	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToOrganization(dispatch.GUID, options.Role, token)
-	if err != nil {
-		return responses, err
-	}
-
-	var users []User
-	for _, guid := range userGUIDs {
-		users = append(users, User{GUID: guid})
+		return BatchResponse{}, err
 	}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		organization,
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		"",
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return strategy.fanOutEnqueuer.Enqueue(dispatch.Connection, FanOut{
+		Audience:        AudienceOrganization,
+		GUID:            dispatch.GUID,
+		Organization:    organization,
+		Options:         options,
+		ClientID:        dispatch.Client.ID,
+		UAAHost:         dispatch.UAAHost,
+		VCAPRequestID:   dispatch.VCAPRequest.ID,
+		RequestReceived: dispatch.VCAPRequest.ReceiptTime,
+	})
 }
@@ -18,9 +18,8 @@ var _ = Describe("Organization Strategy", func() {
 		strategy           services.OrganizationStrategy
 		tokenLoader        *mocks.TokenLoader
 		organizationLoader *mocks.OrganizationLoader
-		enqueuer           *mocks.Enqueuer
+		fanOutEnqueuer     *mocks.FanOutEnqueuer
 		conn               *mocks.Connection
-		findsUserIDs       *mocks.FindsUserIDs
 		requestReceived    time.Time
 		token              string
 	)
@@ -40,10 +39,13 @@ var _ = Describe("Organization Strategy", func() {
 		tokenLoader = mocks.NewTokenLoader()
 		token = helpers.BuildToken(tokenHeader, tokenClaims)
 		tokenLoader.LoadCall.Returns.Token = token
-		enqueuer = mocks.NewEnqueuer()
 
-		findsUserIDs = mocks.NewFindsUserIDs()
-		findsUserIDs.UserIDsBelongingToOrganizationCall.Returns.UserIDs = []string{"user-123", "user-456"}
+		fanOutEnqueuer = mocks.NewFanOutEnqueuer()
+		fanOutEnqueuer.EnqueueCall.Returns.Response = services.BatchResponse{
+			BatchID:       "some-batch-id",
+			Status:        "pending",
+			VCAPRequestID: "some-vcap-request-id",
+		}
 
 		organizationLoader = mocks.NewOrganizationLoader()
 		organizationLoader.LoadCall.Returns.Organizations = []cf.CloudControllerOrganization{
@@ -52,146 +54,100 @@ var _ = Describe("Organization Strategy", func() {
 				GUID: "org-001",
 			},
 		}
-		strategy = services.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserIDs, enqueuer)
+		strategy = services.NewOrganizationStrategy(tokenLoader, organizationLoader, fanOutEnqueuer)
 	})
 
 	Describe("Dispatch", func() {
-		Context("when the dispatch JobType is unspecified", func() {
-			Context("when the request is valid", func() {
-				It("call enqueuer.Enqueue with the correct arguments for an organization", func() {
-					_, err := strategy.Dispatch(services.Dispatch{
-						GUID:       "org-001",
-						Connection: conn,
-						Message: services.DispatchMessage{
-							To:      "dr@strangelove.com",
-							ReplyTo: "reply-to@example.com",
-							Subject: "this is the subject",
-							Text:    "Please reset your password by clicking on this link...",
-							HTML: services.HTML{
-								BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
-								BodyAttributes: "some-html-body-attributes",
-								Head:           "<head></head>",
-								Doctype:        "<html>",
-							},
-						},
-						Kind: services.DispatchKind{
-							ID:          "forgot_password",
-							Description: "Password reminder",
-						},
-						TemplateID: "some-template-id",
-						Client: services.DispatchClient{
-							ID:          "mister-client",
-							Description: "Login system",
-						},
-						VCAPRequest: services.DispatchVCAPRequest{
-							ID:          "some-vcap-request-id",
-							ReceiptTime: requestReceived,
-						},
-						UAAHost: "testzone1",
-					})
-					Expect(err).NotTo(HaveOccurred())
-
-					users := []services.User{
-						{GUID: "user-123"},
-						{GUID: "user-456"},
-					}
+		var dispatch services.Dispatch
+
+		BeforeEach(func() {
+			dispatch = services.Dispatch{
+				GUID:       "org-001",
+				Connection: conn,
+				Message: services.DispatchMessage{
+					To:      "dr@strangelove.com",
+					ReplyTo: "reply-to@example.com",
+					Subject: "this is the subject",
+					Text:    "Please reset your password by clicking on this link...",
+					HTML: services.HTML{
+						BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
+						BodyAttributes: "some-html-body-attributes",
+						Head:           "<head></head>",
+						Doctype:        "<html>",
+					},
+				},
+				Kind: services.DispatchKind{
+					ID:          "forgot_password",
+					Description: "Password reminder",
+				},
+				TemplateID: "some-template-id",
+				Client: services.DispatchClient{
+					ID:          "mister-client",
+					Description: "Login system",
+				},
+				VCAPRequest: services.DispatchVCAPRequest{
+					ID:          "some-vcap-request-id",
+					ReceiptTime: requestReceived,
+				},
+				UAAHost: "testzone1",
+			}
+		})
 
-					Expect(organizationLoader.LoadCall.Receives.OrganizationGUID).To(Equal("org-001"))
-					Expect(organizationLoader.LoadCall.Receives.Token).To(Equal(tokenLoader.LoadCall.Returns.Token))
-
-					Expect(enqueuer.EnqueueCall.Receives.Connection).To(Equal(conn))
-					Expect(enqueuer.EnqueueCall.Receives.Users).To(Equal(users))
-					Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
-						ReplyTo:           "reply-to@example.com",
-						Subject:           "this is the subject",
-						To:                "dr@strangelove.com",
-						KindID:            "forgot_password",
-						KindDescription:   "Password reminder",
-						SourceDescription: "Login system",
-						Text:              "Please reset your password by clicking on this link...",
-						TemplateID:        "some-template-id",
-						HTML: services.HTML{
-							BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
-							BodyAttributes: "some-html-body-attributes",
-							Head:           "<head></head>",
-							Doctype:        "<html>",
-						},
-						Endorsement: services.OrganizationEndorsement,
-					}))
-					Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
-					Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{
-						Name: "my-org",
-						GUID: "org-001",
-					}))
-					Expect(enqueuer.EnqueueCall.Receives.Client).To(Equal("mister-client"))
-					Expect(enqueuer.EnqueueCall.Receives.Scope).To(Equal(""))
-					Expect(enqueuer.EnqueueCall.Receives.VCAPRequestID).To(Equal("some-vcap-request-id"))
-					Expect(enqueuer.EnqueueCall.Receives.RequestReceived).To(Equal(requestReceived))
-					Expect(enqueuer.EnqueueCall.Receives.UAAHost).To(Equal("testzone1"))
-
-					Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("testzone1"))
-
-					Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.OrgGUID).To(Equal("org-001"))
-					Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.Role).To(Equal(""))
-					Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.Token).To(Equal(token))
-				})
+		It("enqueues a fan-out to the organization", func() {
+			response, err := strategy.Dispatch(dispatch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(services.BatchResponse{
+				BatchID:       "some-batch-id",
+				Status:        "pending",
+				VCAPRequestID: "some-vcap-request-id",
+			}))
+
+			Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("testzone1"))
+			Expect(organizationLoader.LoadCall.Receives.OrganizationGUID).To(Equal("org-001"))
+			Expect(organizationLoader.LoadCall.Receives.Token).To(Equal(token))
+
+			Expect(fanOutEnqueuer.EnqueueCall.Receives.Connection).To(Equal(conn))
+			Expect(fanOutEnqueuer.EnqueueCall.Receives.FanOut).To(Equal(services.FanOut{
+				Audience: services.AudienceOrganization,
+				GUID:     "org-001",
+				Organization: cf.CloudControllerOrganization{
+					Name: "my-org",
+					GUID: "org-001",
+				},
+				Options: services.Options{
+					ReplyTo:           "reply-to@example.com",
+					Subject:           "this is the subject",
+					To:                "dr@strangelove.com",
+					KindID:            "forgot_password",
+					KindDescription:   "Password reminder",
+					SourceDescription: "Login system",
+					Text:              "Please reset your password by clicking on this link...",
+					TemplateID:        "some-template-id",
+					HTML: services.HTML{
+						BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
+						BodyAttributes: "some-html-body-attributes",
+						Head:           "<head></head>",
+						Doctype:        "<html>",
+					},
+					Endorsement: services.OrganizationEndorsement,
+				},
+				ClientID:        "mister-client",
+				UAAHost:         "testzone1",
+				VCAPRequestID:   "some-vcap-request-id",
+				RequestReceived: requestReceived,
+			}))
+		})
 
-				Context("when the org role field is set", func() {
-					It("calls enqueuer.Enqueue with the correct arguments", func() {
-						_, err := strategy.Dispatch(services.Dispatch{
-							GUID:       "org-001",
-							Role:       "OrgManager",
-							Connection: conn,
-							Message: services.DispatchMessage{
-								To:      "dr@strangelove.com",
-								ReplyTo: "reply-to@example.com",
-								Subject: "this is the subject",
-								Text:    "Please reset your password by clicking on this link...",
-								HTML: services.HTML{
-									BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
-									BodyAttributes: "some-html-body-attributes",
-									Head:           "<head></head>",
-									Doctype:        "<html>",
-								},
-							},
-							Kind: services.DispatchKind{
-								ID:          "forgot_password",
-								Description: "Password reminder",
-							},
-							Client: services.DispatchClient{
-								ID:          "mister-client",
-								Description: "Login system",
-							},
-							VCAPRequest: services.DispatchVCAPRequest{
-								ID:          "some-vcap-request-id",
-								ReceiptTime: requestReceived,
-							},
-						})
-						Expect(err).NotTo(HaveOccurred())
-
-						Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
-							ReplyTo:           "reply-to@example.com",
-							Subject:           "this is the subject",
-							To:                "dr@strangelove.com",
-							KindID:            "forgot_password",
-							KindDescription:   "Password reminder",
-							SourceDescription: "Login system",
-							Text:              "Please reset your password by clicking on this link...",
-							Role:              "OrgManager",
-							HTML: services.HTML{
-								BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
-								BodyAttributes: "some-html-body-attributes",
-								Head:           "<head></head>",
-								Doctype:        "<html>",
-							},
-							Endorsement: services.OrganizationRoleEndorsement,
-						}))
-
-						Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.OrgGUID).To(Equal("org-001"))
-						Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.Role).To(Equal("OrgManager"))
-						Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.Token).To(Equal(token))
-					})
-				})
+		Context("when the org role field is set", func() {
+			It("uses the role endorsement", func() {
+				dispatch.Role = "OrgManager"
+
+				_, err := strategy.Dispatch(dispatch)
+				Expect(err).NotTo(HaveOccurred())
+
+				fanOut := fanOutEnqueuer.EnqueueCall.Receives.FanOut
+				Expect(fanOut.Options.Role).To(Equal("OrgManager"))
+				Expect(fanOut.Options.Endorsement).To(Equal(services.OrganizationRoleEndorsement))
 			})
 		})
 
@@ -216,11 +172,11 @@ var _ = Describe("Organization Strategy", func() {
 				})
 			})
 
-			Context("when finds user IDs returns an error", func() {
-				It("returns an error", func() {
-					findsUserIDs.UserIDsBelongingToOrganizationCall.Returns.Error = errors.New("BOOM!")
+			Context("when the fan-out cannot be enqueued", func() {
+				It("returns the error", func() {
+					fanOutEnqueuer.EnqueueCall.Returns.Error = errors.New("BOOM!")
 
-					_, err := strategy.Dispatch(services.Dispatch{})
+					_, err := strategy.Dispatch(dispatch)
 					Expect(err).To(Equal(errors.New("BOOM!")))
 				})
 			})
@@ -10,22 +10,31 @@ type PreferenceUpdater struct {
 	globalUnsubscribesRepo GlobalUnsubscribesRepo
 	unsubscribesRepo       UnsubscribesRepo
 	kindsRepo              KindsRepo
+	userLocalesRepo        UserLocalesRepo
 }
 
-func NewPreferenceUpdater(globalUnsubscribesRepo GlobalUnsubscribesRepo, unsubscribesRepo UnsubscribesRepo, kindsRepo KindsRepo) PreferenceUpdater {
+func NewPreferenceUpdater(globalUnsubscribesRepo GlobalUnsubscribesRepo, unsubscribesRepo UnsubscribesRepo, kindsRepo KindsRepo, userLocalesRepo UserLocalesRepo) PreferenceUpdater {
 	return PreferenceUpdater{
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
 		unsubscribesRepo:       unsubscribesRepo,
 		kindsRepo:              kindsRepo,
+		userLocalesRepo:        userLocalesRepo,
 	}
 }
 
-func (updater PreferenceUpdater) Update(conn ConnectionInterface, preferences []models.Preference, globalUnsubscribe bool, userID string) error {
+func (updater PreferenceUpdater) Update(conn ConnectionInterface, preferences []models.Preference, globalUnsubscribe bool, locale *string, userID string) error {
 	err := updater.globalUnsubscribesRepo.Set(conn, userID, globalUnsubscribe)
 	if err != nil {
 		return err
 	}
 
+	if locale != nil {
+		err = updater.userLocalesRepo.Set(conn, userID, *locale)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, preference := range preferences {
 		kind, err := updater.kindsRepo.Find(conn, preference.KindID, preference.ClientID)
 		if err != nil {
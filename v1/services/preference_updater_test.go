@@ -17,6 +17,7 @@ var _ = Describe("PreferenceUpdater", func() {
 			unsubscribesRepo           *mocks.UnsubscribesRepo
 			kindsRepo                  *mocks.KindsRepo
 			fakeGlobalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
+			userLocalesRepo            *mocks.UserLocalesRepo
 			conn                       *mocks.Connection
 			updater                    services.PreferenceUpdater
 		)
@@ -26,15 +27,16 @@ var _ = Describe("PreferenceUpdater", func() {
 			unsubscribesRepo = mocks.NewUnsubscribesRepo()
 			kindsRepo = mocks.NewKindsRepo()
 			fakeGlobalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
-			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
+			userLocalesRepo = mocks.NewUserLocalesRepo()
+			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, kindsRepo, userLocalesRepo)
 		})
 
 		Context("when globally unsubscribing", func() {
 			It("inserts a record into the global unsubscribes repo", func() {
-				updater.Update(conn, []models.Preference{}, true, "user-guid")
+				updater.Update(conn, []models.Preference{}, true, nil, "user-guid")
 				Expect(fakeGlobalUnsubscribesRepo.SetCall.Receives.Unsubscribed).To(BeTrue())
 
-				updater.Update(conn, []models.Preference{}, false, "user-guid")
+				updater.Update(conn, []models.Preference{}, false, nil, "user-guid")
 				Expect(fakeGlobalUnsubscribesRepo.SetCall.Receives.Unsubscribed).To(BeFalse())
 			})
 
@@ -42,12 +44,39 @@ var _ = Describe("PreferenceUpdater", func() {
 				It("returns the error", func() {
 					fakeGlobalUnsubscribesRepo.SetCall.Returns.Error = errors.New("global unsubscribe db error")
 
-					err := updater.Update(conn, []models.Preference{}, true, "user-guid")
+					err := updater.Update(conn, []models.Preference{}, true, nil, "user-guid")
 					Expect(err).To(MatchError(errors.New("global unsubscribe db error")))
 				})
 			})
 		})
 
+		Context("when setting the locale", func() {
+			It("stores the locale of the user", func() {
+				locale := "fr-CA"
+				err := updater.Update(conn, []models.Preference{}, false, &locale, "user-guid")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(userLocalesRepo.SetCall.Receives.Connection).To(Equal(conn))
+				Expect(userLocalesRepo.SetCall.Receives.UserID).To(Equal("user-guid"))
+				Expect(userLocalesRepo.SetCall.Receives.Locale).To(Equal("fr-CA"))
+			})
+
+			It("leaves the locale alone when none is given", func() {
+				err := updater.Update(conn, []models.Preference{}, false, nil, "user-guid")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(userLocalesRepo.SetCall.WasCalled).To(BeFalse())
+			})
+
+			It("returns errors from the user locales repo", func() {
+				userLocalesRepo.SetCall.Returns.Error = errors.New("user locales db error")
+
+				locale := "fr-CA"
+				err := updater.Update(conn, []models.Preference{}, false, &locale, "user-guid")
+				Expect(err).To(MatchError(errors.New("user locales db error")))
+			})
+		})
+
 		Context("When unsubscribing from existing kinds of existing clients", func() {
 			BeforeEach(func() {
 
@@ -70,7 +99,7 @@ var _ = Describe("PreferenceUpdater", func() {
 						KindID:   "door-open",
 						Email:    false,
 					},
-				}, false, "the-user")
+				}, false, nil, "the-user")
 
 				Expect(unsubscribesRepo.SetCall.Receives.Connection).To(Equal(conn))
 				Expect(unsubscribesRepo.SetCall.Receives.UserID).To(Equal("the-user"))
@@ -86,7 +115,7 @@ var _ = Describe("PreferenceUpdater", func() {
 						KindID:   "barking",
 						Email:    true,
 					},
-				}, false, "the-user")
+				}, false, nil, "the-user")
 
 				unsubscribed, err := unsubscribesRepo.Get(conn, "the-user", "dogs", "barking")
 				Expect(err).NotTo(HaveOccurred())
@@ -103,7 +132,7 @@ var _ = Describe("PreferenceUpdater", func() {
 						KindID:   "door-open",
 						Email:    true,
 					},
-				}, false, "my-user")
+				}, false, nil, "my-user")
 				Expect(err).NotTo(HaveOccurred())
 
 				unsubscribed, err := unsubscribesRepo.Get(conn, "my-user", "raptors", "door-open")
@@ -140,7 +169,7 @@ var _ = Describe("PreferenceUpdater", func() {
 				}
 				kindsRepo.FindCall.Returns.Error = errors.New("something bad happened")
 
-				err := updater.Update(conn, preferences, false, "the-user")
+				err := updater.Update(conn, preferences, false, nil, "the-user")
 				Expect(err).To(MatchError(services.MissingKindOrClientError{Err: errors.New("The kind 'boo' cannot be found for client 'ghosts'")}))
 			})
 		})
@@ -168,7 +197,7 @@ var _ = Describe("PreferenceUpdater", func() {
 				}
 				kindsRepo.FindCall.Returns.Error = errors.New("something bad happened")
 
-				err := updater.Update(conn, preferences, false, "the-user")
+				err := updater.Update(conn, preferences, false, nil, "the-user")
 				Expect(err).To(Equal(services.MissingKindOrClientError{Err: errors.New("The kind 'dead' cannot be found for client 'raptors'")}))
 			})
 		})
@@ -200,7 +229,7 @@ var _ = Describe("PreferenceUpdater", func() {
 					},
 				}
 
-				err := updater.Update(conn, preferences, false, "the-user")
+				err := updater.Update(conn, preferences, false, nil, "the-user")
 				Expect(err).To(Equal(services.CriticalKindError{Err: errors.New("The kind 'hungry' for the 'raptors' client is critical and cannot be unsubscribed from")}))
 			})
 		})
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
@@ -17,6 +18,7 @@ type ClientsMap map[string]ClientMap
 
 type PreferencesBuilder struct {
 	GlobalUnsubscribe bool       `json:"global_unsubscribe"`
+	Locale            *string    `json:"locale,omitempty"`
 	Clients           ClientsMap `json:"clients"`
 }
 
@@ -73,3 +75,18 @@ func (pref PreferencesBuilder) ToPreferences() ([]models.Preference, error) {
 
 	return preferences, nil
 }
+
+// ToLocale returns the canonical form of the locale, nil when the locale was
+// left out and should not change, or an empty string to clear it.
+func (pref PreferencesBuilder) ToLocale() (*string, error) {
+	if pref.Locale == nil || *pref.Locale == "" {
+		return pref.Locale, nil
+	}
+
+	locale, err := common.CanonicalLocale(*pref.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &locale, nil
+}
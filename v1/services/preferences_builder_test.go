@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 
@@ -215,4 +216,33 @@ var _ = Describe("NotificationsPreferences", func() {
 			})
 		})
 	})
+
+	Describe("ToLocale", func() {
+		BeforeEach(func() {
+			builder = services.NewPreferencesBuilder()
+		})
+
+		It("returns the canonical form of the locale", func() {
+			locale := "fr_ca"
+			builder.Locale = &locale
+
+			result, err := builder.ToLocale()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*result).To(Equal("fr-CA"))
+		})
+
+		It("returns nil when the locale was left out", func() {
+			result, err := builder.ToLocale()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeNil())
+		})
+
+		It("returns an error when the locale is not a valid language tag", func() {
+			locale := "not a locale"
+			builder.Locale = &locale
+
+			_, err := builder.ToLocale()
+			Expect(err).To(MatchError(common.InvalidLocaleError{Locale: "not a locale"}))
+		})
+	})
 })
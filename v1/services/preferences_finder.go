@@ -3,12 +3,14 @@ package services
 type PreferencesFinder struct {
 	preferencesRepo        PreferencesRepo
 	globalUnsubscribesRepo GlobalUnsubscribesRepo
+	userLocalesRepo        UserLocalesRepo
 }
 
-func NewPreferencesFinder(preferencesRepo PreferencesRepo, globalUnsubscribesRepo GlobalUnsubscribesRepo) *PreferencesFinder {
+func NewPreferencesFinder(preferencesRepo PreferencesRepo, globalUnsubscribesRepo GlobalUnsubscribesRepo, userLocalesRepo UserLocalesRepo) *PreferencesFinder {
 	return &PreferencesFinder{
 		preferencesRepo:        preferencesRepo,
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
+		userLocalesRepo:        userLocalesRepo,
 	}
 }
 
@@ -21,12 +23,18 @@ func (finder PreferencesFinder) Find(database DatabaseInterface, userGUID string
 		return builder, err
 	}
 
+	locale, err := finder.userLocalesRepo.Get(conn, userGUID)
+	if err != nil {
+		return builder, err
+	}
+
 	preferences, err := finder.preferencesRepo.FindNonCriticalPreferences(conn, userGUID)
 	if err != nil {
 		return builder, err
 	}
 
 	builder.GlobalUnsubscribe = globallyUnsubscribed
+	builder.Locale = &locale
 	for _, preference := range preferences {
 		builder.Add(preference)
 	}
@@ -15,6 +15,7 @@ var _ = Describe("PreferencesFinder", func() {
 	var (
 		finder          *services.PreferencesFinder
 		preferencesRepo *mocks.PreferencesRepo
+		userLocalesRepo *mocks.UserLocalesRepo
 		preferences     []models.Preference
 		database        *mocks.Database
 		conn            *mocks.Connection
@@ -48,7 +49,10 @@ var _ = Describe("PreferencesFinder", func() {
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo)
+		userLocalesRepo = mocks.NewUserLocalesRepo()
+		userLocalesRepo.GetCall.Returns.Locale = "fr-CA"
+
+		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo, userLocalesRepo)
 	})
 
 	Describe("Find", func() {
@@ -57,6 +61,8 @@ var _ = Describe("PreferencesFinder", func() {
 			expectedResult.Add(preferences[0])
 			expectedResult.Add(preferences[1])
 			expectedResult.GlobalUnsubscribe = true
+			locale := "fr-CA"
+			expectedResult.Locale = &locale
 
 			resultPreferences, err := finder.Find(database, "correct-user")
 			Expect(err).NotTo(HaveOccurred())
@@ -64,6 +70,17 @@ var _ = Describe("PreferencesFinder", func() {
 
 			Expect(preferencesRepo.FindNonCriticalPreferencesCall.Receives.Connection).To(Equal(conn))
 			Expect(preferencesRepo.FindNonCriticalPreferencesCall.Receives.UserGUID).To(Equal("correct-user"))
+			Expect(userLocalesRepo.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(userLocalesRepo.GetCall.Receives.UserID).To(Equal("correct-user"))
+		})
+
+		Context("when the user locales repo returns an error", func() {
+			It("should propagate the error", func() {
+				userLocalesRepo.GetCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := finder.Find(database, "correct-user")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
 		})
 
 		Context("when the preferences repo returns an error", func() {
@@ -31,6 +31,13 @@ type TemplatesRepo interface {
 	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
 }
 
+type TemplateVersionsRepo interface {
+	Create(connection models.ConnectionInterface, template models.Template, author string) (models.TemplateVersion, error)
+	Find(connection models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+	List(connection models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error)
+	DestroyAll(connection models.ConnectionInterface, templateID string) error
+}
+
 type UnsubscribesRepo interface {
 	Set(connection models.ConnectionInterface, userID string, clientID string, kindID string, unsubscribe bool) error
 }
@@ -39,3 +46,8 @@ type GlobalUnsubscribesRepo interface {
 	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
 	Set(connection models.ConnectionInterface, userGUID string, unsubscribe bool) error
 }
+
+type UserLocalesRepo interface {
+	Get(connection models.ConnectionInterface, userGUID string) (string, error)
+	Set(connection models.ConnectionInterface, userGUID, locale string) error
+}
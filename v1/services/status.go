@@ -0,0 +1,17 @@
+package services
+
+const (
+	StatusQueued        = "queued"
+	StatusScheduled     = "scheduled"
+	StatusDelivered     = "delivered"
+	StatusFailed        = "failed"
+	StatusUndeliverable = "undeliverable"
+	StatusCanceled      = "canceled"
+)
+
+const (
+	BatchStatusPending   = "pending"
+	BatchStatusEnqueuing = "enqueuing"
+	BatchStatusEnqueued  = "enqueued"
+	BatchStatusFailed    = "failed"
+)
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/conceal"
+)
+
+type htmlProcessor interface {
+	Process(html string) (string, error)
+}
+
+type TemplatePreviewerConfig struct {
+	Cloak         conceal.CloakInterface
+	Sender        string
+	Domain        string
+	PublicURL     string
+	HTMLProcessor htmlProcessor
+}
+
+// TemplatePreviewer renders a template against a sample delivery with the
+// same packager the delivery worker uses, without enqueueing anything.
+type TemplatePreviewer struct {
+	cloak         conceal.CloakInterface
+	sender        string
+	domain        string
+	publicURL     string
+	htmlProcessor htmlProcessor
+}
+
+func NewTemplatePreviewer(config TemplatePreviewerConfig) TemplatePreviewer {
+	return TemplatePreviewer{
+		cloak:         config.Cloak,
+		sender:        config.Sender,
+		domain:        config.Domain,
+		publicURL:     config.PublicURL,
+		htmlProcessor: config.HTMLProcessor,
+	}
+}
+
+func (p TemplatePreviewer) Preview(template models.Template, delivery common.Delivery) (mail.Message, error) {
+	localizations, err := template.ParseLocalizations()
+	if err != nil {
+		return mail.Message{}, err
+	}
+
+	templates := common.Templates{
+		Name:    template.Name,
+		Subject: template.Subject,
+		Text:    template.Text,
+		HTML:    template.HTML,
+	}
+
+	for locale, localization := range localizations {
+		if templates.Localizations == nil {
+			templates.Localizations = map[string]common.Templates{}
+		}
+
+		templates.Localizations[locale] = common.Templates{
+			Subject: localization.Subject,
+			Text:    localization.Text,
+			HTML:    localization.HTML,
+		}
+	}
+
+	packager := common.NewPackager(previewTemplatesLoader{
+		templates: templates,
+	}, p.cloak, p.publicURL, p.htmlProcessor)
+
+	context, err := packager.PrepareContext(delivery, p.sender, p.domain)
+	if err != nil {
+		return mail.Message{}, err
+	}
+
+	message, err := packager.Pack(context)
+	if err != nil {
+		return mail.Message{}, TemplateRenderError{err}
+	}
+
+	return message, nil
+}
+
+type previewTemplatesLoader struct {
+	templates common.Templates
+}
+
+func (loader previewTemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
+	return loader.templates, nil
+}
@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplatePreviewer", func() {
+	var (
+		previewer services.TemplatePreviewer
+		cloak     *mocks.Cloak
+		template  models.Template
+		delivery  common.Delivery
+	)
+
+	BeforeEach(func() {
+		cloak = mocks.NewCloak()
+		cloak.VeilCall.Returns.CipherText = []byte("unsubscribe-id")
+
+		previewer = services.NewTemplatePreviewer(services.TemplatePreviewerConfig{
+			Cloak:         cloak,
+			Sender:        "no-reply@example.com",
+			Domain:        "example.com",
+			PublicURL:     "https://notifications.example.com",
+			HTMLProcessor: common.NewHTMLPipeline(common.HTMLPipelineConfig{}),
+		})
+
+		template = models.Template{
+			Subject: "CF Notification: {{.Subject}}",
+			Text:    "{{.Text}} for {{.Space}}",
+			HTML:    "{{.HTML}}<p>{{.Organization}}</p>",
+		}
+
+		delivery = common.Delivery{
+			UserGUID: "user-123",
+			Email:    "user@example.com",
+			ClientID: "some-client",
+			Options: common.Options{
+				Subject: "Deployed",
+				Text:    "Your app was deployed",
+				HTML: common.HTML{
+					BodyContent: "<p>Your app was deployed</p>",
+				},
+				KindID: "deploy",
+			},
+		}
+		delivery.Space.Name = "production"
+		delivery.Organization.Name = "acme"
+	})
+
+	It("renders the template the way the delivery worker would", func() {
+		message, err := previewer.Preview(template, delivery)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(message.From).To(Equal("no-reply@example.com"))
+		Expect(message.To).To(Equal("user@example.com"))
+		Expect(message.Subject).To(Equal("CF Notification: Deployed"))
+		Expect(message.Body).To(Equal([]mail.Part{
+			{ContentType: "text/plain", Content: "Your app was deployed for production"},
+			{ContentType: "text/html", Content: "<html><head></head>\n\t<body>\n\t\t<p>Your app was deployed</p><p>acme</p>\n\t\n</body></html>"},
+		}))
+		Expect(message.Headers).To(ContainElement("List-Unsubscribe: <https://notifications.example.com/unsubscribe/unsubscribe-id>"))
+
+		Expect(cloak.VeilCall.Receives.PlainText).To(Equal([]byte("user-123|some-client|deploy")))
+	})
+
+	It("renders the variant of the template for the locale of the recipient", func() {
+		template.Localizations = `{"fr": {"subject": "Notification CF : {{.Subject}}"}}`
+		delivery.Locale = "fr-CA"
+
+		message, err := previewer.Preview(template, delivery)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(message.Subject).To(Equal("Notification CF : Deployed"))
+	})
+
+	It("returns a render error when the template cannot be compiled", func() {
+		template.Subject = "{{.Subject"
+
+		_, err := previewer.Preview(template, delivery)
+		Expect(err).To(BeAssignableToTypeOf(services.TemplateRenderError{}))
+	})
+})
@@ -3,19 +3,36 @@ package services
 import "github.com/cloudfoundry-incubator/notifications/v1/models"
 
 type TemplateUpdater struct {
-	templatesRepo TemplatesRepo
+	templatesRepo        TemplatesRepo
+	templateVersionsRepo TemplateVersionsRepo
 }
 
-func NewTemplateUpdater(templatesRepo TemplatesRepo) TemplateUpdater {
+func NewTemplateUpdater(templatesRepo TemplatesRepo, templateVersionsRepo TemplateVersionsRepo) TemplateUpdater {
 	return TemplateUpdater{
-		templatesRepo: templatesRepo,
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
 	}
 }
 
-func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template) error {
-	_, err := updater.templatesRepo.Update(database.Connection(), templateID, template)
+// Update replaces the content of the template and records it as a new
+// version attributed to the given author.
+func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template, author string) error {
+	transaction := database.Connection().Transaction()
+	if err := transaction.Begin(); err != nil {
+		return err
+	}
+
+	updatedTemplate, err := updater.templatesRepo.Update(transaction, templateID, template)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	_, err = updater.templateVersionsRepo.Create(transaction, updatedTemplate, author)
 	if err != nil {
+		transaction.Rollback()
 		return err
 	}
-	return nil
+
+	return transaction.Commit()
 }
@@ -14,19 +14,30 @@ import (
 var _ = Describe("Updater", func() {
 	Describe("Update", func() {
 		var (
-			conn          *mocks.Connection
-			database      *mocks.Database
-			templatesRepo *mocks.TemplatesRepo
-			updater       services.TemplateUpdater
+			conn                 *mocks.Connection
+			transaction          *mocks.Transaction
+			database             *mocks.Database
+			templatesRepo        *mocks.TemplatesRepo
+			templateVersionsRepo *mocks.TemplateVersionsRepo
+			updater              services.TemplateUpdater
 		)
 
 		BeforeEach(func() {
+			transaction = mocks.NewTransaction()
 			conn = mocks.NewConnection()
+			conn.TransactionCall.Returns.Transaction = transaction
 			database = mocks.NewDatabase()
 			database.ConnectionCall.Returns.Connection = conn
 			templatesRepo = mocks.NewTemplatesRepo()
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:   "my-awesome-id",
+				Name: "gobble template",
+				Text: "gobble",
+				HTML: "<p>gobble</p>",
+			}
+			templateVersionsRepo = mocks.NewTemplateVersionsRepo()
 
-			updater = services.NewTemplateUpdater(templatesRepo)
+			updater = services.NewTemplateUpdater(templatesRepo, templateVersionsRepo)
 		})
 
 		It("Inserts templates into the templates repo", func() {
@@ -34,23 +45,44 @@ var _ = Describe("Updater", func() {
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
-			})
+			}, "some-client-id")
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
 			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
 			}))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("records the updated template as a new version", func() {
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(templateVersionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(templateVersionsRepo.CreateCall.Receives.Template).To(Equal(templatesRepo.UpdateCall.Returns.Template))
+			Expect(templateVersionsRepo.CreateCall.Receives.Author).To(Equal("some-client-id"))
 		})
 
 		It("propagates errors from repo", func() {
 			templatesRepo.UpdateCall.Returns.Error = errors.New("Boom!")
 
-			err := updater.Update(database, "unimportant", models.Template{})
+			err := updater.Update(database, "unimportant", models.Template{}, "")
+			Expect(err).To(MatchError(errors.New("Boom!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls back the update when the version cannot be recorded", func() {
+			templateVersionsRepo.CreateCall.Returns.Error = errors.New("Boom!")
+
+			err := updater.Update(database, "unimportant", models.Template{}, "")
 			Expect(err).To(MatchError(errors.New("Boom!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
 		})
 	})
 })
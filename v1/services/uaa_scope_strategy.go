@@ -1,31 +1,20 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/cf"
-
 const ScopeEndorsement = "You received this message because you have the {{.Scope}} scope."
 
-type scopeUserIDFinder interface {
-	UserIDsBelongingToScope(token, scope string) (userIDs []string, err error)
-}
-
 type UAAScopeStrategy struct {
-	findsUserIDs  scopeUserIDFinder
-	tokenLoader   loadsTokens
-	enqueuer      enqueuer
-	defaultScopes []string
+	fanOutEnqueuer fanOutEnqueuer
+	defaultScopes  []string
 }
 
-func NewUAAScopeStrategy(tokenLoader loadsTokens, findsUserIDs scopeUserIDFinder, enqueuer enqueuer, defaultScopes []string) UAAScopeStrategy {
+func NewUAAScopeStrategy(fanOutEnqueuer fanOutEnqueuer, defaultScopes []string) UAAScopeStrategy {
 	return UAAScopeStrategy{
-		findsUserIDs:  findsUserIDs,
-		tokenLoader:   tokenLoader,
-		enqueuer:      enqueuer,
-		defaultScopes: defaultScopes,
+		fanOutEnqueuer: fanOutEnqueuer,
+		defaultScopes:  defaultScopes,
 	}
 }
 
-func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
-	responses := []Response{}
+func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) (BatchResponse, error) {
 	options := Options{
 		ReplyTo:           dispatch.Message.ReplyTo,
 		Subject:           dispatch.Message.Subject,
@@ -36,6 +25,9 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		Data:              dispatch.Message.Data,
+		SendAt:            dispatch.Message.SendAt,
+		Localizations:     dispatch.Message.Localizations,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -45,35 +37,18 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 	}
 
 	if strategy.scopeIsDefault(dispatch.GUID) {
-		return responses, DefaultScopeError{}
-	}
-
-	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
-	if err != nil {
-		return responses, err
-	}
-
-	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToScope(token, dispatch.GUID)
-	if err != nil {
-		return responses, err
-	}
-
-	var users []User
-	for _, guid := range userGUIDs {
-		users = append(users, User{GUID: guid})
+		return BatchResponse{}, DefaultScopeError{}
 	}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		cf.CloudControllerOrganization{},
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		dispatch.GUID,
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return strategy.fanOutEnqueuer.Enqueue(dispatch.Connection, FanOut{
+		Audience:        AudienceScope,
+		GUID:            dispatch.GUID,
+		Options:         options,
+		ClientID:        dispatch.Client.ID,
+		UAAHost:         dispatch.UAAHost,
+		VCAPRequestID:   dispatch.VCAPRequest.ID,
+		RequestReceived: dispatch.VCAPRequest.ReceiptTime,
+	})
 }
 
 func (strategy UAAScopeStrategy) scopeIsDefault(scope string) bool {
@@ -4,8 +4,6 @@ import (
 	"errors"
 	"time"
 
-	"github.com/cloudfoundry-incubator/notifications/cf"
-	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 
@@ -16,13 +14,10 @@ import (
 var _ = Describe("UAA Scope Strategy", func() {
 	var (
 		strategy        services.UAAScopeStrategy
-		tokenLoader     *mocks.TokenLoader
-		enqueuer        *mocks.Enqueuer
+		fanOutEnqueuer  *mocks.FanOutEnqueuer
 		conn            *mocks.Connection
-		findsUserIDs    *mocks.FindsUserIDs
 		requestReceived time.Time
 		defaultScopes   []string
-		token           string
 	)
 
 	BeforeEach(func() {
@@ -42,31 +37,21 @@ var _ = Describe("UAA Scope Strategy", func() {
 		requestReceived, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:37:35.181067085-07:00")
 		conn = mocks.NewConnection()
 
-		tokenHeader := map[string]interface{}{
-			"alg": "RS256",
+		fanOutEnqueuer = mocks.NewFanOutEnqueuer()
+		fanOutEnqueuer.EnqueueCall.Returns.Response = services.BatchResponse{
+			BatchID:       "some-batch-id",
+			Status:        "pending",
+			VCAPRequestID: "some-vcap-request-id",
 		}
-		tokenClaims := map[string]interface{}{
-			"client_id": "mister-client",
-			"exp":       int64(3404281214),
-			"scope":     []string{"notifications.write"},
-		}
-		token = helpers.BuildToken(tokenHeader, tokenClaims)
-
-		tokenLoader = mocks.NewTokenLoader()
-		tokenLoader.LoadCall.Returns.Token = token
-		enqueuer = mocks.NewEnqueuer()
-
-		findsUserIDs = mocks.NewFindsUserIDs()
-		findsUserIDs.UserIDsBelongingToScopeCall.Returns.UserIDs = []string{"user-311"}
 
-		strategy = services.NewUAAScopeStrategy(tokenLoader, findsUserIDs, enqueuer, defaultScopes)
+		strategy = services.NewUAAScopeStrategy(fanOutEnqueuer, defaultScopes)
 	})
 
 	Describe("Dispatch", func() {
 		Context("when the JobType is unspecified", func() {
 			Context("when the request is valid", func() {
-				It("should call enqueuer.Enqueue with the correct arguments for an UAA Scope", func() {
-					_, err := strategy.Dispatch(services.Dispatch{
+				It("enqueues a fan-out to the users with the scope", func() {
+					response, err := strategy.Dispatch(services.Dispatch{
 						GUID:       "great.scope",
 						Connection: conn,
 						Message: services.DispatchMessage{
@@ -97,61 +82,52 @@ var _ = Describe("UAA Scope Strategy", func() {
 						UAAHost: "uaa",
 					})
 					Expect(err).NotTo(HaveOccurred())
+					Expect(response).To(Equal(services.BatchResponse{
+						BatchID:       "some-batch-id",
+						Status:        "pending",
+						VCAPRequestID: "some-vcap-request-id",
+					}))
 
-					users := []services.User{{GUID: "user-311"}}
-
-					Expect(enqueuer.EnqueueCall.Receives.Connection).To(Equal(conn))
-					Expect(enqueuer.EnqueueCall.Receives.Users).To(Equal(users))
-					Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
-						ReplyTo:           "reply-to@example.com",
-						Subject:           "this is the subject",
-						To:                "dr@strangelove.com",
-						KindID:            "forgot_waterbottle",
-						KindDescription:   "Water Bottle Reminder",
-						SourceDescription: "The Water Bottle System",
-						Text:              "Please make sure to leave your bottle in a place that is safe and dry",
-						TemplateID:        "some-template-id",
-						HTML: services.HTML{
-							BodyContent:    "<p>The water bottle needs to be safe and dry</p>",
-							BodyAttributes: "some-html-body-attributes",
-							Head:           "<head></head>",
-							Doctype:        "<html>",
+					Expect(fanOutEnqueuer.EnqueueCall.Receives.Connection).To(Equal(conn))
+					Expect(fanOutEnqueuer.EnqueueCall.Receives.FanOut).To(Equal(services.FanOut{
+						Audience: services.AudienceScope,
+						GUID:     "great.scope",
+						Options: services.Options{
+							ReplyTo:           "reply-to@example.com",
+							Subject:           "this is the subject",
+							To:                "dr@strangelove.com",
+							KindID:            "forgot_waterbottle",
+							KindDescription:   "Water Bottle Reminder",
+							SourceDescription: "The Water Bottle System",
+							Text:              "Please make sure to leave your bottle in a place that is safe and dry",
+							TemplateID:        "some-template-id",
+							HTML: services.HTML{
+								BodyContent:    "<p>The water bottle needs to be safe and dry</p>",
+								BodyAttributes: "some-html-body-attributes",
+								Head:           "<head></head>",
+								Doctype:        "<html>",
+							},
+							Endorsement: services.ScopeEndorsement,
 						},
-						Endorsement: services.ScopeEndorsement,
+						ClientID:        "mister-client",
+						UAAHost:         "uaa",
+						VCAPRequestID:   "some-vcap-request-id",
+						RequestReceived: requestReceived,
 					}))
-					Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
-					Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
-					Expect(enqueuer.EnqueueCall.Receives.Client).To(Equal("mister-client"))
-					Expect(enqueuer.EnqueueCall.Receives.Scope).To(Equal("great.scope"))
-					Expect(enqueuer.EnqueueCall.Receives.VCAPRequestID).To(Equal("some-vcap-request-id"))
-					Expect(enqueuer.EnqueueCall.Receives.RequestReceived).To(Equal(requestReceived))
-					Expect(enqueuer.EnqueueCall.Receives.UAAHost).To(Equal("uaa"))
-
-					Expect(findsUserIDs.UserIDsBelongingToScopeCall.Receives.Scope).To(Equal("great.scope"))
-					Expect(findsUserIDs.UserIDsBelongingToScopeCall.Receives.Token).To(Equal(token))
 				})
 			})
 		})
 
 		Context("failure cases", func() {
-			Context("when token loader fails to return a token", func() {
-				It("returns an error", func() {
-					tokenLoader.LoadCall.Returns.Error = errors.New("BOOM!")
+			Context("when the fan-out cannot be enqueued", func() {
+				It("returns the error", func() {
+					fanOutEnqueuer.EnqueueCall.Returns.Error = errors.New("BOOM!")
 
 					_, err := strategy.Dispatch(services.Dispatch{})
 					Expect(err).To(Equal(errors.New("BOOM!")))
 				})
 			})
 
-			Context("when finds user IDs returns an error", func() {
-				It("returns an error", func() {
-					findsUserIDs.UserIDsBelongingToScopeCall.Returns.Error = errors.New("BOOM!")
-
-					_, err := strategy.Dispatch(services.Dispatch{})
-					Expect(err).To(HaveOccurred())
-				})
-			})
-
 			Context("when an default scope is passed", func() {
 				It("returns an error", func() {
 					for _, scope := range defaultScopes {
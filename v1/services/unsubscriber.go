@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/conceal"
+)
+
+type UnsubscribeTarget struct {
+	UserGUID string
+	ClientID string
+	Kind     models.Kind
+}
+
+type Unsubscriber struct {
+	cloak            conceal.CloakInterface
+	unsubscribesRepo UnsubscribesRepo
+	kindsRepo        KindsRepo
+}
+
+func NewUnsubscriber(cloak conceal.CloakInterface, unsubscribesRepo UnsubscribesRepo, kindsRepo KindsRepo) Unsubscriber {
+	return Unsubscriber{
+		cloak:            cloak,
+		unsubscribesRepo: unsubscribesRepo,
+		kindsRepo:        kindsRepo,
+	}
+}
+
+func (u Unsubscriber) Lookup(conn ConnectionInterface, token string) (UnsubscribeTarget, error) {
+	plainText, err := u.cloak.Unveil([]byte(token))
+	if err != nil {
+		return UnsubscribeTarget{}, InvalidUnsubscribeTokenError{errors.New("The unsubscribe token is invalid")}
+	}
+
+	parts := strings.Split(string(plainText), "|")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return UnsubscribeTarget{}, InvalidUnsubscribeTokenError{errors.New("The unsubscribe token is invalid")}
+	}
+
+	userGUID, clientID, kindID := parts[0], parts[1], parts[2]
+
+	kind, err := u.kindsRepo.Find(conn, kindID, clientID)
+	if err != nil {
+		return UnsubscribeTarget{}, MissingKindOrClientError{fmt.Errorf("The kind '%s' cannot be found for client '%s'", kindID, clientID)}
+	}
+
+	return UnsubscribeTarget{
+		UserGUID: userGUID,
+		ClientID: clientID,
+		Kind:     kind,
+	}, nil
+}
+
+func (u Unsubscriber) Unsubscribe(conn ConnectionInterface, token string) (UnsubscribeTarget, error) {
+	target, err := u.Lookup(conn, token)
+	if err != nil {
+		return target, err
+	}
+
+	if target.Kind.Critical {
+		return target, CriticalKindError{fmt.Errorf("The kind '%s' for the '%s' client is critical and cannot be unsubscribed from", target.Kind.ID, target.ClientID)}
+	}
+
+	err = u.unsubscribesRepo.Set(conn, target.UserGUID, target.ClientID, target.Kind.ID, true)
+	if err != nil {
+		return target, err
+	}
+
+	return target, nil
+}
@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unsubscriber", func() {
+	var (
+		cloak            *mocks.Cloak
+		unsubscribesRepo *mocks.UnsubscribesRepo
+		kindsRepo        *mocks.KindsRepo
+		conn             *mocks.Connection
+		unsubscriber     services.Unsubscriber
+	)
+
+	BeforeEach(func() {
+		cloak = mocks.NewCloak()
+		cloak.UnveilCall.Returns.PlainText = []byte("user-123|some-client|some-kind")
+
+		unsubscribesRepo = mocks.NewUnsubscribesRepo()
+		kindsRepo = mocks.NewKindsRepo()
+		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+			{
+				ID:          "some-kind",
+				ClientID:    "some-client",
+				Description: "Some Kind",
+			},
+		}
+		conn = mocks.NewConnection()
+
+		unsubscriber = services.NewUnsubscriber(cloak, unsubscribesRepo, kindsRepo)
+	})
+
+	Describe("Lookup", func() {
+		It("unveils the token and finds the kind it refers to", func() {
+			target, err := unsubscriber.Lookup(conn, "some-token")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cloak.UnveilCall.Receives.CipherText).To(Equal([]byte("some-token")))
+			Expect(kindsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(kindsRepo.FindCall.Receives.KindID).To(Equal("some-kind"))
+			Expect(kindsRepo.FindCall.Receives.ClientID).To(Equal("some-client"))
+
+			Expect(target).To(Equal(services.UnsubscribeTarget{
+				UserGUID: "user-123",
+				ClientID: "some-client",
+				Kind: models.Kind{
+					ID:          "some-kind",
+					ClientID:    "some-client",
+					Description: "Some Kind",
+				},
+			}))
+		})
+
+		Context("when the token cannot be unveiled", func() {
+			It("returns an invalid token error", func() {
+				cloak.UnveilCall.Returns.Error = errors.New("bad cipher")
+
+				_, err := unsubscriber.Lookup(conn, "some-token")
+				Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeTokenError{}))
+			})
+		})
+
+		Context("when the token is not properly formatted", func() {
+			It("returns an invalid token error", func() {
+				cloak.UnveilCall.Returns.PlainText = []byte("user-123|some-client")
+
+				_, err := unsubscriber.Lookup(conn, "some-token")
+				Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeTokenError{}))
+			})
+		})
+
+		Context("when the kind cannot be found", func() {
+			It("returns a missing kind or client error", func() {
+				kindsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				_, err := unsubscriber.Lookup(conn, "some-token")
+				Expect(err).To(BeAssignableToTypeOf(services.MissingKindOrClientError{}))
+			})
+		})
+	})
+
+	Describe("Unsubscribe", func() {
+		It("unsubscribes the user from the kind", func() {
+			target, err := unsubscriber.Unsubscribe(conn, "some-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(target.Kind.ID).To(Equal("some-kind"))
+
+			Expect(unsubscribesRepo.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(unsubscribesRepo.SetCall.Receives.UserID).To(Equal("user-123"))
+			Expect(unsubscribesRepo.SetCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(unsubscribesRepo.SetCall.Receives.KindID).To(Equal("some-kind"))
+			Expect(unsubscribesRepo.SetCall.Receives.Unsubscribe).To(BeTrue())
+		})
+
+		Context("when the kind is critical", func() {
+			It("refuses to unsubscribe the user", func() {
+				kindsRepo.FindCall.Returns.Kinds[0].Critical = true
+
+				_, err := unsubscriber.Unsubscribe(conn, "some-token")
+				Expect(err).To(MatchError(services.CriticalKindError{errors.New("The kind 'some-kind' for the 'some-client' client is critical and cannot be unsubscribed from")}))
+				Expect(unsubscribesRepo.SetCall.Receives.UserID).To(BeEmpty())
+			})
+		})
+
+		Context("when the unsubscribes repo errors", func() {
+			It("returns the error", func() {
+				unsubscribesRepo.SetCall.Returns.Error = errors.New("db error")
+
+				_, err := unsubscriber.Unsubscribe(conn, "some-token")
+				Expect(err).To(MatchError(errors.New("db error")))
+			})
+		})
+	})
+})
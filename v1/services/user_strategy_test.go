@@ -43,6 +43,15 @@ var _ = Describe("UserStrategy", func() {
 						Head:           "<head></head>",
 						Doctype:        "<html>",
 					},
+					Data: map[string]interface{}{
+						"bottle_color": "blue",
+					},
+					Attachments: []services.Attachment{
+						{Filename: "bottle.png", ContentType: "image/png", Content: []byte("png bytes"), Inline: true, ContentID: "bottle"},
+					},
+					Localizations: map[string]services.Localization{
+						"de": {Subject: "Wasserflasche"},
+					},
 				},
 				TemplateID: "some-template-id",
 				UAAHost:    "uaa",
@@ -74,6 +83,15 @@ var _ = Describe("UserStrategy", func() {
 				SourceDescription: "The Water Bottle System",
 				Text:              "Please make sure to leave your bottle in a place that is safe and dry",
 				TemplateID:        "some-template-id",
+				Data: map[string]interface{}{
+					"bottle_color": "blue",
+				},
+				Attachments: []services.Attachment{
+					{Filename: "bottle.png", ContentType: "image/png", Content: []byte("png bytes"), Inline: true, ContentID: "bottle"},
+				},
+				Localizations: map[string]services.Localization{
+					"de": {Subject: "Wasserflasche"},
+				},
 				HTML: services.HTML{
 					BodyContent:    "<p>The water bottle needs to be safe and dry</p>",
 					BodyAttributes: "some-html-body-attributes",
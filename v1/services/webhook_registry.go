@@ -0,0 +1,94 @@
+package services
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type Webhook struct {
+	ID        string
+	ClientID  string
+	URL       string
+	Secret    string
+	CreatedAt time.Time
+}
+
+type webhooksRepo interface {
+	Create(models.ConnectionInterface, models.Webhook) (models.Webhook, error)
+	FindAllByClientID(models.ConnectionInterface, string) ([]models.Webhook, error)
+	Delete(conn models.ConnectionInterface, clientID, webhookID string) error
+}
+
+type clientFinder interface {
+	Find(models.ConnectionInterface, string) (models.Client, error)
+}
+
+type WebhookRegistry struct {
+	clientsRepo    clientFinder
+	webhooksRepo   webhooksRepo
+	generateSecret func() (string, error)
+}
+
+func NewWebhookRegistry(clientsRepo clientFinder, webhooksRepo webhooksRepo, secretGenerator func() (string, error)) WebhookRegistry {
+	return WebhookRegistry{
+		clientsRepo:    clientsRepo,
+		webhooksRepo:   webhooksRepo,
+		generateSecret: secretGenerator,
+	}
+}
+
+func (registry WebhookRegistry) Register(database DatabaseInterface, clientID, url, secret string) (Webhook, error) {
+	conn := database.Connection()
+
+	_, err := registry.clientsRepo.Find(conn, clientID)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	if secret == "" {
+		secret, err = registry.generateSecret()
+		if err != nil {
+			return Webhook{}, err
+		}
+	}
+
+	webhook, err := registry.webhooksRepo.Create(conn, models.Webhook{
+		ClientID: clientID,
+		URL:      url,
+		Secret:   secret,
+	})
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	return newWebhook(webhook), nil
+}
+
+func (registry WebhookRegistry) List(database DatabaseInterface, clientID string) ([]Webhook, error) {
+	webhooks, err := registry.webhooksRepo.FindAllByClientID(database.Connection(), clientID)
+	if err != nil {
+		return []Webhook{}, err
+	}
+
+	results := []Webhook{}
+	for _, webhook := range webhooks {
+		results = append(results, newWebhook(webhook))
+	}
+
+	return results, nil
+}
+
+func (registry WebhookRegistry) Unregister(database DatabaseInterface, clientID, webhookID string) error {
+	return registry.webhooksRepo.Delete(database.Connection(), clientID, webhookID)
+}
+
+func newWebhook(webhook models.Webhook) Webhook {
+	return Webhook{
+		ID:        webhook.ID,
+		ClientID:  webhook.ClientID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
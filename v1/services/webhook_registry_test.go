@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookRegistry", func() {
+	var (
+		registry        services.WebhookRegistry
+		clientsRepo     *mocks.ClientsRepository
+		webhooksRepo    *mocks.WebhooksRepo
+		secretGenerator *mocks.IDGenerator
+		database        *mocks.Database
+		conn            *mocks.Connection
+		createdAt       time.Time
+	)
+
+	BeforeEach(func() {
+		createdAt = time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC)
+
+		clientsRepo = mocks.NewClientsRepository()
+		webhooksRepo = mocks.NewWebhooksRepo()
+		secretGenerator = mocks.NewIDGenerator()
+		secretGenerator.GenerateCall.Returns.IDs = []string{"generated-secret"}
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		registry = services.NewWebhookRegistry(clientsRepo, webhooksRepo, secretGenerator.Generate)
+	})
+
+	Describe("Register", func() {
+		BeforeEach(func() {
+			webhooksRepo.CreateCall.Returns.Webhook = models.Webhook{
+				ID:        "some-webhook-id",
+				ClientID:  "some-client",
+				URL:       "https://example.com/hooks",
+				Secret:    "generated-secret",
+				CreatedAt: createdAt,
+			}
+		})
+
+		It("stores the webhook with a generated secret", func() {
+			webhook, err := registry.Register(database, "some-client", "https://example.com/hooks", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhook).To(Equal(services.Webhook{
+				ID:        "some-webhook-id",
+				ClientID:  "some-client",
+				URL:       "https://example.com/hooks",
+				Secret:    "generated-secret",
+				CreatedAt: createdAt,
+			}))
+
+			Expect(webhooksRepo.CreateCall.Receives.Connection).To(Equal(conn))
+			Expect(webhooksRepo.CreateCall.Receives.Webhook).To(Equal(models.Webhook{
+				ClientID: "some-client",
+				URL:      "https://example.com/hooks",
+				Secret:   "generated-secret",
+			}))
+		})
+
+		It("checks that the client exists", func() {
+			_, err := registry.Register(database, "some-client", "https://example.com/hooks", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(clientsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(clientsRepo.FindCall.Receives.ClientID).To(Equal("some-client"))
+		})
+
+		It("does not store webhooks for clients that do not exist", func() {
+			clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("Client with ID \"missing-client\" could not be found")}
+
+			_, err := registry.Register(database, "missing-client", "https://example.com/hooks", "")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Client with ID \"missing-client\" could not be found")}))
+			Expect(webhooksRepo.CreateCall.Receives.Webhook).To(Equal(models.Webhook{}))
+		})
+
+		It("keeps a secret provided by the client", func() {
+			_, err := registry.Register(database, "some-client", "https://example.com/hooks", "my-secret")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(secretGenerator.GenerateCall.CallCount).To(Equal(0))
+			Expect(webhooksRepo.CreateCall.Receives.Webhook.Secret).To(Equal("my-secret"))
+		})
+
+		It("returns an error when the secret cannot be generated", func() {
+			secretGenerator.GenerateCall.Returns.Error = errors.New("no entropy")
+
+			_, err := registry.Register(database, "some-client", "https://example.com/hooks", "")
+			Expect(err).To(MatchError(errors.New("no entropy")))
+		})
+
+		It("returns an error when the repo fails", func() {
+			webhooksRepo.CreateCall.Returns.Error = errors.New("db is down")
+
+			_, err := registry.Register(database, "some-client", "https://example.com/hooks", "")
+			Expect(err).To(MatchError(errors.New("db is down")))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the webhooks for the client", func() {
+			webhooksRepo.FindAllByClientIDCall.Returns.Webhooks = []models.Webhook{
+				{ID: "first-webhook", ClientID: "some-client", URL: "https://example.com/first", Secret: "s1", CreatedAt: createdAt},
+				{ID: "second-webhook", ClientID: "some-client", URL: "https://example.com/second", Secret: "s2", CreatedAt: createdAt},
+			}
+
+			webhooks, err := registry.List(database, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhooks).To(Equal([]services.Webhook{
+				{ID: "first-webhook", ClientID: "some-client", URL: "https://example.com/first", Secret: "s1", CreatedAt: createdAt},
+				{ID: "second-webhook", ClientID: "some-client", URL: "https://example.com/second", Secret: "s2", CreatedAt: createdAt},
+			}))
+
+			Expect(webhooksRepo.FindAllByClientIDCall.Receives.Connection).To(Equal(conn))
+			Expect(webhooksRepo.FindAllByClientIDCall.Receives.ClientID).To(Equal("some-client"))
+		})
+
+		It("returns an error when the repo fails", func() {
+			webhooksRepo.FindAllByClientIDCall.Returns.Error = errors.New("db is down")
+
+			_, err := registry.List(database, "some-client")
+			Expect(err).To(MatchError(errors.New("db is down")))
+		})
+	})
+
+	Describe("Unregister", func() {
+		It("deletes the webhook", func() {
+			err := registry.Unregister(database, "some-client", "some-webhook-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(webhooksRepo.DeleteCall.Receives.Connection).To(Equal(conn))
+			Expect(webhooksRepo.DeleteCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(webhooksRepo.DeleteCall.Receives.WebhookID).To(Equal("some-webhook-id"))
+		})
+
+		It("returns an error when the repo fails", func() {
+			webhooksRepo.DeleteCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := registry.Unregister(database, "some-client", "some-webhook-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package batches
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type batchFinder interface {
+	Find(services.DatabaseInterface, string) (services.Batch, error)
+}
+
+type GetHandler struct {
+	finder      batchFinder
+	errorWriter errorWriter
+}
+
+func NewGetHandler(finder batchFinder, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		finder:      finder,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	batchID := strings.TrimPrefix(req.URL.Path, "/batches/")
+
+	batch, err := h.finder.Find(context.Get("database").(DatabaseInterface), batchID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	var document struct {
+		BatchID       string         `json:"batch_id"`
+		Status        string         `json:"status"`
+		Error         string         `json:"error,omitempty"`
+		VCAPRequestID string         `json:"vcap_request_id"`
+		ClientID      string         `json:"client_id"`
+		KindID        string         `json:"kind_id"`
+		Audience      string         `json:"audience"`
+		GUID          string         `json:"guid"`
+		Total         int            `json:"total"`
+		Enqueued      int            `json:"enqueued"`
+		Counts        map[string]int `json:"counts"`
+		CreatedAt     string         `json:"created_at"`
+	}
+	document.BatchID = batch.ID
+	document.Status = batch.Status
+	document.Error = batch.Error
+	document.VCAPRequestID = batch.VCAPRequestID
+	document.ClientID = batch.ClientID
+	document.KindID = batch.KindID
+	document.Audience = batch.Audience
+	document.GUID = batch.GUID
+	document.Total = batch.Total
+	document.Enqueued = batch.Enqueued
+	document.Counts = batch.Counts
+	document.CreatedAt = batch.CreatedAt.UTC().Format(time.RFC3339)
+
+	output, err := json.Marshal(document)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
@@ -0,0 +1,121 @@
+package batches_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/batches"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler     batches.GetHandler
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		batchFinder *mocks.BatchFinder
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		errorWriter = mocks.NewErrorWriter()
+		batchFinder = mocks.NewBatchFinder()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("GET", "/batches/some-batch-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = batches.NewGetHandler(batchFinder, errorWriter)
+	})
+
+	It("returns the batch with its delivery counts", func() {
+		batchFinder.FindCall.Returns.Batch = services.Batch{
+			ID:            "some-batch-id",
+			ClientID:      "some-client",
+			KindID:        "some-kind",
+			VCAPRequestID: "some-request-id",
+			Audience:      "organization",
+			GUID:          "some-org-guid",
+			Status:        "enqueued",
+			Total:         5,
+			Enqueued:      5,
+			Counts: map[string]int{
+				"queued":        2,
+				"scheduled":     0,
+				"delivered":     3,
+				"failed":        0,
+				"undeliverable": 0,
+				"canceled":      0,
+			},
+			CreatedAt: time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC),
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{
+			"batch_id": "some-batch-id",
+			"status": "enqueued",
+			"vcap_request_id": "some-request-id",
+			"client_id": "some-client",
+			"kind_id": "some-kind",
+			"audience": "organization",
+			"guid": "some-org-guid",
+			"total": 5,
+			"enqueued": 5,
+			"counts": {
+				"queued": 2,
+				"scheduled": 0,
+				"delivered": 3,
+				"failed": 0,
+				"undeliverable": 0,
+				"canceled": 0
+			},
+			"created_at": "2015-06-08T14:32:11Z"
+		}`))
+
+		Expect(batchFinder.FindCall.Receives.Database).To(Equal(database))
+		Expect(batchFinder.FindCall.Receives.BatchID).To(Equal("some-batch-id"))
+	})
+
+	It("includes the error when the batch failed", func() {
+		batchFinder.FindCall.Returns.Batch = services.Batch{
+			ID:     "some-batch-id",
+			Status: "failed",
+			Error:  "uaa is down",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+
+		var document map[string]interface{}
+		Expect(json.Unmarshal(writer.Body.Bytes(), &document)).To(Succeed())
+		Expect(document["status"]).To(Equal("failed"))
+		Expect(document["error"]).To(Equal("uaa is down"))
+	})
+
+	Context("when the finder errors", func() {
+		It("delegates to the error writer", func() {
+			batchFinder.FindCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("BOOM!")))
+		})
+	})
+})
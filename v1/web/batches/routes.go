@@ -0,0 +1,21 @@
+package batches
+
+import "github.com/ryanmoran/stack"
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestCounter                               stack.Middleware
+	RequestLogging                               stack.Middleware
+	NotificationsWriteOrEmailsWriteAuthenticator stack.Middleware
+	DatabaseAllocator                            stack.Middleware
+
+	BatchFinder batchFinder
+	ErrorWriter errorWriter
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/batches/{batch_id}", NewGetHandler(r.BatchFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
+}
@@ -15,7 +15,7 @@ type errorWriter interface {
 }
 
 type assignsTemplates interface {
-	AssignToClient(connection collections.ConnectionInterface, clientID, templateID string) error
+	AssignToClient(connection collections.ConnectionInterface, clientID, templateID string, version int) error
 }
 
 type AssignTemplateHandler struct {
@@ -31,7 +31,8 @@ func NewAssignTemplateHandler(assigner assignsTemplates, errWriter errorWriter)
 }
 
 type TemplateAssignment struct {
-	Template string `json:"template"`
+	Template        string `json:"template"`
+	TemplateVersion int    `json:"template_version"`
 }
 
 func (h AssignTemplateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
@@ -46,7 +47,7 @@ func (h AssignTemplateHandler) ServeHTTP(w http.ResponseWriter, req *http.Reques
 	}
 
 	database := context.Get("database").(DatabaseInterface)
-	err = h.templateAssigner.AssignToClient(database.Connection(), clientID, templateAssignment.Template)
+	err = h.templateAssigner.AssignToClient(database.Connection(), clientID, templateAssignment.Template, templateAssignment.TemplateVersion)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
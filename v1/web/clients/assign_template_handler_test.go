@@ -54,6 +54,21 @@ var _ = Describe("AssignTemplateHandler", func() {
 		Expect(templateAssigner.AssignToClientCall.Receives.Connection).To(Equal(connection))
 		Expect(templateAssigner.AssignToClientCall.Receives.ClientID).To(Equal("my-client"))
 		Expect(templateAssigner.AssignToClientCall.Receives.TemplateID).To(Equal("my-template"))
+		Expect(templateAssigner.AssignToClientCall.Receives.Version).To(Equal(0))
+	})
+
+	It("passes along the template version to pin", func() {
+		body := []byte(`{"template": "my-template", "template_version": 3}`)
+
+		w := httptest.NewRecorder()
+		request, err := http.NewRequest("PUT", "/clients/my-client/template", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(w, request, context)
+
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(templateAssigner.AssignToClientCall.Receives.TemplateID).To(Equal("my-template"))
+		Expect(templateAssigner.AssignToClientCall.Receives.Version).To(Equal(3))
 	})
 
 	It("delegates to the error writer when the assigner errors", func() {
@@ -0,0 +1,35 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type DeleteHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewDeleteHandler(queue deadJobsQueue, errWriter errorWriter) DeleteHandler {
+	return DeleteHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	id, err := parseDeadJobID(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	err = h.queue.DeleteDeadJob(id)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
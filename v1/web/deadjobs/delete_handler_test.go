@@ -0,0 +1,52 @@
+package deadjobs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler     deadjobs.DeleteHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		queue       *mocks.Queue
+		errorWriter *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		queue = mocks.NewQueue()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/dead_jobs/1", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		handler = deadjobs.NewDeleteHandler(queue, errorWriter)
+	})
+
+	It("deletes the dead job", func() {
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(queue.DeleteDeadJobCall.Receives.ID).To(Equal(1))
+	})
+
+	It("delegates queue errors to the error writer", func() {
+		queue.DeleteDeadJobCall.Returns.Error = gobble.DeadJobNotFoundError{ID: 1}
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(gobble.DeadJobNotFoundError{ID: 1}))
+		Expect(writer.Code).NotTo(Equal(http.StatusNoContent))
+	})
+})
@@ -0,0 +1,60 @@
+package deadjobs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+)
+
+type deadJobDocument struct {
+	ID         int       `json:"id"`
+	JobID      int       `json:"job_id"`
+	MessageID  string    `json:"message_id,omitempty"`
+	Error      string    `json:"error"`
+	RetryCount int       `json:"retry_count"`
+	ActiveAt   time.Time `json:"active_at"`
+	DiedAt     time.Time `json:"died_at"`
+	Payload    string    `json:"payload,omitempty"`
+}
+
+func newDeadJobDocument(deadJob gobble.DeadJob) deadJobDocument {
+	var delivery struct {
+		MessageID string
+	}
+	json.Unmarshal([]byte(deadJob.Payload), &delivery) // Dead payloads may not be valid JSON
+
+	return deadJobDocument{
+		ID:         deadJob.ID,
+		JobID:      deadJob.JobID,
+		MessageID:  delivery.MessageID,
+		Error:      deadJob.Error,
+		RetryCount: deadJob.RetryCount,
+		ActiveAt:   deadJob.ActiveAt,
+		DiedAt:     deadJob.DiedAt,
+	}
+}
+
+func parseDeadJobID(path string) (int, error) {
+	segments := strings.Split(strings.TrimPrefix(path, "/dead_jobs/"), "/")
+
+	id, err := strconv.Atoi(segments[0])
+	if err != nil {
+		return 0, gobble.DeadJobNotFoundError{}
+	}
+
+	return id, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
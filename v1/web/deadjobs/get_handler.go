@@ -0,0 +1,38 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type GetHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewGetHandler(queue deadJobsQueue, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	id, err := parseDeadJobID(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	deadJob, err := h.queue.DeadJob(id)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := newDeadJobDocument(deadJob)
+	document.Payload = deadJob.Payload
+
+	writeJSON(w, http.StatusOK, document)
+}
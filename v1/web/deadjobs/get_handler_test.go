@@ -0,0 +1,83 @@
+package deadjobs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler     deadjobs.GetHandler
+		writer      *httptest.ResponseRecorder
+		queue       *mocks.Queue
+		errorWriter *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		diedAt := time.Date(2015, time.June, 8, 14, 38, 3, 0, time.UTC)
+
+		queue = mocks.NewQueue()
+		queue.DeadJobCall.Returns.DeadJob = gobble.DeadJob{
+			ID:         1,
+			JobID:      42,
+			Payload:    `{"MessageID":"some-message-id"}`,
+			Error:      "some error",
+			RetryCount: 10,
+			ActiveAt:   diedAt,
+			DiedAt:     diedAt,
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		handler = deadjobs.NewGetHandler(queue, errorWriter)
+	})
+
+	It("returns the dead job including its payload", func() {
+		request, err := http.NewRequest("GET", "/dead_jobs/1", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": 1,
+			"job_id": 42,
+			"message_id": "some-message-id",
+			"error": "some error",
+			"retry_count": 10,
+			"active_at": "2015-06-08T14:38:03Z",
+			"died_at": "2015-06-08T14:38:03Z",
+			"payload": "{\"MessageID\":\"some-message-id\"}"
+		}`))
+		Expect(queue.DeadJobCall.Receives.ID).To(Equal(1))
+	})
+
+	It("writes a not found error when the ID is not a number", func() {
+		request, err := http.NewRequest("GET", "/dead_jobs/banana", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(gobble.DeadJobNotFoundError{}))
+	})
+
+	It("delegates queue errors to the error writer", func() {
+		queue.DeadJobCall.Returns.Error = gobble.DeadJobNotFoundError{ID: 1}
+
+		request, err := http.NewRequest("GET", "/dead_jobs/1", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(gobble.DeadJobNotFoundError{ID: 1}))
+	})
+})
@@ -0,0 +1,46 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type ListHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewListHandler(queue deadJobsQueue, errWriter errorWriter) ListHandler {
+	return ListHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	limit, offset, err := webutil.ParsePagination(req.URL.Query())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	deadJobs, err := h.queue.DeadJobs(offset, limit)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := struct {
+		DeadJobs []deadJobDocument `json:"dead_jobs"`
+	}{
+		DeadJobs: []deadJobDocument{},
+	}
+
+	for _, deadJob := range deadJobs {
+		document.DeadJobs = append(document.DeadJobs, newDeadJobDocument(deadJob))
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
@@ -0,0 +1,143 @@
+package deadjobs_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler     deadjobs.ListHandler
+		writer      *httptest.ResponseRecorder
+		queue       *mocks.Queue
+		errorWriter *mocks.ErrorWriter
+		diedAt      time.Time
+	)
+
+	BeforeEach(func() {
+		diedAt = time.Date(2015, time.June, 8, 14, 38, 3, 0, time.UTC)
+
+		queue = mocks.NewQueue()
+		queue.DeadJobsCall.Returns.DeadJobs = []gobble.DeadJob{
+			{
+				ID:         1,
+				JobID:      42,
+				Payload:    `{"MessageID":"some-message-id"}`,
+				Error:      "some error",
+				RetryCount: 10,
+				ActiveAt:   diedAt.Add(-time.Minute),
+				DiedAt:     diedAt,
+			},
+			{
+				ID:      2,
+				JobID:   43,
+				Payload: "%%",
+				Error:   "invalid character '%' looking for beginning of value",
+				DiedAt:  diedAt,
+			},
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		handler = deadjobs.NewListHandler(queue, errorWriter)
+	})
+
+	It("lists the dead jobs without their payloads", func() {
+		request, err := http.NewRequest("GET", "/dead_jobs", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"dead_jobs": [
+				{
+					"id": 1,
+					"job_id": 42,
+					"message_id": "some-message-id",
+					"error": "some error",
+					"retry_count": 10,
+					"active_at": "2015-06-08T14:37:03Z",
+					"died_at": "2015-06-08T14:38:03Z"
+				},
+				{
+					"id": 2,
+					"job_id": 43,
+					"error": "invalid character '%' looking for beginning of value",
+					"retry_count": 0,
+					"active_at": "0001-01-01T00:00:00Z",
+					"died_at": "2015-06-08T14:38:03Z"
+				}
+			]
+		}`))
+
+		Expect(queue.DeadJobsCall.Receives.Offset).To(Equal(0))
+		Expect(queue.DeadJobsCall.Receives.Limit).To(Equal(50))
+	})
+
+	It("pages using the limit and offset query parameters", func() {
+		request, err := http.NewRequest("GET", "/dead_jobs?limit=10&offset=20", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(queue.DeadJobsCall.Receives.Offset).To(Equal(20))
+		Expect(queue.DeadJobsCall.Receives.Limit).To(Equal(10))
+	})
+
+	It("returns an empty list when there are no dead jobs", func() {
+		queue.DeadJobsCall.Returns.DeadJobs = nil
+
+		request, err := http.NewRequest("GET", "/dead_jobs", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Body.String()).To(MatchJSON(`{"dead_jobs": []}`))
+	})
+
+	Context("when the paging parameters are invalid", func() {
+		It("writes a validation error for a bad limit", func() {
+			request, err := http.NewRequest("GET", "/dead_jobs?limit=banana", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, stack.NewContext())
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		})
+
+		It("writes a validation error for a negative offset", func() {
+			request, err := http.NewRequest("GET", "/dead_jobs?offset=-1", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, stack.NewContext())
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		})
+	})
+
+	Context("when the queue errors", func() {
+		It("delegates to the error writer", func() {
+			queue.DeadJobsCall.Returns.Error = errors.New("database is down")
+
+			request, err := http.NewRequest("GET", "/dead_jobs", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, stack.NewContext())
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("database is down"))
+		})
+	})
+})
@@ -0,0 +1,31 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type PurgeHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewPurgeHandler(queue deadJobsQueue, errWriter errorWriter) PurgeHandler {
+	return PurgeHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h PurgeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	count, err := h.queue.PurgeDeadJobs()
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"purged": count,
+	})
+}
@@ -0,0 +1,53 @@
+package deadjobs_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PurgeHandler", func() {
+	var (
+		handler     deadjobs.PurgeHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		queue       *mocks.Queue
+		errorWriter *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		queue = mocks.NewQueue()
+		queue.PurgeDeadJobsCall.Returns.Count = 3
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/dead_jobs", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		handler = deadjobs.NewPurgeHandler(queue, errorWriter)
+	})
+
+	It("purges all dead jobs and reports how many were removed", func() {
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"purged": 3}`))
+		Expect(queue.PurgeDeadJobsCall.WasCalled).To(BeTrue())
+	})
+
+	It("delegates queue errors to the error writer", func() {
+		queue.PurgeDeadJobsCall.Returns.Error = errors.New("database is down")
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("database is down"))
+	})
+})
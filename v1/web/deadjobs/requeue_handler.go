@@ -0,0 +1,37 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type RequeueHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewRequeueHandler(queue deadJobsQueue, errWriter errorWriter) RequeueHandler {
+	return RequeueHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h RequeueHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	id, err := parseDeadJobID(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	job, err := h.queue.RequeueDeadJob(id)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]int{
+		"job_id": job.ID,
+	})
+}
@@ -0,0 +1,54 @@
+package deadjobs_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequeueHandler", func() {
+	var (
+		handler     deadjobs.RequeueHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		queue       *mocks.Queue
+		errorWriter *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		queue = mocks.NewQueue()
+		queue.RequeueDeadJobCall.Returns.Job = &gobble.Job{ID: 99}
+
+		var err error
+		request, err = http.NewRequest("POST", "/dead_jobs/1/requeue", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		handler = deadjobs.NewRequeueHandler(queue, errorWriter)
+	})
+
+	It("requeues the dead job and returns the new job ID", func() {
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(writer.Body.String()).To(MatchJSON(`{"job_id": 99}`))
+		Expect(queue.RequeueDeadJobCall.Receives.ID).To(Equal(1))
+	})
+
+	It("delegates queue errors to the error writer", func() {
+		queue.RequeueDeadJobCall.Returns.Error = errors.New("database is down")
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("database is down"))
+	})
+})
@@ -0,0 +1,41 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type deadJobsQueue interface {
+	DeadJobs(offset, limit int) ([]gobble.DeadJob, error)
+	DeadJob(id int) (gobble.DeadJob, error)
+	RequeueDeadJob(id int) (*gobble.Job, error)
+	DeleteDeadJob(id int) error
+	PurgeDeadJobs() (int, error)
+}
+
+type Routes struct {
+	RequestCounter                  stack.Middleware
+	RequestLogging                  stack.Middleware
+	NotificationsAdminAuthenticator stack.Middleware
+
+	ErrorWriter errorWriter
+	Queue       deadJobsQueue
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/dead_jobs", NewListHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("DELETE", "/dead_jobs", NewPurgeHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("GET", "/dead_jobs/{id}", NewGetHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("DELETE", "/dead_jobs/{id}", NewDeleteHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("POST", "/dead_jobs/{id}/requeue", NewRequeueHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+}
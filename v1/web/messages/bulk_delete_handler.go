@@ -0,0 +1,54 @@
+package messages
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type BulkDeleteHandler struct {
+	canceler    messageCanceler
+	errorWriter errorWriter
+}
+
+func NewBulkDeleteHandler(canceler messageCanceler, errWriter errorWriter) BulkDeleteHandler {
+	return BulkDeleteHandler{
+		canceler:    canceler,
+		errorWriter: errWriter,
+	}
+}
+
+func (h BulkDeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	vcapRequestID := req.URL.Query().Get("vcap_request_id")
+	if vcapRequestID == "" {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"vcap_request_id" is a required parameter`)})
+		return
+	}
+
+	messages, err := h.canceler.CancelByVCAPRequestID(context.Get("database").(DatabaseInterface), vcapRequestID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	type messageDocument struct {
+		NotificationID string `json:"notification_id"`
+		Status         string `json:"status"`
+	}
+
+	var document struct {
+		Messages []messageDocument `json:"messages"`
+	}
+	document.Messages = []messageDocument{}
+
+	for _, message := range messages {
+		document.Messages = append(document.Messages, messageDocument{
+			NotificationID: message.ID,
+			Status:         message.Status,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
@@ -0,0 +1,82 @@
+package messages_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BulkDeleteHandler", func() {
+	var (
+		handler         messages.BulkDeleteHandler
+		errorWriter     *mocks.ErrorWriter
+		writer          *httptest.ResponseRecorder
+		request         *http.Request
+		messageCanceler *mocks.MessageCanceler
+		database        *mocks.Database
+		context         stack.Context
+	)
+
+	BeforeEach(func() {
+		errorWriter = mocks.NewErrorWriter()
+		messageCanceler = mocks.NewMessageCanceler()
+		messageCanceler.CancelByVCAPRequestIDCall.Returns.Messages = []services.Message{
+			{ID: "first-message-id", Status: "canceled"},
+			{ID: "second-message-id", Status: "delivered"},
+		}
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/messages?vcap_request_id=some-request-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = messages.NewBulkDeleteHandler(messageCanceler, errorWriter)
+	})
+
+	It("cancels the messages for the request and reports their statuses", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{
+			"messages": [
+				{"notification_id": "first-message-id", "status": "canceled"},
+				{"notification_id": "second-message-id", "status": "delivered"}
+			]
+		}`))
+
+		Expect(messageCanceler.CancelByVCAPRequestIDCall.Receives.Database).To(Equal(database))
+		Expect(messageCanceler.CancelByVCAPRequestIDCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+	})
+
+	It("writes a validation error when the vcap_request_id is missing", func() {
+		var err error
+		request, err = http.NewRequest("DELETE", "/messages", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"vcap_request_id" is a required parameter`)}))
+	})
+
+	Context("when the canceler errors", func() {
+		It("delegates to the error writer", func() {
+			messageCanceler.CancelByVCAPRequestIDCall.Returns.Error = errors.New("cannot cancel")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("cannot cancel"))
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package messages
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type messageCanceler interface {
+	Cancel(services.DatabaseInterface, string) error
+	CancelByVCAPRequestID(services.DatabaseInterface, string) ([]services.Message, error)
+}
+
+type DeleteHandler struct {
+	canceler    messageCanceler
+	errorWriter errorWriter
+}
+
+func NewDeleteHandler(canceler messageCanceler, errWriter errorWriter) DeleteHandler {
+	return DeleteHandler{
+		canceler:    canceler,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	messageID := strings.Split(req.URL.Path, "/messages/")[1]
+
+	err := h.canceler.Cancel(context.Get("database").(DatabaseInterface), messageID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
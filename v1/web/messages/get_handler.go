@@ -20,6 +20,7 @@ type errorWriter interface {
 
 type messageFinder interface {
 	Find(services.DatabaseInterface, string) (services.Message, error)
+	List(services.DatabaseInterface, services.MessageFilter) ([]services.Message, error)
 }
 
 func NewGetHandler(finder messageFinder, errWriter errorWriter) GetHandler {
@@ -40,8 +41,10 @@ func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 
 	var document struct {
 		Status string `json:"status"`
+		Reason string `json:"reason,omitempty"`
 	}
 	document.Status = message.Status
+	document.Reason = message.Reason
 
 	writeJSON(w, http.StatusOK, document)
 }
@@ -61,6 +61,21 @@ var _ = Describe("GetHandler", func() {
 			Expect(messageFinder.FindCall.Receives.MessageID).To(Equal(messageID))
 		})
 
+		It("includes the reason when the message could not be delivered", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				Status: "undeliverable",
+				Reason: "550 mailbox does not exist",
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{
+				"status": "undeliverable",
+				"reason": "550 mailbox does not exist"
+			}`))
+		})
+
 		Context("When the finder errors", func() {
 			It("Delegates to the error writer", func() {
 				findError := errors.New("The finder returns a generic error")
@@ -0,0 +1,112 @@
+package messages
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type messageLister interface {
+	List(services.DatabaseInterface, services.MessageFilter) ([]services.Message, error)
+}
+
+type ListHandler struct {
+	lister      messageLister
+	errorWriter errorWriter
+}
+
+func NewListHandler(lister messageLister, errWriter errorWriter) ListHandler {
+	return ListHandler{
+		lister:      lister,
+		errorWriter: errWriter,
+	}
+}
+
+type messageDocument struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	Reason        string `json:"reason,omitempty"`
+	VCAPRequestID string `json:"vcap_request_id"`
+	BatchID       string `json:"batch_id"`
+	UserGUID      string `json:"user_guid"`
+	Email         string `json:"email"`
+	ClientID      string `json:"client_id"`
+	KindID        string `json:"kind_id"`
+	Attempts      int    `json:"attempts"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+	DeliveredAt   string `json:"delivered_at,omitempty"`
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	query := req.URL.Query()
+
+	filter := services.MessageFilter{
+		UserGUID:      query.Get("user_guid"),
+		Email:         query.Get("email"),
+		ClientID:      query.Get("client_id"),
+		KindID:        query.Get("kind_id"),
+		VCAPRequestID: query.Get("vcap_request_id"),
+		Status:        query.Get("status"),
+	}
+
+	var err error
+	filter.Limit, filter.Offset, err = webutil.ParsePagination(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	for name, field := range map[string]*time.Time{
+		"created_after":  &filter.CreatedAfter,
+		"created_before": &filter.CreatedBefore,
+	} {
+		if value := query.Get(name); value != "" {
+			*field, err = time.Parse(time.RFC3339, value)
+			if err != nil {
+				h.errorWriter.Write(w, webutil.ValidationError{Err: fmt.Errorf("%s must be an RFC 3339 timestamp", name)})
+				return
+			}
+		}
+	}
+
+	messages, err := h.lister.List(context.Get("database").(DatabaseInterface), filter)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := struct {
+		Messages []messageDocument `json:"messages"`
+	}{
+		Messages: []messageDocument{},
+	}
+
+	for _, message := range messages {
+		messageDoc := messageDocument{
+			ID:            message.ID,
+			Status:        message.Status,
+			Reason:        message.Reason,
+			VCAPRequestID: message.VCAPRequestID,
+			BatchID:       message.BatchID,
+			UserGUID:      message.UserGUID,
+			Email:         message.Email,
+			ClientID:      message.ClientID,
+			KindID:        message.KindID,
+			Attempts:      message.Attempts,
+			CreatedAt:     message.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:     message.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if !message.DeliveredAt.IsZero() {
+			messageDoc.DeliveredAt = message.DeliveredAt.UTC().Format(time.RFC3339)
+		}
+
+		document.Messages = append(document.Messages, messageDoc)
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
@@ -0,0 +1,172 @@
+package messages_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler       messages.ListHandler
+		messageFinder *mocks.MessageFinder
+		errorWriter   *mocks.ErrorWriter
+		writer        *httptest.ResponseRecorder
+		database      *mocks.Database
+		context       stack.Context
+	)
+
+	BeforeEach(func() {
+		messageFinder = mocks.NewMessageFinder()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = messages.NewListHandler(messageFinder, errorWriter)
+	})
+
+	It("lists the messages matching the filters", func() {
+		createdAt := time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC)
+		messageFinder.ListCall.Returns.Messages = []services.Message{
+			{
+				ID:            "first-message-id",
+				Status:        "delivered",
+				VCAPRequestID: "some-request-id",
+				BatchID:       "some-batch-id",
+				UserGUID:      "some-user",
+				Email:         "user@example.com",
+				ClientID:      "some-client",
+				KindID:        "billing",
+				Attempts:      1,
+				CreatedAt:     createdAt,
+				UpdatedAt:     createdAt.Add(time.Minute),
+				DeliveredAt:   createdAt.Add(time.Minute),
+			},
+			{
+				ID:        "second-message-id",
+				Status:    "failed",
+				Reason:    "connection refused",
+				UserGUID:  "some-user",
+				ClientID:  "some-client",
+				KindID:    "billing",
+				Attempts:  3,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			},
+		}
+
+		request, err := http.NewRequest("GET", "/messages?user_guid=some-user&kind_id=billing&status=delivered&created_after=2015-06-08T00:00:00Z&created_before=2015-06-09T00:00:00-07:00&limit=10&offset=20", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{
+			"messages": [
+				{
+					"id": "first-message-id",
+					"status": "delivered",
+					"vcap_request_id": "some-request-id",
+					"batch_id": "some-batch-id",
+					"user_guid": "some-user",
+					"email": "user@example.com",
+					"client_id": "some-client",
+					"kind_id": "billing",
+					"attempts": 1,
+					"created_at": "2015-06-08T14:32:11Z",
+					"updated_at": "2015-06-08T14:33:11Z",
+					"delivered_at": "2015-06-08T14:33:11Z"
+				},
+				{
+					"id": "second-message-id",
+					"status": "failed",
+					"reason": "connection refused",
+					"vcap_request_id": "",
+					"batch_id": "",
+					"user_guid": "some-user",
+					"email": "",
+					"client_id": "some-client",
+					"kind_id": "billing",
+					"attempts": 3,
+					"created_at": "2015-06-08T14:32:11Z",
+					"updated_at": "2015-06-08T14:32:11Z"
+				}
+			]
+		}`))
+
+		Expect(messageFinder.ListCall.Receives.Database).To(Equal(database))
+
+		filter := messageFinder.ListCall.Receives.Filter
+		Expect(filter.UserGUID).To(Equal("some-user"))
+		Expect(filter.KindID).To(Equal("billing"))
+		Expect(filter.Status).To(Equal("delivered"))
+		Expect(filter.CreatedAfter).To(BeTemporally("==", time.Date(2015, 6, 8, 0, 0, 0, 0, time.UTC)))
+		Expect(filter.CreatedBefore).To(BeTemporally("==", time.Date(2015, 6, 9, 7, 0, 0, 0, time.UTC)))
+		Expect(filter.Limit).To(Equal(10))
+		Expect(filter.Offset).To(Equal(20))
+	})
+
+	It("defaults the pagination parameters", func() {
+		request, err := http.NewRequest("GET", "/messages", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{"messages": []}`))
+		Expect(messageFinder.ListCall.Receives.Filter).To(Equal(services.MessageFilter{
+			Limit:  50,
+			Offset: 0,
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("rejects a limit outside of 1 to 500", func() {
+			for _, limit := range []string{"0", "501", "abc"} {
+				request, err := http.NewRequest("GET", "/messages?limit="+limit, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New("limit must be an integer between 1 and 500")}))
+			}
+		})
+
+		It("rejects a negative offset", func() {
+			request, err := http.NewRequest("GET", "/messages?offset=-1", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New("offset must be a non-negative integer")}))
+		})
+
+		It("rejects timestamps that are not RFC 3339", func() {
+			request, err := http.NewRequest("GET", "/messages?created_before=tuesday", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New("created_before must be an RFC 3339 timestamp")}))
+		})
+
+		It("delegates finder errors to the error writer", func() {
+			messageFinder.ListCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("GET", "/messages", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("BOOM!")))
+		})
+	})
+})
@@ -10,12 +10,17 @@ type Routes struct {
 	RequestCounter                               stack.Middleware
 	RequestLogging                               stack.Middleware
 	NotificationsWriteOrEmailsWriteAuthenticator stack.Middleware
+	NotificationsAdminAuthenticator              stack.Middleware
 	DatabaseAllocator                            stack.Middleware
 
-	MessageFinder messageFinder
-	ErrorWriter   errorWriter
+	MessageFinder   messageFinder
+	MessageCanceler messageCanceler
+	ErrorWriter     errorWriter
 }
 
 func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/messages", NewListHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/messages/{message_id}", NewGetHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/messages/{message_id}", NewDeleteHandler(r.MessageCanceler, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/messages", NewBulkDeleteHandler(r.MessageCanceler, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
 }
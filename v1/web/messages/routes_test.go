@@ -24,12 +24,26 @@ var _ = Describe("Routes", func() {
 			RequestLogging:    middleware.RequestLogging{},
 			DatabaseAllocator: middleware.DatabaseAllocator{},
 			NotificationsWriteOrEmailsWriteAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.write", "emails.write"}},
+			NotificationsAdminAuthenticator:              middleware.Authenticator{Scopes: []string{"notifications.admin"}},
 
-			ErrorWriter:   mocks.NewErrorWriter(),
-			MessageFinder: mocks.NewMessageFinder(),
+			ErrorWriter:     mocks.NewErrorWriter(),
+			MessageFinder:   mocks.NewMessageFinder(),
+			MessageCanceler: mocks.NewMessageCanceler(),
 		}.Register(muxer)
 	})
 
+	It("routes GET /messages", func() {
+		request, err := http.NewRequest("GET", "/messages", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(messages.ListHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.admin"}))
+	})
+
 	It("routes GET /messages/{message_id}", func() {
 		request, err := http.NewRequest("GET", "/messages/some-message-id", nil)
 		Expect(err).NotTo(HaveOccurred())
@@ -41,4 +55,28 @@ var _ = Describe("Routes", func() {
 		authenticator := s.Middleware[2].(middleware.Authenticator)
 		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
 	})
+
+	It("routes DELETE /messages/{message_id}", func() {
+		request, err := http.NewRequest("DELETE", "/messages/some-message-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(messages.DeleteHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+	})
+
+	It("routes DELETE /messages", func() {
+		request, err := http.NewRequest("DELETE", "/messages?vcap_request_id=some-request-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(messages.BulkDeleteHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+	})
 })
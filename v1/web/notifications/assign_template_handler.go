@@ -11,11 +11,12 @@ import (
 )
 
 type TemplateAssignment struct {
-	Template string `json:"template"`
+	Template        string `json:"template"`
+	TemplateVersion int    `json:"template_version"`
 }
 
 type assignsTemplates interface {
-	AssignToNotification(connection collections.ConnectionInterface, clientID, notificationID, templateID string) error
+	AssignToNotification(connection collections.ConnectionInterface, clientID, notificationID, templateID string, version int) error
 }
 
 type AssignTemplateHandler struct {
@@ -41,7 +42,7 @@ func (h AssignTemplateHandler) ServeHTTP(w http.ResponseWriter, req *http.Reques
 	}
 
 	database := context.Get("database").(DatabaseInterface)
-	err = h.templateAssigner.AssignToNotification(database.Connection(), clientID, notificationID, templateAssignment.Template)
+	err = h.templateAssigner.AssignToNotification(database.Connection(), clientID, notificationID, templateAssignment.Template, templateAssignment.TemplateVersion)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
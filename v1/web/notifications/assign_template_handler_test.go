@@ -55,6 +55,21 @@ var _ = Describe("AssignTemplateHandler", func() {
 		Expect(templateAssigner.AssignToNotificationCall.Receives.ClientID).To(Equal("my-client"))
 		Expect(templateAssigner.AssignToNotificationCall.Receives.NotificationID).To(Equal("my-notification"))
 		Expect(templateAssigner.AssignToNotificationCall.Receives.TemplateID).To(Equal("my-template"))
+		Expect(templateAssigner.AssignToNotificationCall.Receives.Version).To(Equal(0))
+	})
+
+	It("passes along the template version to pin", func() {
+		body := []byte(`{"template": "my-template", "template_version": 3}`)
+
+		w := httptest.NewRecorder()
+		request, err := http.NewRequest("PUT", "/clients/my-client/notifications/my-notification/template", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(w, request, context)
+
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(templateAssigner.AssignToNotificationCall.Receives.TemplateID).To(Equal("my-template"))
+		Expect(templateAssigner.AssignToNotificationCall.Receives.Version).To(Equal(3))
 	})
 
 	It("delegates to the error writer when the assigner errors", func() {
@@ -13,6 +13,7 @@ import (
 
 type ClientRegistrationParams struct {
 	SourceName    string                           `json:"source_name"`
+	GenerateText  *bool                            `json:"generate_text"`
 	Notifications map[string](*NotificationStruct) `json:"notifications"`
 }
 
@@ -54,7 +55,7 @@ func strictValidateJSON(bytes []byte) error {
 	}
 
 	for key := range untypedClientRegistration {
-		if key == "source_name" {
+		if key == "source_name" || key == "generate_text" {
 			continue
 		} else if key == "notifications" {
 			if untypedClientRegistration[key] == nil {
@@ -47,6 +47,17 @@ var _ = Describe("ClientRegistrationParams", func() {
 			}))
 		})
 
+		It("parses the generate_text setting when it is present", func() {
+			parameters, err := notifications.NewClientRegistrationParams(strings.NewReader(`{"source_name": "Raptor", "generate_text": false}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parameters.GenerateText).NotTo(BeNil())
+			Expect(*parameters.GenerateText).To(BeFalse())
+
+			parameters, err = notifications.NewClientRegistrationParams(strings.NewReader(`{"source_name": "Raptor"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parameters.GenerateText).To(BeNil())
+		})
+
 		Context("error cases", func() {
 			It("returns an error when the parameters are invalid JSON", func() {
 				_, err := notifications.NewClientRegistrationParams(strings.NewReader("this is not valid JSON"))
@@ -59,9 +59,10 @@ func (h PutHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 	clientID := claims["client_id"].(string)
 
 	client := models.Client{
-		ID:          clientID,
-		Description: parameters.SourceName,
-		TemplateID:  models.DoNotSetTemplateID,
+		ID:           clientID,
+		Description:  parameters.SourceName,
+		TemplateID:   models.DoNotSetTemplateID,
+		GenerateText: parameters.GenerateText,
 	}
 
 	kinds, err := h.ValidateCriticalScopes(claims["scope"], generatedKinds, client)
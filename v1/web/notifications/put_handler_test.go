@@ -131,6 +131,21 @@ var _ = Describe("PutHandler", func() {
 			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
 		})
 
+		It("passes the generate_text setting through to Register", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"source_name":   "Raptor Containment Unit",
+				"generate_text": false,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Body = ioutil.NopCloser(bytes.NewBuffer(requestBody))
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(registrar.RegisterCall.Receives.Client.GenerateText).NotTo(BeNil())
+			Expect(*registrar.RegisterCall.Receives.Client.GenerateText).To(BeFalse())
+		})
+
 		It("does not prune kinds if they are not in the request", func() {
 			requestBody, err := json.Marshal(map[string]interface{}{
 				"source_name": "Raptor Containment Unit",
@@ -16,6 +16,10 @@ type notifyExecutor interface {
 	Execute(conn ConnectionInterface, req *http.Request, context stack.Context, guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string) (response []byte, err error)
 }
 
+type batchNotifyExecutor interface {
+	ExecuteBatch(conn ConnectionInterface, req *http.Request, context stack.Context, guid string, strategy BatchDispatcher, validator ValidatorInterface, vcapRequestID string) (response []byte, err error)
+}
+
 type errorWriter interface {
 	Write(writer http.ResponseWriter, err error)
 }
@@ -24,6 +28,10 @@ type Dispatcher interface {
 	Dispatch(dispatch services.Dispatch) ([]services.Response, error)
 }
 
+type BatchDispatcher interface {
+	Dispatch(dispatch services.Dispatch) (services.BatchResponse, error)
+}
+
 type EmailHandler struct {
 	errorWriter errorWriter
 	notify      notifyExecutor
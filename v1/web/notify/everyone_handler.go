@@ -8,11 +8,11 @@ import (
 
 type EveryoneHandler struct {
 	errorWriter errorWriter
-	notify      notifyExecutor
-	strategy    Dispatcher
+	notify      batchNotifyExecutor
+	strategy    BatchDispatcher
 }
 
-func NewEveryoneHandler(notify notifyExecutor, errWriter errorWriter, strategy Dispatcher) EveryoneHandler {
+func NewEveryoneHandler(notify batchNotifyExecutor, errWriter errorWriter, strategy BatchDispatcher) EveryoneHandler {
 	return EveryoneHandler{
 		errorWriter: errWriter,
 		notify:      notify,
@@ -24,12 +24,12 @@ func (h EveryoneHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, con
 	connection := context.Get("database").(DatabaseInterface).Connection()
 	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(connection, req, context, "", h.strategy, GUIDValidator{}, vcapRequestID)
+	output, err := h.notify.ExecuteBatch(connection, req, context, "", h.strategy, AudienceValidator{}, vcapRequestID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 }
@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	whitespaceRegexp    = regexp.MustCompile(`[ \t\r\n\f]+`)
+	trailingSpaceRegexp = regexp.MustCompile(`[ \t]+\n`)
+	blankLinesRegexp    = regexp.MustCompile(`\n{3,}`)
+
+	blockElements = map[string]bool{
+		"address": true, "article": true, "aside": true, "blockquote": true,
+		"center": true, "dd": true, "div": true, "dl": true, "dt": true,
+		"fieldset": true, "figure": true, "footer": true, "form": true,
+		"header": true, "hr": true, "main": true, "nav": true, "p": true,
+		"section": true, "table": true,
+	}
+
+	skippedElements = map[string]bool{
+		"head": true, "script": true, "style": true, "template": true, "title": true,
+	}
+)
+
+// HTMLTextConverter renders HTML as plain text for clients that cannot or
+// will not display the HTML part. Links become numbered footnotes, headings
+// are prefixed with "#" and list items keep their markers.
+type HTMLTextConverter struct{}
+
+func (HTMLTextConverter) Convert(selection *goquery.Selection) string {
+	writer := &textWriter{}
+	writer.walk(selection.Contents())
+
+	text := writer.builder.String()
+	text = trailingSpaceRegexp.ReplaceAllString(text, "\n")
+	text = blankLinesRegexp.ReplaceAllString(text, "\n\n")
+	text = strings.TrimSpace(text)
+
+	if len(writer.links) > 0 {
+		text += "\n\n"
+		for i, link := range writer.links {
+			text += fmt.Sprintf("[%d] %s\n", i+1, link)
+		}
+		text = strings.TrimSuffix(text, "\n")
+	}
+
+	return text
+}
+
+type textList struct {
+	ordered bool
+	count   int
+}
+
+type textWriter struct {
+	builder  strings.Builder
+	newlines int
+	prefix   string
+	lists    []textList
+	pre      int
+	links    []string
+}
+
+func (w *textWriter) walk(selection *goquery.Selection) {
+	selection.Each(func(_ int, node *goquery.Selection) {
+		name := goquery.NodeName(node)
+
+		switch {
+		case name == "#text":
+			w.text(node.Text())
+		case skippedElements[name]:
+		case name == "br":
+			w.breakLine(1)
+		case name == "a":
+			w.link(node)
+		case name == "img":
+			if alt, ok := node.Attr("alt"); ok {
+				w.text(alt)
+			}
+		case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+			w.breakLine(2)
+			w.text(strings.Repeat("#", int(name[1]-'0')) + " ")
+			w.walk(node.Contents())
+			w.breakLine(2)
+		case name == "ul" || name == "ol":
+			spacing := 2
+			if len(w.lists) > 0 {
+				spacing = 1
+			}
+
+			w.breakLine(spacing)
+			w.lists = append(w.lists, textList{ordered: name == "ol"})
+			w.walk(node.Contents())
+			w.lists = w.lists[:len(w.lists)-1]
+			w.breakLine(spacing)
+		case name == "li":
+			w.listItem(node)
+		case name == "pre":
+			w.breakLine(2)
+			w.pre++
+			w.walk(node.Contents())
+			w.pre--
+			w.breakLine(2)
+		case name == "tr":
+			w.breakLine(1)
+			w.walk(node.Contents())
+			w.breakLine(1)
+		case name == "td" || name == "th":
+			w.text(" ")
+			w.walk(node.Contents())
+			w.text(" ")
+		case blockElements[name]:
+			w.breakLine(2)
+			w.walk(node.Contents())
+			w.breakLine(2)
+		default:
+			w.walk(node.Contents())
+		}
+	})
+}
+
+func (w *textWriter) listItem(node *goquery.Selection) {
+	depth := len(w.lists)
+	marker := "* "
+	if depth > 0 && w.lists[depth-1].ordered {
+		w.lists[depth-1].count++
+		marker = fmt.Sprintf("%d. ", w.lists[depth-1].count)
+	}
+
+	indent := ""
+	if depth > 1 {
+		indent = strings.Repeat("  ", depth-1)
+	}
+
+	w.breakLine(1)
+	w.prefix = indent + marker
+	w.walk(node.Contents())
+	w.breakLine(1)
+}
+
+func (w *textWriter) link(node *goquery.Selection) {
+	before := w.builder.Len()
+	w.walk(node.Contents())
+	label := strings.TrimSpace(w.builder.String()[before:])
+
+	href, _ := node.Attr("href")
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || href == label || "mailto:"+label == href {
+		return
+	}
+
+	index := -1
+	for i, link := range w.links {
+		if link == href {
+			index = i
+		}
+	}
+	if index < 0 {
+		w.links = append(w.links, href)
+		index = len(w.links) - 1
+	}
+
+	w.text(fmt.Sprintf(" [%d]", index+1))
+}
+
+func (w *textWriter) text(content string) {
+	if w.pre == 0 {
+		content = whitespaceRegexp.ReplaceAllString(content, " ")
+		if w.atLineStart() || strings.HasSuffix(w.builder.String(), " ") {
+			content = strings.TrimLeft(content, " ")
+		}
+	}
+
+	if content == "" {
+		return
+	}
+
+	if w.builder.Len() > 0 && w.newlines > 0 {
+		w.builder.WriteString(strings.Repeat("\n", w.newlines))
+	}
+	w.newlines = 0
+
+	if w.prefix != "" {
+		w.builder.WriteString(w.prefix)
+		w.prefix = ""
+	}
+
+	w.builder.WriteString(content)
+}
+
+func (w *textWriter) atLineStart() bool {
+	return w.newlines > 0 || w.prefix != "" || w.builder.Len() == 0 || strings.HasSuffix(w.builder.String(), "\n")
+}
+
+func (w *textWriter) breakLine(count int) {
+	if count > w.newlines {
+		w.newlines = count
+	}
+}
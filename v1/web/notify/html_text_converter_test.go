@@ -0,0 +1,53 @@
+package notify_test
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTMLTextConverter", func() {
+	convert := func(html string) string {
+		document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		Expect(err).NotTo(HaveOccurred())
+
+		return notify.HTMLTextConverter{}.Convert(document.Find("body"))
+	}
+
+	It("separates paragraphs and collapses whitespace", func() {
+		Expect(convert("<p>Your   instance\n is down.</p><div>Restart it<br>soon.</div>")).To(Equal("Your instance is down.\n\nRestart it\nsoon."))
+	})
+
+	It("renders links as footnotes", func() {
+		text := convert(`<p>See the <a href="https://example.com/docs">docs</a> and the <a href="https://example.com/status">status page</a>.
+			Or read the <a href="https://example.com/docs">docs</a> again.</p>`)
+
+		Expect(text).To(Equal("See the docs [1] and the status page [2]. Or read the docs [1] again.\n\n[1] https://example.com/docs\n[2] https://example.com/status"))
+	})
+
+	It("does not footnote links whose text is the address", func() {
+		Expect(convert(`<a href="https://example.com">https://example.com</a> <a href="mailto:me@example.com">me@example.com</a> <a href="#top">top</a>`)).To(Equal("https://example.com me@example.com top"))
+	})
+
+	It("preserves headings and lists", func() {
+		text := convert(`<h1>Weekly report</h1>
+			<h2>Apps</h2>
+			<ul><li>web</li><li>worker<ol><li>first</li><li>second</li></ol></li></ul>`)
+
+		Expect(text).To(Equal("# Weekly report\n\n## Apps\n\n* web\n* worker\n  1. first\n  2. second"))
+	})
+
+	It("keeps preformatted text and skips scripts and styles", func() {
+		text := convert("<style>p { color: red }</style><pre>line one\n  line two</pre><script>alert(1)</script>")
+
+		Expect(text).To(Equal("line one\n  line two"))
+	})
+
+	It("uses the alt text of images", func() {
+		Expect(convert(`<p><img src="cid:logo" alt="ACME"> Invoice</p>`)).To(Equal("ACME Invoice"))
+	})
+})
@@ -43,13 +43,55 @@ type ValidatorInterface interface {
 func (h Notify) Execute(connection ConnectionInterface, req *http.Request, context stack.Context,
 	guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string) ([]byte, error) {
 
-	parameters, err := NewNotifyParams(req.Body)
+	dispatch, err := h.buildDispatch(connection, req, context, guid, validator, vcapRequestID)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	responses, err := strategy.Dispatch(dispatch)
 	if err != nil {
 		return []byte{}, err
 	}
 
+	output, err := json.Marshal(responses)
+	if err != nil {
+		panic(err)
+	}
+
+	return output, nil
+}
+
+func (h Notify) ExecuteBatch(connection ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy BatchDispatcher, validator ValidatorInterface, vcapRequestID string) ([]byte, error) {
+
+	dispatch, err := h.buildDispatch(connection, req, context, guid, validator, vcapRequestID)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	response, err := strategy.Dispatch(dispatch)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	output, err := json.Marshal(response)
+	if err != nil {
+		panic(err)
+	}
+
+	return output, nil
+}
+
+func (h Notify) buildDispatch(connection ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, validator ValidatorInterface, vcapRequestID string) (services.Dispatch, error) {
+
+	parameters, err := NewNotifyParams(req.Body)
+	if err != nil {
+		return services.Dispatch{}, err
+	}
+
 	if !validator.Validate(&parameters) {
-		return []byte{}, webutil.ValidationError{Err: errors.New(strings.Join(parameters.Errors, ","))}
+		return services.Dispatch{}, webutil.ValidationError{Err: errors.New(strings.Join(parameters.Errors, ","))}
 	}
 
 	requestReceivedTime, ok := context.Get(RequestReceivedTime).(time.Time)
@@ -62,27 +104,64 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 
 	tokenIssuerURL, err := url.Parse(claims["iss"].(string))
 	if err != nil {
-		return []byte{}, errors.New("Token issuer URL invalid")
+		return services.Dispatch{}, errors.New("Token issuer URL invalid")
 	}
 	uaaHost := tokenIssuerURL.Scheme + "://" + tokenIssuerURL.Host
 
 	client, kind, err := h.finder.ClientAndKind(context.Get("database").(DatabaseInterface), clientID, parameters.KindID)
 	if err != nil {
-		return []byte{}, err
+		return services.Dispatch{}, err
 	}
 
 	if kind.Critical && !h.hasCriticalNotificationsWriteScope(claims["scope"]) {
-		return []byte{}, webutil.NewCriticalNotificationError(kind.ID)
+		return services.Dispatch{}, webutil.NewCriticalNotificationError(kind.ID)
 	}
 
 	err = h.registrar.Register(connection, client, []models.Kind{kind})
 	if err != nil {
-		return []byte{}, err
+		return services.Dispatch{}, err
 	}
 
-	var responses []services.Response
+	text := parameters.Text
+	if text == "" && client.ShouldGenerateText() {
+		text = parameters.GeneratedText
+	}
 
-	responses, err = strategy.Dispatch(services.Dispatch{
+	var localizations map[string]services.Localization
+	for locale, localization := range parameters.Localizations {
+		if localizations == nil {
+			localizations = map[string]services.Localization{}
+		}
+
+		localizedText := localization.Text
+		if localizedText == "" && client.ShouldGenerateText() {
+			localizedText = localization.GeneratedText
+		}
+
+		localizations[locale] = services.Localization{
+			Subject: localization.Subject,
+			Text:    localizedText,
+			HTML: services.HTML{
+				BodyContent:    localization.ParsedHTML.BodyContent,
+				BodyAttributes: localization.ParsedHTML.BodyAttributes,
+				Head:           localization.ParsedHTML.Head,
+				Doctype:        localization.ParsedHTML.Doctype,
+			},
+		}
+	}
+
+	var attachments []services.Attachment
+	for _, attachment := range parameters.Attachments {
+		attachments = append(attachments, services.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+			Inline:      attachment.Inline,
+			ContentID:   attachment.ContentID,
+		})
+	}
+
+	return services.Dispatch{
 		GUID:       guid,
 		Connection: connection,
 		Role:       parameters.Role,
@@ -100,10 +179,14 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 			ReceiptTime: requestReceivedTime,
 		},
 		Message: services.DispatchMessage{
-			To:      parameters.To,
-			ReplyTo: parameters.ReplyTo,
-			Subject: parameters.Subject,
-			Text:    parameters.Text,
+			To:            parameters.To,
+			ReplyTo:       parameters.ReplyTo,
+			Subject:       parameters.Subject,
+			Text:          text,
+			Data:          parameters.Data,
+			SendAt:        parameters.ParsedSendAt,
+			Attachments:   attachments,
+			Localizations: localizations,
 			HTML: services.HTML{
 				BodyContent:    parameters.ParsedHTML.BodyContent,
 				BodyAttributes: parameters.ParsedHTML.BodyAttributes,
@@ -111,17 +194,7 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 				Doctype:        parameters.ParsedHTML.Doctype,
 			},
 		},
-	})
-	if err != nil {
-		return []byte{}, err
-	}
-
-	output, err := json.Marshal(responses)
-	if err != nil {
-		panic(err)
-	}
-
-	return output, nil
+	}, nil
 }
 
 func (h Notify) hasCriticalNotificationsWriteScope(elements interface{}) bool {
@@ -2,12 +2,15 @@ package notify
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 )
 
@@ -26,13 +29,41 @@ type NotifyParams struct {
 	KindID  string `json:"kind_id"`
 	To      string `json:"to"`
 	Role    string `json:"role"`
+	SendAt  string `json:"send_at"`
+
+	Data          map[string]interface{}  `json:"data"`
+	Attachments   []Attachment            `json:"attachments"`
+	Localizations map[string]Localization `json:"localizations"`
 
 	ParsedHTML        HTML
+	ParsedSendAt      time.Time
+	GeneratedText     string
 	KindDescription   string
 	SourceDescription string
 	Errors            []string
 }
 
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        string `json:"data"`
+	Inline      bool   `json:"inline"`
+	ContentID   string `json:"content_id"`
+
+	Content []byte `json:"-"`
+}
+
+// Localization is translated content for recipients who prefer the locale it
+// is keyed by. Fields that are left empty keep the content of the notification.
+type Localization struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	RawHTML string `json:"html"`
+
+	ParsedHTML    HTML   `json:"-"`
+	GeneratedText string `json:"-"`
+}
+
 type HTML struct {
 	BodyContent    string
 	BodyAttributes string
@@ -53,6 +84,14 @@ func NewNotifyParams(body io.ReadCloser) (NotifyParams, error) {
 		return notify, err
 	}
 
+	notify.parseSendAt()
+	notify.parseAttachments()
+
+	err = notify.parseLocalizations()
+	if err != nil {
+		return notify, err
+	}
+
 	return notify, nil
 }
 
@@ -73,15 +112,84 @@ func (notify *NotifyParams) parseRequestBody(body io.ReadCloser) error {
 func (notify *NotifyParams) FormatEmailAndExtractHTML() error {
 	notify.To = EmailFormatter{}.Format(notify.To)
 
-	doctype, head, bodyContent, bodyAttributes, err := HTMLExtractor{}.Extract(notify.RawHTML)
+	var err error
+	notify.ParsedHTML, notify.GeneratedText, err = extractHTML(notify.RawHTML)
+
+	return err
+}
+
+func extractHTML(rawHTML string) (HTML, string, error) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
+	if err != nil {
+		return HTML{}, "", err
+	}
+
+	doctype, head, bodyContent, bodyAttributes, err := HTMLExtractor{}.ExtractDocument(rawHTML, document)
 	if err != nil {
-		return err
+		return HTML{}, "", err
+	}
+
+	var generatedText string
+	if bodyContent != "" {
+		generatedText = HTMLTextConverter{}.Convert(document.Find("body"))
 	}
 
-	notify.ParsedHTML.Doctype = doctype
-	notify.ParsedHTML.Head = head
-	notify.ParsedHTML.BodyContent = bodyContent
-	notify.ParsedHTML.BodyAttributes = bodyAttributes
+	return HTML{
+		Doctype:        doctype,
+		Head:           head,
+		BodyContent:    bodyContent,
+		BodyAttributes: bodyAttributes,
+	}, generatedText, nil
+}
+
+func (notify *NotifyParams) parseSendAt() {
+	if notify.SendAt == "" {
+		return
+	}
+
+	sendAt, err := time.Parse(time.RFC3339, notify.SendAt)
+	if err != nil {
+		return
+	}
+
+	notify.ParsedSendAt = sendAt
+}
+
+func (notify *NotifyParams) parseAttachments() {
+	for i, attachment := range notify.Attachments {
+		content, err := base64.StdEncoding.DecodeString(attachment.Data)
+		if err != nil {
+			continue
+		}
+
+		notify.Attachments[i].Content = content
+	}
+}
+
+// parseLocalizations keys the localizations by the canonical form of their
+// language tag. Tags that cannot be parsed are kept as they are for the
+// validators to report.
+func (notify *NotifyParams) parseLocalizations() error {
+	if len(notify.Localizations) == 0 {
+		return nil
+	}
+
+	localizations := map[string]Localization{}
+	for locale, localization := range notify.Localizations {
+		var err error
+		localization.ParsedHTML, localization.GeneratedText, err = extractHTML(localization.RawHTML)
+		if err != nil {
+			return err
+		}
+
+		if canonical, err := common.CanonicalLocale(locale); err == nil {
+			locale = canonical
+		}
+
+		localizations[locale] = localization
+	}
+
+	notify.Localizations = localizations
 
 	return nil
 }
@@ -108,13 +216,17 @@ func (EmailFormatter) Format(email string) string {
 
 type HTMLExtractor struct{}
 
-func (HTMLExtractor) Extract(rawHTML string) (string, string, string, string, error) {
+func (extractor HTMLExtractor) Extract(rawHTML string) (string, string, string, string, error) {
 	reader := strings.NewReader(rawHTML)
 	document, err := goquery.NewDocumentFromReader(reader)
 	if err != nil {
 		return "", "", "", "", err
 	}
 
+	return extractor.ExtractDocument(rawHTML, document)
+}
+
+func (HTMLExtractor) ExtractDocument(rawHTML string, document *goquery.Document) (string, string, string, string, error) {
 	doctype, err := extractDoctype(rawHTML)
 	if err != nil {
 		return "", "", "", "", err
@@ -4,8 +4,10 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -30,6 +32,110 @@ var _ = Describe("NotifyParams", func() {
 			Expect(parameters.Text).To(Equal("Contents of the email message"))
 		})
 
+		It("parses the template data", func() {
+			parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+				"kind_id": "test_email",
+				"text": "Contents of the email message",
+				"data": {
+					"app_name": "my-app",
+					"instances": 3
+				}
+			}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.Data).To(Equal(map[string]interface{}{
+				"app_name":  "my-app",
+				"instances": float64(3),
+			}))
+		})
+
+		It("parses the send_at timestamp", func() {
+			parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+				"kind_id": "test_email",
+				"text": "Contents of the email message",
+				"send_at": "2015-06-08T22:00:00-07:00"
+			}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.SendAt).To(Equal("2015-06-08T22:00:00-07:00"))
+			Expect(parameters.ParsedSendAt.Equal(time.Date(2015, time.June, 9, 5, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("leaves the parsed send_at empty when it is not a valid timestamp", func() {
+			parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+				"kind_id": "test_email",
+				"text": "Contents of the email message",
+				"send_at": "tomorrow"
+			}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.SendAt).To(Equal("tomorrow"))
+			Expect(parameters.ParsedSendAt.IsZero()).To(BeTrue())
+		})
+
+		It("decodes the attachments", func() {
+			parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+				"kind_id": "test_email",
+				"html": "<img src=\"cid:logo\">",
+				"attachments": [
+					{"filename": "logo.png", "content_type": "image/png", "data": "cG5nIGJ5dGVz", "inline": true, "content_id": "logo"},
+					{"filename": "invoice.pdf", "data": "not base64!"}
+				]
+			}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.Attachments).To(Equal([]notify.Attachment{
+				{
+					Filename:    "logo.png",
+					ContentType: "image/png",
+					Data:        "cG5nIGJ5dGVz",
+					Inline:      true,
+					ContentID:   "logo",
+					Content:     []byte("png bytes"),
+				},
+				{
+					Filename: "invoice.pdf",
+					Data:     "not base64!",
+				},
+			}))
+		})
+
+		It("parses the localizations under the canonical form of their language tag", func() {
+			parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+				"kind_id": "test_email",
+				"html": "<p>Hello</p>",
+				"localizations": {
+					"fr_ca": {"subject": "Bonjour", "html": "<head><title>Salut</title></head><body class=\"fr\"><p>Bonjour</p></body>"},
+					"not a locale": {"text": "Hallo"}
+				}
+			}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.Localizations).To(Equal(map[string]notify.Localization{
+				"fr-CA": {
+					Subject: "Bonjour",
+					RawHTML: `<head><title>Salut</title></head><body class="fr"><p>Bonjour</p></body>`,
+					ParsedHTML: notify.HTML{
+						BodyContent:    "<p>Bonjour</p>",
+						BodyAttributes: `class="fr"`,
+						Head:           "<title>Salut</title>",
+					},
+					GeneratedText: "Bonjour",
+				},
+				"not a locale": {
+					Text: "Hallo",
+				},
+			}))
+		})
+
+		It("returns a parse error when the template data is not an object", func() {
+			_, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+				"kind_id": "test_email",
+				"data": "not-an-object"
+			}`)))
+			Expect(err).To(Equal(webutil.ParseError{}))
+		})
+
 		It("does not blow up if the request body is empty", func() {
 			Expect(func() {
 				notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader("")))
@@ -1,8 +1,23 @@
 package notify
 
-import "regexp"
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"sort"
 
-var kindIDFormat = regexp.MustCompile(`^[0-9a-zA-Z_\-.]+$`)
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+)
+
+const (
+	MaxAttachments     = 10
+	MaxAttachmentsSize = 2 << 20
+)
+
+var (
+	kindIDFormat    = regexp.MustCompile(`^[0-9a-zA-Z_\-.]+$`)
+	contentIDFormat = regexp.MustCompile(`^[0-9a-zA-Z_\-.@]+$`)
+)
 
 type EmailValidator struct{}
 
@@ -21,6 +36,10 @@ func (validator EmailValidator) Validate(notify *NotifyParams) bool {
 		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
 	}
 
+	checkSendAtField(notify)
+	checkAttachmentsField(notify)
+	checkLocalizationsField(notify)
+
 	return len(notify.Errors) == 0
 }
 
@@ -39,6 +58,46 @@ func (validator GUIDValidator) Validate(notify *NotifyParams) bool {
 		notify.Errors = append(notify.Errors, `"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)
 	}
 
+	checkSendAtField(notify)
+	checkAttachmentsField(notify)
+	checkLocalizationsField(notify)
+
+	return len(notify.Errors) == 0
+}
+
+// AudienceValidator checks notifications sent to spaces, organizations,
+// scopes and everyone. Every recipient gets a job of their own, so these
+// audiences cannot carry attachments.
+type AudienceValidator struct{}
+
+func (validator AudienceValidator) Validate(notify *NotifyParams) bool {
+	GUIDValidator{}.Validate(notify)
+
+	if len(notify.Attachments) > 0 {
+		notify.Errors = append(notify.Errors, `"attachments" can only be sent to a user or an email address`)
+	}
+
+	return len(notify.Errors) == 0
+}
+
+// PreviewValidator checks the parts of a notification that affect how it
+// renders; the recipient and kind are supplied by the preview request.
+type PreviewValidator struct{}
+
+func (validator PreviewValidator) Validate(notify *NotifyParams) bool {
+	notify.Errors = []string{}
+
+	if missingTextOrHTMLFields(notify) {
+		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
+	}
+
+	if (GUIDValidator{}).invalidRoleField(notify.Role) {
+		notify.Errors = append(notify.Errors, `"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)
+	}
+
+	checkAttachmentsField(notify)
+	checkLocalizationsField(notify)
+
 	return len(notify.Errors) == 0
 }
 
@@ -46,6 +105,79 @@ func missingTextOrHTMLFields(notify *NotifyParams) bool {
 	return notify.Text == "" && notify.ParsedHTML.BodyContent == ""
 }
 
+func checkSendAtField(notify *NotifyParams) {
+	if notify.SendAt != "" && notify.ParsedSendAt.IsZero() {
+		notify.Errors = append(notify.Errors, `"send_at" must be an RFC 3339 timestamp`)
+	}
+}
+
+// The decoded attachments are stored in the delivery job, so the limits are
+// kept small.
+func checkAttachmentsField(notify *NotifyParams) {
+	if len(notify.Attachments) > MaxAttachments {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"attachments" must not contain more than %d files`, MaxAttachments))
+	}
+
+	size := 0
+	for i, attachment := range notify.Attachments {
+		field := fmt.Sprintf(`"attachments[%d]`, i)
+
+		if attachment.Filename == "" {
+			notify.Errors = append(notify.Errors, field+`.filename" is a required field`)
+		}
+
+		if attachment.ContentType != "" {
+			_, _, err := mime.ParseMediaType(attachment.ContentType)
+			if err != nil {
+				notify.Errors = append(notify.Errors, field+`.content_type" is improperly formatted`)
+			}
+		}
+
+		if attachment.Data == "" {
+			notify.Errors = append(notify.Errors, field+`.data" is a required field`)
+		} else if attachment.Content == nil {
+			notify.Errors = append(notify.Errors, field+`.data" must be base64 encoded`)
+		}
+
+		if attachment.Inline && attachment.ContentID == "" {
+			notify.Errors = append(notify.Errors, field+`.content_id" is required for inline attachments`)
+		}
+
+		if attachment.ContentID != "" && !contentIDFormat.MatchString(attachment.ContentID) {
+			notify.Errors = append(notify.Errors, field+`.content_id" is improperly formatted`)
+		}
+
+		size += len(attachment.Content)
+	}
+
+	if size > MaxAttachmentsSize {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"attachments" must not exceed %d bytes in total`, MaxAttachmentsSize))
+	}
+}
+
+func checkLocalizationsField(notify *NotifyParams) {
+	var locales []string
+	for locale := range notify.Localizations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		field := fmt.Sprintf(`"localizations.%s"`, locale)
+
+		_, err := common.CanonicalLocale(locale)
+		if err != nil {
+			notify.Errors = append(notify.Errors, field+` is not a valid BCP 47 language tag`)
+			continue
+		}
+
+		localization := notify.Localizations[locale]
+		if localization.Subject == "" && localization.Text == "" && localization.ParsedHTML.BodyContent == "" {
+			notify.Errors = append(notify.Errors, field+` must supply "subject", "text" or "html"`)
+		}
+	}
+}
+
 func (validator GUIDValidator) invalidRoleField(roleName string) bool {
 	if roleName == "" {
 		return false
@@ -1,6 +1,8 @@
 package notify_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -56,6 +58,81 @@ var _ = Describe("Validator", func() {
 					Expect(params.Errors).To(ContainElement(`"to" is improperly formatted`))
 				})
 			})
+
+			It("validates that send_at is a timestamp", func() {
+				params.SendAt = "2015-06-08T22:00:00Z"
+				params.ParsedSendAt = time.Date(2015, time.June, 8, 22, 0, 0, 0, time.UTC)
+
+				Expect(validator.Validate(params)).To(BeTrue())
+
+				params.ParsedSendAt = time.Time{}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"send_at" must be an RFC 3339 timestamp`))
+			})
+
+			Context("when the params include attachments", func() {
+				BeforeEach(func() {
+					params.Attachments = []notify.Attachment{
+						{
+							Filename:    "invoice.pdf",
+							ContentType: "application/pdf",
+							Data:        "JVBERi0xLjQ=",
+							Content:     []byte("%PDF-1.4"),
+						},
+						{
+							Filename:    "logo.png",
+							ContentType: "image/png",
+							Data:        "cG5n",
+							Content:     []byte("png"),
+							Inline:      true,
+							ContentID:   "logo",
+						},
+					}
+				})
+
+				It("accepts well formed attachments", func() {
+					Expect(validator.Validate(params)).To(BeTrue())
+				})
+
+				It("validates the attachment fields", func() {
+					params.Attachments[0].Filename = ""
+					params.Attachments[0].ContentType = "not a content type"
+					params.Attachments[0].Content = nil
+					params.Attachments[1].ContentID = ""
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(params.Errors).To(ConsistOf(
+						`"attachments[0].filename" is a required field`,
+						`"attachments[0].content_type" is improperly formatted`,
+						`"attachments[0].data" must be base64 encoded`,
+						`"attachments[1].content_id" is required for inline attachments`,
+					))
+				})
+
+				It("validates that the content ID is safe to use in a header", func() {
+					params.Attachments[1].ContentID = "logo>\r\nBcc: someone@example.com"
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(params.Errors).To(ConsistOf(`"attachments[1].content_id" is improperly formatted`))
+				})
+
+				It("limits the number of attachments", func() {
+					for len(params.Attachments) <= notify.MaxAttachments {
+						params.Attachments = append(params.Attachments, params.Attachments[0])
+					}
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(params.Errors).To(ConsistOf(`"attachments" must not contain more than 10 files`))
+				})
+
+				It("limits the total size of the attachments", func() {
+					params.Attachments[0].Content = make([]byte, notify.MaxAttachmentsSize)
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(params.Errors).To(ConsistOf(`"attachments" must not exceed 2097152 bytes in total`))
+				})
+			})
 		})
 	})
 
@@ -124,6 +201,108 @@ var _ = Describe("Validator", func() {
 				Expect(len(params.Errors)).To(Equal(1))
 				Expect(params.Errors).To(ContainElement(`"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`))
 			})
+
+			It("validates that send_at is a timestamp", func() {
+				params.SendAt = "tomorrow"
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"send_at" must be an RFC 3339 timestamp`))
+			})
+
+			It("validates the attachments", func() {
+				params.Attachments = []notify.Attachment{
+					{Filename: "invoice.pdf", Data: "%%%"},
+				}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"attachments[0].data" must be base64 encoded`))
+			})
+
+			It("validates the localizations", func() {
+				params.Localizations = map[string]notify.Localization{
+					"fr":           {Subject: "Bonjour"},
+					"de":           {},
+					"not a locale": {Text: "Hallo"},
+				}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(Equal([]string{
+					`"localizations.de" must supply "subject", "text" or "html"`,
+					`"localizations.not a locale" is not a valid BCP 47 language tag`,
+				}))
+			})
+		})
+	})
+
+	Describe("AudienceValidator", func() {
+		var (
+			params    *notify.NotifyParams
+			validator notify.AudienceValidator
+		)
+
+		BeforeEach(func() {
+			params = &notify.NotifyParams{
+				KindID:  "test_email",
+				Subject: "Summary of contents",
+				Text:    "Contents of the email message",
+			}
+			validator = notify.AudienceValidator{}
+		})
+
+		It("validates the same fields as the GUIDValidator", func() {
+			Expect(validator.Validate(params)).To(BeTrue())
+			Expect(params.Errors).To(BeEmpty())
+
+			params.KindID = ""
+			params.SendAt = "tomorrow"
+
+			Expect(validator.Validate(params)).To(BeFalse())
+			Expect(params.Errors).To(ConsistOf(
+				`"kind_id" is a required field`,
+				`"send_at" must be an RFC 3339 timestamp`,
+			))
+		})
+
+		It("rejects attachments", func() {
+			params.Attachments = []notify.Attachment{
+				{Filename: "invoice.pdf", Data: "JVBERi0xLjQK", Content: []byte("%PDF-1.4\n")},
+			}
+
+			Expect(validator.Validate(params)).To(BeFalse())
+			Expect(params.Errors).To(ConsistOf(`"attachments" can only be sent to a user or an email address`))
+		})
+	})
+
+	Describe("PreviewValidator", func() {
+		var (
+			params    *notify.NotifyParams
+			validator notify.PreviewValidator
+		)
+
+		BeforeEach(func() {
+			params = &notify.NotifyParams{
+				Text: "my silly text",
+			}
+			validator = notify.PreviewValidator{}
+		})
+
+		It("does not require a recipient or kind", func() {
+			Expect(validator.Validate(params)).To(BeTrue())
+			Expect(params.Errors).To(BeEmpty())
+		})
+
+		It("validates the text, role and attachments fields", func() {
+			params.Text = ""
+			params.Role = "SpaceManager"
+			params.Attachments = []notify.Attachment{{Data: "not base64"}}
+
+			Expect(validator.Validate(params)).To(BeFalse())
+			Expect(params.Errors).To(ConsistOf(
+				`"text" or "html" fields must be supplied`,
+				`"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`,
+				`"attachments[0].filename" is a required field`,
+				`"attachments[0].data" must be base64 encoded`,
+			))
 		})
 	})
 })
@@ -146,6 +146,108 @@ var _ = Describe("Notify", func() {
 				}))
 			})
 
+			It("passes the template data through to the strategy", func() {
+				request, err := http.NewRequest("POST", "/spaces/space-001", strings.NewReader(`{
+					"kind_id": "test_email",
+					"text": "This is the plain text body of the email",
+					"data": {"app_name": "my-app"}
+				}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCallsCount).To(Equal(1))
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Data).To(Equal(map[string]interface{}{
+					"app_name": "my-app",
+				}))
+			})
+
+			It("passes the send_at time through to the strategy", func() {
+				request, err := http.NewRequest("POST", "/spaces/space-001", strings.NewReader(`{
+					"kind_id": "test_email",
+					"text": "This is the plain text body of the email",
+					"send_at": "2015-06-08T22:00:00Z"
+				}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCallsCount).To(Equal(1))
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.SendAt).To(Equal(time.Date(2015, time.June, 8, 22, 0, 0, 0, time.UTC)))
+			})
+
+			It("passes the decoded attachments through to the strategy", func() {
+				request, err := http.NewRequest("POST", "/spaces/space-001", strings.NewReader(`{
+					"kind_id": "test_email",
+					"text": "This is the plain text body of the email",
+					"attachments": [{"filename": "invoice.pdf", "content_type": "application/pdf", "data": "JVBERi0xLjQ="}]
+				}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCallsCount).To(Equal(1))
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Attachments).To(Equal([]services.Attachment{
+					{Filename: "invoice.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4")},
+				}))
+			})
+
+			It("passes the localizations through to the strategy", func() {
+				request, err := http.NewRequest("POST", "/spaces/space-001", strings.NewReader(`{
+					"kind_id": "test_email",
+					"text": "Your app is down",
+					"localizations": {
+						"fr": {"subject": "Alerte", "html": "<p>Votre application est en panne</p>"}
+					}
+				}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Localizations).To(Equal(map[string]services.Localization{
+					"fr": {
+						Subject: "Alerte",
+						Text:    "Votre application est en panne",
+						HTML: services.HTML{
+							BodyContent: "<p>Votre application est en panne</p>",
+						},
+					},
+				}))
+			})
+
+			Context("when only html is supplied", func() {
+				BeforeEach(func() {
+					var err error
+					request, err = http.NewRequest("POST", "/spaces/space-001", strings.NewReader(`{
+						"kind_id": "test_email",
+						"html": "<p>Your <a href=\"https://example.com/app\">app</a> is down</p>"
+					}`))
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("generates the text from the html", func() {
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Text).To(Equal("Your app [1] is down\n\n[1] https://example.com/app"))
+				})
+
+				It("leaves the text empty when the client has opted out", func() {
+					generateText := false
+					client.GenerateText = &generateText
+					finder.ClientAndKindCall.Returns.Client = client
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Text).To(BeEmpty())
+				})
+			})
+
 			It("registers the client and kind", func() {
 				_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
 				Expect(err).NotTo(HaveOccurred())
@@ -159,6 +261,43 @@ var _ = Describe("Notify", func() {
 				Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf([]models.Kind{kind}))
 			})
 
+			Context("when executing a batch", func() {
+				var batchStrategy *mocks.BatchStrategy
+
+				BeforeEach(func() {
+					batchStrategy = mocks.NewBatchStrategy()
+					batchStrategy.DispatchCall.Returns.Response = services.BatchResponse{
+						BatchID:       "some-batch-id",
+						Status:        "pending",
+						VCAPRequestID: "some-request-id",
+					}
+				})
+
+				It("delegates to the strategy and returns the batch", func() {
+					output, err := handler.ExecuteBatch(conn, request, context, "org-001", batchStrategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(output).To(MatchJSON(`{
+						"batch_id": "some-batch-id",
+						"status": "pending",
+						"vcap_request_id": "some-request-id"
+					}`))
+
+					dispatch := batchStrategy.DispatchCall.Receives.Dispatch
+					Expect(dispatch.GUID).To(Equal("org-001"))
+					Expect(dispatch.Connection).To(Equal(conn))
+					Expect(dispatch.Client.ID).To(Equal("mister-client"))
+					Expect(dispatch.Kind.ID).To(Equal("test_email"))
+					Expect(dispatch.UAAHost).To(Equal("http://zone-uaa-host"))
+				})
+
+				It("returns the error when the strategy fails", func() {
+					batchStrategy.DispatchCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := handler.ExecuteBatch(conn, request, context, "org-001", batchStrategy, validator, vcapRequestID)
+					Expect(err).To(Equal(errors.New("BOOM!")))
+				})
+			})
+
 			Context("failure cases", func() {
 				Context("when validating params", func() {
 					It("returns a error response when params are missing", func() {
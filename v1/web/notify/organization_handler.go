@@ -9,11 +9,11 @@ import (
 
 type OrganizationHandler struct {
 	errorWriter errorWriter
-	notify      notifyExecutor
-	strategy    Dispatcher
+	notify      batchNotifyExecutor
+	strategy    BatchDispatcher
 }
 
-func NewOrganizationHandler(notify notifyExecutor, errWriter errorWriter, strategy Dispatcher) OrganizationHandler {
+func NewOrganizationHandler(notify batchNotifyExecutor, errWriter errorWriter, strategy BatchDispatcher) OrganizationHandler {
 	return OrganizationHandler{
 		errorWriter: errWriter,
 		notify:      notify,
@@ -26,12 +26,12 @@ func (h OrganizationHandler) ServeHTTP(w http.ResponseWriter, req *http.Request,
 	orgGUID := strings.TrimPrefix(req.URL.Path, "/organizations/")
 	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, orgGUID, h.strategy, GUIDValidator{}, vcapRequestID)
+	output, err := h.notify.ExecuteBatch(conn, req, context, orgGUID, h.strategy, AudienceValidator{}, vcapRequestID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 }
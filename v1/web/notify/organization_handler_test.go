@@ -25,13 +25,13 @@ var _ = Describe("OrganizationHandler", func() {
 			context     stack.Context
 			connection  *mocks.Connection
 			errorWriter *mocks.ErrorWriter
-			strategy    *mocks.Strategy
+			strategy    *mocks.BatchStrategy
 		)
 
 		BeforeEach(func() {
 			writer = httptest.NewRecorder()
 			request = &http.Request{URL: &url.URL{Path: "/organizations/org-001"}}
-			strategy = mocks.NewStrategy()
+			strategy = mocks.NewBatchStrategy()
 			errorWriter = mocks.NewErrorWriter()
 
 			connection = mocks.NewConnection()
@@ -46,35 +46,35 @@ var _ = Describe("OrganizationHandler", func() {
 			handler = notify.NewOrganizationHandler(notifyObj, errorWriter, strategy)
 		})
 
-		Context("when the notifyObj.Execute returns a successful response", func() {
+		Context("when the notifyObj.ExecuteBatch returns a successful response", func() {
 			It("returns the JSON representation of the response", func() {
-				notifyObj.ExecuteCall.Returns.Response = []byte("whatever")
+				notifyObj.ExecuteBatchCall.Returns.Response = []byte("whatever")
 
 				handler.ServeHTTP(writer, request, context)
 
-				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(writer.Code).To(Equal(http.StatusAccepted))
 				Expect(writer.Body.String()).To(Equal("whatever"))
 			})
 
 			It("delegates to the notifyObj object with the correct arguments", func() {
 				handler.ServeHTTP(writer, request, context)
 
-				Expect(reflect.ValueOf(notifyObj.ExecuteCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
-				Expect(notifyObj.ExecuteCall.Receives.Request).To(Equal(request))
-				Expect(notifyObj.ExecuteCall.Receives.Context).To(Equal(context))
-				Expect(notifyObj.ExecuteCall.Receives.GUID).To(Equal("org-001"))
-				Expect(notifyObj.ExecuteCall.Receives.Strategy).To(Equal(strategy))
-				Expect(notifyObj.ExecuteCall.Receives.Validator).To(BeAssignableToTypeOf(notify.GUIDValidator{}))
-				Expect(notifyObj.ExecuteCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+				Expect(reflect.ValueOf(notifyObj.ExecuteBatchCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+				Expect(notifyObj.ExecuteBatchCall.Receives.Request).To(Equal(request))
+				Expect(notifyObj.ExecuteBatchCall.Receives.Context).To(Equal(context))
+				Expect(notifyObj.ExecuteBatchCall.Receives.GUID).To(Equal("org-001"))
+				Expect(notifyObj.ExecuteBatchCall.Receives.Strategy).To(Equal(strategy))
+				Expect(notifyObj.ExecuteBatchCall.Receives.Validator).To(BeAssignableToTypeOf(notify.AudienceValidator{}))
+				Expect(notifyObj.ExecuteBatchCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
 			})
 		})
 
-		Context("when the notifyObj.Execute returns an error", func() {
+		Context("when the notifyObj.ExecuteBatch returns an error", func() {
 			It("propagates the error", func() {
-				notifyObj.ExecuteCall.Returns.Error = errors.New("the error")
+				notifyObj.ExecuteBatchCall.Returns.Error = errors.New("the error")
 
 				handler.ServeHTTP(writer, request, context)
-				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notifyObj.ExecuteCall.Returns.Error))
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notifyObj.ExecuteBatchCall.Returns.Error))
 			})
 		})
 	})
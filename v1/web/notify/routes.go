@@ -6,6 +6,11 @@ type muxer interface {
 	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
 }
 
+type notifier interface {
+	notifyExecutor
+	batchNotifyExecutor
+}
+
 type Routes struct {
 	RequestCounter                  stack.Middleware
 	RequestLogging                  stack.Middleware
@@ -13,13 +18,13 @@ type Routes struct {
 	NotificationsWriteAuthenticator stack.Middleware
 	EmailsWriteAuthenticator        stack.Middleware
 
-	Notify               notifyExecutor
+	Notify               notifier
 	ErrorWriter          errorWriter
 	UserStrategy         Dispatcher
 	SpaceStrategy        Dispatcher
-	OrganizationStrategy Dispatcher
-	EveryoneStrategy     Dispatcher
-	UAAScopeStrategy     Dispatcher
+	OrganizationStrategy BatchDispatcher
+	EveryoneStrategy     BatchDispatcher
+	UAAScopeStrategy     BatchDispatcher
 	EmailStrategy        Dispatcher
 }
 
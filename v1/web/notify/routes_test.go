@@ -24,9 +24,9 @@ var _ = Describe("Routes", func() {
 			ErrorWriter:          mocks.NewErrorWriter(),
 			UserStrategy:         mocks.NewStrategy(),
 			SpaceStrategy:        mocks.NewStrategy(),
-			OrganizationStrategy: mocks.NewStrategy(),
-			EveryoneStrategy:     mocks.NewStrategy(),
-			UAAScopeStrategy:     mocks.NewStrategy(),
+			OrganizationStrategy: mocks.NewBatchStrategy(),
+			EveryoneStrategy:     mocks.NewBatchStrategy(),
+			UAAScopeStrategy:     mocks.NewBatchStrategy(),
 			EmailStrategy:        mocks.NewStrategy(),
 
 			RequestCounter:                  middleware.RequestCounter{},
@@ -26,7 +26,7 @@ func (h SpaceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, contex
 	spaceGUID := strings.TrimPrefix(req.URL.Path, "/spaces/")
 	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, spaceGUID, h.strategy, GUIDValidator{}, vcapRequestID)
+	output, err := h.notify.Execute(conn, req, context, spaceGUID, h.strategy, AudienceValidator{}, vcapRequestID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
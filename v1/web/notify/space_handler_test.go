@@ -63,7 +63,7 @@ var _ = Describe("SpaceHandler", func() {
 				Expect(notifyObj.ExecuteCall.Receives.Context).To(Equal(context))
 				Expect(notifyObj.ExecuteCall.Receives.GUID).To(Equal("space-001"))
 				Expect(notifyObj.ExecuteCall.Receives.Strategy).To(Equal(strategy))
-				Expect(notifyObj.ExecuteCall.Receives.Validator).To(BeAssignableToTypeOf(notify.GUIDValidator{}))
+				Expect(notifyObj.ExecuteCall.Receives.Validator).To(BeAssignableToTypeOf(notify.AudienceValidator{}))
 				Expect(notifyObj.ExecuteCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
 			})
 		})
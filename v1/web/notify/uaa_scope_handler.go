@@ -9,11 +9,11 @@ import (
 
 type UAAScopeHandler struct {
 	errorWriter errorWriter
-	notify      notifyExecutor
-	strategy    Dispatcher
+	notify      batchNotifyExecutor
+	strategy    BatchDispatcher
 }
 
-func NewUAAScopeHandler(notify notifyExecutor, errWriter errorWriter, strategy Dispatcher) UAAScopeHandler {
+func NewUAAScopeHandler(notify batchNotifyExecutor, errWriter errorWriter, strategy BatchDispatcher) UAAScopeHandler {
 	return UAAScopeHandler{
 		errorWriter: errWriter,
 		notify:      notify,
@@ -26,12 +26,12 @@ func (h UAAScopeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, con
 	scope := strings.TrimPrefix(req.URL.Path, "/uaa_scopes/")
 	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, scope, h.strategy, GUIDValidator{}, vcapRequestID)
+	output, err := h.notify.ExecuteBatch(conn, req, context, scope, h.strategy, AudienceValidator{}, vcapRequestID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 }
@@ -0,0 +1,23 @@
+package outbox
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type ClearHandler struct {
+	outbox outbox
+}
+
+func NewClearHandler(outbox outbox) ClearHandler {
+	return ClearHandler{
+		outbox: outbox,
+	}
+}
+
+func (h ClearHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	writeJSON(w, http.StatusOK, map[string]int{
+		"deleted": h.outbox.Clear(),
+	})
+}
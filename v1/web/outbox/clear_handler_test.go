@@ -0,0 +1,30 @@
+package outbox_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/outbox"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClearHandler", func() {
+	It("clears the outbox and reports how many messages were removed", func() {
+		box := mocks.NewOutbox()
+		box.ClearCall.Returns.Count = 3
+
+		request, err := http.NewRequest("DELETE", "/outbox", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		writer := httptest.NewRecorder()
+		outbox.NewClearHandler(box).ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"deleted": 3}`))
+		Expect(box.ClearCall.WasCalled).To(BeTrue())
+	})
+})
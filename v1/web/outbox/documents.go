@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+)
+
+type messageDocument struct {
+	ID         int            `json:"id"`
+	CapturedAt time.Time      `json:"captured_at"`
+	MessageID  string         `json:"message_id,omitempty"`
+	From       string         `json:"from"`
+	ReplyTo    string         `json:"reply_to,omitempty"`
+	To         string         `json:"to"`
+	Subject    string         `json:"subject"`
+	Headers    []string       `json:"headers,omitempty"`
+	Parts      []partDocument `json:"parts,omitempty"`
+	Raw        string         `json:"raw,omitempty"`
+}
+
+type partDocument struct {
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+}
+
+func newMessageDocument(captured mail.CapturedMessage) messageDocument {
+	return messageDocument{
+		ID:         captured.ID,
+		CapturedAt: captured.CapturedAt,
+		MessageID:  captured.Message.MessageID,
+		From:       captured.Message.From,
+		ReplyTo:    captured.Message.ReplyTo,
+		To:         captured.Message.To,
+		Subject:    captured.Message.Subject,
+	}
+}
+
+func parseMessageID(path string) (int, error) {
+	segments := strings.Split(strings.TrimPrefix(path, "/outbox/"), "/")
+
+	id, err := strconv.Atoi(segments[0])
+	if err != nil {
+		return 0, mail.OutboxMessageNotFoundError{}
+	}
+
+	return id, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ryanmoran/stack"
+)
+
+type GetHandler struct {
+	outbox      outbox
+	errorWriter errorWriter
+}
+
+func NewGetHandler(outbox outbox, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		outbox:      outbox,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	id, err := parseMessageID(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	captured, err := h.outbox.Find(id)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	if strings.Contains(req.Header.Get("Accept"), "message/rfc822") {
+		w.Header().Set("Content-Type", "message/rfc822")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(captured.Raw))
+		return
+	}
+
+	document := newMessageDocument(captured)
+	document.Headers = captured.Message.Headers
+	document.Raw = captured.Raw
+	for _, part := range captured.Message.Body {
+		document.Parts = append(document.Parts, partDocument{
+			ContentType: part.ContentType,
+			Content:     part.Content,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
@@ -0,0 +1,104 @@
+package outbox_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/outbox"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler     outbox.GetHandler
+		writer      *httptest.ResponseRecorder
+		box         *mocks.Outbox
+		errorWriter *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		box = mocks.NewOutbox()
+		box.FindCall.Returns.Message = mail.CapturedMessage{
+			ID:         1,
+			CapturedAt: time.Date(2015, time.June, 8, 14, 38, 3, 0, time.UTC),
+			Message: mail.Message{
+				From:    "me@example.com",
+				ReplyTo: "reply@example.com",
+				To:      "you@example.com",
+				Subject: "Urgent! Read now!",
+				Headers: []string{"X-CF-Notification-ID: some-message-id"},
+				Body: []mail.Part{
+					{ContentType: "text/plain", Content: "This email is important"},
+					{ContentType: "text/html", Content: "<p>This email is important</p>"},
+				},
+			},
+			Raw: "Subject: Urgent! Read now!\r\n\r\nThis email is important",
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		handler = outbox.NewGetHandler(box, errorWriter)
+	})
+
+	It("returns the message with its parsed parts and raw form", func() {
+		request, err := http.NewRequest("GET", "/outbox/1", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": 1,
+			"captured_at": "2015-06-08T14:38:03Z",
+			"from": "me@example.com",
+			"reply_to": "reply@example.com",
+			"to": "you@example.com",
+			"subject": "Urgent! Read now!",
+			"headers": ["X-CF-Notification-ID: some-message-id"],
+			"parts": [
+				{"content_type": "text/plain", "content": "This email is important"},
+				{"content_type": "text/html", "content": "<p>This email is important</p>"}
+			],
+			"raw": "Subject: Urgent! Read now!\r\n\r\nThis email is important"
+		}`))
+		Expect(box.FindCall.Receives.ID).To(Equal(1))
+	})
+
+	It("returns the raw message when message/rfc822 is accepted", func() {
+		request, err := http.NewRequest("GET", "/outbox/1", nil)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Accept", "message/rfc822")
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Header().Get("Content-Type")).To(Equal("message/rfc822"))
+		Expect(writer.Body.String()).To(Equal("Subject: Urgent! Read now!\r\n\r\nThis email is important"))
+	})
+
+	It("writes a not found error when the ID is not a number", func() {
+		request, err := http.NewRequest("GET", "/outbox/banana", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(mail.OutboxMessageNotFoundError{}))
+	})
+
+	It("delegates outbox errors to the error writer", func() {
+		box.FindCall.Returns.Error = mail.OutboxMessageNotFoundError{ID: 1}
+
+		request, err := http.NewRequest("GET", "/outbox/1", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(mail.OutboxMessageNotFoundError{ID: 1}))
+	})
+})
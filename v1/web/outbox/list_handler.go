@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type ListHandler struct {
+	outbox outbox
+}
+
+func NewListHandler(outbox outbox) ListHandler {
+	return ListHandler{
+		outbox: outbox,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	document := struct {
+		Messages []messageDocument `json:"messages"`
+	}{
+		Messages: []messageDocument{},
+	}
+
+	for _, captured := range h.outbox.List() {
+		document.Messages = append(document.Messages, newMessageDocument(captured))
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
@@ -0,0 +1,74 @@
+package outbox_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/outbox"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler outbox.ListHandler
+		writer  *httptest.ResponseRecorder
+		request *http.Request
+		box     *mocks.Outbox
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("GET", "/outbox", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		box = mocks.NewOutbox()
+		writer = httptest.NewRecorder()
+		handler = outbox.NewListHandler(box)
+	})
+
+	It("lists the captured messages without their content", func() {
+		box.ListCall.Returns.Messages = []mail.CapturedMessage{
+			{
+				ID:         2,
+				CapturedAt: time.Date(2015, time.June, 8, 14, 38, 3, 0, time.UTC),
+				Message: mail.Message{
+					From:      "me@example.com",
+					To:        "you@example.com",
+					Subject:   "Urgent! Read now!",
+					MessageID: "<some-message-id@example.com>",
+					Body:      []mail.Part{{ContentType: "text/plain", Content: "This email is important"}},
+				},
+				Raw: "Subject: Urgent! Read now!\r\n\r\nThis email is important",
+			},
+		}
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"messages": [
+				{
+					"id": 2,
+					"captured_at": "2015-06-08T14:38:03Z",
+					"message_id": "<some-message-id@example.com>",
+					"from": "me@example.com",
+					"to": "you@example.com",
+					"subject": "Urgent! Read now!"
+				}
+			]
+		}`))
+	})
+
+	It("returns an empty list when nothing has been captured", func() {
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"messages": []}`))
+	})
+})
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type outbox interface {
+	List() []mail.CapturedMessage
+	Find(id int) (mail.CapturedMessage, error)
+	Clear() int
+}
+
+type Routes struct {
+	RequestCounter                  stack.Middleware
+	RequestLogging                  stack.Middleware
+	NotificationsAdminAuthenticator stack.Middleware
+
+	ErrorWriter errorWriter
+	Outbox      outbox
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/outbox", NewListHandler(r.Outbox), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("DELETE", "/outbox", NewClearHandler(r.Outbox), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("GET", "/outbox/{id}", NewGetHandler(r.Outbox, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+}
@@ -11,7 +11,7 @@ type muxer interface {
 }
 
 type preferenceUpdater interface {
-	Update(connection services.ConnectionInterface, preferences []models.Preference, globallyUnsubscribe bool, userID string) error
+	Update(connection services.ConnectionInterface, preferences []models.Preference, globallyUnsubscribe bool, locale *string, userID string) error
 }
 
 type Routes struct {
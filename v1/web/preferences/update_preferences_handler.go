@@ -52,9 +52,15 @@ func (h UpdatePreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 		return
 	}
 
+	locale, err := builder.ToLocale()
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+		return
+	}
+
 	transaction := connection.Transaction()
 	transaction.Begin()
-	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, userID)
+	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, locale, userID)
 	if err != nil {
 		transaction.Rollback()
 
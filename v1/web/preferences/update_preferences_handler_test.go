@@ -61,6 +61,8 @@ var _ = Describe("UpdatePreferencesHandler", func() {
 				Email:    false,
 			})
 			builder.GlobalUnsubscribe = true
+			locale := "fr_ca"
+			builder.Locale = &locale
 
 			body, err := json.Marshal(builder)
 			Expect(err).NotTo(HaveOccurred())
@@ -117,6 +119,7 @@ var _ = Describe("UpdatePreferencesHandler", func() {
 			}))
 
 			Expect(updater.UpdateCall.Receives.GlobalUnsubscribe).To(BeTrue())
+			Expect(*updater.UpdateCall.Receives.Locale).To(Equal("fr-CA"))
 			Expect(updater.UpdateCall.Receives.UserID).To(Equal("correct-user"))
 		})
 
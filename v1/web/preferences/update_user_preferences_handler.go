@@ -44,9 +44,15 @@ func (h UpdateUserPreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http
 		return
 	}
 
+	locale, err := builder.ToLocale()
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+		return
+	}
+
 	transaction := connection.Transaction()
 	transaction.Begin()
-	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, userGUID)
+	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, locale, userGUID)
 	if err != nil {
 		transaction.Rollback()
 
@@ -61,6 +61,8 @@ var _ = Describe("UpdateUserPreferencesHandler", func() {
 				Email:    false,
 			})
 			builder.GlobalUnsubscribe = true
+			locale := "fr_ca"
+			builder.Locale = &locale
 
 			body, err := json.Marshal(builder)
 			Expect(err).NotTo(HaveOccurred())
@@ -117,6 +119,7 @@ var _ = Describe("UpdateUserPreferencesHandler", func() {
 			}))
 
 			Expect(updater.UpdateCall.Receives.GlobalUnsubscribe).To(BeTrue())
+			Expect(*updater.UpdateCall.Receives.Locale).To(Equal("fr-CA"))
 			Expect(updater.UpdateCall.Receives.UserID).To(Equal(userGUID))
 		})
 
@@ -155,6 +158,16 @@ var _ = Describe("UpdateUserPreferencesHandler", func() {
 				})
 			})
 
+			It("returns a validation error when the locale is not a valid language tag", func() {
+				request, err := http.NewRequest("PATCH", "domain/user_preferences/"+userGUID, bytes.NewBuffer([]byte(`{"locale": "not a locale"}`)))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+
 			It("delegates MissingKindOrClientErrors as webutil.ValidationError to the ErrorWriter", func() {
 				updateError := services.MissingKindOrClientError{Err: errors.New("BOOM!")}
 				updater.UpdateCall.Returns.Error = updateError
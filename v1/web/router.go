@@ -8,21 +8,30 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/batches"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/clients"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/info"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notifications"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/outbox"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/preferences"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/unsubscribes"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webhooks"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/gorilla/mux"
+	"github.com/pivotal-golang/conceal"
 	"github.com/pivotal-golang/lager"
 	"github.com/rcrowley/go-metrics"
 	"github.com/rcrowley/go-metrics/exp"
@@ -47,6 +56,13 @@ type Config struct {
 	CORSOrigin           string
 	SQLDB                *sql.DB
 	QueueWaitMaxDuration int
+	EncryptionKey        []byte
+	Outbox               *mail.Outbox
+	Sender               string
+	Domain               string
+	PublicURL            string
+	HTMLAllowedTags      []string
+	HTMLBaseURL          string
 }
 
 func NewRouter(mx muxer, config Config) http.Handler {
@@ -56,23 +72,45 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	clientsRepo := models.NewClientsRepo()
 	kindsRepo := models.NewKindsRepo()
 	globalUnsubscribesRepo := models.NewGlobalUnsubscribesRepo()
+	userLocalesRepo := models.NewUserLocalesRepo()
 	preferencesRepo := models.NewPreferencesRepo()
 	unsubscribesRepo := models.NewUnsubscribesRepo()
 	messagesRepo := models.NewMessagesRepo(guidGenerator.Generate)
 	templatesRepo := models.NewTemplatesRepo()
+	templateVersionsRepo := models.NewTemplateVersionsRepo()
+	batchesRepo := models.NewBatchesRepo(guidGenerator.Generate)
+	webhooksRepo := models.NewWebhooksRepo(guidGenerator.Generate)
 
 	registrar := services.NewRegistrar(clientsRepo, kindsRepo)
 	notificationsFinder := services.NewNotificationsFinder(clientsRepo, kindsRepo)
-	preferencesFinder := services.NewPreferencesFinder(preferencesRepo, globalUnsubscribesRepo)
-	preferenceUpdater := services.NewPreferenceUpdater(globalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
+	preferencesFinder := services.NewPreferencesFinder(preferencesRepo, globalUnsubscribesRepo, userLocalesRepo)
+	preferenceUpdater := services.NewPreferenceUpdater(globalUnsubscribesRepo, unsubscribesRepo, kindsRepo, userLocalesRepo)
 	notificationsUpdater := services.NewNotificationsUpdater(kindsRepo)
 	messageFinder := services.NewMessageFinder(messagesRepo)
+	batchFinder := services.NewBatchFinder(batchesRepo, messagesRepo)
+	webhookRegistry := services.NewWebhookRegistry(clientsRepo, webhooksRepo, util.NewSecretGenerator(rand.Reader).Generate)
 
-	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+	cloak, err := conceal.NewCloak(config.EncryptionKey)
+	if err != nil {
+		panic(err)
+	}
+	unsubscriber := services.NewUnsubscriber(cloak, unsubscribesRepo, kindsRepo)
+
+	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo)
 
 	templateFinder := services.NewTemplateFinder(templatesRepo)
-	templateUpdater := services.NewTemplateUpdater(templatesRepo)
+	templateUpdater := services.NewTemplateUpdater(templatesRepo, templateVersionsRepo)
 	templateLister := services.NewTemplateLister(templatesRepo)
+	templatePreviewer := services.NewTemplatePreviewer(services.TemplatePreviewerConfig{
+		Cloak:     cloak,
+		Sender:    config.Sender,
+		Domain:    config.Domain,
+		PublicURL: config.PublicURL,
+		HTMLProcessor: common.NewHTMLPipeline(common.HTMLPipelineConfig{
+			AllowedTags: config.HTMLAllowedTags,
+			BaseURL:     config.HTMLBaseURL,
+		}),
+	})
 
 	notifyObj := notify.NewNotify(notificationsFinder, registrar)
 
@@ -80,7 +118,10 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		WaitMaxDuration: time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
 	})
 
-	v1enqueuer := services.NewEnqueuer(gobbleQueue, messagesRepo, gobble.Initializer{})
+	v1enqueuer := services.NewEnqueuer(gobbleQueue, messagesRepo, batchesRepo, gobble.Initializer{})
+	fanOutEnqueuer := services.NewFanOutEnqueuer(gobbleQueue, batchesRepo, gobble.Initializer{})
+	webhookNotifier := v1.NewWebhookNotifier(batchesRepo, webhooksRepo, gobbleQueue, gobble.Initializer{})
+	messageCanceler := services.NewMessageCanceler(messagesRepo, gobbleQueue, webhookNotifier)
 
 	uaaClient := uaa.NewZonedUAAClient(config.UAAClientID, config.UAAClientSecret, config.VerifySSL, config.UAATokenValidator)
 	cloudController := cf.NewCloudController(config.CCHost, !config.VerifySSL)
@@ -88,14 +129,13 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	spaceLoader := services.NewSpaceLoader(cloudController)
 	organizationLoader := services.NewOrganizationLoader(cloudController)
 	findsUserIDs := services.NewFindsUserIDs(cloudController, uaaClient)
-	allUsers := services.NewAllUsers(uaaClient)
 
 	emailStrategy := services.NewEmailStrategy(v1enqueuer)
 	userStrategy := services.NewUserStrategy(v1enqueuer)
 	spaceStrategy := services.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserIDs, v1enqueuer)
-	organizationStrategy := services.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserIDs, v1enqueuer)
-	everyoneStrategy := services.NewEveryoneStrategy(tokenLoader, allUsers, v1enqueuer)
-	uaaScopeStrategy := services.NewUAAScopeStrategy(tokenLoader, findsUserIDs, v1enqueuer, config.DefaultUAAScopes)
+	organizationStrategy := services.NewOrganizationStrategy(tokenLoader, organizationLoader, fanOutEnqueuer)
+	everyoneStrategy := services.NewEveryoneStrategy(fanOutEnqueuer)
+	uaaScopeStrategy := services.NewUAAScopeStrategy(fanOutEnqueuer, config.DefaultUAAScopes)
 
 	errorWriter := webutil.NewErrorWriter()
 
@@ -138,14 +178,36 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		TemplateAssigner: templatesCollection,
 	}.Register(mx)
 
+	webhooks.Routes{
+		RequestCounter:                   requestCounter,
+		RequestLogging:                   requestLogging,
+		DatabaseAllocator:                databaseAllocator,
+		NotificationsManageAuthenticator: auth("notifications.manage"),
+
+		ErrorWriter:     errorWriter,
+		WebhookRegistry: webhookRegistry,
+	}.Register(mx)
+
 	messages.Routes{
 		RequestCounter:                               requestCounter,
 		RequestLogging:                               requestLogging,
 		DatabaseAllocator:                            databaseAllocator,
 		NotificationsWriteOrEmailsWriteAuthenticator: auth("notifications.write", "emails.write"),
+		NotificationsAdminAuthenticator:              auth("notifications.admin"),
 
-		ErrorWriter:   errorWriter,
-		MessageFinder: messageFinder,
+		ErrorWriter:     errorWriter,
+		MessageFinder:   messageFinder,
+		MessageCanceler: messageCanceler,
+	}.Register(mx)
+
+	batches.Routes{
+		RequestCounter:    requestCounter,
+		RequestLogging:    requestLogging,
+		DatabaseAllocator: databaseAllocator,
+		NotificationsWriteOrEmailsWriteAuthenticator: auth("notifications.write", "emails.write"),
+
+		ErrorWriter: errorWriter,
+		BatchFinder: batchFinder,
 	}.Register(mx)
 
 	templates.Routes{
@@ -163,6 +225,11 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		TemplateDeleter:           templatesCollection,
 		TemplateLister:            templateLister,
 		TemplateAssociationLister: templatesCollection,
+		TemplatePreviewer:         templatePreviewer,
+		NotificationsFinder:       notificationsFinder,
+		TemplateVersionLister:     templatesCollection,
+		TemplateVersionFinder:     templatesCollection,
+		TemplateVersionRestorer:   templatesCollection,
 	}.Register(mx)
 
 	notifications.Routes{
@@ -179,6 +246,34 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		TemplateAssigner:     templatesCollection,
 	}.Register(mx)
 
+	unsubscribes.Routes{
+		RequestCounter:    requestCounter,
+		RequestLogging:    requestLogging,
+		DatabaseAllocator: databaseAllocator,
+
+		Unsubscriber: unsubscriber,
+	}.Register(mx)
+
+	deadjobs.Routes{
+		RequestCounter:                  requestCounter,
+		RequestLogging:                  requestLogging,
+		NotificationsAdminAuthenticator: auth("notifications.admin"),
+
+		ErrorWriter: errorWriter,
+		Queue:       gobbleQueue,
+	}.Register(mx)
+
+	if config.Outbox != nil {
+		outbox.Routes{
+			RequestCounter:                  requestCounter,
+			RequestLogging:                  requestLogging,
+			NotificationsAdminAuthenticator: auth("notifications.admin"),
+
+			ErrorWriter: errorWriter,
+			Outbox:      config.Outbox,
+		}.Register(mx)
+	}
+
 	notify.Routes{
 		RequestCounter:                  requestCounter,
 		RequestLogging:                  requestLogging,
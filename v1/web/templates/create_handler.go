@@ -37,19 +37,24 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	}
 
 	connection := context.Get("database").(DatabaseInterface).Connection()
+	author, _ := context.Get("client_id").(string)
 
+	model := templateParams.ToModel()
 	template, err := h.creator.Create(connection, collections.Template{
-		Name:     templateParams.Name,
-		Text:     templateParams.Text,
-		HTML:     templateParams.HTML,
-		Subject:  templateParams.Subject,
-		Metadata: string(templateParams.Metadata),
+		Name:          model.Name,
+		Text:          model.Text,
+		HTML:          model.HTML,
+		Subject:       model.Subject,
+		Metadata:      model.Metadata,
+		Localizations: model.Localizations,
+		Author:        author,
 	})
 	if err != nil {
 		h.errorWriter.Write(w, webutil.TemplateCreateError{})
 		return
 	}
 
+	templateParams.writeWarnings(w)
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte(`{"template_id":"` + template.ID + `"}`))
 }
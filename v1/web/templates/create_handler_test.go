@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
@@ -59,6 +60,7 @@ var _ = Describe("CreateHandler", func() {
 
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("client_id", "some-client-id")
 
 			request, err = http.NewRequest("POST", "/templates", body)
 			Expect(err).NotTo(HaveOccurred())
@@ -71,17 +73,32 @@ var _ = Describe("CreateHandler", func() {
 
 			Expect(creator.CreateCall.Receives.Connection).To(Equal(connection))
 			Expect(creator.CreateCall.Receives.Template).To(Equal(collections.Template{
-				Name:     "Emergency Template",
-				Text:     "Message to: {{.To}}. Raptor Alert.",
-				HTML:     "<p>{{.ClientID}} you should run.</p>",
-				Subject:  "Raptor Containment Unit Breached",
-				Metadata: "{}",
+				Name:          "Emergency Template",
+				Text:          "Message to: {{.To}}. Raptor Alert.",
+				HTML:          "<p>{{.ClientID}} you should run.</p>",
+				Subject:       "Raptor Containment Unit Breached",
+				Metadata:      "{}",
+				Localizations: "{}",
+				Author:        "some-client-id",
 			}))
 
 			Expect(writer.Code).To(Equal(http.StatusCreated))
 			Expect(writer.Body.String()).To(MatchJSON(`{"template_id":"template-guid"}`))
 		})
 
+		It("reports template warnings in the Warning header", func() {
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusCreated))
+			Expect(writer.Header()["Warning"]).To(Equal([]string{
+				`299 - "subject template does not include {{.Subject}}"`,
+				`299 - "text template does not include {{.Text}}"`,
+				`299 - "text template does not include {{.Endorsement}}"`,
+				`299 - "html template does not include {{.HTML}}"`,
+				`299 - "html template does not include {{.Endorsement}}"`,
+			}))
+		})
+
 		Context("when an errors occurs", func() {
 			It("Writes a validation error to the errorwriter when the request is missing the name field", func() {
 				request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"html": "<p>gobble</p>"}`)))
@@ -99,6 +116,17 @@ var _ = Describe("CreateHandler", func() {
 				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: valiant.RequiredFieldError{ErrorMessage: "Missing required field 'html'"}}))
 			})
 
+			It("writes the template errors when a template is invalid", func() {
+				request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"name": "gobble", "html": "<p>{{.Raptors}}</p>"}`)))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+					{Field: "html", Line: 1, Column: 6, Message: "can't evaluate field Raptors in type common.MessageContext"},
+				}}))
+				Expect(creator.CreateCall.Receives.Template).To(BeZero())
+			})
+
 			It("writes a parse error for an invalid request", func() {
 				request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"name":"foobar", "html": forgot to close the curly brace`)))
 				Expect(err).NotTo(HaveOccurred())
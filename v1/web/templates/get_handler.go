@@ -5,15 +5,18 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
 	"github.com/ryanmoran/stack"
 )
 
 type TemplateOutput struct {
-	Name     string                 `json:"name"`
-	Subject  string                 `json:"subject"`
-	HTML     string                 `json:"html"`
-	Text     string                 `json:"text"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Name          string                                 `json:"name"`
+	Subject       string                                 `json:"subject"`
+	HTML          string                                 `json:"html"`
+	Text          string                                 `json:"text"`
+	Metadata      map[string]interface{}                 `json:"metadata"`
+	Localizations map[string]models.TemplateLocalization `json:"localizations"`
 }
 
 type GetHandler struct {
@@ -44,12 +47,19 @@ func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 		return
 	}
 
+	localizations, err := template.ParseLocalizations()
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
 	templateOutput := TemplateOutput{
-		Name:     template.Name,
-		Subject:  template.Subject,
-		HTML:     template.HTML,
-		Text:     template.Text,
-		Metadata: metadata,
+		Name:          template.Name,
+		Subject:       template.Subject,
+		HTML:          template.HTML,
+		Text:          template.Text,
+		Metadata:      metadata,
+		Localizations: localizations,
 	}
 
 	writeJSON(w, http.StatusOK, templateOutput)
@@ -74,12 +74,13 @@ var _ = Describe("GetHandler", func() {
 					panic(err)
 				}
 
-				Expect(template).To(HaveLen(5))
+				Expect(template).To(HaveLen(6))
 				Expect(template["name"]).To(Equal("The Name of The Template"))
 				Expect(template["subject"]).To(Equal("All about the {{.Subject}}"))
 				Expect(template["text"]).To(Equal("the template {{variable}}"))
 				Expect(template["html"]).To(Equal("<p> the template {{variable}} </p>"))
 				Expect(template["metadata"]).To(Equal(map[string]interface{}{"hello": "world"}))
+				Expect(template["localizations"]).To(Equal(map[string]interface{}{}))
 			})
 		})
 
@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type templateVersionFinder interface {
+	FindVersion(connection collections.ConnectionInterface, templateID string, version int) (collections.TemplateVersion, error)
+}
+
+type GetVersionHandler struct {
+	finder      templateVersionFinder
+	errorWriter errorWriter
+}
+
+func NewGetVersionHandler(finder templateVersionFinder, errWriter errorWriter) GetVersionHandler {
+	return GetVersionHandler{
+		finder:      finder,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetVersionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, version := parseTemplateVersion(req.URL.Path)
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	templateVersion, err := h.finder.FindVersion(connection, templateID, version)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := newTemplateVersionOutput(templateVersion)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+func parseTemplateVersion(path string) (string, int) {
+	matches := regexp.MustCompile(`/templates/(.*)/versions/(\d+)`).FindStringSubmatch(path)
+	version, _ := strconv.Atoi(matches[2])
+
+	return matches[1], version
+}
@@ -0,0 +1,90 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetVersionHandler", func() {
+	var (
+		handler     templates.GetVersionHandler
+		finder      *mocks.TemplateVersionFinder
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		context     stack.Context
+		connection  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		finder = mocks.NewTemplateVersionFinder()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("GET", "/templates/some-template-id/versions/12", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = templates.NewGetVersionHandler(finder, errorWriter)
+	})
+
+	It("returns the requested version of the template", func() {
+		finder.FindVersionCall.Returns.TemplateVersion = collections.TemplateVersion{
+			TemplateID: "some-template-id",
+			Version:    12,
+			Name:       "Some Template",
+			Subject:    "{{.Subject}}",
+			Text:       "{{.Text}}",
+			HTML:       "<p>{{.HTML}}</p>",
+			Metadata:   "{}",
+			Author:     "some-client",
+			CreatedAt:  time.Date(2015, time.March, 4, 10, 30, 0, 0, time.UTC),
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 12,
+			"name": "Some Template",
+			"subject": "{{.Subject}}",
+			"text": "{{.Text}}",
+			"html": "<p>{{.HTML}}</p>",
+			"metadata": {},
+			"localizations": {},
+			"author": "some-client",
+			"created_at": "2015-03-04T10:30:00Z"
+		}`))
+
+		Expect(finder.FindVersionCall.Receives.Connection).To(Equal(connection))
+		Expect(finder.FindVersionCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(finder.FindVersionCall.Receives.Version).To(Equal(12))
+	})
+
+	It("writes errors to the error writer", func() {
+		finder.FindVersionCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+	})
+})
@@ -0,0 +1,118 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListVersionsHandler", func() {
+	var (
+		handler     templates.ListVersionsHandler
+		lister      *mocks.TemplateVersionLister
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		context     stack.Context
+		connection  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		lister = mocks.NewTemplateVersionLister()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("GET", "/templates/some-template-id/versions", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = templates.NewListVersionsHandler(lister, errorWriter)
+	})
+
+	It("lists the versions of the template", func() {
+		createdAt := time.Date(2015, time.March, 4, 10, 30, 0, 0, time.UTC)
+		lister.ListVersionsCall.Returns.TemplateVersions = []collections.TemplateVersion{
+			{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "Some Template",
+				Subject:    "{{.Subject}}",
+				Text:       "{{.Text}}",
+				HTML:       "<p>{{.HTML}}</p>",
+				Metadata:   "{}",
+				Author:     "some-client",
+				CreatedAt:  createdAt,
+			},
+			{
+				TemplateID:    "some-template-id",
+				Version:       2,
+				Name:          "Some Template",
+				Subject:       "Hello {{.Subject}}",
+				Text:          "{{.Text}}",
+				HTML:          "<p>{{.HTML}}</p>",
+				Metadata:      `{"color": "blue"}`,
+				Localizations: `{"fr": {"subject": "Bonjour {{.Subject}}"}}`,
+				Author:        "other-client",
+				CreatedAt:     createdAt.Add(time.Hour),
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [
+				{
+					"version": 1,
+					"name": "Some Template",
+					"subject": "{{.Subject}}",
+					"text": "{{.Text}}",
+					"html": "<p>{{.HTML}}</p>",
+					"metadata": {},
+					"localizations": {},
+					"author": "some-client",
+					"created_at": "2015-03-04T10:30:00Z"
+				},
+				{
+					"version": 2,
+					"name": "Some Template",
+					"subject": "Hello {{.Subject}}",
+					"text": "{{.Text}}",
+					"html": "<p>{{.HTML}}</p>",
+					"metadata": {"color": "blue"},
+					"localizations": {"fr": {"subject": "Bonjour {{.Subject}}", "text": "", "html": ""}},
+					"author": "other-client",
+					"created_at": "2015-03-04T11:30:00Z"
+				}
+			]
+		}`))
+
+		Expect(lister.ListVersionsCall.Receives.Connection).To(Equal(connection))
+		Expect(lister.ListVersionsCall.Receives.TemplateID).To(Equal("some-template-id"))
+	})
+
+	It("writes errors to the error writer", func() {
+		lister.ListVersionsCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+	})
+})
@@ -0,0 +1,103 @@
+package templates
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePreviewer interface {
+	Preview(template models.Template, delivery common.Delivery) (mail.Message, error)
+}
+
+type clientAndKindFinder interface {
+	ClientAndKind(database services.DatabaseInterface, clientID, kindID string) (models.Client, models.Kind, error)
+}
+
+type PreviewOutput struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	Raw     string `json:"raw"`
+}
+
+type PreviewHandler struct {
+	finder        templateFinder
+	clientsFinder clientAndKindFinder
+	previewer     templatePreviewer
+	errorWriter   errorWriter
+}
+
+func NewPreviewHandler(finder templateFinder, clientsFinder clientAndKindFinder, previewer templatePreviewer, errWriter errorWriter) PreviewHandler {
+	return PreviewHandler{
+		finder:        finder,
+		clientsFinder: clientsFinder,
+		previewer:     previewer,
+		errorWriter:   errWriter,
+	}
+}
+
+func (h PreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	params, err := NewPreviewParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	template, err := h.finder.FindByID(database, h.parseTemplateID(req.URL.Path))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	generateText := true
+	if params.ClientID != "" {
+		client, _, err := h.clientsFinder.ClientAndKind(database, params.ClientID, params.Notify.KindID)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		generateText = client.ShouldGenerateText()
+	}
+
+	message, err := h.previewer.Preview(template, params.ToDelivery(time.Now().UTC(), generateText))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output := PreviewOutput{
+		Subject: message.Subject,
+		Raw:     message.Data(),
+	}
+
+	for _, part := range message.Body {
+		switch part.ContentType {
+		case "text/plain":
+			output.Text = part.Content
+		case "text/html":
+			output.HTML = part.Content
+		}
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+func (h PreviewHandler) parseTemplateID(path string) string {
+	r := regexp.MustCompile(`\/templates\/(.*)\/preview`)
+	matches := r.FindStringSubmatch(path)
+	if matches == nil {
+		return models.DefaultTemplateID
+	}
+
+	return matches[1]
+}
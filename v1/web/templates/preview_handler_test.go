@@ -0,0 +1,195 @@
+package templates_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreviewHandler", func() {
+	var (
+		handler     templates.PreviewHandler
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		finder      *mocks.TemplateFinder
+		clients     *mocks.NotificationsFinder
+		previewer   *mocks.TemplatePreviewer
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+		body        []byte
+	)
+
+	BeforeEach(func() {
+		finder = mocks.NewTemplateFinder()
+		finder.FindByIDCall.Returns.Template = models.Template{
+			ID:      "some-template-id",
+			Subject: "CF Notification: {{.Subject}}",
+			Text:    "{{.Text}}",
+			HTML:    "{{.HTML}}",
+		}
+
+		clients = mocks.NewNotificationsFinder()
+
+		previewer = mocks.NewTemplatePreviewer()
+		previewer.PreviewCall.Returns.Message = mail.Message{
+			From:    "no-reply@example.com",
+			To:      "user@example.com",
+			Subject: "CF Notification: Hello",
+			Body: []mail.Part{
+				{ContentType: "text/plain", Content: "Hello world"},
+				{ContentType: "text/html", Content: "<p>Hello world</p>"},
+			},
+		}
+
+		writer = httptest.NewRecorder()
+		errorWriter = mocks.NewErrorWriter()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		body = []byte(`{
+			"client_id": "some-client",
+			"recipient": {"user_guid": "user-123", "email": "user@example.com"},
+			"notification": {"subject": "Hello", "text": "Hello world", "html": "<p>Hello world</p>"}
+		}`)
+
+		handler = templates.NewPreviewHandler(finder, clients, previewer, errorWriter)
+	})
+
+	It("renders the requested template", func() {
+		request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusOK))
+
+		Expect(finder.FindByIDCall.Receives.Database).To(Equal(database))
+		Expect(finder.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+
+		Expect(previewer.PreviewCall.Receives.Template.ID).To(Equal("some-template-id"))
+		Expect(previewer.PreviewCall.Receives.Delivery.ClientID).To(Equal("some-client"))
+		Expect(previewer.PreviewCall.Receives.Delivery.UserGUID).To(Equal("user-123"))
+		Expect(previewer.PreviewCall.Receives.Delivery.Options.Subject).To(Equal("Hello"))
+		Expect(previewer.PreviewCall.Receives.Delivery.RequestReceived).NotTo(BeZero())
+
+		var output templates.PreviewOutput
+		err = json.Unmarshal(writer.Body.Bytes(), &output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.Subject).To(Equal("CF Notification: Hello"))
+		Expect(output.Text).To(Equal("Hello world"))
+		Expect(output.HTML).To(Equal("<p>Hello world</p>"))
+		Expect(output.Raw).To(ContainSubstring("Subject: CF Notification: Hello"))
+		Expect(output.Raw).To(ContainSubstring("To: user@example.com"))
+	})
+
+	It("generates the text part only when the client would have it generated", func() {
+		body = []byte(`{
+			"client_id": "some-client",
+			"notification": {"kind_id": "some-kind", "html": "<p>Hello world</p>"}
+		}`)
+		request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusOK))
+
+		Expect(clients.ClientAndKindCall.Receives.Database).To(Equal(database))
+		Expect(clients.ClientAndKindCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(clients.ClientAndKindCall.Receives.KindID).To(Equal("some-kind"))
+		Expect(previewer.PreviewCall.Receives.Delivery.Options.Text).To(Equal("Hello world"))
+
+		generateText := false
+		clients.ClientAndKindCall.Returns.Client = models.Client{ID: "some-client", GenerateText: &generateText}
+
+		request, err = http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(previewer.PreviewCall.Receives.Delivery.Options.Text).To(BeEmpty())
+	})
+
+	It("gives the preview a sample message ID", func() {
+		request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(previewer.PreviewCall.Receives.Delivery.MessageID).To(Equal(templates.PreviewMessageID))
+	})
+
+	It("renders the default template", func() {
+		request, err := http.NewRequest("POST", "/default_template/preview", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusOK))
+
+		Expect(finder.FindByIDCall.Receives.TemplateID).To(Equal(models.DefaultTemplateID))
+	})
+
+	Context("when the request is invalid", func() {
+		It("writes a validation error", func() {
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer([]byte(`{"notification": {"subject": "Hello"}}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"text" or "html" fields must be supplied`)}))
+		})
+	})
+
+	Context("when the template cannot be found", func() {
+		It("writes the error", func() {
+			finder.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("POST", "/templates/missing-template-id/preview", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Context("when the client cannot be loaded", func() {
+		It("writes the error", func() {
+			clients.ClientAndKindCall.Returns.Error = errors.New("database is down")
+
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("database is down"))
+			Expect(previewer.PreviewCall.Receives.Template).To(Equal(models.Template{}))
+		})
+	})
+
+	Context("when the template cannot be rendered", func() {
+		It("writes the error", func() {
+			previewer.PreviewCall.Returns.Error = services.TemplateRenderError{Err: errors.New("template: unexpected EOF")}
+
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(services.TemplateRenderError{Err: errors.New("template: unexpected EOF")}))
+		})
+	})
+})
@@ -0,0 +1,177 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+)
+
+// PreviewMessageID stands in for the message ID a sent notification would
+// have, so that the Message-ID header of a preview is well formed.
+const PreviewMessageID = "preview"
+
+type PreviewRecipient struct {
+	UserGUID string `json:"user_guid"`
+	Email    string `json:"email"`
+	Locale   string `json:"locale"`
+}
+
+type PreviewSpace struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+type PreviewOrganization struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+type PreviewParams struct {
+	ClientID          string              `json:"client_id"`
+	KindDescription   string              `json:"kind_description"`
+	SourceDescription string              `json:"source_description"`
+	Scope             string              `json:"scope"`
+	Notification      json.RawMessage     `json:"notification"`
+	Recipient         PreviewRecipient    `json:"recipient"`
+	Space             PreviewSpace        `json:"space"`
+	Organization      PreviewOrganization `json:"organization"`
+
+	Notify notify.NotifyParams `json:"-"`
+}
+
+func NewPreviewParams(body io.ReadCloser) (PreviewParams, error) {
+	defer body.Close()
+
+	var params PreviewParams
+	err := json.NewDecoder(body).Decode(&params)
+	if err != nil {
+		return params, webutil.ParseError{}
+	}
+
+	params.Notify, err = notify.NewNotifyParams(ioutil.NopCloser(bytes.NewReader(params.Notification)))
+	if err != nil {
+		return params, err
+	}
+
+	if !(notify.PreviewValidator{}).Validate(&params.Notify) {
+		return params, webutil.ValidationError{Err: errors.New(strings.Join(params.Notify.Errors, ","))}
+	}
+
+	if params.Recipient.Locale != "" {
+		params.Recipient.Locale, err = common.CanonicalLocale(params.Recipient.Locale)
+		if err != nil {
+			return params, webutil.ValidationError{Err: err}
+		}
+	}
+
+	return params, nil
+}
+
+// ToDelivery builds the delivery a worker would receive for this
+// notification, including the endorsement the matching strategy would add.
+// The text part is only generated from the HTML when the client would have it
+// generated for a real send.
+func (p PreviewParams) ToDelivery(requestReceived time.Time, generateText bool) common.Delivery {
+	text := p.Notify.Text
+	if text == "" && generateText {
+		text = p.Notify.GeneratedText
+	}
+
+	var localizations map[string]common.Localization
+	for locale, localization := range p.Notify.Localizations {
+		if localizations == nil {
+			localizations = map[string]common.Localization{}
+		}
+
+		localizedText := localization.Text
+		if localizedText == "" && generateText {
+			localizedText = localization.GeneratedText
+		}
+
+		localizations[locale] = common.Localization{
+			Subject: localization.Subject,
+			Text:    localizedText,
+			HTML: common.HTML{
+				BodyContent:    localization.ParsedHTML.BodyContent,
+				BodyAttributes: localization.ParsedHTML.BodyAttributes,
+				Head:           localization.ParsedHTML.Head,
+				Doctype:        localization.ParsedHTML.Doctype,
+			},
+		}
+	}
+
+	var attachments []common.Attachment
+	for _, attachment := range p.Notify.Attachments {
+		attachments = append(attachments, common.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+			Inline:      attachment.Inline,
+			ContentID:   attachment.ContentID,
+		})
+	}
+
+	return common.Delivery{
+		Options: common.Options{
+			ReplyTo:           p.Notify.ReplyTo,
+			Subject:           p.Notify.Subject,
+			KindDescription:   p.KindDescription,
+			SourceDescription: p.SourceDescription,
+			Text:              text,
+			HTML: common.HTML{
+				BodyContent:    p.Notify.ParsedHTML.BodyContent,
+				BodyAttributes: p.Notify.ParsedHTML.BodyAttributes,
+				Head:           p.Notify.ParsedHTML.Head,
+				Doctype:        p.Notify.ParsedHTML.Doctype,
+			},
+			KindID:        p.Notify.KindID,
+			To:            p.Recipient.Email,
+			Role:          p.Notify.Role,
+			Endorsement:   p.endorsement(),
+			Data:          p.Notify.Data,
+			Attachments:   attachments,
+			Localizations: localizations,
+		},
+		MessageID: PreviewMessageID,
+		UserGUID:  p.Recipient.UserGUID,
+		Email:     p.Recipient.Email,
+		Locale:    p.Recipient.Locale,
+		Space: cf.CloudControllerSpace{
+			GUID:             p.Space.GUID,
+			Name:             p.Space.Name,
+			OrganizationGUID: p.Organization.GUID,
+		},
+		Organization: cf.CloudControllerOrganization{
+			GUID: p.Organization.GUID,
+			Name: p.Organization.Name,
+		},
+		ClientID:        p.ClientID,
+		Scope:           p.Scope,
+		RequestReceived: requestReceived,
+	}
+}
+
+func (p PreviewParams) endorsement() string {
+	switch {
+	case p.Space.GUID != "":
+		return services.SpaceEndorsement
+	case p.Organization.GUID != "" && p.Notify.Role != "":
+		return services.OrganizationRoleEndorsement
+	case p.Organization.GUID != "":
+		return services.OrganizationEndorsement
+	case p.Recipient.UserGUID != "":
+		return services.UserEndorsement
+	default:
+		return services.EmailEndorsement
+	}
+}
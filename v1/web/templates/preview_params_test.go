@@ -0,0 +1,163 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreviewParams", func() {
+	var newParams = func(body string) (templates.PreviewParams, error) {
+		return templates.NewPreviewParams(ioutil.NopCloser(bytes.NewBufferString(body)))
+	}
+
+	Describe("NewPreviewParams", func() {
+		It("returns a parse error when the body is not JSON", func() {
+			_, err := newParams(`{"notification":`)
+			Expect(err).To(Equal(webutil.ParseError{}))
+		})
+
+		It("validates the notification", func() {
+			_, err := newParams(`{"notification": {"subject": "Hello", "role": "SpaceManager"}}`)
+			Expect(err).To(Equal(webutil.ValidationError{Err: errors.New(`"text" or "html" fields must be supplied,"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)}))
+		})
+
+		It("validates the locale of the recipient", func() {
+			_, err := newParams(`{"recipient": {"locale": "not a locale"}, "notification": {"text": "Hello"}}`)
+			Expect(err).To(Equal(webutil.ValidationError{Err: common.InvalidLocaleError{Locale: "not a locale"}}))
+		})
+	})
+
+	Describe("ToDelivery", func() {
+		var requestReceived time.Time
+
+		BeforeEach(func() {
+			requestReceived = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+		})
+
+		It("builds the delivery a worker would receive", func() {
+			params, err := newParams(`{
+				"client_id": "some-client",
+				"kind_description": "Deploys",
+				"source_description": "The Deployer",
+				"recipient": {"user_guid": "user-123", "email": "user@example.com"},
+				"space": {"guid": "space-123", "name": "production"},
+				"organization": {"guid": "org-123", "name": "acme"},
+				"notification": {
+					"kind_id": "deploy",
+					"subject": "Deployed",
+					"reply_to": "ops@example.com",
+					"text": "Your app was deployed",
+					"html": "<html><head><title>Deployed</title></head><body class=\"main\"><p>Your app was deployed</p></body></html>",
+					"data": {"app": "dora"},
+					"attachments": [{"filename": "log.txt", "data": "aGVsbG8="}]
+				}
+			}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(params.ToDelivery(requestReceived, true)).To(Equal(common.Delivery{
+				Options: common.Options{
+					ReplyTo:           "ops@example.com",
+					Subject:           "Deployed",
+					KindDescription:   "Deploys",
+					SourceDescription: "The Deployer",
+					Text:              "Your app was deployed",
+					HTML: common.HTML{
+						BodyContent:    "<p>Your app was deployed</p>",
+						BodyAttributes: `class="main"`,
+						Head:           "<title>Deployed</title>",
+					},
+					KindID:      "deploy",
+					To:          "user@example.com",
+					Endorsement: services.SpaceEndorsement,
+					Data:        map[string]interface{}{"app": "dora"},
+					Attachments: []common.Attachment{{Filename: "log.txt", Content: []byte("hello")}},
+				},
+				MessageID: templates.PreviewMessageID,
+				UserGUID:  "user-123",
+				Email:     "user@example.com",
+				Space: cf.CloudControllerSpace{
+					GUID:             "space-123",
+					Name:             "production",
+					OrganizationGUID: "org-123",
+				},
+				Organization: cf.CloudControllerOrganization{
+					GUID: "org-123",
+					Name: "acme",
+				},
+				ClientID:        "some-client",
+				RequestReceived: requestReceived,
+			}))
+		})
+
+		It("includes the locale of the recipient and the localized content", func() {
+			params, err := newParams(`{
+				"recipient": {"email": "user@example.com", "locale": "fr_ca"},
+				"notification": {
+					"text": "Hello",
+					"localizations": {"fr": {"subject": "Bonjour", "html": "<p>Bonjour <b>monde</b></p>"}}
+				}
+			}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			delivery := params.ToDelivery(requestReceived, true)
+			Expect(delivery.Locale).To(Equal("fr-CA"))
+			Expect(delivery.Options.Localizations).To(Equal(map[string]common.Localization{
+				"fr": {
+					Subject: "Bonjour",
+					Text:    "Bonjour monde",
+					HTML:    common.HTML{BodyContent: "<p>Bonjour <b>monde</b></p>"},
+				},
+			}))
+		})
+
+		It("generates the text part from html-only notifications", func() {
+			params, err := newParams(`{"notification": {"html": "<p>Hello <b>world</b></p>"}}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(params.ToDelivery(requestReceived, true).Options.Text).To(Equal("Hello world"))
+		})
+
+		It("does not generate text parts when the client has opted out", func() {
+			params, err := newParams(`{
+				"notification": {
+					"html": "<p>Hello <b>world</b></p>",
+					"localizations": {"fr": {"html": "<p>Bonjour</p>"}}
+				}
+			}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			delivery := params.ToDelivery(requestReceived, false)
+			Expect(delivery.Options.Text).To(BeEmpty())
+			Expect(delivery.Options.Localizations["fr"].Text).To(BeEmpty())
+		})
+
+		It("picks the endorsement of the strategy that matches the context", func() {
+			params, err := newParams(`{"organization": {"guid": "org-123"}, "notification": {"text": "Hello", "role": "OrgManager"}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params.ToDelivery(requestReceived, true).Options.Endorsement).To(Equal(services.OrganizationRoleEndorsement))
+
+			params, err = newParams(`{"organization": {"guid": "org-123"}, "notification": {"text": "Hello"}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params.ToDelivery(requestReceived, true).Options.Endorsement).To(Equal(services.OrganizationEndorsement))
+
+			params, err = newParams(`{"recipient": {"user_guid": "user-123"}, "notification": {"text": "Hello"}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params.ToDelivery(requestReceived, true).Options.Endorsement).To(Equal(services.UserEndorsement))
+
+			params, err = newParams(`{"recipient": {"email": "user@example.com"}, "notification": {"text": "Hello"}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params.ToDelivery(requestReceived, true).Options.Endorsement).To(Equal(services.EmailEndorsement))
+		})
+	})
+})
@@ -0,0 +1,44 @@
+package templates
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type templateVersionRestorer interface {
+	RestoreVersion(connection collections.ConnectionInterface, templateID string, version int, author string) (collections.TemplateVersion, error)
+}
+
+type RestoreVersionHandler struct {
+	restorer    templateVersionRestorer
+	errorWriter errorWriter
+}
+
+func NewRestoreVersionHandler(restorer templateVersionRestorer, errWriter errorWriter) RestoreVersionHandler {
+	return RestoreVersionHandler{
+		restorer:    restorer,
+		errorWriter: errWriter,
+	}
+}
+
+func (h RestoreVersionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, version := parseTemplateVersion(req.URL.Path)
+	connection := context.Get("database").(DatabaseInterface).Connection()
+	author, _ := context.Get("client_id").(string)
+
+	templateVersion, err := h.restorer.RestoreVersion(connection, templateID, version, author)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := newTemplateVersionOutput(templateVersion)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
@@ -0,0 +1,91 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RestoreVersionHandler", func() {
+	var (
+		handler     templates.RestoreVersionHandler
+		restorer    *mocks.TemplateVersionRestorer
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		context     stack.Context
+		connection  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		restorer = mocks.NewTemplateVersionRestorer()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+
+		request, err = http.NewRequest("POST", "/templates/some-template-id/versions/2/restore", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = templates.NewRestoreVersionHandler(restorer, errorWriter)
+	})
+
+	It("restores the version and returns the version it created", func() {
+		restorer.RestoreVersionCall.Returns.TemplateVersion = collections.TemplateVersion{
+			TemplateID: "some-template-id",
+			Version:    5,
+			Name:       "Some Template",
+			Subject:    "{{.Subject}}",
+			Text:       "{{.Text}}",
+			HTML:       "<p>{{.HTML}}</p>",
+			Metadata:   "{}",
+			Author:     "some-client-id",
+			CreatedAt:  time.Date(2015, time.March, 4, 10, 30, 0, 0, time.UTC),
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 5,
+			"name": "Some Template",
+			"subject": "{{.Subject}}",
+			"text": "{{.Text}}",
+			"html": "<p>{{.HTML}}</p>",
+			"metadata": {},
+			"localizations": {},
+			"author": "some-client-id",
+			"created_at": "2015-03-04T10:30:00Z"
+		}`))
+
+		Expect(restorer.RestoreVersionCall.Receives.Connection).To(Equal(connection))
+		Expect(restorer.RestoreVersionCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(restorer.RestoreVersionCall.Receives.Version).To(Equal(2))
+		Expect(restorer.RestoreVersionCall.Receives.Author).To(Equal("some-client-id"))
+	})
+
+	It("writes errors to the error writer", func() {
+		restorer.RestoreVersionCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+	})
+})
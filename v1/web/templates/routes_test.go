@@ -27,6 +27,11 @@ var _ = Describe("Routes", func() {
 			TemplateDeleter:           mocks.NewTemplateDeleter(),
 			TemplateLister:            mocks.NewTemplateLister(),
 			TemplateAssociationLister: mocks.NewTemplateAssociationLister(),
+			TemplatePreviewer:         mocks.NewTemplatePreviewer(),
+			NotificationsFinder:       mocks.NewNotificationsFinder(),
+			TemplateVersionLister:     mocks.NewTemplateVersionLister(),
+			TemplateVersionFinder:     mocks.NewTemplateVersionFinder(),
+			TemplateVersionRestorer:   mocks.NewTemplateVersionRestorer(),
 
 			RequestCounter:                          middleware.RequestCounter{},
 			RequestLogging:                          middleware.RequestLogging{},
@@ -111,6 +116,53 @@ var _ = Describe("Routes", func() {
 			authenticator := s.Middleware[2].(middleware.Authenticator)
 			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
 		})
+
+		It("routes POST /templates/{template_id}/preview", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.PreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+		It("routes GET /templates/{template_id}/versions", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ListVersionsHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /templates/{template_id}/versions/{version}", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions/3", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.GetVersionHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes POST /templates/{template_id}/versions/{version}/restore", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/versions/3/restore", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.RestoreVersionHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
 	})
 
 	Describe("/default_template", func() {
@@ -137,5 +189,17 @@ var _ = Describe("Routes", func() {
 			authenticator := s.Middleware[2].(middleware.Authenticator)
 			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
 		})
+
+		It("routes POST /default_template/preview", func() {
+			request, err := http.NewRequest("POST", "/default_template/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.PreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
 	})
 })
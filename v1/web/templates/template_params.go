@@ -4,19 +4,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"text/template"
+	"net/http"
+	"sort"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/cloudfoundry-incubator/notifications/valiant"
 )
 
 type TemplateParams struct {
-	Name     string          `json:"name" validate-required:"true"`
-	Text     string          `json:"text"`
-	HTML     string          `json:"html" validate-required:"true"`
-	Subject  string          `json:"subject"`
-	Metadata json.RawMessage `json:"metadata"`
+	Name          string                                 `json:"name" validate-required:"true"`
+	Text          string                                 `json:"text"`
+	HTML          string                                 `json:"html" validate-required:"true"`
+	Subject       string                                 `json:"subject"`
+	Metadata      json.RawMessage                        `json:"metadata"`
+	Localizations map[string]models.TemplateLocalization `json:"localizations"`
+
+	Warnings []string `json:"-"`
 }
 
 func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
@@ -39,7 +44,12 @@ func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
 		template.Metadata = json.RawMessage("{}")
 	}
 
-	err = template.validateSyntax()
+	template.Localizations, err = canonicalLocalizations(template.Localizations)
+	if err != nil {
+		return TemplateParams{}, webutil.ValidationError{Err: err}
+	}
+
+	template.Warnings, err = template.validate()
 	if err != nil {
 		return TemplateParams{}, err
 	}
@@ -49,30 +59,105 @@ func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
 	return template, nil
 }
 
-func (t TemplateParams) validateSyntax() error {
-	toValidate := map[string]string{
-		"Subject": t.Subject,
-		"Text":    t.Text,
-		"HTML":    t.HTML,
+// writeWarnings reports templates that are valid but probably not what the
+// author intended, such as a text template that leaves out {{.Text}}.
+func (t TemplateParams) writeWarnings(w http.ResponseWriter) {
+	for _, warning := range t.Warnings {
+		w.Header().Add("Warning", fmt.Sprintf("299 - %q", warning))
+	}
+}
+
+// validate checks the template and each of its localizations. Errors and
+// warnings for a localization are reported against fields such as
+// "localizations.fr.html".
+func (t TemplateParams) validate() ([]string, error) {
+	validator := common.TemplateValidator{}
+
+	warnings, err := validator.Validate(common.Templates{
+		Subject: t.Subject,
+		Text:    t.Text,
+		HTML:    t.HTML,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var locales []string
+	for locale := range t.Localizations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	var errs []common.TemplateError
+	for _, locale := range locales {
+		localization := t.Localizations[locale]
+		prefix := "localizations." + locale + "."
+
+		localizationWarnings, err := validator.Validate(common.Templates{
+			Subject: localization.Subject,
+			Text:    localization.Text,
+			HTML:    localization.HTML,
+		})
+		if err != nil {
+			validationErr, ok := err.(common.TemplateValidationError)
+			if !ok {
+				return nil, err
+			}
+
+			for _, templateErr := range validationErr.Errors {
+				templateErr.Field = prefix + templateErr.Field
+				errs = append(errs, templateErr)
+			}
+			continue
+		}
+
+		for _, warning := range localizationWarnings {
+			warnings = append(warnings, prefix+warning)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, common.TemplateValidationError{Errors: errs}
 	}
 
-	for field, contents := range toValidate {
-		_, err := template.New("test").Parse(contents)
+	return warnings, nil
+}
+
+func canonicalLocalizations(localizations map[string]models.TemplateLocalization) (map[string]models.TemplateLocalization, error) {
+	canonical := map[string]models.TemplateLocalization{}
+	for locale, localization := range localizations {
+		tag, err := common.CanonicalLocale(locale)
 		if err != nil {
-			return webutil.ValidationError{Err: fmt.Errorf("%s syntax is malformed please check your braces", field)}
+			return nil, err
 		}
+
+		if _, ok := canonical[tag]; ok {
+			return nil, fmt.Errorf("localizations contains %q more than once", tag)
+		}
+
+		canonical[tag] = localization
 	}
 
-	return nil
+	return canonical, nil
 }
 
 func (t TemplateParams) ToModel() models.Template {
+	localizations := []byte("{}")
+	if len(t.Localizations) > 0 {
+		var err error
+		localizations, err = json.Marshal(t.Localizations)
+		if err != nil {
+			panic(err) // Marshaling a map of strings cannot fail
+		}
+	}
+
 	return models.Template{
-		Name:     t.Name,
-		Text:     t.Text,
-		HTML:     t.HTML,
-		Subject:  t.Subject,
-		Metadata: string(t.Metadata),
+		Name:          t.Name,
+		Text:          t.Text,
+		HTML:          t.HTML,
+		Subject:       t.Subject,
+		Metadata:      string(t.Metadata),
+		Localizations: string(localizations),
 	}
 }
 
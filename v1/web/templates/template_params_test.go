@@ -3,10 +3,11 @@ package templates_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"io"
 	"io/ioutil"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 
@@ -63,44 +64,90 @@ var _ = Describe("TemplateParams", func() {
 			})
 
 			Context("when the template has invalid syntax", func() {
-				Context("when subject template has invalid syntax", func() {
-					It("returns a validation error", func() {
-						body := buildTemplateRequestBody(templates.TemplateParams{
-							Name:    "Template name",
-							Text:    "Textual template",
-							HTML:    "HTML template",
-							Subject: "{{.bad}",
-						})
-						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("Subject syntax is malformed please check your braces")}))
+				It("returns the position of every error", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name:    "Template name",
+						Text:    "You should feel\n{{.Text}} {{.BAD}",
+						HTML:    "<p>{{.HTML}}</p>\n<p>{{.Endorsment}}</p>",
+						Subject: "{{.bad}",
 					})
+					_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+						{Field: "subject", Line: 1, Column: 1, Message: "bad character U+007D '}'"},
+						{Field: "text", Line: 2, Column: 11, Message: "bad character U+007D '}'"},
+						{Field: "html", Line: 2, Column: 6, Message: "can't evaluate field Endorsment in type common.MessageContext"},
+					}}))
 				})
+			})
 
-				Context("when text template has invalid syntax", func() {
-					It("returns a validation error", func() {
-						body := buildTemplateRequestBody(templates.TemplateParams{
-							Name:    "Template name",
-							Text:    "You should feel {{.BAD}",
-							HTML:    "<h1> Amazing </h1>",
-							Subject: "Great Subject",
-						})
-						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("Text syntax is malformed please check your braces")}))
-					})
+			It("warns about templates that leave out the notification content", func() {
+				body := buildTemplateRequestBody(templates.TemplateParams{
+					Name:    "Template name",
+					Text:    "{{.Text}}",
+					HTML:    "<p>{{.Endorsement}}</p>",
+					Subject: "{{.Subject}}",
 				})
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Warnings).To(Equal([]string{
+					"text template does not include {{.Endorsement}}",
+					"html template does not include {{.HTML}}",
+				}))
+			})
+		})
 
-				Context("when html template has invalid syntax", func() {
-					It("returns a validation error", func() {
-						body := buildTemplateRequestBody(templates.TemplateParams{
-							Name:    "Template name",
-							Text:    "Textual template",
-							HTML:    "{{.bad}",
-							Subject: "Great Subject",
-						})
-						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("HTML syntax is malformed please check your braces")}))
-					})
+		Context("when the template has localizations", func() {
+			It("stores them under the canonical form of their language tag", func() {
+				body := buildTemplateRequestBody(templates.TemplateParams{
+					Name: "Template name",
+					HTML: "<p>{{.HTML}}</p>",
+					Localizations: map[string]models.TemplateLocalization{
+						"fr_ca": {Subject: "Sujet: {{.Subject}}", HTML: "<p>{{.HTML}}</p>"},
+					},
+				})
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Localizations).To(Equal(map[string]models.TemplateLocalization{
+					"fr-CA": {Subject: "Sujet: {{.Subject}}", HTML: "<p>{{.HTML}}</p>"},
+				}))
+			})
+
+			It("returns a validation error when a language tag is not valid", func() {
+				body := buildTemplateRequestBody(templates.TemplateParams{
+					Name: "Template name",
+					HTML: "<p>{{.HTML}}</p>",
+					Localizations: map[string]models.TemplateLocalization{
+						"not a locale": {Subject: "{{.Subject}}"},
+					},
+				})
+				_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+				Expect(err).To(MatchError(webutil.ValidationError{Err: common.InvalidLocaleError{Locale: "not a locale"}}))
+			})
+
+			It("reports errors and warnings against the localization", func() {
+				body := buildTemplateRequestBody(templates.TemplateParams{
+					Name: "Template name",
+					HTML: "<p>{{.HTML}}</p>",
+					Localizations: map[string]models.TemplateLocalization{
+						"de": {HTML: "<p>{{.Endorsement}}</p>"},
+						"fr": {Subject: "{{.bad}"},
+					},
 				})
+				_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+				Expect(err).To(MatchError(common.TemplateValidationError{Errors: []common.TemplateError{
+					{Field: "localizations.fr.subject", Line: 1, Column: 1, Message: "bad character U+007D '}'"},
+				}}))
+
+				body = buildTemplateRequestBody(templates.TemplateParams{
+					Name: "Template name",
+					HTML: "<p>{{.HTML}}</p>",
+					Localizations: map[string]models.TemplateLocalization{
+						"de": {HTML: "<p>{{.Endorsement}}</p>"},
+					},
+				})
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Warnings).To(ContainElement("localizations.de.html template does not include {{.HTML}}"))
 			})
 		})
 	})
@@ -121,6 +168,7 @@ var _ = Describe("TemplateParams", func() {
 			Expect(templateModel.HTML).To(Equal("<p>its foobar</p>"))
 			Expect(templateModel.Subject).To(Equal("Foobar Yah"))
 			Expect(templateModel.Metadata).To(MatchJSON(`{"some_property": "some_value"}`))
+			Expect(templateModel.Localizations).To(MatchJSON(`{}`))
 			Expect(templateModel.CreatedAt).To(BeZero())
 			Expect(templateModel.UpdatedAt).To(BeZero())
 		})
@@ -9,7 +9,7 @@ import (
 )
 
 type templateUpdater interface {
-	Update(database services.DatabaseInterface, templateID string, template models.Template) error
+	Update(database services.DatabaseInterface, templateID string, template models.Template, author string) error
 }
 
 type UpdateDefaultHandler struct {
@@ -31,10 +31,13 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), models.DefaultTemplateID, template.ToModel())
+	author, _ := context.Get("client_id").(string)
+	err = h.updater.Update(context.Get("database").(DatabaseInterface), models.DefaultTemplateID, template.ToModel(), author)
 	if err != nil {
 		h.errorWriter.Write(w, err)
+		return
 	}
 
+	template.writeWarnings(w)
 	w.WriteHeader(http.StatusNoContent)
 }
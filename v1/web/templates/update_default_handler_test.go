@@ -45,6 +45,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		database = mocks.NewDatabase()
 		context = stack.NewContext()
 		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
 
 		handler = templates.NewUpdateDefaultHandler(updater, errorWriter)
 	})
@@ -56,12 +57,14 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		Expect(updater.UpdateCall.Receives.Database).To(Equal(database))
 		Expect(updater.UpdateCall.Receives.TemplateID).To(Equal(models.DefaultTemplateID))
 		Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{
-			Name:     "Defaultish Template",
-			Subject:  "{{.Subject}}",
-			HTML:     "<p>something</p>",
-			Text:     "something",
-			Metadata: `{"hello": true}`,
+			Name:          "Defaultish Template",
+			Subject:       "{{.Subject}}",
+			HTML:          "<p>something</p>",
+			Text:          "something",
+			Metadata:      `{"hello": true}`,
+			Localizations: "{}",
 		}))
+		Expect(updater.UpdateCall.Receives.Author).To(Equal("some-client-id"))
 	})
 
 	Context("when the request is not valid", func() {
@@ -28,11 +28,13 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel())
+	author, _ := context.Get("client_id").(string)
+	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel(), author)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
+	templateParams.writeWarnings(w)
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -41,6 +41,7 @@ var _ = Describe("UpdateHandler", func() {
 			database = mocks.NewDatabase()
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("client_id", "some-client-id")
 
 			handler = templates.NewUpdateHandler(updater, errorWriter)
 		})
@@ -52,12 +53,14 @@ var _ = Describe("UpdateHandler", func() {
 			Expect(updater.UpdateCall.Receives.Database).To(Equal(database))
 			Expect(updater.UpdateCall.Receives.TemplateID).To(Equal("a-template-id"))
 			Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{
-				Name:     "An Interesting Template",
-				Subject:  "very interesting subject",
-				Text:     "Here's the msg {{.Text}}",
-				HTML:     "<p>turkey gobble</p>",
-				Metadata: "{}",
+				Name:          "An Interesting Template",
+				Subject:       "very interesting subject",
+				Text:          "Here's the msg {{.Text}}",
+				HTML:          "<p>turkey gobble</p>",
+				Metadata:      "{}",
+				Localizations: "{}",
 			}))
+			Expect(updater.UpdateCall.Receives.Author).To(Equal("some-client-id"))
 		})
 
 		It("can update a template without a subject field", func() {
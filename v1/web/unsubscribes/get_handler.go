@@ -0,0 +1,39 @@
+package unsubscribes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ryanmoran/stack"
+)
+
+type GetHandler struct {
+	unsubscriber unsubscriber
+}
+
+func NewGetHandler(unsubscriber unsubscriber) GetHandler {
+	return GetHandler{
+		unsubscriber: unsubscriber,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	token := strings.TrimPrefix(req.URL.Path, "/unsubscribe/")
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	target, err := h.unsubscriber.Lookup(connection, token)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if target.Kind.Critical {
+		writePage(w, 422, errorPage, page{Message: "This notification is critical and cannot be unsubscribed from."})
+		return
+	}
+
+	writePage(w, http.StatusOK, confirmationPage, page{
+		Token:       token,
+		Description: describe(target),
+	})
+}
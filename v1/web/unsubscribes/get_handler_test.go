@@ -0,0 +1,106 @@
+package unsubscribes_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/unsubscribes"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler      unsubscribes.GetHandler
+		writer       *httptest.ResponseRecorder
+		request      *http.Request
+		context      stack.Context
+		connection   *mocks.Connection
+		unsubscriber *mocks.Unsubscriber
+	)
+
+	BeforeEach(func() {
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		unsubscriber = mocks.NewUnsubscriber()
+		unsubscriber.LookupCall.Returns.Target = services.UnsubscribeTarget{
+			UserGUID: "some-user",
+			ClientID: "some-client",
+			Kind: models.Kind{
+				ID:          "some-kind",
+				ClientID:    "some-client",
+				Description: "Some <Kind>",
+			},
+		}
+
+		var err error
+		request, err = http.NewRequest("GET", "/unsubscribe/some-token", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		writer = httptest.NewRecorder()
+		handler = unsubscribes.NewGetHandler(unsubscriber)
+	})
+
+	It("renders a confirmation page without unsubscribing", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Header().Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		Expect(writer.Body.String()).To(ContainSubstring(`<form method="POST" action="/unsubscribe/some-token">`))
+		Expect(writer.Body.String()).To(ContainSubstring("Some &lt;Kind&gt;"))
+
+		Expect(unsubscriber.LookupCall.Receives.Connection).To(Equal(connection))
+		Expect(unsubscriber.LookupCall.Receives.Token).To(Equal("some-token"))
+		Expect(unsubscriber.UnsubscribeCall.WasCalled).To(BeFalse())
+	})
+
+	It("refuses to offer unsubscribing from a critical kind", func() {
+		unsubscriber.LookupCall.Returns.Target.Kind.Critical = true
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(422))
+		Expect(writer.Body.String()).NotTo(ContainSubstring("<form"))
+	})
+
+	Context("when the token is invalid", func() {
+		It("responds with a 404", func() {
+			unsubscriber.LookupCall.Returns.Error = services.InvalidUnsubscribeTokenError{Err: errors.New("bad token")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when the kind no longer exists", func() {
+		It("responds with a 404", func() {
+			unsubscriber.LookupCall.Returns.Error = services.MissingKindOrClientError{Err: errors.New("missing")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when an unknown error occurs", func() {
+		It("responds with a 500", func() {
+			unsubscriber.LookupCall.Returns.Error = errors.New("boom")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package unsubscribes
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+var confirmationPage = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html>
+	<head><title>Unsubscribe</title></head>
+	<body>
+		<p>Do you want to stop receiving &ldquo;{{.Description}}&rdquo; notifications?</p>
+		<form method="POST" action="/unsubscribe/{{.Token}}">
+			<input type="hidden" name="List-Unsubscribe" value="One-Click">
+			<button type="submit">Unsubscribe</button>
+		</form>
+	</body>
+</html>`))
+
+var unsubscribedPage = template.Must(template.New("unsubscribed").Parse(`<!DOCTYPE html>
+<html>
+	<head><title>Unsubscribed</title></head>
+	<body>
+		<p>You will no longer receive &ldquo;{{.Description}}&rdquo; notifications.</p>
+	</body>
+</html>`))
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+	<head><title>Unsubscribe</title></head>
+	<body>
+		<p>{{.Message}}</p>
+	</body>
+</html>`))
+
+type page struct {
+	Token       string
+	Description string
+	Message     string
+}
+
+func describe(target services.UnsubscribeTarget) string {
+	if target.Kind.Description != "" {
+		return target.Kind.Description
+	}
+
+	return target.Kind.ID
+}
+
+func writePage(w http.ResponseWriter, status int, tmpl *template.Template, data page) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := tmpl.Execute(w, data)
+	if err != nil {
+		panic(err) // The pages are static and their data is always strings
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case services.InvalidUnsubscribeTokenError, services.MissingKindOrClientError:
+		writePage(w, http.StatusNotFound, errorPage, page{Message: "This unsubscribe link is invalid or has expired."})
+	case services.CriticalKindError:
+		writePage(w, 422, errorPage, page{Message: "This notification is critical and cannot be unsubscribed from."})
+	default:
+		writePage(w, http.StatusInternalServerError, errorPage, page{Message: "Something went wrong. Please try again later."})
+	}
+}
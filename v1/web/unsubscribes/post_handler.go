@@ -0,0 +1,33 @@
+package unsubscribes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ryanmoran/stack"
+)
+
+type PostHandler struct {
+	unsubscriber unsubscriber
+}
+
+func NewPostHandler(unsubscriber unsubscriber) PostHandler {
+	return PostHandler{
+		unsubscriber: unsubscriber,
+	}
+}
+
+func (h PostHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	token := strings.TrimPrefix(req.URL.Path, "/unsubscribe/")
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	target, err := h.unsubscriber.Unsubscribe(connection, token)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writePage(w, http.StatusOK, unsubscribedPage, page{
+		Description: describe(target),
+	})
+}
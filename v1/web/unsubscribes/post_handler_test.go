@@ -0,0 +1,95 @@
+package unsubscribes_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/unsubscribes"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostHandler", func() {
+	var (
+		handler      unsubscribes.PostHandler
+		writer       *httptest.ResponseRecorder
+		request      *http.Request
+		context      stack.Context
+		connection   *mocks.Connection
+		unsubscriber *mocks.Unsubscriber
+	)
+
+	BeforeEach(func() {
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		unsubscriber = mocks.NewUnsubscriber()
+		unsubscriber.UnsubscribeCall.Returns.Target = services.UnsubscribeTarget{
+			UserGUID: "some-user",
+			ClientID: "some-client",
+			Kind: models.Kind{
+				ID:       "some-kind",
+				ClientID: "some-client",
+			},
+		}
+
+		var err error
+		request, err = http.NewRequest("POST", "/unsubscribe/some-token", strings.NewReader("List-Unsubscribe=One-Click"))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		writer = httptest.NewRecorder()
+		handler = unsubscribes.NewPostHandler(unsubscriber)
+	})
+
+	It("unsubscribes the user from the kind", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(ContainSubstring("some-kind"))
+
+		Expect(unsubscriber.UnsubscribeCall.Receives.Connection).To(Equal(connection))
+		Expect(unsubscriber.UnsubscribeCall.Receives.Token).To(Equal("some-token"))
+	})
+
+	Context("when the token is invalid", func() {
+		It("responds with a 404", func() {
+			unsubscriber.UnsubscribeCall.Returns.Error = services.InvalidUnsubscribeTokenError{Err: errors.New("bad token")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when the kind is critical", func() {
+		It("responds with a 422", func() {
+			unsubscriber.UnsubscribeCall.Returns.Error = services.CriticalKindError{Err: errors.New("critical")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+		})
+	})
+
+	Context("when an unknown error occurs", func() {
+		It("responds with a 500", func() {
+			unsubscriber.UnsubscribeCall.Returns.Error = errors.New("boom")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+package unsubscribes
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type unsubscriber interface {
+	Lookup(conn services.ConnectionInterface, token string) (services.UnsubscribeTarget, error)
+	Unsubscribe(conn services.ConnectionInterface, token string) (services.UnsubscribeTarget, error)
+}
+
+type Routes struct {
+	RequestCounter    stack.Middleware
+	RequestLogging    stack.Middleware
+	DatabaseAllocator stack.Middleware
+
+	Unsubscriber unsubscriber
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/unsubscribe/{token}", NewGetHandler(r.Unsubscriber), r.RequestLogging, r.RequestCounter, r.DatabaseAllocator)
+	m.Handle("POST", "/unsubscribe/{token}", NewPostHandler(r.Unsubscriber), r.RequestLogging, r.RequestCounter, r.DatabaseAllocator)
+}
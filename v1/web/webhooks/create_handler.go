@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type CreateHandler struct {
+	registry    webhookRegistry
+	errorWriter errorWriter
+}
+
+func NewCreateHandler(registry webhookRegistry, errWriter errorWriter) CreateHandler {
+	return CreateHandler{
+		registry:    registry,
+		errorWriter: errWriter,
+	}
+}
+
+func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	clientID, _ := parsePath(req.URL.Path)
+
+	var params struct {
+		URL    string `json:"url"`
+		Secret string `json:"secret"`
+	}
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	callbackURL, err := url.Parse(params.URL)
+	if err != nil || (callbackURL.Scheme != "http" && callbackURL.Scheme != "https") || callbackURL.Host == "" {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New("url must be an absolute http or https URL")})
+		return
+	}
+
+	webhook, err := h.registry.Register(context.Get("database").(DatabaseInterface), clientID, params.URL, params.Secret)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := newWebhookDocument(webhook)
+	document.Secret = webhook.Secret
+
+	writeJSON(w, http.StatusCreated, document)
+}
@@ -0,0 +1,109 @@
+package webhooks_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webhooks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateHandler", func() {
+	var (
+		handler     webhooks.CreateHandler
+		registry    *mocks.WebhookRegistry
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		registry = mocks.NewWebhookRegistry()
+		registry.RegisterCall.Returns.Webhook = services.Webhook{
+			ID:        "some-webhook-id",
+			ClientID:  "some-client",
+			URL:       "https://example.com/hooks",
+			Secret:    "some-secret",
+			CreatedAt: time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC),
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = webhooks.NewCreateHandler(registry, errorWriter)
+	})
+
+	It("registers the webhook and returns its secret", func() {
+		request, err := http.NewRequest("POST", "/clients/some-client/webhooks", bytes.NewBufferString(`{"url":"https://example.com/hooks"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{
+			"id": "some-webhook-id",
+			"client_id": "some-client",
+			"url": "https://example.com/hooks",
+			"secret": "some-secret",
+			"created_at": "2015-06-08T14:32:11Z"
+		}`))
+
+		Expect(registry.RegisterCall.Receives.Database).To(Equal(database))
+		Expect(registry.RegisterCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(registry.RegisterCall.Receives.URL).To(Equal("https://example.com/hooks"))
+		Expect(registry.RegisterCall.Receives.Secret).To(BeEmpty())
+	})
+
+	It("passes along a secret chosen by the client", func() {
+		request, err := http.NewRequest("POST", "/clients/some-client/webhooks", bytes.NewBufferString(`{"url":"https://example.com/hooks","secret":"my-secret"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(registry.RegisterCall.Receives.Secret).To(Equal("my-secret"))
+	})
+
+	Context("failure cases", func() {
+		It("writes a parse error when the body is not JSON", func() {
+			request, err := http.NewRequest("POST", "/clients/some-client/webhooks", bytes.NewBufferString(`{`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ParseError{}))
+		})
+
+		It("writes a validation error when the url is not an absolute http url", func() {
+			for _, body := range []string{`{}`, `{"url":"/relative"}`, `{"url":"ftp://example.com"}`} {
+				request, err := http.NewRequest("POST", "/clients/some-client/webhooks", bytes.NewBufferString(body))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New("url must be an absolute http or https URL")}))
+			}
+		})
+
+		It("delegates registry errors to the error writer", func() {
+			registry.RegisterCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("POST", "/clients/some-client/webhooks", bytes.NewBufferString(`{"url":"https://example.com/hooks"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("BOOM!")))
+		})
+	})
+})
@@ -0,0 +1,31 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type DeleteHandler struct {
+	registry    webhookRegistry
+	errorWriter errorWriter
+}
+
+func NewDeleteHandler(registry webhookRegistry, errWriter errorWriter) DeleteHandler {
+	return DeleteHandler{
+		registry:    registry,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	clientID, webhookID := parsePath(req.URL.Path)
+
+	err := h.registry.Unregister(context.Get("database").(DatabaseInterface), clientID, webhookID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package webhooks_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webhooks"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler     webhooks.DeleteHandler
+		registry    *mocks.WebhookRegistry
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		registry = mocks.NewWebhookRegistry()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("DELETE", "/clients/some-client/webhooks/some-webhook-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = webhooks.NewDeleteHandler(registry, errorWriter)
+	})
+
+	It("unregisters the webhook", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(registry.UnregisterCall.Receives.Database).To(Equal(database))
+		Expect(registry.UnregisterCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(registry.UnregisterCall.Receives.WebhookID).To(Equal("some-webhook-id"))
+	})
+
+	It("delegates registry errors to the error writer", func() {
+		registry.UnregisterCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{Err: errors.New("not found")}))
+	})
+})
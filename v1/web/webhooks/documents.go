@@ -0,0 +1,48 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+var webhooksPathRegex = regexp.MustCompile("^/clients/([^/]+)/webhooks(?:/([^/]+))?$")
+
+type webhookDocument struct {
+	ID        string `json:"id"`
+	ClientID  string `json:"client_id"`
+	URL       string `json:"url"`
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+func newWebhookDocument(webhook services.Webhook) webhookDocument {
+	return webhookDocument{
+		ID:        webhook.ID,
+		ClientID:  webhook.ClientID,
+		URL:       webhook.URL,
+		CreatedAt: webhook.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func parsePath(path string) (clientID, webhookID string) {
+	matches := webhooksPathRegex.FindStringSubmatch(path)
+	if matches == nil {
+		return "", ""
+	}
+
+	return matches[1], matches[2]
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
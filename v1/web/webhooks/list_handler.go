@@ -0,0 +1,41 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type ListHandler struct {
+	registry    webhookRegistry
+	errorWriter errorWriter
+}
+
+func NewListHandler(registry webhookRegistry, errWriter errorWriter) ListHandler {
+	return ListHandler{
+		registry:    registry,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	clientID, _ := parsePath(req.URL.Path)
+
+	webhooks, err := h.registry.List(context.Get("database").(DatabaseInterface), clientID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := struct {
+		Webhooks []webhookDocument `json:"webhooks"`
+	}{
+		Webhooks: []webhookDocument{},
+	}
+
+	for _, webhook := range webhooks {
+		document.Webhooks = append(document.Webhooks, newWebhookDocument(webhook))
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
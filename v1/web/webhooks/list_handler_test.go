@@ -0,0 +1,87 @@
+package webhooks_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webhooks"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler     webhooks.ListHandler
+		registry    *mocks.WebhookRegistry
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		registry = mocks.NewWebhookRegistry()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("GET", "/clients/some-client/webhooks", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = webhooks.NewListHandler(registry, errorWriter)
+	})
+
+	It("lists the webhooks without their secrets", func() {
+		registry.ListCall.Returns.Webhooks = []services.Webhook{
+			{
+				ID:        "some-webhook-id",
+				ClientID:  "some-client",
+				URL:       "https://example.com/hooks",
+				Secret:    "some-secret",
+				CreatedAt: time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC),
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{
+			"webhooks": [
+				{
+					"id": "some-webhook-id",
+					"client_id": "some-client",
+					"url": "https://example.com/hooks",
+					"created_at": "2015-06-08T14:32:11Z"
+				}
+			]
+		}`))
+
+		Expect(registry.ListCall.Receives.Database).To(Equal(database))
+		Expect(registry.ListCall.Receives.ClientID).To(Equal("some-client"))
+	})
+
+	It("returns an empty list when there are no webhooks", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{"webhooks": []}`))
+	})
+
+	It("delegates registry errors to the error writer", func() {
+		registry.ListCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("BOOM!")))
+	})
+})
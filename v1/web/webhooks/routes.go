@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type webhookRegistry interface {
+	Register(database services.DatabaseInterface, clientID, url, secret string) (services.Webhook, error)
+	List(database services.DatabaseInterface, clientID string) ([]services.Webhook, error)
+	Unregister(database services.DatabaseInterface, clientID, webhookID string) error
+}
+
+type Routes struct {
+	RequestCounter                   stack.Middleware
+	RequestLogging                   stack.Middleware
+	NotificationsManageAuthenticator stack.Middleware
+	DatabaseAllocator                stack.Middleware
+
+	ErrorWriter     errorWriter
+	WebhookRegistry webhookRegistry
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("POST", "/clients/{client_id}/webhooks", NewCreateHandler(r.WebhookRegistry, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/clients/{client_id}/webhooks", NewListHandler(r.WebhookRegistry, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/clients/{client_id}/webhooks/{webhook_id}", NewDeleteHandler(r.WebhookRegistry, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+}
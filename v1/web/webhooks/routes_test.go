@@ -0,0 +1,68 @@
+package webhooks_test
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webhooks"
+	"github.com/cloudfoundry-incubator/notifications/web"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routes", func() {
+	var muxer web.Muxer
+
+	BeforeEach(func() {
+		muxer = web.NewMuxer()
+		webhooks.Routes{
+			RequestCounter:                   middleware.RequestCounter{},
+			RequestLogging:                   middleware.RequestLogging{},
+			DatabaseAllocator:                middleware.DatabaseAllocator{},
+			NotificationsManageAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.manage"}},
+
+			ErrorWriter:     mocks.NewErrorWriter(),
+			WebhookRegistry: mocks.NewWebhookRegistry(),
+		}.Register(muxer)
+	})
+
+	It("routes POST /clients/{client_id}/webhooks", func() {
+		request, err := http.NewRequest("POST", "/clients/some-client-id/webhooks", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(webhooks.CreateHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes GET /clients/{client_id}/webhooks", func() {
+		request, err := http.NewRequest("GET", "/clients/some-client-id/webhooks", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(webhooks.ListHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes DELETE /clients/{client_id}/webhooks/{webhook_id}", func() {
+		request, err := http.NewRequest("DELETE", "/clients/some-client-id/webhooks/some-webhook-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(webhooks.DeleteHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+})
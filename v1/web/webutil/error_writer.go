@@ -5,6 +5,9 @@ import (
 	"net/http"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -18,15 +21,15 @@ func NewErrorWriter() ErrorWriter {
 
 func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
 	switch err.(type) {
-	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, MissingUserTokenError, ValidationError:
+	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, MissingUserTokenError, ValidationError, services.TemplateRenderError, common.TemplateValidationError:
 		w.WriteHeader(422)
 	case services.CCDownError:
 		w.WriteHeader(http.StatusBadGateway)
-	case services.CCNotFoundError, models.NotFoundError, cf.NotFoundError:
+	case services.CCNotFoundError, models.NotFoundError, cf.NotFoundError, gobble.DeadJobNotFoundError, mail.OutboxMessageNotFoundError:
 		w.WriteHeader(http.StatusNotFound)
 	case ParseError, SchemaError:
 		w.WriteHeader(http.StatusBadRequest)
-	case models.DuplicateError:
+	case models.DuplicateError, services.MessageNotCancelableError:
 		w.WriteHeader(http.StatusConflict)
 	case services.DefaultScopeError:
 		w.WriteHeader(http.StatusNotAcceptable)
@@ -34,7 +37,15 @@ func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 
+	messages := []string{err.Error()}
+	if validationErr, ok := err.(common.TemplateValidationError); ok {
+		messages = []string{}
+		for _, templateErr := range validationErr.Errors {
+			messages = append(messages, templateErr.Error())
+		}
+	}
+
 	json.NewEncoder(w).Encode(map[string][]string{
-		"errors": []string{err.Error()},
+		"errors": messages,
 	})
 }
@@ -6,6 +6,9 @@ import (
 	"net/http/httptest"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -90,6 +93,28 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 422 when a template cannot be rendered", func() {
+		writer.Write(recorder, services.TemplateRenderError{Err: errors.New("template: unexpected EOF")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["template: unexpected EOF"]
+		}`))
+	})
+
+	It("returns a 422 listing every error when templates are invalid", func() {
+		writer.Write(recorder, common.TemplateValidationError{Errors: []common.TemplateError{
+			{Field: "subject", Line: 1, Column: 1, Message: "unexpected EOF"},
+			{Field: "html", Line: 2, Column: 5, Message: "can't evaluate field Raptors in type common.MessageContext"},
+		}})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": [
+				"subject:1:1: unexpected EOF",
+				"html:2:5: can't evaluate field Raptors in type common.MessageContext"
+			]
+		}`))
+	})
+
 	It("returns a 422 when trying to send a critical notification without correct scope", func() {
 		writer.Write(recorder, webutil.NewCriticalNotificationError("raptors"))
 		Expect(recorder.Code).To(Equal(422))
@@ -106,6 +131,14 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 409 when a message cannot be canceled", func() {
+		writer.Write(recorder, services.MessageNotCancelableError{Err: errors.New("already delivered")})
+		Expect(recorder.Code).To(Equal(409))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["already delivered"]
+		}`))
+	})
+
 	It("returns a 404 when a record cannot be found", func() {
 		writer.Write(recorder, models.NotFoundError{Err: errors.New("not found")})
 		Expect(recorder.Code).To(Equal(404))
@@ -145,4 +178,20 @@ var _ = Describe("ErrorWriter", func() {
 			"errors": ["unknown error"]
 		}`))
 	})
+
+	It("returns a 404 when a dead job cannot be found", func() {
+		writer.Write(recorder, gobble.DeadJobNotFoundError{ID: 42})
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["Dead job with ID 42 could not be found"]
+		}`))
+	})
+
+	It("returns a 404 when an outbox message cannot be found", func() {
+		writer.Write(recorder, mail.OutboxMessageNotFoundError{ID: 42})
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["Outbox message with ID 42 could not be found"]
+		}`))
+	})
 })
@@ -0,0 +1,37 @@
+package webutil
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+// ParsePagination reads the limit and offset query parameters shared by the
+// list endpoints. Missing values fall back to the defaults, and values out of
+// range are reported as a ValidationError.
+func ParsePagination(query url.Values) (limit, offset int, err error) {
+	limit, err = parseParam(query.Get("limit"), DefaultListLimit)
+	if err != nil || limit < 1 || limit > MaxListLimit {
+		return 0, 0, ValidationError{Err: errors.New("limit must be an integer between 1 and 500")}
+	}
+
+	offset, err = parseParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		return 0, 0, ValidationError{Err: errors.New("offset must be a non-negative integer")}
+	}
+
+	return limit, offset, nil
+}
+
+func parseParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,39 @@
+package webutil_test
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParsePagination", func() {
+	It("defaults the limit and offset", func() {
+		limit, offset, err := webutil.ParsePagination(url.Values{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limit).To(Equal(50))
+		Expect(offset).To(Equal(0))
+	})
+
+	It("reads the limit and offset from the query", func() {
+		limit, offset, err := webutil.ParsePagination(url.Values{"limit": {"500"}, "offset": {"20"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limit).To(Equal(500))
+		Expect(offset).To(Equal(20))
+	})
+
+	It("rejects limits that are not between 1 and 500", func() {
+		for _, limit := range []string{"0", "501", "ten"} {
+			_, _, err := webutil.ParsePagination(url.Values{"limit": {limit}})
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("limit must be an integer between 1 and 500")}))
+		}
+	})
+
+	It("rejects negative offsets", func() {
+		_, _, err := webutil.ParsePagination(url.Values{"offset": {"-1"}})
+		Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("offset must be a non-negative integer")}))
+	})
+})
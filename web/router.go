@@ -18,6 +18,13 @@ func NewRouter(config Config) http.Handler {
 		CCHost:            config.CCHost,
 		CORSOrigin:        config.CORSOrigin,
 		SQLDB:             config.SQLDB,
+		EncryptionKey:     config.EncryptionKey,
+		Outbox:            config.Outbox,
+		Sender:            config.Sender,
+		Domain:            config.Domain,
+		PublicURL:         config.PublicURL,
+		HTMLAllowedTags:   config.HTMLAllowedTags,
+		HTMLBaseURL:       config.HTMLBaseURL,
 	})
 
 	return VersionRouter{
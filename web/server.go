@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/pivotal-golang/lager"
 )
@@ -27,6 +28,15 @@ type Config struct {
 	UAAClientSecret   string
 	DefaultUAAScopes  []string
 	CCHost            string
+
+	EncryptionKey []byte
+	Outbox        *mail.Outbox
+
+	Sender          string
+	Domain          string
+	PublicURL       string
+	HTMLAllowedTags []string
+	HTMLBaseURL     string
 }
 
 type Server struct{}